@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func GetAccountSettings(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getAccountSettings")
+
+	return handle(c, w, r)
+}
+
+func AccountSettingsSet(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "accountSettingsSet")
+
+	return handle(c, w, r)
+}
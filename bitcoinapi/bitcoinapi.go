@@ -156,6 +156,11 @@ func SendRawTransaction(c context.Context, txHexString string) (string, error) {
 		return "", err
 	}
 
+	// Transactions serialised with witness data can't be deserialised by btcd so they are sent as is
+	if len(txBytes) > 6 && txBytes[4] == 0x00 && txBytes[5] == 0x01 {
+		return sendRawTransactionHex(txHexString)
+	}
+
 	// Deserialise the transaction
 	tx, err := btcutil.NewTxFromBytes(txBytes)
 	if err != nil {
@@ -184,6 +189,36 @@ func SendRawTransaction(c context.Context, txHexString string) (string, error) {
 	return fmt.Sprintf("%s", result.String()), nil
 }
 
+// Sends the hex encoded transaction to bitcoind without deserialising it first
+func sendRawTransactionHex(txHexString string) (string, error) {
+	client, err := btcrpcclient.New(&config, nil)
+	if err != nil {
+		log.Println(err.Error())
+		return "", err
+	}
+	defer client.Shutdown()
+
+	txHexJson, err := json.Marshal(txHexString)
+	if err != nil {
+		log.Println(err.Error())
+		return "", err
+	}
+
+	result, err := client.RawRequest("sendrawtransaction", []json.RawMessage{txHexJson})
+	if err != nil {
+		log.Println(err.Error())
+		return "", err
+	}
+
+	var txId string
+	if err := json.Unmarshal(result, &txId); err != nil {
+		log.Println(err.Error())
+		return "", err
+	}
+
+	return txId, nil
+}
+
 func GetBalance(c context.Context, address string) (uint64, error) {
 	if isInit == false {
 		Init()
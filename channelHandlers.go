@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func ChannelCreate(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "channelCreate")
+
+	return handle(c, w, r)
+}
+
+func GetChannel(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getChannel")
+
+	return handle(c, w, r)
+}
+
+func GetChannels(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getChannels")
+
+	return handle(c, w, r)
+}
+
+func ChannelFund(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "channelFund")
+
+	return handle(c, w, r)
+}
+
+func ChannelAuthorise(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "channelAuthorise")
+
+	return handle(c, w, r)
+}
+
+func ChannelVerify(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "channelVerify")
+
+	return handle(c, w, r)
+}
+
+func ChannelClaim(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "channelClaim")
+
+	return handle(c, w, r)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func CheckCreate(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "checkCreate")
+
+	return handle(c, w, r)
+}
+
+func GetCheck(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getCheck")
+
+	return handle(c, w, r)
+}
+
+func CheckCash(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "checkCash")
+
+	return handle(c, w, r)
+}
+
+func CheckCancel(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "checkCancel")
+
+	return handle(c, w, r)
+}
+
+func GetChecks(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getChecks")
+
+	return handle(c, w, r)
+}
@@ -10,6 +10,7 @@ const NonceIntKey key = 2
 const BlockchainIdKey key = 3
 const RequestTypeKey key = 4
 const EnvKey key = 5
+const MaxFeeKey key = 6
 
 const CounterpartyBlockchainId string = "counterparty"
 const RippleBlockchainId string = "ripple"
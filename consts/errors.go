@@ -28,6 +28,12 @@ type CounterpartyStruct struct {
 	MalformedAddress          ErrCodes
 	OnlyIssuerCanPayDividends ErrCodes
 	NoSuchAsset               ErrCodes
+	InvalidCosigners          ErrCodes
+	UnknownCosigner           ErrCodes
+	InvalidMultisigSignature  ErrCodes
+	AlreadySigned             ErrCodes
+	QuorumNotReached          ErrCodes
+	ProposalNotPending        ErrCodes
 }
 
 var CounterpartyErrors = CounterpartyStruct{
@@ -43,6 +49,12 @@ var CounterpartyErrors = CounterpartyStruct{
 	MalformedAddress:          ErrCodes{1010, "One of the addresses provided was not correct. Please check the addresses involved in the transaction."},
 	OnlyIssuerCanPayDividends: ErrCodes{1011, "Only the issuer may pay dividends."},
 	NoSuchAsset:               ErrCodes{1012, "The asset specified is incorrect or doesn't exist."},
+	InvalidCosigners:          ErrCodes{1013, "The cosigners or the number of required signatures are invalid. Between 1 and 15 distinct cosigners must be given."},
+	UnknownCosigner:           ErrCodes{1014, "The public key given is not a cosigner of the multisig wallet."},
+	InvalidMultisigSignature:  ErrCodes{1015, "The signatures given could not be verified against the transaction."},
+	AlreadySigned:             ErrCodes{1016, "The cosigner has already signed the transaction."},
+	QuorumNotReached:          ErrCodes{1017, "The required number of signatures has not yet been collected."},
+	ProposalNotPending:        ErrCodes{1018, "The transaction is no longer awaiting signatures."},
 }
 
 type GenericStruct struct {
@@ -61,6 +73,19 @@ type GenericStruct struct {
 	InvalidAddress        ErrCodes
 	InvalidAsset          ErrCodes
 	ApiKeyDisabled        ErrCodes
+	InvalidWalletId       ErrCodes
+	KeystoreUnavailable   ErrCodes
+	InvalidWalletPassword ErrCodes
+	WalletLocked          ErrCodes
+	InvalidXpub           ErrCodes
+	AddressNotInWallet    ErrCodes
+	FuelUnavailable       ErrCodes
+	NotOperator           ErrCodes
+	InvalidActivationId   ErrCodes
+	InvalidOfferId        ErrCodes
+	InvalidEscrowId       ErrCodes
+	InvalidChannelId      ErrCodes
+	InvalidCheckId        ErrCodes
 
 	GeneralError ErrCodes
 }
@@ -82,6 +107,19 @@ var GenericErrors = GenericStruct{
 	InvalidAddress:        ErrCodes{14, "The specified address is invalid. Please correct the address and resubmit."},
 	InvalidAsset:          ErrCodes{15, "The specified asset is invalid. Please correct the asset and resubmit."},
 	ApiKeyDisabled:        ErrCodes{16, "The specified API is valid. However it has been disabled by an administrator."},
+	InvalidWalletId:       ErrCodes{17, "The specified walletId is invalid or does not exist."},
+	KeystoreUnavailable:   ErrCodes{18, "The custodial keystore is not available. Please contact Vennd.io support."},
+	InvalidWalletPassword: ErrCodes{19, "The wallet password is incorrect."},
+	WalletLocked:          ErrCodes{20, "The wallet is locked. Unlock the wallet or change its unlock policy before signing."},
+	InvalidXpub:           ErrCodes{21, "The extended public key is invalid. The xpub of the account, ie m/0', or the public generator of the Ripple seed must be given."},
+	AddressNotInWallet:    ErrCodes{22, "The specified address does not belong to the wallet."},
+	FuelUnavailable:       ErrCodes{23, "No activation wallet with sufficient funds is available. Please try again later or contact Vennd.io support."},
+	NotOperator:           ErrCodes{24, "The access key is not permitted to manage the activation wallets."},
+	InvalidActivationId:   ErrCodes{25, "The specified activationId is invalid. Please correct the activationId and resubmit."},
+	InvalidOfferId:        ErrCodes{26, "The specified offerId is invalid. Please correct the offerId and resubmit."},
+	InvalidEscrowId:       ErrCodes{27, "The specified escrowId is invalid. Please correct the escrowId and resubmit."},
+	InvalidChannelId:      ErrCodes{28, "The specified channelId is invalid. Please correct the channelId and resubmit."},
+	InvalidCheckId:        ErrCodes{29, "The specified checkId is invalid. Please correct the checkId and resubmit."},
 }
 
 type RippleStruct struct {
@@ -101,6 +139,48 @@ type RippleStruct struct {
 	InsufficientXRP               ErrCodes
 	UnableToGetLatestLedger       ErrCodes
 	QueuedNotAccepted             ErrCodes
+	InsufficientFunds             ErrCodes
+	InsufficientReserve           ErrCodes
+	TrustLineNotFound             ErrCodes
+	TrustLineHasBalance           ErrCodes
+	InvalidTransferRate           ErrCodes
+	InvalidDomain                 ErrCodes
+	InvalidEmailHash              ErrCodes
+	RequireAuthHasOwners          ErrCodes
+	GlobalFreezePermanent         ErrCodes
+	NoSettingsGiven               ErrCodes
+	InvalidOfferFlags             ErrCodes
+	SameAsset                     ErrCodes
+	OfferNotOpen                  ErrCodes
+	OfferUnfunded                 ErrCodes
+	OfferKilled                   ErrCodes
+	NoPathFound                   ErrCodes
+	PathPartial                   ErrCodes
+	TxNotValidated                ErrCodes
+	InvalidMemo                   ErrCodes
+	DestinationTagRequired        ErrCodes
+	InvalidEscrowTimes            ErrCodes
+	InvalidCondition              ErrCodes
+	EscrowNotOpen                 ErrCodes
+	EscrowNotXRP                  ErrCodes
+	EscrowNotReady                ErrCodes
+	ChannelNotOpen                ErrCodes
+	ChannelInsufficientFunds      ErrCodes
+	InvalidClaim                  ErrCodes
+	SignerCannotSignClaims        ErrCodes
+	InvalidChannelTimes           ErrCodes
+	InvalidSignerList             ErrCodes
+	UnknownSigner                 ErrCodes
+	InvalidMultiSignature         ErrCodes
+	AlreadySigned                 ErrCodes
+	QuorumNotReached              ErrCodes
+	ProposalNotPending            ErrCodes
+	SignerCannotMultiSign         ErrCodes
+	CheckNotOpen                  ErrCodes
+	InvalidCheckExpiration        ErrCodes
+	InvalidInvoiceId              ErrCodes
+	InvalidMarker                 ErrCodes
+	FeeTooHigh                    ErrCodes
 }
 
 var RippleErrors = RippleStruct{
@@ -120,4 +200,46 @@ var RippleErrors = RippleStruct{
 	InsufficientXRP:               ErrCodes{2013, "There was insufficient XRP in the address to perform the payment. Please activate the address and try again."},
 	UnableToGetLatestLedger:       ErrCodes{2014, "Unable to retrieve the latest ledger that Ripple has validated. Internal server error..."},
 	QueuedNotAccepted:             ErrCodes{2015, "The transaction was queued due to esclation of transaction fees. However, it was not accepted after the maximum ledger sequence."},
+	InsufficientFunds:             ErrCodes{2016, "Insufficient asset in the wallet to perform the payment."},
+	InsufficientReserve:           ErrCodes{2017, "The address does not hold enough XRP to meet the reserve of another trust line. Please activate the address and try again."},
+	TrustLineNotFound:             ErrCodes{2018, "The trust line does not exist."},
+	TrustLineHasBalance:           ErrCodes{2019, "The trust line can not be removed while it holds a balance."},
+	InvalidTransferRate:           ErrCodes{2020, "The transfer rate must be 0 for no fee or between 1000000000 and 2000000000."},
+	InvalidDomain:                 ErrCodes{2021, "The domain is invalid. The domain must be 256 characters or less."},
+	InvalidEmailHash:              ErrCodes{2022, "The email hash is invalid. The email hash must be the MD5 hash of the email address as 32 hex characters."},
+	RequireAuthHasOwners:          ErrCodes{2023, "RequireAuth can only be enabled on an address which has no trust lines or offers."},
+	GlobalFreezePermanent:         ErrCodes{2024, "Global freeze can not be cleared on an address which has enabled NoFreeze."},
+	NoSettingsGiven:               ErrCodes{2025, "No account settings to change were given."},
+	InvalidOfferFlags:             ErrCodes{2026, "An offer can not be both immediateOrCancel and fillOrKill."},
+	SameAsset:                     ErrCodes{2027, "The offer must exchange two different assets."},
+	OfferNotOpen:                  ErrCodes{2028, "The offer can not be cancelled as it has not been placed in the ledger or has already been cancelled."},
+	OfferUnfunded:                 ErrCodes{2029, "The address does not hold enough of the asset it is selling to place the offer."},
+	OfferKilled:                   ErrCodes{2030, "The fillOrKill offer could not be filled completely and was cancelled."},
+	NoPathFound:                   ErrCodes{2031, "No path was found to pay the destination in the asset with the send asset. Please check the trust lines and order books of the assets."},
+	PathPartial:                   ErrCodes{2032, "The payment could not deliver the amount within the maximum slippage."},
+	TxNotValidated:                ErrCodes{2033, "The transaction was submitted but was not validated before it expired."},
+	InvalidMemo:                   ErrCodes{2034, "The memos are invalid. Each memo must have a type, format or data and the memos must not exceed 1KB in total."},
+	DestinationTagRequired:        ErrCodes{2035, "The destination requires a destination tag on payments to it. Please specify a destinationTag."},
+	InvalidEscrowTimes:            ErrCodes{2036, "The escrow must have a finishAfter or a condition, finishAfter must be before cancelAfter and both must be in the future."},
+	InvalidCondition:              ErrCodes{2037, "The condition or fulfillment is not a valid hex encoded crypto-condition or does not match the escrow."},
+	EscrowNotOpen:                 ErrCodes{2038, "The escrow can not be finished or cancelled as it has not been created in the ledger or has already been finished or cancelled."},
+	EscrowNotXRP:                  ErrCodes{2039, "Escrows can only hold XRP."},
+	EscrowNotReady:                ErrCodes{2040, "The escrow can not be finished before its finishAfter time, or cancelled before its cancelAfter time."},
+	ChannelNotOpen:                ErrCodes{2041, "The payment channel is not open. It has not been created in the ledger or has already been closed."},
+	ChannelInsufficientFunds:      ErrCodes{2042, "The payment channel does not hold enough XRP to authorise the claim. Please fund the channel and try again."},
+	InvalidClaim:                  ErrCodes{2043, "The claim is invalid. The signature must be made by the public key of the channel and the amount must not exceed the channel amount."},
+	SignerCannotSignClaims:        ErrCodes{2044, "Claims against payment channels can only be signed by wallets held in the keystore or with a passphrase."},
+	InvalidChannelTimes:           ErrCodes{2045, "The cancelAfter and expiration of the payment channel must be in the future."},
+	InvalidSignerList:             ErrCodes{2046, "The signers or the quorum are invalid. Between 1 and 8 distinct signers other than the address must be given and their weights must be able to reach the quorum."},
+	UnknownSigner:                 ErrCodes{2047, "The address given is not a signer of the multisig wallet."},
+	InvalidMultiSignature:         ErrCodes{2048, "The signature is not a valid signature of the transaction by the signer."},
+	AlreadySigned:                 ErrCodes{2049, "The signer has already signed the transaction."},
+	QuorumNotReached:              ErrCodes{2050, "The weights of the signers who have signed do not yet reach the quorum."},
+	ProposalNotPending:            ErrCodes{2051, "The transaction is no longer awaiting signatures."},
+	SignerCannotMultiSign:         ErrCodes{2052, "Only wallets held in the keystore or given with a passphrase can multisign on the server. Otherwise give the signature and public key made offline."},
+	CheckNotOpen:                  ErrCodes{2053, "The check is not in the ledger. It has not been created yet or has already been cashed, cancelled or has expired."},
+	InvalidCheckExpiration:        ErrCodes{2054, "The expiration of the check must be in the future."},
+	InvalidInvoiceId:              ErrCodes{2055, "The invoiceId must be 64 hex characters."},
+	InvalidMarker:                 ErrCodes{2056, "The marker is invalid. Give the marker exactly as it was returned with the previous page."},
+	FeeTooHigh:                    ErrCodes{2057, "The fee required by the Ripple network is more than the maxFee given. Retry later or give a higher maxFee."},
 }
@@ -5,17 +5,57 @@ type Validations map[string]string
 // cf http://spacetelescope.github.io/understanding-json-schema/
 var ParameterValidations = map[string]Validations{
 	"counterparty": {
-		"asset":           `{"properties":{"blockchainId":{"type":"string"},"passphrase":{"type":"string"},"distributionAddress":{"type":"string","maxLength":34,"minLength":34},"distributionPassphrase":{"type":"string"},"description":{"type":"string"},"asset":{"type":"string","minLength":4},"quantity":{"type":"integer"},"divisible":{"type":"boolean"},"nonce":{"type":"integer"}},"required":["sourceAddress","passphrase","asset","quantity","divisible"]}`,
-		"dividend":        `{"properties":{"blockchainId":{"type":"string"},"sourceAddress":{"type":"string","maxLength":34,"minLength":34},"asset":{"type":"string","minLength":4},"dividendAsset":{"type":"string"},"quantityPerUnit":{"type":"integer"},"nonce":{"type":"integer"}},"required":["sourceAddress","asset","dividendAsset","quantityPerUnit"]}`,
-		"walletCreate":    `{"properties":{"blockchainId":{"type":"string"},"numberOfAddresses":{"type":"number","minimum":1,"maximum":100,"exclusiveMaximum":false},"nonce":{"type":"integer"}}}`,
-		"walletPayment":   `{"properties":{"blockchainId":{"type":"string"},"sourceAddress":{"type":"string","maxLength":34,"minLength":34},"destinationAddress":{"type":"string","maxLength":34,"minLength":34},"asset":{"type":"string","minLength":4},"quantity":{"type":"integer"},"nonce":{"type":"integer"}},"required":["sourceAddress","asset","quantity","destinationAddress"]}`,
-		"simplePayment":   `{"properties":{"sourceAddress":{"type":"string", "maxLength":34, "minLength":34},"destinationAddress":{"type":"string", "maxLength":34, "minLength":34},"asset":{"type":"string","minLength":4},"amount":{"type":"integer"},"txFee":{"type":"integer"}},"required":["sourceAddress","destinationAddress","asset","amount"]}`,
-		"activateaddress": `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string","maxLength":34,"minLength":34},"amount":{"type":"integer"},"nonce":{"type":"integer"}},"required":["address","amount"]}`,
+		"asset":                `{"properties":{"blockchainId":{"type":"string"},"passphrase":{"type":"string"},"walletId":{"type":"string"},"distributionAddress":{"type":"string","maxLength":42,"minLength":34},"distributionPassphrase":{"type":"string"},"description":{"type":"string"},"asset":{"type":"string","minLength":4},"quantity":{"type":"integer"},"divisible":{"type":"boolean"},"nonce":{"type":"integer"}},"required":["sourceAddress","asset","quantity","divisible"]}`,
+		"dividend":             `{"properties":{"blockchainId":{"type":"string"},"sourceAddress":{"type":"string","maxLength":42,"minLength":34},"passphrase":{"type":"string"},"walletId":{"type":"string"},"asset":{"type":"string","minLength":4},"dividendAsset":{"type":"string"},"quantityPerUnit":{"type":"integer"},"nonce":{"type":"integer"}},"required":["sourceAddress","asset","dividendAsset","quantityPerUnit"]}`,
+		"walletCreate":         `{"properties":{"blockchainId":{"type":"string"},"numberOfAddresses":{"type":"number","minimum":1,"maximum":1000,"exclusiveMaximum":false},"addressType":{"type":"string","enum":["p2pkh","p2sh-p2wpkh","p2wpkh"]},"custodial":{"type":"boolean"},"walletPassword":{"type":"string","minLength":8},"unlockPolicy":{"type":"string","enum":["always","timed","locked"]},"unlockSeconds":{"type":"integer","minimum":1,"maximum":86400},"nonce":{"type":"integer"}},"anyOf":[{"properties":{"custodial":{"enum":[false]}}},{"required":["walletPassword"]}]}`,
+		"walletPayment":        `{"properties":{"blockchainId":{"type":"string"},"passphrase":{"type":"string"},"walletId":{"type":"string"},"sourceAddress":{"type":"string","maxLength":42,"minLength":34},"destinationAddress":{"type":"string","maxLength":42,"minLength":34},"asset":{"type":"string","minLength":4},"quantity":{"type":"integer"},"nonce":{"type":"integer"}},"required":["sourceAddress","asset","quantity","destinationAddress"]}`,
+		"simplePayment":        `{"properties":{"sourceAddress":{"type":"string", "maxLength":42, "minLength":34},"destinationAddress":{"type":"string", "maxLength":42, "minLength":34},"asset":{"type":"string","minLength":4},"amount":{"type":"integer"},"txFee":{"type":"integer"}},"required":["sourceAddress","destinationAddress","asset","amount"]}`,
+		"activateaddress":      `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string","maxLength":42,"minLength":34},"amount":{"type":"integer"},"nonce":{"type":"integer"}},"required":["address","amount"]}`,
+		"walletDiscover":       `{"properties":{"blockchainId":{"type":"string"},"passphrase":{"type":"string"},"addressType":{"type":"string","enum":["p2pkh","p2sh-p2wpkh","p2wpkh"]},"gapLimit":{"type":"integer","minimum":1,"maximum":1000},"nonce":{"type":"integer"}},"required":["passphrase"]}`,
+		"multisigWalletCreate": `{"properties":{"blockchainId":{"type":"string"},"requiredSignatures":{"type":"integer","minimum":1,"maximum":15},"cosigners":{"type":"array","minItems":1,"maxItems":15,"items":{"type":"object","properties":{"publicKey":{"type":"string","minLength":66,"maxLength":66},"passphrase":{"type":"string"},"xpub":{"type":"string"},"index":{"type":"integer","minimum":0}}}},"nonce":{"type":"integer"}},"required":["requiredSignatures","cosigners"]}`,
+		"multisigProposal":     `{"properties":{"blockchainId":{"type":"string"},"walletId":{"type":"string"},"proposalType":{"type":"string","enum":["send","issuance"]},"destinationAddress":{"type":"string","maxLength":42,"minLength":34},"asset":{"type":"string","minLength":4},"description":{"type":"string"},"quantity":{"type":"integer"},"divisible":{"type":"boolean"},"nonce":{"type":"integer"}},"required":["walletId","proposalType","asset","quantity"]}`,
+		"multisigSign":         `{"properties":{"blockchainId":{"type":"string"},"publicKey":{"type":"string","minLength":66,"maxLength":66},"passphrase":{"type":"string"},"signatures":{"type":"array","items":{"type":"string"}},"nonce":{"type":"integer"}},"required":["publicKey"],"oneOf":[{"required":["passphrase"]},{"required":["signatures"]}]}`,
+		"keystoreUnlock":       `{"properties":{"blockchainId":{"type":"string"},"walletPassword":{"type":"string"},"unlockSeconds":{"type":"integer","minimum":1,"maximum":86400},"nonce":{"type":"integer"}},"required":["walletPassword"]}`,
+		"keystorePolicy":       `{"properties":{"blockchainId":{"type":"string"},"walletPassword":{"type":"string"},"unlockPolicy":{"type":"string","enum":["always","timed","locked"]},"unlockSeconds":{"type":"integer","minimum":1,"maximum":86400},"nonce":{"type":"integer"}},"required":["walletPassword","unlockPolicy"]}`,
+		"keystoreExport":       `{"properties":{"blockchainId":{"type":"string"},"walletPassword":{"type":"string"},"nonce":{"type":"integer"}},"required":["walletPassword"]}`,
+		"watchWalletCreate":    `{"properties":{"blockchainId":{"type":"string"},"xpub":{"type":"string","minLength":111,"maxLength":112},"numberOfAddresses":{"type":"integer","minimum":1,"maximum":1000},"nonce":{"type":"integer"}},"required":["xpub"]}`,
+		"watchWalletCompose":   `{"properties":{"blockchainId":{"type":"string"},"sourceAddress":{"type":"string","maxLength":42,"minLength":34},"destinationAddress":{"type":"string","maxLength":42,"minLength":34},"asset":{"type":"string","minLength":3},"quantity":{"type":"integer","minimum":1},"nonce":{"type":"integer"}},"required":["destinationAddress","asset","quantity"]}`,
+		"walletNextAddress":    `{"properties":{"blockchainId":{"type":"string"},"walletId":{"type":"string"},"xpub":{"type":"string","minLength":111,"maxLength":112},"label":{"type":"string","maxLength":200},"nonce":{"type":"integer"}},"oneOf":[{"required":["walletId"]},{"required":["xpub"]}]}`,
+		"signMessage":          `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string","maxLength":42,"minLength":34},"message":{"type":"string","minLength":1},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}},"required":["address","message"],"oneOf":[{"required":["passphrase"]},{"required":["walletId"]}]}`,
+		"verifyMessage":        `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string","maxLength":42,"minLength":34},"message":{"type":"string","minLength":1},"signature":{"type":"string"},"nonce":{"type":"integer"}},"required":["address","message","signature"]}`,
+		"topUpPolicyCreate":    `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string","maxLength":42,"minLength":34},"minimumTransactions":{"type":"integer","minimum":1},"topUpAmount":{"type":"integer","minimum":1},"nonce":{"type":"integer"}},"required":["address","minimumTransactions","topUpAmount"]}`,
+		"topUpCapSet":          `{"properties":{"blockchainId":{"type":"string"},"dailyCap":{"type":"integer","minimum":0},"nonce":{"type":"integer"}},"required":["dailyCap"]}`,
 	},
 	"ripple": {
-		"asset":           `{"properties":{"blockchainId":{"type":"string"},"passphrase":{"type":"string"},"distributionAddress":{"type":"string"},"distributionPassphrase":{"type":"string"},"description":{"type":"string"},"asset":{"type":"string","minLength":4},"quantity":{"type":"integer"},"divisible":{"type":"boolean"},"nonce":{"type":"integer"}},"required":["sourceAddress","passphrase","asset","quantity","divisible"]}`,
-		"walletCreate":    `{"properties":{"blockchainId":{"type":"string"},"nonce":{"type":"integer"}}}`,
-		"walletPayment":   `{"properties":{"blockchainId":{"type":"string"},"sourceAddress":{"type":"string"},"destinationAddress":{"type":"string"},"asset":{"type":"string","minLength":3},"quantity":{"type":"integer"},"nonce":{"type":"integer"}},"required":["sourceAddress","asset","quantity","destinationAddress"]}`,
-		"activateaddress": `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"passphrase":{"type":"string"},"amount":{"type":"integer"},"assets":{"type":"array", "items": [{"type":"object","properties":{"currency":{"type":"string"},"issuer":{"type":"string"}}}]},"nonce":{"type":"integer"}},"required":["address","amount"]}`,
+		"asset":                `{"properties":{"blockchainId":{"type":"string"},"passphrase":{"type":"string"},"walletId":{"type":"string"},"distributionAddress":{"type":"string"},"distributionPassphrase":{"type":"string"},"description":{"type":"string"},"asset":{"type":"string","minLength":4},"quantity":{"type":"integer"},"divisible":{"type":"boolean"},"nonce":{"type":"integer"}},"required":["sourceAddress","asset","quantity","divisible"]}`,
+		"walletCreate":         `{"properties":{"blockchainId":{"type":"string"},"custodial":{"type":"boolean"},"walletPassword":{"type":"string","minLength":8},"unlockPolicy":{"type":"string","enum":["always","timed","locked"]},"unlockSeconds":{"type":"integer","minimum":1,"maximum":86400},"nonce":{"type":"integer"}},"anyOf":[{"properties":{"custodial":{"enum":[false]}}},{"required":["walletPassword"]}]}`,
+		"walletPayment":        `{"properties":{"blockchainId":{"type":"string"},"passphrase":{"type":"string"},"walletId":{"type":"string"},"sourceAddress":{"type":"string"},"destinationAddress":{"type":"string"},"asset":{"type":"string","minLength":3},"issuer":{"type":"string"},"quantity":{"type":"integer"},"paymentTag":{"type":"string"},"sendAsset":{"type":"string","minLength":3},"sendIssuer":{"type":"string"},"maxSlippage":{"type":"number","minimum":0,"maximum":100},"deliverMin":{"type":"integer","minimum":1},"destinationTag":{"type":"integer","minimum":0,"maximum":4294967295},"sourceTag":{"type":"integer","minimum":0,"maximum":4294967295},"memos":{"type":"array","items":{"type":"object","properties":{"type":{"type":"string"},"format":{"type":"string"},"data":{"type":"string"}}}},"nonce":{"type":"integer"}},"required":["sourceAddress","asset","quantity","destinationAddress"]}`,
+		"activateaddress":      `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"passphrase":{"type":"string"},"amount":{"type":"integer"},"assets":{"type":"array", "items": [{"type":"object","properties":{"currency":{"type":"string"},"issuer":{"type":"string"}}}]},"nonce":{"type":"integer"}},"required":["address","amount"]}`,
+		"keystoreUnlock":       `{"properties":{"blockchainId":{"type":"string"},"walletPassword":{"type":"string"},"unlockSeconds":{"type":"integer","minimum":1,"maximum":86400},"nonce":{"type":"integer"}},"required":["walletPassword"]}`,
+		"keystorePolicy":       `{"properties":{"blockchainId":{"type":"string"},"walletPassword":{"type":"string"},"unlockPolicy":{"type":"string","enum":["always","timed","locked"]},"unlockSeconds":{"type":"integer","minimum":1,"maximum":86400},"nonce":{"type":"integer"}},"required":["walletPassword","unlockPolicy"]}`,
+		"keystoreExport":       `{"properties":{"blockchainId":{"type":"string"},"walletPassword":{"type":"string"},"nonce":{"type":"integer"}},"required":["walletPassword"]}`,
+		"watchWalletCreate":    `{"properties":{"blockchainId":{"type":"string"},"addresses":{"type":"array","minItems":1,"maxItems":1000,"items":{"type":"string"}},"nonce":{"type":"integer"}},"required":["addresses"]}`,
+		"watchWalletCompose":   `{"properties":{"blockchainId":{"type":"string"},"sourceAddress":{"type":"string"},"destinationAddress":{"type":"string"},"asset":{"type":"string","minLength":3},"issuer":{"type":"string"},"quantity":{"type":"integer","minimum":1},"nonce":{"type":"integer"}},"required":["destinationAddress","asset","quantity"]}`,
+		"walletNextAddress":    `{"properties":{"blockchainId":{"type":"string"},"walletId":{"type":"string"},"publicGenerator":{"type":"string"},"label":{"type":"string","maxLength":200},"nonce":{"type":"integer"}},"oneOf":[{"required":["walletId"]},{"required":["publicGenerator"]}]}`,
+		"signMessage":          `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"message":{"type":"string","minLength":1},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}},"required":["address","message"],"oneOf":[{"required":["passphrase"]},{"required":["walletId"]}]}`,
+		"verifyMessage":        `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"message":{"type":"string","minLength":1},"signature":{"type":"string"},"publicKey":{"type":"string","minLength":66,"maxLength":66},"nonce":{"type":"integer"}},"required":["address","message","signature","publicKey"]}`,
+		"topUpPolicyCreate":    `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"minimumTransactions":{"type":"integer","minimum":1},"topUpAmount":{"type":"integer","minimum":1},"nonce":{"type":"integer"}},"required":["address","minimumTransactions","topUpAmount"]}`,
+		"topUpCapSet":          `{"properties":{"blockchainId":{"type":"string"},"dailyCap":{"type":"integer","minimum":0},"nonce":{"type":"integer"}},"required":["dailyCap"]}`,
+		"trustLineSet":         `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"asset":{"type":"string","minLength":3},"issuer":{"type":"string"},"limit":{"type":"integer","minimum":0},"noRipple":{"type":"boolean"},"freeze":{"type":"boolean"},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}},"required":["address","asset","issuer","limit"]}`,
+		"trustLineRemove":      `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"asset":{"type":"string","minLength":3},"issuer":{"type":"string"},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}},"required":["address","asset","issuer"]}`,
+		"accountSettingsSet":   `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"requireDestTag":{"type":"boolean"},"requireAuth":{"type":"boolean"},"disallowXRP":{"type":"boolean"},"globalFreeze":{"type":"boolean"},"transferRate":{"type":"integer","minimum":0,"maximum":2000000000},"domain":{"type":"string","minLength":1,"maxLength":256},"emailHash":{"type":"string","minLength":32,"maxLength":32},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}},"required":["address"]}`,
+		"offerCreate":          `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"takerGets":{"type":"object","properties":{"asset":{"type":"string","minLength":3},"issuer":{"type":"string"},"quantity":{"type":"integer","minimum":1}},"required":["asset","quantity"]},"takerPays":{"type":"object","properties":{"asset":{"type":"string","minLength":3},"issuer":{"type":"string"},"quantity":{"type":"integer","minimum":1}},"required":["asset","quantity"]},"passive":{"type":"boolean"},"sell":{"type":"boolean"},"immediateOrCancel":{"type":"boolean"},"fillOrKill":{"type":"boolean"},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}},"required":["address","takerGets","takerPays"]}`,
+		"escrowCreate":         `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"destination":{"type":"string"},"destinationTag":{"type":"integer","minimum":0,"maximum":4294967295},"asset":{"type":"string"},"quantity":{"type":"integer","minimum":1},"finishAfter":{"type":"integer","minimum":1},"cancelAfter":{"type":"integer","minimum":1},"condition":{"type":"string"},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}},"required":["address","destination","quantity"]}`,
+		"escrowFinish":         `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"fulfillment":{"type":"string"},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}}}`,
+		"channelCreate":        `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"destination":{"type":"string"},"destinationTag":{"type":"integer","minimum":0,"maximum":4294967295},"quantity":{"type":"integer","minimum":1},"settleDelay":{"type":"integer","minimum":0,"maximum":4294967295},"cancelAfter":{"type":"integer","minimum":1},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}},"required":["address","destination","quantity","settleDelay"]}`,
+		"channelFund":          `{"properties":{"blockchainId":{"type":"string"},"quantity":{"type":"integer","minimum":1},"expiration":{"type":"integer","minimum":1},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}},"required":["quantity"]}`,
+		"channelAuthorise":     `{"properties":{"blockchainId":{"type":"string"},"quantity":{"type":"integer","minimum":1},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}},"required":["quantity"]}`,
+		"channelVerify":        `{"properties":{"blockchainId":{"type":"string"},"channelId":{"type":"string"},"quantity":{"type":"integer","minimum":0},"signature":{"type":"string"},"nonce":{"type":"integer"}},"required":["channelId","quantity","signature"]}`,
+		"channelClaim":         `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"quantity":{"type":"integer","minimum":0},"signature":{"type":"string"},"close":{"type":"boolean"},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}}}`,
+		"multisigWalletCreate": `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"requiredSignatures":{"type":"integer","minimum":1,"maximum":4294967295},"cosigners":{"type":"array","minItems":1,"maxItems":8,"items":{"type":"object","properties":{"address":{"type":"string"},"weight":{"type":"integer","minimum":1,"maximum":65535}},"required":["address"]}},"disableMaster":{"type":"boolean"},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}},"required":["address","requiredSignatures","cosigners"]}`,
+		"multisigProposal":     `{"properties":{"blockchainId":{"type":"string"},"walletId":{"type":"string"},"proposalType":{"type":"string","enum":["send","disableMaster"]},"destinationAddress":{"type":"string"},"asset":{"type":"string","minLength":3},"issuer":{"type":"string"},"quantity":{"type":"integer","minimum":1},"destinationTag":{"type":"integer","minimum":0,"maximum":4294967295},"sourceTag":{"type":"integer","minimum":0,"maximum":4294967295},"memos":{"type":"array","items":{"type":"object","properties":{"type":{"type":"string"},"format":{"type":"string"},"data":{"type":"string"}}}},"nonce":{"type":"integer"}},"required":["walletId","proposalType"]}`,
+		"multisigSign":         `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"passphrase":{"type":"string"},"walletId":{"type":"string"},"publicKey":{"type":"string","minLength":66,"maxLength":66},"signature":{"type":"string"},"nonce":{"type":"integer"}},"required":["address"],"dependencies":{"signature":["publicKey"],"publicKey":["signature"]}}`,
+		"checkCreate":          `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"destination":{"type":"string"},"destinationTag":{"type":"integer","minimum":0,"maximum":4294967295},"asset":{"type":"string","minLength":3},"issuer":{"type":"string"},"quantity":{"type":"integer","minimum":1},"expiration":{"type":"integer","minimum":1},"invoiceId":{"type":"string"},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}},"required":["address","destination","asset","quantity"]}`,
+		"checkCash":            `{"properties":{"blockchainId":{"type":"string"},"address":{"type":"string"},"quantity":{"type":"integer","minimum":1},"deliverMin":{"type":"integer","minimum":1},"passphrase":{"type":"string"},"walletId":{"type":"string"},"nonce":{"type":"integer"}},"oneOf":[{"required":["quantity"]},{"required":["deliverMin"]}]}`,
 	},
 }
@@ -316,6 +316,16 @@ func InitWithConfigPath(configFilePath string) {
 	counterpartyTransactionEncoding = m["counterpartytransactionencoding"].(string) // The encoding that should be used for Counterparty transactions "auto" will let Counterparty select, valid values "multisig", "opreturn"
 	counterpartyDBLocation = m["counterpartydblocation"].(string)                   // Direct location of counterpartydb if we can't reach the API
 
+	// Optional HD wallet derivation depth and discovery gap limit
+	var maxAddressIndex, gapLimit uint32
+	if m["hdmaxaddressindex"] != nil {
+		maxAddressIndex = uint32(m["hdmaxaddressindex"].(float64))
+	}
+	if m["hdgaplimit"] != nil {
+		gapLimit = uint32(m["hdgaplimit"].(float64))
+	}
+	counterpartycrypto.SetDerivationLimits(maxAddressIndex, gapLimit)
+
 	isInit = true
 }
 
@@ -983,6 +993,10 @@ func CreateSend(c context.Context, sourceAddress string, destinationAddress stri
 // 2) Derives the parent key and the child key for the address found in step 1)
 // 3) Signs all the TX inputs
 //
+// Inputs spending pubkeyhash outputs are signed with the legacy signature hash. Inputs spending P2SH-P2WPKH (BIP49)
+// and P2WPKH (BIP84) outputs are signed with the BIP143 signature hash and the transaction is returned in the
+// BIP144 witness serialisation.
+//
 // Assumptions
 // 1) This is a Counterparty transaction so all inputs need to be signed with the same pubkeyhash
 func SignRawTransaction(c context.Context, passphrase string, rawTxHexString string) (string, error) {
@@ -1005,6 +1019,10 @@ func SignRawTransaction(c context.Context, passphrase string, rawTxHexString str
 
 	msgTx := tx.MsgTx()
 	redeemTx := wire.NewMsgTx() // Create a new transaction and copy the details from the tx that was serialised. For some reason BTCD can't sign in place transactions
+	redeemTx.Version = msgTx.Version
+	redeemTx.LockTime = msgTx.LockTime
+	witnesses := make([][][]byte, len(msgTx.TxIn))
+	amounts := make([]int64, len(msgTx.TxIn))
 	//	log.Printf("MsgTx: %+v", msgTx)
 	//	log.Printf("Number of txes in: %d\n", len(msgTx.TxIn))
 	for i := 0; i <= len(msgTx.TxIn)-1; i++ {
@@ -1021,19 +1039,28 @@ func SignRawTransaction(c context.Context, passphrase string, rawTxHexString str
 		//		}
 		//		log.Printf("TxIn[%d] Script Disassembly: %s", i, disasm)
 
-		// Extract and print details from the script.
-		// next line is for debugging only
-		//		scriptClass, addresses, reqSigs, err := txscript.ExtractPkScriptAddrs(script, &chaincfg.MainNetParams)
-		scriptClass, _, _, err := txscript.ExtractPkScriptAddrs(script, &chaincfg.MainNetParams)
-		if err != nil {
-			log.FluentfContext(consts.LOGERROR, c, "Error in ExtractPkScriptAddrs(): %s", err.Error())
-			return "", err
-		}
+		// Segwit outputs need the value of the output being spent to be signed
+		if isWitnessPubKeyHash(script) || isScriptHash(script) {
+			amounts[i], err = getPrevOutValue(msgTx.TxIn[i].PreviousOutPoint.Hash.String(), msgTx.TxIn[i].PreviousOutPoint.Index)
+			if err != nil {
+				log.FluentfContext(consts.LOGERROR, c, "Error in getPrevOutValue(): %s", err.Error())
+				return "", err
+			}
+		} else {
+			// Extract and print details from the script.
+			// next line is for debugging only
+			//		scriptClass, addresses, reqSigs, err := txscript.ExtractPkScriptAddrs(script, &chaincfg.MainNetParams)
+			scriptClass, _, _, err := txscript.ExtractPkScriptAddrs(script, &chaincfg.MainNetParams)
+			if err != nil {
+				log.FluentfContext(consts.LOGERROR, c, "Error in ExtractPkScriptAddrs(): %s", err.Error())
+				return "", err
+			}
 
-		// This function only supports pubkeyhash signing at this time (ie not multisig or P2SH)
-		//		log.Printf("TxIn[%d] Script Class: %s\n", i, scriptClass)
-		if scriptClass.String() != "pubkeyhash" {
-			return "", errors.New("Counterparty_SignRawTransaction() currently only supports pubkeyhash script signing. However, the script type in the TX to sign was: " + scriptClass.String())
+			// This function only supports pubkeyhash, P2SH-P2WPKH and P2WPKH signing at this time (ie not multisig)
+			//		log.Printf("TxIn[%d] Script Class: %s\n", i, scriptClass)
+			if scriptClass.String() != "pubkeyhash" {
+				return "", errors.New("Counterparty_SignRawTransaction() currently only supports pubkeyhash, P2SH-P2WPKH and P2WPKH script signing. However, the script type in the TX to sign was: " + scriptClass.String())
+			}
 		}
 
 		//		log.Printf("TxIn[%d] Addresses: %s\n", i, addresses)
@@ -1042,6 +1069,7 @@ func SignRawTransaction(c context.Context, passphrase string, rawTxHexString str
 		// Build txIn for new redeeming transaction
 		prevOut := wire.NewOutPoint(&msgTx.TxIn[i].PreviousOutPoint.Hash, msgTx.TxIn[i].PreviousOutPoint.Index)
 		txIn := wire.NewTxIn(prevOut, nil)
+		txIn.Sequence = msgTx.TxIn[i].Sequence
 		redeemTx.AddTxIn(txIn)
 	}
 
@@ -1055,32 +1083,55 @@ func SignRawTransaction(c context.Context, passphrase string, rawTxHexString str
 	lookupKey := func(a btcutil.Address) (*btcec.PrivateKey, bool, error) {
 		address := a.String()
 
-		//		log.Printf("Looking up the private key for: %s\n", address)
-		privateKeyString, err := counterpartycrypto.GetPrivateKey(passphrase, address)
+		privKey, err := lookupPrivateKey(c, passphrase, address)
 		if err != nil {
-			log.FluentfContext(consts.LOGERROR, c, "Error in counterpartycrypto.GetPrivateKey(): %s", err.Error())
 			return nil, false, nil
 		}
-		//		log.Printf("Private key retrieved!\n")
-
-		privateKeyBytes, err := hex.DecodeString(privateKeyString)
-		if err != nil {
-			log.FluentfContext(consts.LOGERROR, c, "Error in DecodeString(): %s", err.Error())
-			return nil, false, nil
-		}
-
-		privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), privateKeyBytes)
 
 		return privKey, true, nil
 	}
 
 	// Range over TxIns and sign
 	for i, _ := range redeemTx.TxIn {
+		script := msgTx.TxIn[i].SignatureScript
+
+		if isWitnessPubKeyHash(script) || isScriptHash(script) {
+			address, err := segWitScriptAddress(script)
+			if err != nil {
+				return "", err
+			}
+
+			// The key is only found if the address was derived from the BIP49 or BIP84 path, which also
+			// proves the P2SH redeem script is the P2WPKH script for our key
+			privKey, err := lookupPrivateKey(c, passphrase, address)
+			if err != nil {
+				return "", err
+			}
+
+			witness, err := signWitnessInput(redeemTx, i, amounts[i], privKey)
+			if err != nil {
+				return "", err
+			}
+			witnesses[i] = witness
+
+			// P2SH-P2WPKH needs the redeem script pushed in the sigscript. Native P2WPKH has an empty sigscript
+			if isScriptHash(script) {
+				redeemScript := counterpartycrypto.WitnessPubKeyHashScript(btcutil.Hash160(privKey.PubKey().SerializeCompressed()))
+				sigScript, err := txscript.NewScriptBuilder().AddData(redeemScript).Script()
+				if err != nil {
+					return "", err
+				}
+				redeemTx.TxIn[i].SignatureScript = sigScript
+			}
+
+			continue
+		}
+
 		// Get the sigscript
 		// Notice that the script database parameter is nil here since it isn't
 		// used.  It must be specified when pay-to-script-hash transactions are
 		// being signed.
-		sigScript, err := txscript.SignTxOutput(&chaincfg.MainNetParams, redeemTx, i, msgTx.TxIn[i].SignatureScript, txscript.SigHashAll, txscript.KeyClosure(lookupKey), nil, nil)
+		sigScript, err := txscript.SignTxOutput(&chaincfg.MainNetParams, redeemTx, i, script, txscript.SigHashAll, txscript.KeyClosure(lookupKey), nil, nil)
 
 		if err != nil {
 			return "", err
@@ -1093,13 +1144,23 @@ func SignRawTransaction(c context.Context, passphrase string, rawTxHexString str
 
 	// Prove that the transaction has been validly signed by executing the
 	// script pair.
+	// The script engine doesn't understand witness programs so for segwit inputs it is only able to check the
+	// sigscript is push only and satisfies the P2SH redeem script hash. The witness signature is checked separately.
 	//	log.Println("Checking signature(s)")
 	flags := txscript.ScriptBip16 | txscript.ScriptVerifyDERSignatures | txscript.ScriptStrictMultiSig | txscript.ScriptDiscourageUpgradableNops | txscript.ScriptVerifyLowS | txscript.ScriptVerifyCleanStack | txscript.ScriptVerifyMinimalData | txscript.ScriptVerifySigPushOnly | txscript.ScriptVerifyStrictEncoding
+	witnessFlags := flags &^ txscript.ScriptVerifyCleanStack
 	var buildError string
 	for i, _ := range redeemTx.TxIn {
-		vm, err := txscript.NewEngine(msgTx.TxIn[i].SignatureScript, redeemTx, i, flags)
+		script := msgTx.TxIn[i].SignatureScript
+		inputFlags := flags
+		if witnesses[i] != nil {
+			inputFlags = witnessFlags
+		}
+
+		vm, err := txscript.NewEngine(script, redeemTx, i, inputFlags)
 		if err != nil {
 			buildError += "NewEngine() error: " + err.Error() + ","
+			continue
 		}
 
 		if err := vm.Execute(); err != nil {
@@ -1108,6 +1169,17 @@ func SignRawTransaction(c context.Context, passphrase string, rawTxHexString str
 			// Signature verified
 			//			log.Printf("TxIn[%d] ok!\n", i)
 		}
+
+		if witnesses[i] != nil {
+			program := script[2:]
+			if isScriptHash(script) {
+				program = btcutil.Hash160(witnesses[i][1])
+			}
+
+			if err := verifyWitnessInput(redeemTx, i, program, amounts[i], witnesses[i]); err != nil {
+				buildError += "TxIn[" + strconv.Itoa(i) + "] witness: " + err.Error() + ", "
+			}
+		}
 	}
 	if len(buildError) > 0 {
 		return "", errors.New(buildError)
@@ -1115,20 +1187,39 @@ func SignRawTransaction(c context.Context, passphrase string, rawTxHexString str
 	//	log.Println("Transaction successfully signed")
 
 	// Encode the struct into BTC bytes wire format
-	var byteBuffer bytes.Buffer
-	encodeError := redeemTx.BtcEncode(&byteBuffer, wire.ProtocolVersion)
-	if encodeError != nil {
+	payloadBytes, err := serializeWitnessTx(redeemTx, witnesses)
+	if err != nil {
 		return "", err
 	}
 
 	// Encode bytes to hex string
-	payloadBytes := byteBuffer.Bytes()
 	payloadHexString := hex.EncodeToString(payloadBytes)
 	//	log.Printf("Signed and encoded transaction: %s\n", payloadHexString)
 
 	return payloadHexString, nil
 }
 
+// Derives the private key for the address from the passphrase
+func lookupPrivateKey(c context.Context, passphrase string, address string) (*btcec.PrivateKey, error) {
+	//		log.Printf("Looking up the private key for: %s\n", address)
+	privateKeyString, err := counterpartycrypto.GetPrivateKey(passphrase, address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in counterpartycrypto.GetPrivateKey(): %s", err.Error())
+		return nil, err
+	}
+	//		log.Printf("Private key retrieved!\n")
+
+	privateKeyBytes, err := hex.DecodeString(privateKeyString)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in DecodeString(): %s", err.Error())
+		return nil, err
+	}
+
+	privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), privateKeyBytes)
+
+	return privKey, nil
+}
+
 // Reproduces counterwallet function to generate a random asset name
 // Original JS:
 //self.generateRandomId = function() {
@@ -0,0 +1,270 @@
+package counterpartyapi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strconv"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/counterpartycrypto"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/btcec"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/txscript"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/wire"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcutil"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Functions to support the partially signed multisig workflow:
+// 1) A transaction is composed by Counterparty with the P2SH multisig address as the source
+// 2) Each cosigner signs every input of the unsigned transaction with their key, either on the server with their passphrase or offline
+// 3) Once the required number of signatures is collected, the signatures are assembled into the sigscripts and the transaction is broadcast
+
+// Parses the unsigned transaction returned from Counterparty and checks every input spends the P2SH output of the redeem script.
+// The sigscripts are emptied so the transaction is ready to sign.
+func parseMultisigTx(c context.Context, rawTxHexString string, redeemScript []byte) (*wire.MsgTx, error) {
+	txBytes, err := hex.DecodeString(rawTxHexString)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in DecodeString(): %s", err.Error())
+		return nil, err
+	}
+
+	tx, err := btcutil.NewTxFromBytes(txBytes)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in NewTxFromBytes(): %s", err.Error())
+		return nil, err
+	}
+
+	scriptHash := btcutil.Hash160(redeemScript)
+	msgTx := tx.MsgTx().Copy()
+	for i, txIn := range msgTx.TxIn {
+		// Counterparty places the output script being spent in the sigscript of the unsigned transaction
+		script := txIn.SignatureScript
+		if len(script) > 0 && !(isScriptHash(script) && bytes.Equal(script[2:22], scriptHash)) {
+			return nil, errors.New("TxIn[" + strconv.Itoa(i) + "] does not spend the multisig address")
+		}
+
+		msgTx.TxIn[i].SignatureScript = nil
+	}
+
+	return msgTx, nil
+}
+
+// Calculates the legacy signature hash of the input for the given subscript
+func calcSignatureHash(tx *wire.MsgTx, idx int, subScript []byte, hashType txscript.SigHashType) ([]byte, error) {
+	txCopy := tx.Copy()
+	for i := range txCopy.TxIn {
+		if i == idx {
+			txCopy.TxIn[i].SignatureScript = subScript
+		} else {
+			txCopy.TxIn[i].SignatureScript = nil
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := txCopy.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	binary.Write(&buf, binary.LittleEndian, uint32(hashType))
+
+	return wire.DoubleSha256(buf.Bytes()), nil
+}
+
+// Returns the hex encoded signature hash of each input. These are given to cosigners who sign offline.
+func MultisigSignatureHashes(c context.Context, rawTxHexString string, redeemScriptHex string) ([]string, error) {
+	redeemScript, err := hex.DecodeString(redeemScriptHex)
+	if err != nil {
+		return nil, err
+	}
+
+	msgTx, err := parseMultisigTx(c, rawTxHexString, redeemScript)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for i := range msgTx.TxIn {
+		hash, err := calcSignatureHash(msgTx, i, redeemScript, txscript.SigHashAll)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, hex.EncodeToString(hash))
+	}
+
+	return result, nil
+}
+
+// Signs every input of the multisig transaction with the cosigner's key derived from the passphrase.
+// Returns the hex encoded DER signatures (with the hash type appended) in input order.
+func SignMultisigTransaction(c context.Context, passphrase string, publicKey string, rawTxHexString string, redeemScriptHex string) ([]string, error) {
+	privateKeyString, err := counterpartycrypto.GetPrivateKeyForPublicKey(passphrase, publicKey)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in counterpartycrypto.GetPrivateKeyForPublicKey(): %s", err.Error())
+		return nil, err
+	}
+
+	privateKeyBytes, err := hex.DecodeString(privateKeyString)
+	if err != nil {
+		return nil, err
+	}
+	privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), privateKeyBytes)
+
+	hashes, err := MultisigSignatureHashes(c, rawTxHexString, redeemScriptHex)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, h := range hashes {
+		hash, _ := hex.DecodeString(h)
+
+		signature, err := privKey.Sign(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, hex.EncodeToString(append(signature.Serialize(), byte(txscript.SigHashAll))))
+	}
+
+	return result, nil
+}
+
+// Verifies the cosigner has given a valid SIGHASH_ALL signature for every input of the multisig transaction
+func VerifyMultisigSignatures(c context.Context, publicKey string, signatures []string, rawTxHexString string, redeemScriptHex string) error {
+	pubKeyBytes, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return err
+	}
+
+	pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return err
+	}
+
+	hashes, err := MultisigSignatureHashes(c, rawTxHexString, redeemScriptHex)
+	if err != nil {
+		return err
+	}
+
+	if len(signatures) != len(hashes) {
+		return errors.New("Expected " + strconv.Itoa(len(hashes)) + " signatures, got " + strconv.Itoa(len(signatures)))
+	}
+
+	for i, h := range hashes {
+		hash, _ := hex.DecodeString(h)
+
+		sig, err := hex.DecodeString(signatures[i])
+		if err != nil {
+			return err
+		}
+
+		if len(sig) < 1 || txscript.SigHashType(sig[len(sig)-1]) != txscript.SigHashAll {
+			return errors.New("TxIn[" + strconv.Itoa(i) + "]: only SIGHASH_ALL signatures are supported")
+		}
+
+		signature, err := btcec.ParseDERSignature(sig[:len(sig)-1], btcec.S256())
+		if err != nil {
+			return errors.New("TxIn[" + strconv.Itoa(i) + "]: " + err.Error())
+		}
+
+		if !signature.Verify(hash, pubKey) {
+			return errors.New("TxIn[" + strconv.Itoa(i) + "]: signature verification failed")
+		}
+	}
+
+	return nil
+}
+
+// Assembles the collected signatures into the sigscripts of the multisig transaction and verifies the result.
+// signatures is keyed by the cosigner's public key. Only the required number of signatures are used and they are
+// placed in the order of the public keys in the redeem script as required by OP_CHECKMULTISIG.
+func FinaliseMultisigTransaction(c context.Context, rawTxHexString string, redeemScriptHex string, signatures map[string][]string) (string, error) {
+	redeemScript, err := hex.DecodeString(redeemScriptHex)
+	if err != nil {
+		return "", err
+	}
+
+	publicKeys, requiredSignatures, err := counterpartycrypto.MultisigPublicKeys(redeemScriptHex)
+	if err != nil {
+		return "", err
+	}
+
+	msgTx, err := parseMultisigTx(c, rawTxHexString, redeemScript)
+	if err != nil {
+		return "", err
+	}
+
+	var signers []string
+	for _, publicKey := range publicKeys {
+		if len(signers) == requiredSignatures {
+			break
+		}
+
+		if _, ok := signatures[publicKey]; ok {
+			signers = append(signers, publicKey)
+		}
+	}
+
+	if len(signers) < requiredSignatures {
+		return "", errors.New("Insufficient signatures. Required: " + strconv.Itoa(requiredSignatures) + ", given: " + strconv.Itoa(len(signers)))
+	}
+
+	for i := range msgTx.TxIn {
+		// OP_CHECKMULTISIG pops an extra item from the stack so a dummy OP_0 is required
+		builder := txscript.NewScriptBuilder().AddOp(txscript.OP_0)
+		for _, publicKey := range signers {
+			if len(signatures[publicKey]) != len(msgTx.TxIn) {
+				return "", errors.New("Signatures from " + publicKey + " do not cover every input")
+			}
+
+			sig, err := hex.DecodeString(signatures[publicKey][i])
+			if err != nil {
+				return "", err
+			}
+			builder.AddData(sig)
+		}
+		builder.AddData(redeemScript)
+
+		sigScript, err := builder.Script()
+		if err != nil {
+			return "", err
+		}
+
+		msgTx.TxIn[i].SignatureScript = sigScript
+	}
+
+	// Prove that the transaction has been validly signed by executing the script pair
+	p2shScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_HASH160).AddData(btcutil.Hash160(redeemScript)).AddOp(txscript.OP_EQUAL).Script()
+	if err != nil {
+		return "", err
+	}
+
+	flags := txscript.ScriptBip16 | txscript.ScriptVerifyDERSignatures | txscript.ScriptStrictMultiSig | txscript.ScriptDiscourageUpgradableNops | txscript.ScriptVerifyLowS | txscript.ScriptVerifyCleanStack | txscript.ScriptVerifyMinimalData | txscript.ScriptVerifySigPushOnly | txscript.ScriptVerifyStrictEncoding
+	var buildError string
+	for i := range msgTx.TxIn {
+		vm, err := txscript.NewEngine(p2shScript, msgTx, i, flags)
+		if err != nil {
+			buildError += "NewEngine() error: " + err.Error() + ","
+			continue
+		}
+
+		if err := vm.Execute(); err != nil {
+			buildError += "TxIn[" + strconv.Itoa(i) + "]: " + err.Error() + ", "
+		}
+	}
+	if len(buildError) > 0 {
+		return "", errors.New(buildError)
+	}
+
+	// Encode the struct into BTC bytes wire format
+	var byteBuffer bytes.Buffer
+	if err := msgTx.BtcEncode(&byteBuffer, wire.ProtocolVersion); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(byteBuffer.Bytes()), nil
+}
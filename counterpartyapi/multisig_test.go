@@ -0,0 +1,78 @@
+package counterpartyapi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/vennd/enu/counterpartycrypto"
+
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/txscript"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/wire"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcutil"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func TestMultisigWorkflow(t *testing.T) {
+	c := context.TODO()
+
+	var passphrases []string
+	var publicKeys []string
+	for i := 0; i < 3; i++ {
+		wallet, err := counterpartycrypto.CreateWallet(1)
+		if err != nil {
+			t.Fatalf("Unable to create wallet: %s\n", err.Error())
+		}
+
+		publicKey, err := counterpartycrypto.PublicKeyFromPassphrase(wallet.Passphrase, 0)
+		if err != nil {
+			t.Fatalf("Unable to derive public key: %s\n", err.Error())
+		}
+
+		passphrases = append(passphrases, wallet.Passphrase)
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	redeemScriptHex, _, err := counterpartycrypto.CreateMultisigAddress(2, publicKeys)
+	if err != nil {
+		t.Fatalf("Unable to create multisig address: %s\n", err.Error())
+	}
+	redeemScript, _ := hex.DecodeString(redeemScriptHex)
+
+	// Build an unsigned transaction the way Counterparty returns it, with the output script being spent in the sigscript
+	p2shScript, _ := txscript.NewScriptBuilder().AddOp(txscript.OP_HASH160).AddData(btcutil.Hash160(redeemScript)).AddOp(txscript.OP_EQUAL).Script()
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&wire.ShaHash{1}, 0), p2shScript))
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&wire.ShaHash{2}, 1), p2shScript))
+	tx.AddTxOut(wire.NewTxOut(5430, p2shScript))
+	var buf bytes.Buffer
+	tx.BtcEncode(&buf, wire.ProtocolVersion)
+	unsignedTx := hex.EncodeToString(buf.Bytes())
+
+	signatures := make(map[string][]string)
+	for _, i := range []int{2, 0} {
+		sigs, err := SignMultisigTransaction(c, passphrases[i], publicKeys[i], unsignedTx, redeemScriptHex)
+		if err != nil {
+			t.Fatalf("Unable to sign: %s\n", err.Error())
+		}
+
+		if err := VerifyMultisigSignatures(c, publicKeys[i], sigs, unsignedTx, redeemScriptHex); err != nil {
+			t.Errorf("Signature verification failed: %s\n", err.Error())
+		}
+
+		if err := VerifyMultisigSignatures(c, publicKeys[1], sigs, unsignedTx, redeemScriptHex); err == nil {
+			t.Errorf("Expected signatures to fail verification against another cosigner's key\n")
+		}
+
+		signatures[publicKeys[i]] = sigs
+
+		_, err = FinaliseMultisigTransaction(c, unsignedTx, redeemScriptHex, signatures)
+		if len(signatures) < 2 && err == nil {
+			t.Errorf("Expected finalisation to fail before quorum\n")
+		}
+	}
+
+	if _, err := FinaliseMultisigTransaction(c, unsignedTx, redeemScriptHex, signatures); err != nil {
+		t.Errorf("Unable to finalise: %s\n", err.Error())
+	}
+}
@@ -0,0 +1,237 @@
+package counterpartyapi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/vennd/enu/bitcoinapi"
+	"github.com/vennd/enu/counterpartycrypto"
+
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/btcec"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/chaincfg"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/txscript"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/wire"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcutil"
+)
+
+// The vendored btcd predates segwit so it is unable to calculate BIP143 signature hashes or serialise witness data.
+// The functions in this file provide the minimum required to sign P2SH-P2WPKH (BIP49) and P2WPKH (BIP84) inputs.
+
+// Lookup of the value in satoshis of a previous output. Segwit signatures commit to the amount being spent
+// which isn't contained in the unsigned transaction returned by Counterparty. Replaceable for testing.
+var getPrevOutValue = func(hash string, index uint32) (int64, error) {
+	rawTx, err := bitcoinapi.GetRawTransaction(hash)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, vout := range rawTx.Vout {
+		if vout.N == index {
+			return int64(math.Floor(vout.Value*1e8 + 0.5)), nil
+		}
+	}
+
+	return 0, errors.New("Previous output not found: " + hash)
+}
+
+// Returns true if the script is a pay to witness pubkey hash output script: OP_0 <20 bytes>
+func isWitnessPubKeyHash(script []byte) bool {
+	return len(script) == 22 && script[0] == txscript.OP_0 && script[1] == txscript.OP_DATA_20
+}
+
+// Returns true if the script is a pay to script hash output script: OP_HASH160 <20 bytes> OP_EQUAL
+func isScriptHash(script []byte) bool {
+	return len(script) == 23 && script[0] == txscript.OP_HASH160 && script[1] == txscript.OP_DATA_20 && script[22] == txscript.OP_EQUAL
+}
+
+// Returns the address that owns the output script for the segwit output types we are able to sign
+func segWitScriptAddress(script []byte) (string, error) {
+	if isWitnessPubKeyHash(script) {
+		return counterpartycrypto.EncodeSegWitAddress(counterpartycrypto.SegWitHrp, 0, script[2:])
+	}
+
+	if isScriptHash(script) {
+		address, err := btcutil.NewAddressScriptHashFromHash(script[2:22], &chaincfg.MainNetParams)
+		if err != nil {
+			return "", err
+		}
+
+		return address.String(), nil
+	}
+
+	return "", errors.New("Not a segwit output script")
+}
+
+// Calculates the BIP143 signature hash for a version 0 witness pubkey hash input
+func calcWitnessSignatureHash(tx *wire.MsgTx, idx int, pubKeyHash []byte, amount int64, hashType txscript.SigHashType) []byte {
+	var prevOuts, sequences, outputs bytes.Buffer
+
+	for _, txIn := range tx.TxIn {
+		prevOuts.Write(txIn.PreviousOutPoint.Hash[:])
+		binary.Write(&prevOuts, binary.LittleEndian, txIn.PreviousOutPoint.Index)
+		binary.Write(&sequences, binary.LittleEndian, txIn.Sequence)
+	}
+
+	for _, txOut := range tx.TxOut {
+		binary.Write(&outputs, binary.LittleEndian, txOut.Value)
+		writeVarBytes(&outputs, txOut.PkScript)
+	}
+
+	// The script code for P2WPKH is the P2PKH script for the pubkey hash
+	scriptCode := append([]byte{txscript.OP_DUP, txscript.OP_HASH160, txscript.OP_DATA_20}, pubKeyHash...)
+	scriptCode = append(scriptCode, txscript.OP_EQUALVERIFY, txscript.OP_CHECKSIG)
+
+	var preimage bytes.Buffer
+	binary.Write(&preimage, binary.LittleEndian, tx.Version)
+	preimage.Write(wire.DoubleSha256(prevOuts.Bytes()))
+	preimage.Write(wire.DoubleSha256(sequences.Bytes()))
+	preimage.Write(tx.TxIn[idx].PreviousOutPoint.Hash[:])
+	binary.Write(&preimage, binary.LittleEndian, tx.TxIn[idx].PreviousOutPoint.Index)
+	writeVarBytes(&preimage, scriptCode)
+	binary.Write(&preimage, binary.LittleEndian, amount)
+	binary.Write(&preimage, binary.LittleEndian, tx.TxIn[idx].Sequence)
+	preimage.Write(wire.DoubleSha256(outputs.Bytes()))
+	binary.Write(&preimage, binary.LittleEndian, tx.LockTime)
+	binary.Write(&preimage, binary.LittleEndian, uint32(hashType))
+
+	return wire.DoubleSha256(preimage.Bytes())
+}
+
+// Signs a witness pubkey hash input and returns the witness stack [signature, pubkey]
+func signWitnessInput(tx *wire.MsgTx, idx int, amount int64, privKey *btcec.PrivateKey) ([][]byte, error) {
+	pubKey := privKey.PubKey().SerializeCompressed()
+	hash := calcWitnessSignatureHash(tx, idx, btcutil.Hash160(pubKey), amount, txscript.SigHashAll)
+
+	signature, err := privKey.Sign(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := append(signature.Serialize(), byte(txscript.SigHashAll))
+
+	return [][]byte{sig, pubKey}, nil
+}
+
+// Verifies the witness of a witness pubkey hash input against the witness program
+func verifyWitnessInput(tx *wire.MsgTx, idx int, program []byte, amount int64, witness [][]byte) error {
+	if len(witness) != 2 {
+		return errors.New("Invalid witness stack size")
+	}
+
+	sig := witness[0]
+	pubKeyBytes := witness[1]
+
+	if !bytes.Equal(btcutil.Hash160(pubKeyBytes), program) {
+		return errors.New("Witness pubkey does not match the witness program")
+	}
+
+	if len(sig) < 1 || txscript.SigHashType(sig[len(sig)-1]) != txscript.SigHashAll {
+		return errors.New("Unsupported signature hash type")
+	}
+
+	signature, err := btcec.ParseDERSignature(sig[:len(sig)-1], btcec.S256())
+	if err != nil {
+		return err
+	}
+
+	pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return err
+	}
+
+	hash := calcWitnessSignatureHash(tx, idx, program, amount, txscript.SigHashAll)
+	if !signature.Verify(hash, pubKey) {
+		return errors.New("Witness signature verification failed")
+	}
+
+	return nil
+}
+
+// Serialises the transaction in the BIP144 witness format. If no input has witness data the legacy format is used.
+func serializeWitnessTx(tx *wire.MsgTx, witnesses [][][]byte) ([]byte, error) {
+	var hasWitness bool
+	for _, w := range witnesses {
+		if len(w) > 0 {
+			hasWitness = true
+		}
+	}
+
+	var buf bytes.Buffer
+	if !hasWitness {
+		if err := tx.BtcEncode(&buf, wire.ProtocolVersion); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	binary.Write(&buf, binary.LittleEndian, tx.Version)
+
+	// Marker and flag
+	buf.Write([]byte{0x00, 0x01})
+
+	writeVarInt(&buf, uint64(len(tx.TxIn)))
+	for _, txIn := range tx.TxIn {
+		buf.Write(txIn.PreviousOutPoint.Hash[:])
+		binary.Write(&buf, binary.LittleEndian, txIn.PreviousOutPoint.Index)
+		writeVarBytes(&buf, txIn.SignatureScript)
+		binary.Write(&buf, binary.LittleEndian, txIn.Sequence)
+	}
+
+	writeVarInt(&buf, uint64(len(tx.TxOut)))
+	for _, txOut := range tx.TxOut {
+		binary.Write(&buf, binary.LittleEndian, txOut.Value)
+		writeVarBytes(&buf, txOut.PkScript)
+	}
+
+	for i := range tx.TxIn {
+		var witness [][]byte
+		if i < len(witnesses) {
+			witness = witnesses[i]
+		}
+
+		writeVarInt(&buf, uint64(len(witness)))
+		for _, item := range witness {
+			writeVarBytes(&buf, item)
+		}
+	}
+
+	binary.Write(&buf, binary.LittleEndian, tx.LockTime)
+
+	return buf.Bytes(), nil
+}
+
+// Returns true if the hex encoded transaction is serialised with the BIP144 marker and flag
+func IsWitnessTx(txHexString string) bool {
+	txBytes, err := hex.DecodeString(txHexString)
+	if err != nil {
+		return false
+	}
+
+	return len(txBytes) > 6 && txBytes[4] == 0x00 && txBytes[5] == 0x01
+}
+
+func writeVarInt(w io.Writer, val uint64) {
+	switch {
+	case val < 0xfd:
+		w.Write([]byte{byte(val)})
+	case val <= math.MaxUint16:
+		w.Write([]byte{0xfd})
+		binary.Write(w, binary.LittleEndian, uint16(val))
+	case val <= math.MaxUint32:
+		w.Write([]byte{0xfe})
+		binary.Write(w, binary.LittleEndian, uint32(val))
+	default:
+		w.Write([]byte{0xff})
+		binary.Write(w, binary.LittleEndian, val)
+	}
+}
+
+func writeVarBytes(w io.Writer, b []byte) {
+	writeVarInt(w, uint64(len(b)))
+	w.Write(b)
+}
@@ -0,0 +1,27 @@
+package counterpartyapi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/txscript"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/wire"
+)
+
+func TestCalcWitnessSignatureHash(t *testing.T) {
+	// Native P2WPKH test vector from BIP143
+	txBytes, _ := hex.DecodeString("0100000002fff7f7881a8099afa6940d42d1e7f6362bec38171ea3edf433541db4e4ad969f0000000000eeffffffef51e1b804cc89d182d279655c3aa89e815b1b309fe287d9b2b55d57b90ec68a0100000000ffffffff02202cb206000000001976a9148280b37df378db99f66f85c95a783a76ac7a6d5988ac9093510d000000001976a9143bde42dbee7e4dbe6a21b2d50ce2f0167faa815988ac11000000")
+	pubKeyHash, _ := hex.DecodeString("1d0f172a0ecb48aee1be1f2687d2963ae33f71a1")
+	expected := "c37af31116d1b27caf68aae9e3ac82f1477929014d5b917657d0eb49478cb670"
+
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(txBytes)); err != nil {
+		t.Fatalf("Unable to deserialise tx: %s\n", err.Error())
+	}
+
+	hash := calcWitnessSignatureHash(&tx, 1, pubKeyHash, 600000000, txscript.SigHashAll)
+	if hex.EncodeToString(hash) != expected {
+		t.Errorf("Expected: %s, got: %s\n", expected, hex.EncodeToString(hash))
+	}
+}
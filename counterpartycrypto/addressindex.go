@@ -0,0 +1,210 @@
+package counterpartycrypto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcutil/hdkeychain"
+)
+
+// Derivation depth. Addresses are created and searched for signing up to this index
+var MaxAddressIndex uint32 = 1000
+
+// Number of consecutive unused addresses after which discovery stops, as per BIP44
+var GapLimit uint32 = 20
+
+// Maximum number of external chain keys held in the derivation cache
+var MaxCachedChains = 1000
+
+// Stores the mapping of a wallet's address index to address so the index of an address can be found without
+// searching the derivation path. Wallets are identified by a hash of the seed so the seed itself is never stored.
+type AddressIndexStore interface {
+	GetAddressIndex(seedHash string, addressType string, address string) (uint32, bool)
+	PutAddressIndex(seedHash string, addressType string, index uint32, address string)
+}
+
+// In memory AddressIndexStore used when no persistent store has been set
+type memoryAddressIndexStore struct {
+	sync.RWMutex
+	m map[string]uint32
+}
+
+func (s *memoryAddressIndexStore) GetAddressIndex(seedHash string, addressType string, address string) (uint32, bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	index, ok := s.m[seedHash+addressType+address]
+
+	return index, ok
+}
+
+func (s *memoryAddressIndexStore) PutAddressIndex(seedHash string, addressType string, index uint32, address string) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.m[seedHash+addressType+address] = index
+}
+
+var addressIndexStore AddressIndexStore = &memoryAddressIndexStore{m: make(map[string]uint32)}
+
+// Replaces the store used to record address indexes, typically with a persistent store
+func SetAddressIndexStore(store AddressIndexStore) {
+	addressIndexStore = store
+}
+
+// Sets the derivation depth and the gap limit used for discovery
+func SetDerivationLimits(maxAddressIndex uint32, gapLimit uint32) {
+	if maxAddressIndex > 0 {
+		MaxAddressIndex = maxAddressIndex
+	}
+
+	if gapLimit > 0 {
+		GapLimit = gapLimit
+	}
+}
+
+// Returns the identifier of a wallet used in the address index store
+func SeedHash(seed []byte) string {
+	first := sha256.Sum256(seed)
+	second := sha256.Sum256(first[:])
+
+	return hex.EncodeToString(second[:])
+}
+
+// Caches the external chain extended key of recently used wallets so the master key and the account path aren't
+// derived on every request
+var chainCache = struct {
+	sync.Mutex
+	m map[string]*hdkeychain.ExtendedKey
+}{m: make(map[string]*hdkeychain.ExtendedKey)}
+
+// Returns the external chain for the seed and address type from the cache, deriving it if required
+func cachedExternalChain(seed []byte, addressType string) (*hdkeychain.ExtendedKey, string, error) {
+	seedHash := SeedHash(seed)
+	cacheKey := seedHash + addressType
+
+	chainCache.Lock()
+	extAcct, ok := chainCache.m[cacheKey]
+	chainCache.Unlock()
+
+	if ok {
+		return extAcct, seedHash, nil
+	}
+
+	masterKey, err := hdkeychain.NewMaster(seed)
+	if err != nil {
+		return nil, "", err
+	}
+
+	extAcct, err = externalChain(masterKey, addressType)
+	if err != nil {
+		return nil, "", err
+	}
+
+	chainCache.Lock()
+	// Crude eviction, the cache is simply emptied when full
+	if len(chainCache.m) >= MaxCachedChains {
+		chainCache.m = make(map[string]*hdkeychain.ExtendedKey)
+	}
+	chainCache.m[cacheKey] = extAcct
+	chainCache.Unlock()
+
+	return extAcct, seedHash, nil
+}
+
+// Finds the keys for the address on the external chain. The address index store is checked first and if the address
+// isn't known the chain is searched up to MaxAddressIndex. The index found is recorded in the store.
+func findAddress(seed []byte, addressType string, address string) (CounterpartyAddress, error) {
+	var result CounterpartyAddress
+
+	extAcct, seedHash, err := cachedExternalChain(seed, addressType)
+	if err != nil {
+		return result, err
+	}
+
+	if index, ok := addressIndexStore.GetAddressIndex(seedHash, addressType, address); ok {
+		generatedAddress, err := addressFromExternalChain(extAcct, addressType, index)
+		if err != nil {
+			return result, err
+		}
+
+		if generatedAddress.Value == address {
+			return generatedAddress, nil
+		}
+	}
+
+	for i := uint32(0); i <= MaxAddressIndex; i++ {
+		generatedAddress, err := addressFromExternalChain(extAcct, addressType, i)
+		if err != nil {
+			// Invalid child keys are skipped as per BIP32
+			if err == hdkeychain.ErrInvalidChild {
+				continue
+			}
+			return result, err
+		}
+
+		if generatedAddress.Value == address {
+			addressIndexStore.PutAddressIndex(seedHash, addressType, i, address)
+
+			return generatedAddress, nil
+		}
+	}
+
+	return result, errors.New("Address not found within the first " + strconv.FormatUint(uint64(MaxAddressIndex)+1, 10) + " addresses")
+}
+
+// Discovers the addresses of the wallet which have been used on chain. Addresses are derived in order until GapLimit
+// consecutive addresses are unused. The used addresses are recorded in the address index store.
+// Returns the used addresses and the index of the first address after the last used address.
+func DiscoverAddresses(passphrase string, addressType string, gapLimit uint32, isUsed func(address string) (bool, error)) ([]string, uint32, error) {
+	var used []string
+	var nextIndex uint32
+
+	if gapLimit == 0 {
+		gapLimit = GapLimit
+	}
+
+	if addressType == "" {
+		addressType = AddressTypeP2PKH
+	}
+
+	seed, err := seedFromPassphrase(passphrase)
+	if err != nil {
+		return used, 0, err
+	}
+
+	extAcct, seedHash, err := cachedExternalChain(seed, addressType)
+	if err != nil {
+		return used, 0, err
+	}
+
+	var unused uint32
+	for i := uint32(0); i <= MaxAddressIndex && unused < gapLimit; i++ {
+		generatedAddress, err := addressFromExternalChain(extAcct, addressType, i)
+		if err == hdkeychain.ErrInvalidChild {
+			continue
+		} else if err != nil {
+			return used, nextIndex, err
+		}
+
+		isAddressUsed, err := isUsed(generatedAddress.Value)
+		if err != nil {
+			return used, nextIndex, fmt.Errorf("Unable to determine usage of %s: %s", generatedAddress.Value, err.Error())
+		}
+
+		if isAddressUsed {
+			addressIndexStore.PutAddressIndex(seedHash, addressType, i, generatedAddress.Value)
+			used = append(used, generatedAddress.Value)
+			nextIndex = i + 1
+			unused = 0
+		} else {
+			unused++
+		}
+	}
+
+	return used, nextIndex, nil
+}
@@ -0,0 +1,175 @@
+package counterpartycrypto
+
+import (
+	"errors"
+	"strings"
+)
+
+// Implementation of the BIP173 bech32 encoding used by native segwit (bc1...) addresses.
+// The vendored btcutil predates segwit so the encoding is implemented here.
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var bech32Generator = []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// Mainnet human readable part for segwit addresses
+const SegWitHrp = "bc"
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func bech32HrpExpand(hrp string) []byte {
+	result := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		result = append(result, hrp[i]>>5)
+	}
+	result = append(result, 0)
+	for i := 0; i < len(hrp); i++ {
+		result = append(result, hrp[i]&31)
+	}
+
+	return result
+}
+
+func bech32CreateChecksum(hrp string, data []byte) []byte {
+	values := append(bech32HrpExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	polymod := bech32Polymod(values) ^ 1
+
+	result := make([]byte, 6)
+	for i := 0; i < 6; i++ {
+		result[i] = byte((polymod >> uint(5*(5-i))) & 31)
+	}
+
+	return result
+}
+
+func bech32Encode(hrp string, data []byte) string {
+	combined := append(data, bech32CreateChecksum(hrp, data)...)
+
+	var result = hrp + "1"
+	for _, d := range combined {
+		result += string(bech32Charset[d])
+	}
+
+	return result
+}
+
+func bech32Decode(bech string) (string, []byte, error) {
+	if len(bech) > 90 {
+		return "", nil, errors.New("Invalid bech32 string length")
+	}
+
+	if strings.ToLower(bech) != bech && strings.ToUpper(bech) != bech {
+		return "", nil, errors.New("Invalid bech32 string, mixed case")
+	}
+	bech = strings.ToLower(bech)
+
+	pos := strings.LastIndex(bech, "1")
+	if pos < 1 || pos+7 > len(bech) {
+		return "", nil, errors.New("Invalid bech32 separator position")
+	}
+
+	hrp := bech[:pos]
+	var data []byte
+	for i := pos + 1; i < len(bech); i++ {
+		d := strings.IndexByte(bech32Charset, bech[i])
+		if d == -1 {
+			return "", nil, errors.New("Invalid bech32 character")
+		}
+		data = append(data, byte(d))
+	}
+
+	if bech32Polymod(append(bech32HrpExpand(hrp), data...)) != 1 {
+		return "", nil, errors.New("Invalid bech32 checksum")
+	}
+
+	return hrp, data[:len(data)-6], nil
+}
+
+// Regroups a byte slice from fromBits per element to toBits per element
+func convertBits(data []byte, fromBits uint, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	var result []byte
+	maxv := uint32(1<<toBits) - 1
+
+	for _, value := range data {
+		if uint32(value)>>fromBits != 0 {
+			return nil, errors.New("Invalid data range")
+		}
+		acc = acc<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte((acc>>bits)&maxv))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			result = append(result, byte((acc<<(toBits-bits))&maxv))
+		}
+	} else if bits >= fromBits || (acc<<(toBits-bits))&maxv != 0 {
+		return nil, errors.New("Invalid padding")
+	}
+
+	return result, nil
+}
+
+// Encodes a segwit witness program as a bech32 address
+func EncodeSegWitAddress(hrp string, version byte, program []byte) (string, error) {
+	if version > 16 || len(program) < 2 || len(program) > 40 {
+		return "", errors.New("Invalid witness program")
+	}
+
+	data, err := convertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	return bech32Encode(hrp, append([]byte{version}, data...)), nil
+}
+
+// Decodes a bech32 segwit address and returns the witness version and program
+func DecodeSegWitAddress(hrp string, address string) (byte, []byte, error) {
+	decodedHrp, data, err := bech32Decode(address)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if decodedHrp != hrp {
+		return 0, nil, errors.New("Invalid segwit address prefix: " + decodedHrp)
+	}
+
+	if len(data) < 1 || data[0] > 16 {
+		return 0, nil, errors.New("Invalid witness version")
+	}
+
+	program, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if len(program) < 2 || len(program) > 40 {
+		return 0, nil, errors.New("Invalid witness program length")
+	}
+
+	if data[0] == 0 && len(program) != 20 && len(program) != 32 {
+		return 0, nil, errors.New("Invalid witness program length for version 0")
+	}
+
+	return data[0], program, nil
+}
@@ -8,15 +8,30 @@ import (
 	"strings"
 
 	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/chaincfg"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcutil"
 	"github.com/vennd/enu/internal/github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/vennd/enu/internal/github.com/vennd/mneumonic"
 )
 
+// Supported address types.
+// P2PKH addresses are derived from m/0'/0/i as per Counterwallet
+// P2SH-P2WPKH addresses are derived from m/49'/0'/0'/0/i as per BIP49
+// P2WPKH addresses are derived from m/84'/0'/0'/0/i as per BIP84
+const (
+	AddressTypeP2PKH      = "p2pkh"
+	AddressTypeP2SHP2WPKH = "p2sh-p2wpkh"
+	AddressTypeP2WPKH     = "p2wpkh"
+)
+
+var AddressTypes = []string{AddressTypeP2PKH, AddressTypeP2SHP2WPKH, AddressTypeP2WPKH}
+
 type CounterpartyWallet struct {
-	Passphrase string   `json:"passphrase"`
-	HexSeed    string   `json:"hexSeed"`
-	Addresses  []string `json:"addresses"`
-	RequestId  string   `json:"requestId"`
+	Passphrase  string   `json:"passphrase"`
+	HexSeed     string   `json:"hexSeed"`
+	AddressType string   `json:"addressType,omitempty"`
+	Addresses   []string `json:"addresses"`
+	WalletId    string   `json:"walletId,omitempty"`
+	RequestId   string   `json:"requestId"`
 }
 
 type CounterpartyAddress struct {
@@ -25,77 +40,177 @@ type CounterpartyAddress struct {
 	PrivateKey string `json:"privateKey"`
 }
 
-func getAddressFromPassphrase(passphrase string, position uint32) (CounterpartyAddress, error) {
-	var returnValue CounterpartyAddress
+// Returns true if the given address type is supported
+func IsValidAddressType(addressType string) bool {
+	for _, t := range AddressTypes {
+		if t == addressType {
+			return true
+		}
+	}
 
-	m := mneumonic.FromWords(strings.Split(passphrase, " "))
-	hexSeed := m.ToHex()
+	return false
+}
 
-	hexValue, err := hex.DecodeString(hexSeed)
+// Infers the address type from the encoding of the address
+func AddressTypeFromAddress(address string) string {
+	if strings.HasPrefix(strings.ToLower(address), SegWitHrp+"1") {
+		return AddressTypeP2WPKH
+	}
 
-	if err != nil {
-		return returnValue, err
+	if strings.HasPrefix(address, "3") {
+		return AddressTypeP2SHP2WPKH
 	}
 
-	masterKey, err := hdkeychain.NewMaster(hexValue)
-	if err != nil {
-		return returnValue, err
+	return AddressTypeP2PKH
+}
+
+// Returns the external chain extended key for the derivation path of the given address type
+func externalChain(masterKey *hdkeychain.ExtendedKey, addressType string) (*hdkeychain.ExtendedKey, error) {
+	var path []uint32
+
+	switch addressType {
+	case AddressTypeP2SHP2WPKH:
+		// m/49'/0'/0'/0
+		path = []uint32{hdkeychain.HardenedKeyStart + 49, hdkeychain.HardenedKeyStart + 0, hdkeychain.HardenedKeyStart + 0, 0}
+	case AddressTypeP2WPKH:
+		// m/84'/0'/0'/0
+		path = []uint32{hdkeychain.HardenedKeyStart + 84, hdkeychain.HardenedKeyStart + 0, hdkeychain.HardenedKeyStart + 0, 0}
+	case AddressTypeP2PKH, "":
+		// m/0'/0
+		path = []uint32{hdkeychain.HardenedKeyStart + 0, 0}
+	default:
+		return nil, errors.New("Unsupported address type: " + addressType)
 	}
 
-	// get m/0'/0/0
-	// Hardened key for account 0. ie 0'
-	acct0, err := masterKey.Child(hdkeychain.HardenedKeyStart + 0)
-	if err != nil {
-		return returnValue, err
+	key := masterKey
+	for _, index := range path {
+		child, err := key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+		key = child
+	}
+
+	return key, nil
+}
+
+// Returns the P2WPKH redeem script (OP_0 <20 byte pubkey hash>) which is nested in P2SH for P2SH-P2WPKH addresses
+func WitnessPubKeyHashScript(pubKeyHash []byte) []byte {
+	return append([]byte{0x00, 0x14}, pubKeyHash...)
+}
+
+// Encodes the address for the given compressed public key in the format of the address type
+func addressFromPubKey(serializedPubKey []byte, addressType string) (string, error) {
+	pubKeyHash := btcutil.Hash160(serializedPubKey)
+
+	switch addressType {
+	case AddressTypeP2SHP2WPKH:
+		address, err := btcutil.NewAddressScriptHash(WitnessPubKeyHashScript(pubKeyHash), &chaincfg.MainNetParams)
+		if err != nil {
+			return "", err
+		}
+		return address.String(), nil
+	case AddressTypeP2WPKH:
+		return EncodeSegWitAddress(SegWitHrp, 0, pubKeyHash)
+	case AddressTypeP2PKH, "":
+		address, err := btcutil.NewAddressPubKeyHash(pubKeyHash, &chaincfg.MainNetParams)
+		if err != nil {
+			return "", err
+		}
+		return address.String(), nil
 	}
 
-	// External account for 0'
-	extAcct0, err := acct0.Child(0)
+	return "", errors.New("Unsupported address type: " + addressType)
+}
+
+// Derives the address and keys at the given position of the external chain
+func addressFromExternalChain(extAcct *hdkeychain.ExtendedKey, addressType string, position uint32) (CounterpartyAddress, error) {
+	var returnValue CounterpartyAddress
+
+	key, err := extAcct.Child(position)
 	if err != nil {
 		return returnValue, err
 	}
 
-	key, err := extAcct0.Child(uint32(position))
+	// Get the pubkey and serialise the compressed public key
+	privKey, err := key.ECPrivKey()
 	if err != nil {
 		return returnValue, err
 	}
 
+	publicKey := privKey.PubKey().SerializeCompressed()
+
 	// Get the address
-	address, err := key.Address(&chaincfg.MainNetParams)
+	address, err := addressFromPubKey(publicKey, addressType)
 	if err != nil {
 		return returnValue, err
 	}
 
-	//		log.Printf("Address: %s", address)
+	returnValue.Value = address
+	returnValue.PrivateKey = hex.EncodeToString(privKey.Serialize())
+	returnValue.PublicKey = hex.EncodeToString(publicKey)
 
-	// Get the pubkey and serialise the compressed public key
-	privKey, err := key.ECPrivKey()
+	return returnValue, nil
+}
+
+func seedFromPassphrase(passphrase string) ([]byte, error) {
+	m := mneumonic.FromWords(strings.Split(passphrase, " "))
+	hexSeed := m.ToHex()
+
+	return hex.DecodeString(hexSeed)
+}
+
+func masterKeyFromPassphrase(passphrase string) (*hdkeychain.ExtendedKey, error) {
+	seed, err := seedFromPassphrase(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return hdkeychain.NewMaster(seed)
+}
+
+func getAddressFromPassphrase(passphrase string, addressType string, position uint32) (CounterpartyAddress, error) {
+	var returnValue CounterpartyAddress
+
+	masterKey, err := masterKeyFromPassphrase(passphrase)
 	if err != nil {
 		return returnValue, err
 	}
 
-	returnValue.Value = fmt.Sprintf("%s", address)
-	returnValue.PrivateKey = hex.EncodeToString(privKey.Serialize())
-	returnValue.PublicKey = hex.EncodeToString(privKey.PubKey().SerializeCompressed())
+	extAcct, err := externalChain(masterKey, addressType)
+	if err != nil {
+		return returnValue, err
+	}
 
-	return returnValue, nil
+	return addressFromExternalChain(extAcct, addressType, position)
 }
 
+// Creates a Counterwallet compatible wallet with P2PKH addresses
 func CreateWallet(numberOfAddressesToGenerate int) (CounterpartyWallet, error) {
+	return CreateWalletWithType(numberOfAddressesToGenerate, AddressTypeP2PKH)
+}
+
+// Creates a wallet with addresses of the given address type
+func CreateWalletWithType(numberOfAddressesToGenerate int, addressType string) (CounterpartyWallet, error) {
 	var wallet CounterpartyWallet
 	var numAddresses int
 
 	if numberOfAddressesToGenerate <= 0 {
 		numAddresses = 20
-	} else if numberOfAddressesToGenerate > 100 {
-		numAddresses = 100
+	} else if numberOfAddressesToGenerate > int(MaxAddressIndex)+1 {
+		numAddresses = int(MaxAddressIndex) + 1
 	} else {
 		numAddresses = numberOfAddressesToGenerate
 	}
 
+	if addressType == "" {
+		addressType = AddressTypeP2PKH
+	}
+
 	m := mneumonic.GenerateRandom(128)
 	wallet.Passphrase = strings.Join(m.ToWords(), " ")
 	wallet.HexSeed = m.ToHex()
+	wallet.AddressType = addressType
 
 	hexValue, err := hex.DecodeString(wallet.HexSeed)
 
@@ -103,51 +218,19 @@ func CreateWallet(numberOfAddressesToGenerate int) (CounterpartyWallet, error) {
 		return wallet, err
 	}
 
-	masterKey, err := hdkeychain.NewMaster(hexValue)
-	if err != nil {
-		return wallet, err
-	}
-
-	// get m/0'/0/0
-	// Hardened key for account 0. ie 0'
-	acct0, err := masterKey.Child(hdkeychain.HardenedKeyStart + 0)
+	extAcct, seedHash, err := cachedExternalChain(hexValue, addressType)
 	if err != nil {
 		return wallet, err
 	}
 
-	// External account for 0'
-	extAcct0, err := acct0.Child(0)
-	if err != nil {
-		return wallet, err
-	}
-
-	// Derive extended key (repeat this from 0 to number of addresses-1)
+	// Derive extended key (repeat this from 0 to number of addresses-1) and record the index of each address
 	for i := 0; i <= numAddresses-1; i++ {
-		var counterpartyAddress CounterpartyAddress
-
-		key, err := extAcct0.Child(uint32(i))
-		if err != nil {
-			return wallet, err
-		}
-
-		// Get the address
-		address, err := key.Address(&chaincfg.MainNetParams)
+		counterpartyAddress, err := addressFromExternalChain(extAcct, addressType, uint32(i))
 		if err != nil {
 			return wallet, err
 		}
 
-		//		log.Printf("Address: %s", address)
-
-		// Get the pubkey and serialise the compressed public key
-		privKey, err := key.ECPrivKey()
-		if err != nil {
-			return wallet, err
-		}
-
-		counterpartyAddress.Value = fmt.Sprintf("%s", address.String())
-		counterpartyAddress.PrivateKey = hex.EncodeToString(privKey.Serialize())
-		counterpartyAddress.PublicKey = hex.EncodeToString(privKey.PubKey().SerializeCompressed())
-
+		addressIndexStore.PutAddressIndex(seedHash, addressType, uint32(i), counterpartyAddress.Value)
 		wallet.Addresses = append(wallet.Addresses, counterpartyAddress.Value)
 	}
 
@@ -155,8 +238,8 @@ func CreateWallet(numberOfAddressesToGenerate int) (CounterpartyWallet, error) {
 }
 
 // GetPrivateKey_Counterparty will retrieve the private key that corresponds to the address given.
-// The hierarchical master key is derived from the passphrase and then searches up to the first
-// 20 addresses for a match
+// The index of the address is looked up in the address index store, otherwise the external chain
+// is searched up to MaxAddressIndex for a match
 func GetPrivateKey(passphrase string, address string) (string, error) {
 	keys, err := GetPublicPrivateKey(passphrase, address)
 
@@ -164,35 +247,38 @@ func GetPrivateKey(passphrase string, address string) (string, error) {
 }
 
 // GetPublicKey_Counterparty will retrieve the public key that corresponds to the address given.
-// The hierarchical master key is derived from the passphrase and then searches up to the first
-// 20 addresses for a match
+// The index of the address is looked up in the address index store, otherwise the external chain
+// is searched up to MaxAddressIndex for a match
 func GetPublicKey(passphrase string, address string) (string, error) {
 	keys, err := GetPublicPrivateKey(passphrase, address)
 
 	return keys.PublicKey, err
 }
 
+// The derivation path searched is chosen from the address type (1... P2PKH, 3... P2SH-P2WPKH, bc1... P2WPKH)
 func GetPublicPrivateKey(passphrase string, address string) (CounterpartyAddress, error) {
 	var result CounterpartyAddress
 
-	for i := 0; i <= 19; i++ {
-		generatedAddress, err := getAddressFromPassphrase(passphrase, uint32(i))
-
-		if err != nil {
-			errorMessage := fmt.Sprintf("Error with passphrase value: %s\n", err)
-			return result, errors.New(errorMessage)
-		}
+	addressType := AddressTypeFromAddress(address)
+	if addressType == AddressTypeP2WPKH {
+		address = strings.ToLower(address)
+	}
 
-		if generatedAddress.Value == address {
-			result.Value = address
-			result.PrivateKey = generatedAddress.PrivateKey
-			result.PublicKey = generatedAddress.PublicKey
+	seed, err := seedFromPassphrase(passphrase)
+	if err != nil {
+		errorMessage := fmt.Sprintf("Error with passphrase value: %s\n", err)
+		return result, errors.New(errorMessage)
+	}
 
-			return result, nil
-		}
+	generatedAddress, err := findAddress(seed, addressType, address)
+	if err != nil {
+		errorMessage := fmt.Sprintf("Private and public keys not found for address: %s. %s", address, err.Error())
+		return result, errors.New(errorMessage)
 	}
 
-	errorMessage := fmt.Sprintf("Private and public keys not found for address: %s", address)
+	result.Value = address
+	result.PrivateKey = generatedAddress.PrivateKey
+	result.PublicKey = generatedAddress.PublicKey
 
-	return result, errors.New(errorMessage)
+	return result, nil
 }
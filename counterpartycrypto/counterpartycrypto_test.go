@@ -0,0 +1,152 @@
+package counterpartycrypto
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestSegWitAddressEncoding(t *testing.T) {
+	// Test vector from BIP173
+	address := "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4"
+	program := "751e76e8199196d454941c45d1b3a323f1433bd6"
+
+	version, decoded, err := DecodeSegWitAddress(SegWitHrp, address)
+	if err != nil {
+		t.Fatalf("Unable to decode address: %s\n", err.Error())
+	}
+
+	if version != 0 || hex.EncodeToString(decoded) != program {
+		t.Errorf("Expected: version 0 program %s, got: version %d program %s\n", program, version, hex.EncodeToString(decoded))
+	}
+
+	encoded, err := EncodeSegWitAddress(SegWitHrp, 0, decoded)
+	if err != nil {
+		t.Fatalf("Unable to encode address: %s\n", err.Error())
+	}
+
+	if encoded != strings.ToLower(address) {
+		t.Errorf("Expected: %s, got: %s\n", strings.ToLower(address), encoded)
+	}
+
+	// Invalid checksum
+	if _, _, err := DecodeSegWitAddress(SegWitHrp, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5"); err == nil {
+		t.Errorf("Expected invalid checksum error\n")
+	}
+}
+
+func TestGetPublicPrivateKeyByAddressType(t *testing.T) {
+	for _, addressType := range AddressTypes {
+		wallet, err := CreateWalletWithType(3, addressType)
+		if err != nil {
+			t.Fatalf("Unable to create %s wallet: %s\n", addressType, err.Error())
+		}
+
+		if len(wallet.Addresses) != 3 {
+			t.Fatalf("Expected 3 addresses, got: %d\n", len(wallet.Addresses))
+		}
+
+		address := wallet.Addresses[2]
+		if AddressTypeFromAddress(address) != addressType {
+			t.Errorf("Expected address type: %s, got: %s for address %s\n", addressType, AddressTypeFromAddress(address), address)
+		}
+
+		keys, err := GetPublicPrivateKey(wallet.Passphrase, address)
+		if err != nil {
+			t.Errorf("Unable to retrieve keys for %s: %s\n", address, err.Error())
+		}
+
+		if keys.Value != address {
+			t.Errorf("Expected: %s, got: %s\n", address, keys.Value)
+		}
+	}
+}
+
+func TestAddressDiscoveryBeyondIndex19(t *testing.T) {
+	wallet, err := CreateWallet(50)
+	if err != nil {
+		t.Fatalf("Unable to create wallet: %s\n", err.Error())
+	}
+
+	// Addresses past the first 20 must be found for signing
+	address := wallet.Addresses[45]
+	keys, err := GetPublicPrivateKey(wallet.Passphrase, address)
+	if err != nil {
+		t.Fatalf("Unable to retrieve keys for index 45: %s\n", err.Error())
+	}
+
+	if keys.Value != address {
+		t.Errorf("Expected: %s, got: %s\n", address, keys.Value)
+	}
+
+	// Addresses 3 and 25 are used so discovery with a gap limit of 22 finds both and stops at 48
+	used := map[string]bool{wallet.Addresses[3]: true, wallet.Addresses[25]: true}
+	var checked int
+	isUsed := func(address string) (bool, error) {
+		checked++
+		return used[address], nil
+	}
+
+	discovered, nextIndex, err := DiscoverAddresses(wallet.Passphrase, AddressTypeP2PKH, 22, isUsed)
+	if err != nil {
+		t.Fatalf("Unable to discover addresses: %s\n", err.Error())
+	}
+
+	if len(discovered) != 2 || discovered[0] != wallet.Addresses[3] || discovered[1] != wallet.Addresses[25] {
+		t.Errorf("Expected: %s, got: %s\n", []string{wallet.Addresses[3], wallet.Addresses[25]}, discovered)
+	}
+
+	if nextIndex != 26 || checked != 48 {
+		t.Errorf("Expected next index 26 after checking 48 addresses, got: %d after checking %d\n", nextIndex, checked)
+	}
+}
+
+func TestAddressesFromXpub(t *testing.T) {
+	wallet, err := CreateWallet(25)
+	if err != nil {
+		t.Fatalf("Unable to create wallet: %s\n", err.Error())
+	}
+
+	xpub, err := XpubFromPassphrase(wallet.Passphrase)
+	if err != nil {
+		t.Fatalf("Unable to get xpub: %s\n", err.Error())
+	}
+
+	// Watch only addresses must be the same as those derived from the passphrase
+	addresses, err := AddressesFromXpub(xpub, 5, 20)
+	if err != nil {
+		t.Fatalf("Unable to derive addresses from xpub: %s\n", err.Error())
+	}
+
+	for i, address := range addresses {
+		if address != wallet.Addresses[i+5] {
+			t.Errorf("Expected: %s, got: %s\n", wallet.Addresses[i+5], address)
+		}
+	}
+}
+
+func TestSignVerifyMessage(t *testing.T) {
+	for _, addressType := range AddressTypes {
+		wallet, err := CreateWalletWithType(2, addressType)
+		if err != nil {
+			t.Fatalf("Unable to create wallet: %s\n", err.Error())
+		}
+
+		signature, err := SignMessage(wallet.Passphrase, wallet.Addresses[1], "Proof of ownership")
+		if err != nil {
+			t.Fatalf("Unable to sign message with %s address: %s\n", addressType, err.Error())
+		}
+
+		if valid, err := VerifyMessage(wallet.Addresses[1], "Proof of ownership", signature); err != nil || !valid {
+			t.Errorf("Expected signature of %s address to verify, got: %t, %v\n", addressType, valid, err)
+		}
+
+		if valid, _ := VerifyMessage(wallet.Addresses[1], "Another message", signature); valid {
+			t.Errorf("Expected signature of a different message to be rejected for %s address\n", addressType)
+		}
+
+		if valid, _ := VerifyMessage(wallet.Addresses[0], "Proof of ownership", signature); valid {
+			t.Errorf("Expected signature to be rejected for a different %s address\n", addressType)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package counterpartycrypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/btcec"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/wire"
+)
+
+// Prefix of messages signed in the Bitcoin signed message format
+const messageMagic = "Bitcoin Signed Message:\n"
+
+// Offsets added to the recovery id in the header byte of the signature as per BIP137 so the address type can be
+// identified from the signature. P2PKH addresses of compressed keys use the offset which bitcoind uses.
+var messageHeaderOffsets = map[string]byte{
+	AddressTypeP2PKH:      27 + 4,
+	AddressTypeP2SHP2WPKH: 27 + 8,
+	AddressTypeP2WPKH:     27 + 12,
+}
+
+func writeMessageVarInt(buf *bytes.Buffer, val uint64) {
+	switch {
+	case val < 0xfd:
+		buf.WriteByte(byte(val))
+	case val <= 0xffff:
+		buf.WriteByte(0xfd)
+		binary.Write(buf, binary.LittleEndian, uint16(val))
+	case val <= 0xffffffff:
+		buf.WriteByte(0xfe)
+		binary.Write(buf, binary.LittleEndian, uint32(val))
+	default:
+		buf.WriteByte(0xff)
+		binary.Write(buf, binary.LittleEndian, val)
+	}
+}
+
+// Returns the double SHA256 hash of the message with the Bitcoin signed message prefix which is the hash signed
+func messageHash(message string) []byte {
+	var buf bytes.Buffer
+
+	writeMessageVarInt(&buf, uint64(len(messageMagic)))
+	buf.WriteString(messageMagic)
+	writeMessageVarInt(&buf, uint64(len(message)))
+	buf.WriteString(message)
+
+	return wire.DoubleSha256(buf.Bytes())
+}
+
+// Signs the message with the key of the address in the Bitcoin signed message format.
+// Returns the base64 encoded compact signature which can be verified by bitcoind and other wallets.
+func SignMessage(passphrase string, address string, message string) (string, error) {
+	keys, err := GetPublicPrivateKey(passphrase, address)
+	if err != nil {
+		return "", err
+	}
+
+	privateKeyBytes, err := hex.DecodeString(keys.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	privateKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), privateKeyBytes)
+
+	signature, err := btcec.SignCompact(btcec.S256(), privateKey, messageHash(message), true)
+	if err != nil {
+		return "", err
+	}
+
+	// Set the header to identify the address type, SignCompact() sets the header for P2PKH
+	addressType := AddressTypeFromAddress(address)
+	signature[0] = signature[0] - messageHeaderOffsets[AddressTypeP2PKH] + messageHeaderOffsets[addressType]
+
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// Verifies the base64 encoded signature of the message in the Bitcoin signed message format was made by the key of the
+// address. The public key is recovered from the signature so only the address is required.
+func VerifyMessage(address string, message string, signature string) (bool, error) {
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false, err
+	}
+
+	if len(signatureBytes) != 65 || signatureBytes[0] < 27 || signatureBytes[0] > 42 {
+		return false, errors.New("Invalid signature")
+	}
+
+	addressType := AddressTypeFromAddress(address)
+	if addressType == AddressTypeP2WPKH {
+		address = strings.ToLower(address)
+	}
+
+	// Normalise the header to the form RecoverCompact() expects as signatures for segwit addresses use the BIP137 offsets
+	header := signatureBytes[0]
+	if header >= messageHeaderOffsets[AddressTypeP2SHP2WPKH] {
+		header = messageHeaderOffsets[AddressTypeP2PKH] + (header-messageHeaderOffsets[AddressTypeP2PKH])%4
+	}
+	compact := append([]byte{header}, signatureBytes[1:]...)
+
+	publicKey, wasCompressed, err := btcec.RecoverCompact(btcec.S256(), compact, messageHash(message))
+	if err != nil {
+		return false, err
+	}
+
+	var serializedPubKey []byte
+	if wasCompressed {
+		serializedPubKey = publicKey.SerializeCompressed()
+	} else if addressType == AddressTypeP2PKH {
+		serializedPubKey = publicKey.SerializeUncompressed()
+	} else {
+		// Segwit addresses are only defined for compressed keys
+		return false, nil
+	}
+
+	recoveredAddress, err := addressFromPubKey(serializedPubKey, addressType)
+	if err != nil {
+		return false, err
+	}
+
+	return recoveredAddress == address, nil
+}
@@ -0,0 +1,179 @@
+package counterpartycrypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/btcec"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/chaincfg"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/txscript"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcutil"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcutil/hdkeychain"
+)
+
+// Standard P2SH multisig is limited to 15 compressed public keys by the 520 byte redeem script limit
+const MaxMultisigKeys = 15
+
+// Returns the compressed public key at m/0'/0/position of the Counterwallet passphrase
+func PublicKeyFromPassphrase(passphrase string, position uint32) (string, error) {
+	address, err := getAddressFromPassphrase(passphrase, AddressTypeP2PKH, position)
+	if err != nil {
+		return "", err
+	}
+
+	return address.PublicKey, nil
+}
+
+// Returns the compressed public key at <xpub>/0/position where the xpub is the extended public key of the account, ie m/0'
+func PublicKeyFromXpub(xpub string, position uint32) (string, error) {
+	accountKey, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return "", err
+	}
+
+	if accountKey.IsPrivate() {
+		return "", errors.New("An extended public key must be given")
+	}
+
+	extAcct, err := accountKey.Child(0)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := extAcct.Child(position)
+	if err != nil {
+		return "", err
+	}
+
+	pubKey, err := key.ECPubKey()
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(pubKey.SerializeCompressed()), nil
+}
+
+// Creates the m-of-n multisig redeem script and the P2SH address which pays to it.
+// Public keys are sorted as per BIP67 so the same set of keys always produces the same address.
+func CreateMultisigAddress(requiredSignatures int, publicKeys []string) (string, string, error) {
+	if len(publicKeys) == 0 || len(publicKeys) > MaxMultisigKeys {
+		return "", "", fmt.Errorf("Between 1 and %d public keys must be given", MaxMultisigKeys)
+	}
+
+	if requiredSignatures < 1 || requiredSignatures > len(publicKeys) {
+		return "", "", errors.New("The number of required signatures must be between 1 and the number of public keys")
+	}
+
+	var keys [][]byte
+	for _, publicKey := range publicKeys {
+		keyBytes, err := hex.DecodeString(publicKey)
+		if err != nil {
+			return "", "", err
+		}
+
+		pubKey, err := btcec.ParsePubKey(keyBytes, btcec.S256())
+		if err != nil {
+			return "", "", err
+		}
+
+		keyBytes = pubKey.SerializeCompressed()
+		for _, k := range keys {
+			if bytes.Equal(k, keyBytes) {
+				return "", "", errors.New("Duplicate public key: " + publicKey)
+			}
+		}
+
+		keys = append(keys, keyBytes)
+	}
+
+	sort.Sort(byteSlices(keys))
+
+	var addressPubKeys []*btcutil.AddressPubKey
+	for _, k := range keys {
+		addressPubKey, err := btcutil.NewAddressPubKey(k, &chaincfg.MainNetParams)
+		if err != nil {
+			return "", "", err
+		}
+		addressPubKeys = append(addressPubKeys, addressPubKey)
+	}
+
+	redeemScript, err := txscript.MultiSigScript(addressPubKeys, requiredSignatures)
+	if err != nil {
+		return "", "", err
+	}
+
+	address, err := btcutil.NewAddressScriptHash(redeemScript, &chaincfg.MainNetParams)
+	if err != nil {
+		return "", "", err
+	}
+
+	return hex.EncodeToString(redeemScript), address.String(), nil
+}
+
+// Returns the public keys contained in the multisig redeem script in the order they appear
+func MultisigPublicKeys(redeemScriptHex string) ([]string, int, error) {
+	redeemScript, err := hex.DecodeString(redeemScriptHex)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if txscript.GetScriptClass(redeemScript) != txscript.MultiSigTy {
+		return nil, 0, errors.New("Not a multisig redeem script")
+	}
+
+	_, addresses, requiredSignatures, err := txscript.ExtractPkScriptAddrs(redeemScript, &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var publicKeys []string
+	for _, address := range addresses {
+		publicKeys = append(publicKeys, hex.EncodeToString(address.ScriptAddress()))
+	}
+
+	return publicKeys, requiredSignatures, nil
+}
+
+// GetPrivateKeyForPublicKey will retrieve the private key that corresponds to the public key given.
+// The hierarchical master key is derived from the passphrase and then searches m/0'/0 up to
+// MaxAddressIndex for a match
+func GetPrivateKeyForPublicKey(passphrase string, publicKey string) (string, error) {
+	seed, err := seedFromPassphrase(passphrase)
+	if err != nil {
+		errorMessage := fmt.Sprintf("Error with passphrase value: %s\n", err)
+		return "", errors.New(errorMessage)
+	}
+
+	extAcct, _, err := cachedExternalChain(seed, AddressTypeP2PKH)
+	if err != nil {
+		errorMessage := fmt.Sprintf("Error with passphrase value: %s\n", err)
+		return "", errors.New(errorMessage)
+	}
+
+	for i := uint32(0); i <= MaxAddressIndex; i++ {
+		generatedAddress, err := addressFromExternalChain(extAcct, AddressTypeP2PKH, i)
+		if err == hdkeychain.ErrInvalidChild {
+			continue
+		} else if err != nil {
+			errorMessage := fmt.Sprintf("Error with passphrase value: %s\n", err)
+			return "", errors.New(errorMessage)
+		}
+
+		if generatedAddress.PublicKey == publicKey {
+			return generatedAddress.PrivateKey, nil
+		}
+	}
+
+	errorMessage := fmt.Sprintf("Private key not found for public key: %s", publicKey)
+
+	return "", errors.New(errorMessage)
+}
+
+type byteSlices [][]byte
+
+func (s byteSlices) Len() int           { return len(s) }
+func (s byteSlices) Less(i, j int) bool { return bytes.Compare(s[i], s[j]) < 0 }
+func (s byteSlices) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,104 @@
+package counterpartycrypto
+
+import (
+	"errors"
+
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcutil/hdkeychain"
+)
+
+// Returns the extended public key of the account, ie m/0', of the Counterwallet passphrase.
+// Addresses derived from the xpub with AddressesFromXpub() are the same as those of the passphrase.
+func XpubFromPassphrase(passphrase string) (string, error) {
+	masterKey, err := masterKeyFromPassphrase(passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	accountKey, err := masterKey.Child(hdkeychain.HardenedKeyStart + 0)
+	if err != nil {
+		return "", err
+	}
+
+	publicKey, err := accountKey.Neuter()
+	if err != nil {
+		return "", err
+	}
+
+	return publicKey.String(), nil
+}
+
+// Returns the P2PKH addresses at <xpub>/0/start to <xpub>/0/start+count-1 where the xpub is the extended public key
+// of the account, ie m/0'. No private keys are needed so addresses can be watched without holding the passphrase.
+func AddressesFromXpub(xpub string, start uint32, count uint32) ([]string, error) {
+	var addresses []string
+
+	if start+count > MaxAddressIndex+1 {
+		return addresses, errors.New("Addresses beyond the maximum address index can't be derived")
+	}
+
+	accountKey, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return addresses, err
+	}
+
+	if accountKey.IsPrivate() {
+		return addresses, errors.New("An extended public key must be given")
+	}
+
+	extAcct, err := accountKey.Child(0)
+	if err != nil {
+		return addresses, err
+	}
+
+	for i := start; i < start+count; i++ {
+		key, err := extAcct.Child(i)
+		if err != nil {
+			return addresses, err
+		}
+
+		pubKey, err := key.ECPubKey()
+		if err != nil {
+			return addresses, err
+		}
+
+		address, err := addressFromPubKey(pubKey.SerializeCompressed(), AddressTypeP2PKH)
+		if err != nil {
+			return addresses, err
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
+
+// Returns the address at the position of the external chain of the passphrase for the address type. The index is
+// recorded in the address index store so the keys of the address are found without searching when signing.
+func AddressFromPassphrase(passphrase string, addressType string, position uint32) (string, error) {
+	if position > MaxAddressIndex {
+		return "", errors.New("Addresses beyond the maximum address index can't be derived")
+	}
+
+	if addressType == "" {
+		addressType = AddressTypeP2PKH
+	}
+
+	seed, err := seedFromPassphrase(passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	extAcct, seedHash, err := cachedExternalChain(seed, addressType)
+	if err != nil {
+		return "", err
+	}
+
+	address, err := addressFromExternalChain(extAcct, addressType, position)
+	if err != nil {
+		return "", err
+	}
+
+	addressIndexStore.PutAddressIndex(seedHash, addressType, position, address.Value)
+
+	return address.Value, nil
+}
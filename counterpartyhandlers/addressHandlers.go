@@ -2,14 +2,17 @@ package counterpartyhandlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/vennd/enu/bitcoinapi"
 	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/counterpartycrypto"
 	"github.com/vennd/enu/database"
 	"github.com/vennd/enu/enulib"
 	"github.com/vennd/enu/handlers"
 	"github.com/vennd/enu/internal/golang.org/x/net/context"
+	"github.com/vennd/enu/keystore"
 	"github.com/vennd/enu/log"
 )
 
@@ -47,3 +50,117 @@ func AddressCreate(c context.Context, w http.ResponseWriter, r *http.Request, m
 	return nil
 
 }
+
+// Hands out the next unused address of an HD wallet, eg as a deposit address for a customer, and records the
+// assignment against the label. The wallet is given as an xpub, a watch only wallet or a custodial wallet.
+// Addresses of an xpub or watch only wallet are assigned from index 0. The addresses returned when a custodial wallet
+// was created are already in use so its assignments start after them.
+func WalletNextAddress(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var derivationKey string
+	var firstIndex uint32
+	var derive func(index uint32) (string, error)
+	var walletId string
+	var xpub string
+	var label string
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+
+	if m["walletId"] != nil {
+		walletId = m["walletId"].(string)
+	}
+	if m["xpub"] != nil {
+		xpub = m["xpub"].(string)
+	}
+	if m["label"] != nil {
+		label = m["label"].(string)
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "WalletNextAddress: received request walletId: %s, xpub: %s, label: %s from accessKey: %s\n", walletId, xpub, label, accessKey)
+
+	if walletId != "" && xpub == "" {
+		watchWallet, err := database.GetWatchWalletByWalletId(c, accessKey, walletId)
+		if err != nil {
+			handlers.ReturnServerError(c, w)
+
+			return nil
+		}
+
+		if watchWallet.Status != consts.NotFound && watchWallet.BlockchainId == consts.CounterpartyBlockchainId {
+			xpub = watchWallet.Xpub
+		}
+	}
+
+	if xpub != "" {
+		// The xpub identifies the derivation path so a watch only wallet and its xpub share assignments
+		if _, err := counterpartycrypto.AddressesFromXpub(xpub, 0, 1); err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in AddressesFromXpub(): %s", err.Error())
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidXpub.Code, consts.GenericErrors.InvalidXpub.Description)
+
+			return nil
+		}
+
+		derivationKey = xpub
+		derive = func(index uint32) (string, error) {
+			addresses, err := counterpartycrypto.AddressesFromXpub(xpub, index, 1)
+			if err != nil {
+				return "", err
+			}
+
+			return addresses[0], nil
+		}
+	} else {
+		wallet, errorCode, err := keystore.GetWallet(c, accessKey, walletId)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in keystore.GetWallet(): %s", err.Error())
+			handlers.ReturnKeystoreError(c, w, errorCode, err)
+
+			return nil
+		}
+
+		if wallet.BlockchainId != consts.CounterpartyBlockchainId || len(wallet.Addresses) == 0 {
+			handlers.ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidWalletId.Code, consts.GenericErrors.InvalidWalletId.Description)
+
+			return nil
+		}
+
+		passphrase, errorCode, err := keystore.GetPassphrase(c, accessKey, walletId, c.Value(consts.RequestTypeKey).(string), "", "")
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in keystore.GetPassphrase(): %s", err.Error())
+			handlers.ReturnKeystoreError(c, w, errorCode, err)
+
+			return nil
+		}
+
+		addressType := counterpartycrypto.AddressTypeFromAddress(wallet.Addresses[0])
+		derivationKey = walletId
+		firstIndex = uint32(len(wallet.Addresses))
+		derive = func(index uint32) (string, error) {
+			return counterpartycrypto.AddressFromPassphrase(passphrase, addressType, index)
+		}
+	}
+
+	assignment, err := database.AssignNextAddress(c, accessKey, consts.CounterpartyBlockchainId, walletId, derivationKey, firstIndex, label, derive)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in AssignNextAddress(): %s", err.Error())
+		handlers.ReturnUnprocessableEntity(c, w, consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description))
+
+		return nil
+	}
+
+	assignment.Xpub = xpub
+	assignment.RequestId = requestId
+
+	log.FluentfContext(consts.LOGINFO, c, "Assigned address: %s at index %d to label: %s for access key: %s", assignment.Address, assignment.Index, label, accessKey)
+
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(assignment); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
@@ -10,13 +10,13 @@ import (
 	"github.com/vennd/enu/bitcoinapi"
 	"github.com/vennd/enu/consts"
 	"github.com/vennd/enu/counterpartyapi"
-	"github.com/vennd/enu/counterpartycrypto"
 	"github.com/vennd/enu/database"
 	"github.com/vennd/enu/enulib"
 	"github.com/vennd/enu/handlers"
 	"github.com/vennd/enu/internal/github.com/gorilla/mux"
 	"github.com/vennd/enu/internal/golang.org/x/net/context"
 	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/signer"
 )
 
 func AssetCreate(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
@@ -25,7 +25,6 @@ func AssetCreate(c context.Context, w http.ResponseWriter, r *http.Request, m ma
 	assetStruct.RequestId = requestId
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 
-	passphrase := m["passphrase"].(string)
 	sourceAddress := m["sourceAddress"].(string)
 	asset := m["asset"].(string)
 	//	description := m["description"].(string)
@@ -34,9 +33,22 @@ func AssetCreate(c context.Context, w http.ResponseWriter, r *http.Request, m ma
 
 	log.FluentfContext(consts.LOGINFO, c, "AssetCreate: received request sourceAddress: %s, asset: %s, quantity: %s, divisible: %b from accessKey: %s\n", sourceAddress, asset, quantity, divisible, c.Value(consts.AccessKeyKey).(string))
 
-	sourceAddressPubKey, err := counterpartycrypto.GetPublicKey(passphrase, sourceAddress)
+	// Generate an assetId
+	assetId := enulib.GenerateAssetId()
+	log.Printf("Generated assetId: %s", assetId)
+
+	// Sign with the passphrase, the custodial wallet given by walletId or the remote signer
+	txSigner, errorCode, err := signer.FromRequest(c, m, sourceAddress, assetId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in signer.FromRequest(): %s\n", err.Error())
+		handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	sourceAddressPubKey, err := txSigner.PublicKey(c, sourceAddress)
 	if err != nil {
-		log.FluentfContext(consts.LOGERROR, c, "Error in counterpartycrypto.GetPublicKey(): %s\n", err)
+		log.FluentfContext(consts.LOGERROR, c, "Error in PublicKey(): %s\n", err)
 
 		handlers.ReturnServerError(c, w)
 		return nil
@@ -52,9 +64,6 @@ func AssetCreate(c context.Context, w http.ResponseWriter, r *http.Request, m ma
 		return nil
 	}
 
-	// Generate an assetId
-	assetId := enulib.GenerateAssetId()
-	log.Printf("Generated assetId: %s", assetId)
 	assetStruct.AssetId = assetId
 	assetStruct.Asset = randomAssetName
 	assetStruct.Description = asset
@@ -72,19 +81,19 @@ func AssetCreate(c context.Context, w http.ResponseWriter, r *http.Request, m ma
 	}
 
 	// Start asset creation in async mode
-	go delegatedCreateIssuance(c, c.Value(consts.AccessKeyKey).(string), passphrase, sourceAddress, assetId, randomAssetName, asset, quantity, divisible)
+	go delegatedCreateIssuance(c, c.Value(consts.AccessKeyKey).(string), txSigner, sourceAddress, assetId, randomAssetName, asset, quantity, divisible)
 
 	return nil
 }
 
 // Concurrency safe to create and send transactions from a single address.
-func delegatedCreateIssuance(c context.Context, accessKey string, passphrase string, sourceAddress string, assetId string, asset string, assetDescription string, quantity uint64, divisible bool) (string, int64, error) {
+func delegatedCreateIssuance(c context.Context, accessKey string, txSigner signer.Signer, sourceAddress string, assetId string, asset string, assetDescription string, quantity uint64, divisible bool) (string, int64, error) {
 	// Write the asset with the generated asset id to the database
 	go database.InsertAsset(accessKey, c.Value(consts.BlockchainIdKey).(string), assetId, sourceAddress, "", asset, assetDescription, quantity, divisible, "valid")
 
-	sourceAddressPubKey, err := counterpartycrypto.GetPublicKey(passphrase, sourceAddress)
+	sourceAddressPubKey, err := txSigner.PublicKey(c, sourceAddress)
 	if err != nil {
-		log.FluentfContext(consts.LOGERROR, c, "Error with GetPublicKey(): %s", err)
+		log.FluentfContext(consts.LOGERROR, c, "Error with PublicKey(): %s", err)
 		return "", consts.CounterpartyErrors.InvalidPassphrase.Code, errors.New(consts.CounterpartyErrors.InvalidPassphrase.Description)
 	}
 
@@ -121,7 +130,7 @@ func delegatedCreateIssuance(c context.Context, accessKey string, passphrase str
 	//	database.UpdateAssetNameByAssetId(c, accessKey, assetId, asset)
 
 	// Sign the transactions
-	signed, err := counterpartyapi.SignRawTransaction(c, passphrase, createResult)
+	signed, err := txSigner.SignRawTransaction(c, sourceAddress, createResult)
 	if err != nil {
 		log.FluentfContext(consts.LOGERROR, c, "Error in SignRawTransaction(): %s", err.Error())
 
@@ -154,7 +163,6 @@ func DividendCreate(c context.Context, w http.ResponseWriter, r *http.Request, m
 	// Add to the context the RequestType
 	c = context.WithValue(c, consts.RequestTypeKey, "dividend")
 
-	passphrase := m["passphrase"].(string)
 	sourceAddress := m["sourceAddress"].(string)
 	asset := m["asset"].(string)
 	dividendAsset := m["dividendAsset"].(string)
@@ -162,7 +170,20 @@ func DividendCreate(c context.Context, w http.ResponseWriter, r *http.Request, m
 
 	log.FluentfContext(consts.LOGINFO, c, "DividendCreate: received request sourceAddress: %s, asset: %s, dividendAsset: %s, quantityPerUnit: %d from accessKey: %s\n", sourceAddress, asset, dividendAsset, quantityPerUnit, c.Value(consts.AccessKeyKey).(string))
 
-	sourceAddressPubKey, err := counterpartycrypto.GetPublicKey(passphrase, sourceAddress)
+	// Generate a dividendId
+	dividendId := enulib.GenerateDividendId()
+	log.FluentfContext(consts.LOGINFO, c, "Generated dividendId: %s", dividendId)
+
+	// Sign with the passphrase, the custodial wallet given by walletId or the remote signer
+	txSigner, errorCode, err := signer.FromRequest(c, m, sourceAddress, dividendId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in signer.FromRequest(): %s\n", err.Error())
+		handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	sourceAddressPubKey, err := txSigner.PublicKey(c, sourceAddress)
 	if err != nil {
 		log.FluentfContext(consts.LOGERROR, c, "Error: %s\n", err)
 		w.WriteHeader(http.StatusBadRequest)
@@ -178,9 +199,6 @@ func DividendCreate(c context.Context, w http.ResponseWriter, r *http.Request, m
 	}
 	log.FluentfContext(consts.LOGINFO, c, "retrieved publickey: %s", sourceAddressPubKey)
 
-	// Generate a dividendId
-	dividendId := enulib.GenerateDividendId()
-	log.FluentfContext(consts.LOGINFO, c, "Generated dividendId: %s", dividendId)
 	dividendStruct.DividendId = dividendId
 
 	dividendStruct.SourceAddress = sourceAddress
@@ -198,19 +216,19 @@ func DividendCreate(c context.Context, w http.ResponseWriter, r *http.Request, m
 	}
 
 	// Start dividend creation in async mode
-	go delegatedCreateDividend(c, c.Value(consts.AccessKeyKey).(string), passphrase, dividendId, sourceAddress, asset, dividendAsset, quantityPerUnit)
+	go delegatedCreateDividend(c, c.Value(consts.AccessKeyKey).(string), txSigner, dividendId, sourceAddress, asset, dividendAsset, quantityPerUnit)
 
 	return nil
 }
 
 // Concurrency safe to create and send transactions from a single address.
-func delegatedCreateDividend(c context.Context, accessKey string, passphrase string, dividendId string, sourceAddress string, asset string, dividendAsset string, quantityPerUnit uint64) (string, int64, error) {
+func delegatedCreateDividend(c context.Context, accessKey string, txSigner signer.Signer, dividendId string, sourceAddress string, asset string, dividendAsset string, quantityPerUnit uint64) (string, int64, error) {
 	// Write the dividend with the generated dividend id to the database
 	go database.InsertDividend(accessKey, dividendId, sourceAddress, asset, dividendAsset, quantityPerUnit, "valid")
 
-	sourceAddressPubKey, err := counterpartycrypto.GetPublicKey(passphrase, sourceAddress)
+	sourceAddressPubKey, err := txSigner.PublicKey(c, sourceAddress)
 	if err != nil {
-		log.FluentfContext(consts.LOGERROR, c, "Err in GetPublicKey(): %s\n", err.Error())
+		log.FluentfContext(consts.LOGERROR, c, "Err in PublicKey(): %s\n", err.Error())
 		database.UpdateDividendWithErrorByDividendId(c, accessKey, dividendId, consts.CounterpartyErrors.InvalidPassphrase.Code, consts.CounterpartyErrors.InvalidPassphrase.Description)
 		return "", consts.CounterpartyErrors.InvalidPassphrase.Code, errors.New(consts.CounterpartyErrors.InvalidPassphrase.Description)
 	}
@@ -246,7 +264,7 @@ func delegatedCreateDividend(c context.Context, accessKey string, passphrase str
 	log.FluentfContext(consts.LOGINFO, c, "Created dividend of %d %s for each %s from address %s: %s\n", quantityPerUnit, dividendAsset, asset, sourceAddress, createResult)
 
 	// Sign the transactions
-	signed, err := counterpartyapi.SignRawTransaction(c, passphrase, createResult)
+	signed, err := txSigner.SignRawTransaction(c, sourceAddress, createResult)
 	if err != nil {
 		log.FluentfContext(consts.LOGERROR, c, "Error in SignRawTransaction: %s", err.Error())
 		database.UpdateDividendWithErrorByDividendId(c, accessKey, dividendId, consts.CounterpartyErrors.SigningError.Code, consts.CounterpartyErrors.SigningError.Description)
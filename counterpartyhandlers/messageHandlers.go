@@ -0,0 +1,97 @@
+package counterpartyhandlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/counterpartycrypto"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/keystore"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Signs the message with the key of the address in the Bitcoin signed message format as proof of ownership of the
+// address. The key is derived from the passphrase or the passphrase of the custodial wallet given by walletId.
+func SignMessage(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var signedMessage enulib.SignedMessage
+	var passphrase string
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	signedMessage.RequestId = requestId
+
+	signedMessage.BlockchainId = consts.CounterpartyBlockchainId
+	signedMessage.Address = m["address"].(string)
+	signedMessage.Message = m["message"].(string)
+
+	log.FluentfContext(consts.LOGINFO, c, "SignMessage: received request address: %s from accessKey: %s\n", signedMessage.Address, c.Value(consts.AccessKeyKey).(string))
+
+	if m["passphrase"] != nil {
+		passphrase = m["passphrase"].(string)
+	} else {
+		p, errorCode, err := keystore.GetPassphrase(c, c.Value(consts.AccessKeyKey).(string), m["walletId"].(string), c.Value(consts.RequestTypeKey).(string), signedMessage.Address, "")
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in keystore.GetPassphrase(): %s", err.Error())
+			handlers.ReturnKeystoreError(c, w, errorCode, err)
+
+			return nil
+		}
+		passphrase = p
+	}
+
+	signature, err := counterpartycrypto.SignMessage(passphrase, signedMessage.Address, signedMessage.Message)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in counterpartycrypto.SignMessage(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.CounterpartyErrors.SigningError.Code, consts.CounterpartyErrors.SigningError.Description)
+
+		return nil
+	}
+	signedMessage.Signature = signature
+
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(signedMessage); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Verifies the signature of the message in the Bitcoin signed message format was made by the key of the address
+func VerifyMessage(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var verification enulib.MessageVerification
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	verification.RequestId = requestId
+
+	verification.BlockchainId = consts.CounterpartyBlockchainId
+	verification.Address = m["address"].(string)
+	verification.Message = m["message"].(string)
+	verification.Signature = m["signature"].(string)
+
+	log.FluentfContext(consts.LOGINFO, c, "VerifyMessage: received request address: %s from accessKey: %s\n", verification.Address, c.Value(consts.AccessKeyKey).(string))
+
+	valid, err := counterpartycrypto.VerifyMessage(verification.Address, verification.Message, verification.Signature)
+	if err != nil {
+		// A malformed signature is reported as invalid rather than as an error
+		log.FluentfContext(consts.LOGINFO, c, "Unable to verify signature: %s", err.Error())
+	}
+	verification.Valid = valid
+
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(verification); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
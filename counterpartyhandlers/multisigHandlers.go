@@ -0,0 +1,516 @@
+package counterpartyhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/vennd/enu/bitcoinapi"
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/counterpartyapi"
+	"github.com/vennd/enu/counterpartycrypto"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+const (
+	MultisigProposalTypeSend     = "send"
+	MultisigProposalTypeIssuance = "issuance"
+
+	MultisigStatusAwaitingSignatures = "awaiting signatures"
+	MultisigStatusReady              = "ready"
+	MultisigStatusFinalising         = "finalising"
+)
+
+// Creates an m-of-n P2SH multisig wallet. Each cosigner is given as either a public key, a passphrase or an xpub.
+// Passphrases are only used to derive the public key of the cosigner and are not stored.
+func MultisigWalletCreate(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var wallet enulib.MultisigWallet
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	wallet.RequestId = requestId
+
+	requiredSignatures := int(m["requiredSignatures"].(float64))
+	cosigners := m["cosigners"].([]interface{})
+
+	var publicKeys []string
+	for _, cosigner := range cosigners {
+		var publicKey string
+		var err error
+		var index uint32
+
+		cosignerMap := cosigner.(map[string]interface{})
+		if cosignerMap["index"] != nil {
+			index = uint32(cosignerMap["index"].(float64))
+		}
+
+		if cosignerMap["publicKey"] != nil {
+			publicKey = cosignerMap["publicKey"].(string)
+		} else if cosignerMap["passphrase"] != nil {
+			publicKey, err = counterpartycrypto.PublicKeyFromPassphrase(cosignerMap["passphrase"].(string), index)
+		} else if cosignerMap["xpub"] != nil {
+			publicKey, err = counterpartycrypto.PublicKeyFromXpub(cosignerMap["xpub"].(string), index)
+		} else {
+			err = errors.New("A cosigner must be given as a publicKey, passphrase or xpub")
+		}
+
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Unable to determine the public key of the cosigner: %s", err.Error())
+			handlers.ReturnUnprocessableEntity(c, w, consts.CounterpartyErrors.InvalidCosigners.Code, errors.New(consts.CounterpartyErrors.InvalidCosigners.Description))
+
+			return nil
+		}
+
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	redeemScript, address, err := counterpartycrypto.CreateMultisigAddress(requiredSignatures, publicKeys)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in CreateMultisigAddress(): %s", err.Error())
+		handlers.ReturnUnprocessableEntity(c, w, consts.CounterpartyErrors.InvalidCosigners.Code, errors.New(consts.CounterpartyErrors.InvalidCosigners.Description))
+
+		return nil
+	}
+
+	// The public keys are returned in the order they appear in the redeem script
+	sortedPublicKeys, _, err := counterpartycrypto.MultisigPublicKeys(redeemScript)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in MultisigPublicKeys(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	wallet.WalletId = enulib.GenerateWalletId()
+	wallet.BlockchainId = consts.CounterpartyBlockchainId
+	wallet.Address = address
+	wallet.RedeemScript = redeemScript
+	wallet.RequiredSignatures = int64(requiredSignatures)
+	wallet.PublicKeys = sortedPublicKeys
+	wallet.Status = "valid"
+
+	err = database.InsertMultisigWallet(c, c.Value(consts.AccessKeyKey).(string), wallet.WalletId, wallet.BlockchainId, wallet.Address, wallet.RedeemScript, wallet.RequiredSignatures, wallet.PublicKeys, nil)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Created %d of %d multisig wallet: %s with address: %s for access key: %s", requiredSignatures, len(publicKeys), wallet.WalletId, wallet.Address, c.Value(consts.AccessKeyKey).(string))
+
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(wallet); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+func GetMultisigWallet(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	vars := mux.Vars(r)
+	walletId := vars["walletId"]
+
+	wallet, err := database.GetMultisigWalletByWalletId(c, c.Value(consts.AccessKeyKey).(string), walletId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	if wallet.Status == consts.NotFound {
+		handlers.ReturnNotFound(c, w)
+
+		return nil
+	}
+
+	wallet.RequestId = requestId
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(wallet); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Composes a send or issuance from the multisig address. The unsigned transaction and the signature hashes of each input
+// are returned so cosigners are able to sign either on the server or offline.
+func MultisigProposalCreate(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var proposal enulib.MultisigProposal
+	var unsignedTx string
+	var errorCode int64
+	var err error
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	walletId := m["walletId"].(string)
+	proposal.ProposalType = m["proposalType"].(string)
+	proposal.Asset = m["asset"].(string)
+	proposal.Quantity = uint64(m["quantity"].(float64))
+	if m["destinationAddress"] != nil {
+		proposal.DestinationAddress = m["destinationAddress"].(string)
+	}
+	if m["description"] != nil {
+		proposal.Description = m["description"].(string)
+	}
+	if m["divisible"] != nil {
+		proposal.Divisible = m["divisible"].(bool)
+	}
+
+	wallet, err := database.GetMultisigWalletByWalletId(c, accessKey, walletId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	if wallet.Status == consts.NotFound {
+		handlers.ReturnNotFound(c, w)
+
+		return nil
+	}
+
+	// Compose the transaction with the multisig address as the source. No public key is required for a P2SH source
+	switch proposal.ProposalType {
+	case MultisigProposalTypeSend:
+		if proposal.DestinationAddress == "" {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+			return nil
+		}
+		unsignedTx, errorCode, err = counterpartyapi.CreateSend(c, wallet.Address, proposal.DestinationAddress, proposal.Asset, proposal.Quantity, "")
+	case MultisigProposalTypeIssuance:
+		unsignedTx, errorCode, err = counterpartyapi.CreateIssuance(c, wallet.Address, proposal.Asset, proposal.Description, proposal.Quantity, proposal.Divisible, "")
+	}
+
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error composing %s for multisig wallet %s: %s", proposal.ProposalType, walletId, err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	signatureHashes, err := counterpartyapi.MultisigSignatureHashes(c, unsignedTx, wallet.RedeemScript)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in MultisigSignatureHashes(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, consts.CounterpartyErrors.ComposeError.Code, consts.CounterpartyErrors.ComposeError.Description)
+
+		return nil
+	}
+
+	proposal.ProposalId = enulib.GenerateProposalId()
+	proposal.WalletId = walletId
+	proposal.SourceAddress = wallet.Address
+	proposal.UnsignedTx = unsignedTx
+	proposal.SignatureHashes = signatureHashes
+	proposal.RequiredSignatures = wallet.RequiredSignatures
+	proposal.Status = MultisigStatusAwaitingSignatures
+
+	err = database.InsertMultisigProposal(c, accessKey, proposal)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	for _, publicKey := range wallet.PublicKeys {
+		proposal.Cosigners = append(proposal.Cosigners, enulib.MultisigCosigner{PublicKey: publicKey})
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Created multisig proposal: %s for wallet: %s", proposal.ProposalId, walletId)
+
+	proposal.RequestId = requestId
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(proposal); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+func GetMultisigProposal(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	vars := mux.Vars(r)
+	proposalId := vars["proposalId"]
+
+	proposal, err := database.GetMultisigProposalByProposalId(c, c.Value(consts.AccessKeyKey).(string), proposalId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	if proposal.Status == consts.NotFound {
+		handlers.ReturnNotFound(c, w)
+
+		return nil
+	}
+
+	proposal.RequestId = requestId
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(proposal); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Adds the signatures of one cosigner to the proposal. The cosigner either gives their passphrase so the server signs,
+// or the signatures of each input which were created offline from the signature hashes.
+func MultisigProposalSign(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var signatures []string
+	var err error
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	vars := mux.Vars(r)
+	proposalId := vars["proposalId"]
+	publicKey := m["publicKey"].(string)
+
+	proposal, err := database.GetMultisigProposalByProposalId(c, accessKey, proposalId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	if proposal.Status == consts.NotFound {
+		handlers.ReturnNotFound(c, w)
+
+		return nil
+	}
+
+	if proposal.Status != MultisigStatusAwaitingSignatures && proposal.Status != MultisigStatusReady {
+		handlers.ReturnUnprocessableEntity(c, w, consts.CounterpartyErrors.ProposalNotPending.Code, errors.New(consts.CounterpartyErrors.ProposalNotPending.Description))
+
+		return nil
+	}
+
+	var isCosigner bool
+	for _, cosigner := range proposal.Cosigners {
+		if cosigner.PublicKey == publicKey {
+			isCosigner = true
+
+			if cosigner.Signed {
+				handlers.ReturnUnprocessableEntity(c, w, consts.CounterpartyErrors.AlreadySigned.Code, errors.New(consts.CounterpartyErrors.AlreadySigned.Description))
+
+				return nil
+			}
+		}
+	}
+
+	if isCosigner == false {
+		handlers.ReturnUnprocessableEntity(c, w, consts.CounterpartyErrors.UnknownCosigner.Code, errors.New(consts.CounterpartyErrors.UnknownCosigner.Description))
+
+		return nil
+	}
+
+	wallet, err := database.GetMultisigWalletByWalletId(c, accessKey, proposal.WalletId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	if m["passphrase"] != nil {
+		signatures, err = counterpartyapi.SignMultisigTransaction(c, m["passphrase"].(string), publicKey, proposal.UnsignedTx, wallet.RedeemScript)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in SignMultisigTransaction(): %s", err.Error())
+			handlers.ReturnUnprocessableEntity(c, w, consts.CounterpartyErrors.SigningError.Code, errors.New(consts.CounterpartyErrors.SigningError.Description))
+
+			return nil
+		}
+	} else if m["signatures"] != nil {
+		for _, s := range m["signatures"].([]interface{}) {
+			signatures = append(signatures, s.(string))
+		}
+	}
+
+	if err := counterpartyapi.VerifyMultisigSignatures(c, publicKey, signatures, proposal.UnsignedTx, wallet.RedeemScript); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in VerifyMultisigSignatures(): %s", err.Error())
+		handlers.ReturnUnprocessableEntity(c, w, consts.CounterpartyErrors.InvalidMultisigSignature.Code, errors.New(consts.CounterpartyErrors.InvalidMultisigSignature.Description))
+
+		return nil
+	}
+
+	err = database.InsertMultisigSignatures(c, proposalId, publicKey, signatures)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Cosigner %s signed multisig proposal: %s", publicKey, proposalId)
+
+	// Count the signatures after this one is stored so cosigners signing at the same time also see each other's
+	proposal, err = database.GetMultisigProposalByProposalId(c, accessKey, proposalId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	var signedCount int64
+	for _, cosigner := range proposal.Cosigners {
+		if cosigner.Signed {
+			signedCount++
+		}
+	}
+
+	if signedCount >= proposal.RequiredSignatures && proposal.Status == MultisigStatusAwaitingSignatures {
+		if err := database.UpdateMultisigProposalStatusByProposalId(c, accessKey, proposalId, MultisigStatusReady); err != nil {
+			handlers.ReturnServerError(c, w)
+
+			return nil
+		}
+
+		proposal.Status = MultisigStatusReady
+	}
+
+	proposal.RequestId = requestId
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(proposal); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Assembles the collected signatures and broadcasts the transaction once the required number of cosigners have signed
+func MultisigProposalFinalise(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	requestId := c.Value(consts.RequestIdKey).(string)
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	vars := mux.Vars(r)
+	proposalId := vars["proposalId"]
+
+	proposal, err := database.GetMultisigProposalByProposalId(c, accessKey, proposalId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	if proposal.Status == consts.NotFound {
+		handlers.ReturnNotFound(c, w)
+
+		return nil
+	}
+
+	if proposal.Status == MultisigStatusAwaitingSignatures {
+		handlers.ReturnUnprocessableEntity(c, w, consts.CounterpartyErrors.QuorumNotReached.Code, errors.New(consts.CounterpartyErrors.QuorumNotReached.Description))
+
+		return nil
+	}
+
+	if proposal.Status != MultisigStatusReady {
+		handlers.ReturnUnprocessableEntity(c, w, consts.CounterpartyErrors.ProposalNotPending.Code, errors.New(consts.CounterpartyErrors.ProposalNotPending.Description))
+
+		return nil
+	}
+
+	wallet, err := database.GetMultisigWalletByWalletId(c, accessKey, proposal.WalletId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	signatures, err := database.GetMultisigSignaturesByProposalId(c, proposalId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	signed, err := counterpartyapi.FinaliseMultisigTransaction(c, proposal.UnsignedTx, wallet.RedeemScript, signatures)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in FinaliseMultisigTransaction(): %s", err.Error())
+		database.UpdateMultisigProposalWithErrorByProposalId(c, accessKey, proposalId, consts.CounterpartyErrors.SigningError.Code, consts.CounterpartyErrors.SigningError.Description)
+		handlers.ReturnServerErrorWithCustomError(c, w, consts.CounterpartyErrors.SigningError.Code, consts.CounterpartyErrors.SigningError.Description)
+
+		return nil
+	}
+
+	// Claim the proposal so only one finalise request broadcasts it
+	claimed, err := database.ClaimMultisigProposalByProposalId(c, accessKey, proposalId, MultisigStatusReady, MultisigStatusFinalising)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in ClaimMultisigProposalByProposalId(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	if !claimed {
+		handlers.ReturnUnprocessableEntity(c, w, consts.CounterpartyErrors.ProposalNotPending.Code, errors.New(consts.CounterpartyErrors.ProposalNotPending.Description))
+
+		return nil
+	}
+
+	// Transmit the transaction
+	txId, err := bitcoinapi.SendRawTransaction(c, signed)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in SendRawTransaction(): %s", err.Error())
+		database.UpdateMultisigProposalWithErrorByProposalId(c, accessKey, proposalId, consts.CounterpartyErrors.BroadcastError.Code, consts.CounterpartyErrors.BroadcastError.Description)
+		handlers.ReturnServerErrorWithCustomError(c, w, consts.CounterpartyErrors.BroadcastError.Code, consts.CounterpartyErrors.BroadcastError.Description)
+
+		return nil
+	}
+
+	if err := database.UpdateMultisigProposalCompleteByProposalId(c, accessKey, proposalId, signed, txId); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Multisig proposal %s was broadcast in txid %s but couldn't be marked complete: %s", proposalId, txId, err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Broadcast multisig proposal: %s, txid: %s", proposalId, txId)
+
+	proposal, err = database.GetMultisigProposalByProposalId(c, accessKey, proposalId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	proposal.RequestId = requestId
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(proposal); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
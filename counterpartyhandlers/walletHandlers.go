@@ -13,7 +13,10 @@ import (
 	"github.com/vennd/enu/counterpartycrypto"
 	"github.com/vennd/enu/database"
 	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/fuel"
 	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/keystore"
+	"github.com/vennd/enu/signer"
 
 	"github.com/vennd/enu/internal/github.com/gorilla/mux"
 	"github.com/vennd/enu/internal/golang.org/x/net/context"
@@ -34,8 +37,13 @@ func WalletCreate(c context.Context, w http.ResponseWriter, r *http.Request, m m
 		number = int(m["numberOfAddresses"].(float64))
 	}
 
+	addressType := counterpartycrypto.AddressTypeP2PKH
+	if m["addressType"] != nil {
+		addressType = m["addressType"].(string)
+	}
+
 	// Create the wallet
-	wallet, err = counterpartycrypto.CreateWallet(number)
+	wallet, err = counterpartycrypto.CreateWalletWithType(number, addressType)
 	if err != nil {
 		log.FluentfContext(consts.LOGERROR, c, "Error in CreateWallet(): %s", err.Error())
 		handlers.ReturnServerError(c, w)
@@ -44,6 +52,31 @@ func WalletCreate(c context.Context, w http.ResponseWriter, r *http.Request, m m
 	}
 	log.FluentfContext(consts.LOGINFO, c, "Created a new wallet with first address: %s for access key: %s\n (requestID: %s)", wallet.Addresses[0], c.Value(consts.AccessKeyKey).(string), requestId)
 
+	// Custodial wallets are held in the keystore and only the walletId is returned
+	if m["custodial"] != nil && m["custodial"].(bool) == true {
+		var unlockPolicy string
+		var unlockSeconds int64
+
+		if m["unlockPolicy"] != nil {
+			unlockPolicy = m["unlockPolicy"].(string)
+		}
+		if m["unlockSeconds"] != nil {
+			unlockSeconds = int64(m["unlockSeconds"].(float64))
+		}
+
+		keystoreWallet, errorCode, err := keystore.StoreWallet(c, c.Value(consts.AccessKeyKey).(string), consts.CounterpartyBlockchainId, wallet.Passphrase, wallet.Addresses, m["walletPassword"].(string), unlockPolicy, unlockSeconds)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in keystore.StoreWallet(): %s", err.Error())
+			handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+			return nil
+		}
+
+		wallet.WalletId = keystoreWallet.WalletId
+		wallet.Passphrase = ""
+		wallet.HexSeed = ""
+	}
+
 	// Return the wallet
 	wallet.RequestId = requestId
 	w.WriteHeader(http.StatusCreated)
@@ -69,7 +102,6 @@ func WalletSend(c context.Context, w http.ResponseWriter, r *http.Request, m map
 	// Add to the context the RequestType
 	c = context.WithValue(c, consts.RequestTypeKey, "walletPayment")
 
-	passphrase := m["passphrase"].(string)
 	sourceAddress := m["sourceAddress"].(string)
 	destinationAddress := m["destinationAddress"].(string)
 	asset := m["asset"].(string)
@@ -85,6 +117,15 @@ func WalletSend(c context.Context, w http.ResponseWriter, r *http.Request, m map
 
 	log.FluentfContext(consts.LOGINFO, c, "Generated paymentId: %s", paymentId)
 
+	// Sign with the passphrase, the custodial wallet given by walletId or the remote signer
+	txSigner, errorCode, err := signer.FromRequest(c, m, sourceAddress, paymentId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in signer.FromRequest(): %s\n", err.Error())
+		handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
 	// Return to the client the walletPayment containing requestId and paymentId and unblock the client
 	walletPayment.PaymentId = paymentId
 	walletPayment.Asset = asset
@@ -100,19 +141,19 @@ func WalletSend(c context.Context, w http.ResponseWriter, r *http.Request, m map
 		return nil
 	}
 
-	go delegatedSend(c, c.Value(consts.AccessKeyKey).(string), passphrase, sourceAddress, destinationAddress, asset, quantity, paymentId, paymentTag)
+	go delegatedSend(c, c.Value(consts.AccessKeyKey).(string), txSigner, sourceAddress, destinationAddress, asset, quantity, paymentId, paymentTag)
 
 	return nil
 }
 
 // Concurrency safe to create and send transactions from a single address.
-func delegatedSend(c context.Context, accessKey string, passphrase string, sourceAddress string, destinationAddress string, asset string, quantity uint64, paymentId string, paymentTag string) (string, int64, error) {
+func delegatedSend(c context.Context, accessKey string, txSigner signer.Signer, sourceAddress string, destinationAddress string, asset string, quantity uint64, paymentId string, paymentTag string) (string, int64, error) {
 	// Write the payment with the generated payment id to the database
 	go database.InsertPayment(c, accessKey, 0, c.Value(consts.BlockchainIdKey).(string), paymentId, sourceAddress, destinationAddress, asset, "", quantity, "valid", 0, 1500, paymentTag)
 
-	sourceAddressPubKey, err := counterpartycrypto.GetPublicKey(passphrase, sourceAddress)
+	sourceAddressPubKey, err := txSigner.PublicKey(c, sourceAddress)
 	if err != nil {
-		log.FluentfContext(consts.LOGERROR, c, "Err in GetPublicKey(): %s\n", err.Error())
+		log.FluentfContext(consts.LOGERROR, c, "Err in PublicKey(): %s\n", err.Error())
 		database.UpdatePaymentWithErrorByPaymentId(c, accessKey, paymentId, "", consts.CounterpartyErrors.InvalidPassphrase.Code, consts.CounterpartyErrors.InvalidPassphrase.Description)
 		return "", consts.CounterpartyErrors.SigningError.Code, errors.New(consts.CounterpartyErrors.SigningError.Description)
 	}
@@ -150,7 +191,7 @@ func delegatedSend(c context.Context, accessKey string, passphrase string, sourc
 	log.FluentfContext(consts.LOGINFO, c, "Created send of %d %s to %s: %s", quantity, asset, destinationAddress, createResult)
 
 	// Sign the transactions
-	signed, err := counterpartyapi.SignRawTransaction(c, passphrase, createResult)
+	signed, err := txSigner.SignRawTransaction(c, sourceAddress, createResult)
 	if err != nil {
 		log.FluentfContext(consts.LOGERROR, c, "Err in SignRawTransaction(): %s\n", err.Error())
 		database.UpdatePaymentWithErrorByPaymentId(c, accessKey, paymentId, "", consts.CounterpartyErrors.SigningError.Code, consts.CounterpartyErrors.SigningError.Description)
@@ -187,7 +228,7 @@ func WalletBalance(c context.Context, w http.ResponseWriter, r *http.Request, m
 	vars := mux.Vars(r)
 	address := vars["address"]
 
-	if address == "" || len(address) != 34 {
+	if address == "" || (len(address) != 34 && len(address) != 42) {
 		log.FluentfContext(consts.LOGERROR, c, "Invalid address")
 		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
 
@@ -238,6 +279,78 @@ func WalletBalance(c context.Context, w http.ResponseWriter, r *http.Request, m
 	return nil
 }
 
+// Scans the wallet's derivation path for addresses which have been used and records their index so they can be signed for.
+// An address is used if it holds Counterparty assets or BTC, or has sent Counterparty assets.
+func WalletDiscover(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var discovery enulib.WalletDiscovery
+	var gapLimit uint32
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	discovery.RequestId = requestId
+
+	passphrase := m["passphrase"].(string)
+	discovery.AddressType = counterpartycrypto.AddressTypeP2PKH
+	if m["addressType"] != nil {
+		discovery.AddressType = m["addressType"].(string)
+	}
+	if m["gapLimit"] != nil {
+		gapLimit = uint32(m["gapLimit"].(float64))
+	}
+
+	isUsed := func(address string) (bool, error) {
+		balances, _, err := counterpartyapi.GetBalancesByAddress(c, address)
+		if err != nil {
+			return false, err
+		}
+		if len(balances) > 0 {
+			return true, nil
+		}
+
+		sends, _, err := counterpartyapi.GetSendsByAddress(c, address)
+		if err != nil {
+			return false, err
+		}
+		if len(sends) > 0 {
+			return true, nil
+		}
+
+		btcbalance, err := bitcoinapi.GetBalance(c, address)
+		if err != nil {
+			return false, err
+		}
+
+		return btcbalance > 0, nil
+	}
+
+	addresses, nextIndex, err := counterpartycrypto.DiscoverAddresses(passphrase, discovery.AddressType, gapLimit, isUsed)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in DiscoverAddresses(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	discovery.Addresses = addresses
+	discovery.NextIndex = nextIndex
+	discovery.GapLimit = gapLimit
+	if discovery.GapLimit == 0 {
+		discovery.GapLimit = counterpartycrypto.GapLimit
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Discovered %d used addresses for access key: %s", len(addresses), c.Value(consts.AccessKeyKey).(string))
+
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(discovery); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
 func ActivateAddress(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
 	requestId := c.Value(consts.RequestIdKey).(string)
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
@@ -309,40 +422,38 @@ func delegatedActivateAddress(c context.Context, addressToActivate string, amoun
 	accessKey := c.Value(consts.AccessKeyKey).(string)
 	blockchainId := c.Value(consts.BlockchainIdKey).(string)
 
-	// Need a better way to secure internal wallets
-	// Array of internal wallets that can be round robined to activate addresses
-	var wallets = []struct {
-		Address      string
-		Passphrase   string
-		BlockchainId string
-	}{
-		{"1E5YgFkC4HNHwWTF5iUdDbKpzry1SRLv8e", "one two three four five six seven eight nine ten eleven twelve", "counterparty"},
-	}
+	// Write the activation with the generated activation id to the database
+	database.InsertActivation(c, accessKey, activationId, blockchainId, addressToActivate, amount)
 
 	for complete == false {
-		// Pick an internal address to send from
-		var randomNumber int = 0
-		var sourceAddress = wallets[randomNumber].Address
-
-		// Write the dividend with the generated dividend id to the database
-		database.InsertActivation(c, accessKey, activationId, blockchainId, sourceAddress, amount)
-
 		// Calculate the quantity of BTC to send by the amount specified
 		// For Counterparty: each transaction = dust_size + miners_fee
 		quantity, asset, err := counterpartyapi.CalculateFeeAmount(c, amount)
 		if err != nil {
 			log.FluentfContext(consts.LOGERROR, c, "Could not calculate fee: %s", err.Error())
-			database.UpdatePaymentWithErrorByPaymentId(c, accessKey, activationId, "", consts.CounterpartyErrors.MiscError.Code, consts.CounterpartyErrors.MiscError.Description)
+			database.UpdateActivationWithError(c, accessKey, activationId, consts.CounterpartyErrors.MiscError.Code, consts.CounterpartyErrors.MiscError.Description)
 			return "", consts.CounterpartyErrors.MiscError.Code, errors.New(consts.CounterpartyErrors.MiscError.Description)
 		}
 
-		txId, _, err = delegatedSend(c, accessKey, wallets[randomNumber].Passphrase, wallets[randomNumber].Address, addressToActivate, asset, quantity, activationId, "")
+		// Pick a fuel wallet to send from
+		fuelWallet, txSigner, errorCode, err := fuel.Select(c, blockchainId, quantity)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in fuel.Select(): %s", err.Error())
+			database.UpdateActivationWithError(c, accessKey, activationId, errorCode, err.Error())
+			return "", errorCode, err
+		}
+		var sourceAddress = fuelWallet.Address
+
+		txId, _, err = delegatedSend(c, accessKey, txSigner, sourceAddress, addressToActivate, asset, quantity, activationId, "")
 		if err != nil {
 			log.FluentfContext(consts.LOGERROR, c, "Error in DelegatedSend: %s", err.Error())
 			database.UpdatePaymentWithErrorByPaymentId(c, accessKey, activationId, "", consts.CounterpartyErrors.MiscError.Code, consts.CounterpartyErrors.MiscError.Description)
+			fuel.Release(c, blockchainId, sourceAddress, 0)
 
 			complete = false
 		} else {
+			fuel.Release(c, blockchainId, sourceAddress, quantity)
+
 			complete = true
 		}
 	}
@@ -351,3 +462,20 @@ func delegatedActivateAddress(c context.Context, addressToActivate string, amoun
 
 	return txId, 0, nil
 }
+
+// Returns the number of transactions the BTC balance of the address can pay for
+func NumberOfTransactions(c context.Context, address string) (uint64, error) {
+	btcbalance, err := bitcoinapi.GetBalance(c, address)
+	if err != nil {
+		return 0, err
+	}
+
+	return counterpartyapi.CalculateNumberOfTransactions(c, btcbalance)
+}
+
+// Sends the address enough BTC for the amount of transactions using the activation flow. Used by automatic top ups
+func TopUpAddress(c context.Context, address string, amount uint64, activationId string) (int64, error) {
+	_, errorCode, err := delegatedActivateAddress(c, address, amount, activationId)
+
+	return errorCode, err
+}
@@ -0,0 +1,251 @@
+package counterpartyhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/vennd/enu/bitcoinapi"
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/counterpartyapi"
+	"github.com/vennd/enu/counterpartycrypto"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Registers the xpub of an account (m/0') as a watch only wallet. The first numberOfAddresses P2PKH addresses of the
+// external chain (m/0'/0/i) are derived, which are the same addresses as the Counterwallet passphrase of the account.
+func WatchWalletCreate(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var wallet enulib.WatchWallet
+	var numberOfAddresses uint32 = 20
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	wallet.RequestId = requestId
+
+	xpub := m["xpub"].(string)
+	if m["numberOfAddresses"] != nil {
+		numberOfAddresses = uint32(m["numberOfAddresses"].(float64))
+	}
+
+	addresses, err := counterpartycrypto.AddressesFromXpub(xpub, 0, numberOfAddresses)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in AddressesFromXpub(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidXpub.Code, consts.GenericErrors.InvalidXpub.Description)
+
+		return nil
+	}
+
+	wallet.WalletId = enulib.GenerateWalletId()
+	wallet.BlockchainId = consts.CounterpartyBlockchainId
+	wallet.Xpub = xpub
+	wallet.Addresses = addresses
+	wallet.Status = "valid"
+
+	err = database.InsertWatchWallet(c, c.Value(consts.AccessKeyKey).(string), wallet)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Created watch only wallet: %s with %d addresses for access key: %s", wallet.WalletId, len(wallet.Addresses), c.Value(consts.AccessKeyKey).(string))
+
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(wallet); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Returns the balances of each address of the watch only wallet and the total of each asset across the wallet
+func WatchWalletBalance(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var walletbalance enulib.WatchWalletBalances
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	walletbalance.RequestId = requestId
+
+	wallet, ok := handlers.GetWatchWallet(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "WatchWalletBalance: received request walletId: %s from accessKey: %s\n", wallet.WalletId, c.Value(consts.AccessKeyKey).(string))
+
+	walletbalance.WalletId = wallet.WalletId
+	walletbalance.BlockchainId = consts.CounterpartyBlockchainId
+
+	totals := make(map[string]uint64)
+	var assets []string
+	for _, address := range wallet.Addresses {
+		var addressbalance enulib.AddressBalances
+
+		addressbalance.Address = address
+		addressbalance.BlockchainId = consts.CounterpartyBlockchainId
+
+		result, errorCode, err := counterpartyapi.GetBalancesByAddress(c, address)
+		if err != nil {
+			handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+			return nil
+		}
+
+		for _, item := range result {
+			addressbalance.Balances = append(addressbalance.Balances, enulib.Amount{Asset: item.Asset, Quantity: item.Quantity})
+		}
+
+		btcbalance, err := bitcoinapi.GetBalance(c, address)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Unable to get BTC balance of %s: %s", address, err.Error())
+			handlers.ReturnServerError(c, w)
+
+			return nil
+		}
+		addressbalance.Balances = append(addressbalance.Balances, enulib.Amount{Asset: "BTC", Quantity: btcbalance})
+
+		// Fees are paid by the source address so the number of transactions is calculated per address
+		numberOfTransactions, err := counterpartyapi.CalculateNumberOfTransactions(c, btcbalance)
+		if err != nil {
+			numberOfTransactions = 0
+			log.FluentfContext(consts.LOGERROR, c, "Unable to calculate number of transactions: %s", err.Error())
+		}
+		addressbalance.NumberOfTransactions = numberOfTransactions
+		walletbalance.NumberOfTransactions += numberOfTransactions
+
+		for _, balance := range addressbalance.Balances {
+			if _, ok := totals[balance.Asset]; !ok {
+				assets = append(assets, balance.Asset)
+			}
+			totals[balance.Asset] += balance.Quantity
+		}
+
+		walletbalance.Addresses = append(walletbalance.Addresses, addressbalance)
+	}
+
+	for _, asset := range assets {
+		walletbalance.Balances = append(walletbalance.Balances, enulib.Amount{Asset: asset, Quantity: totals[asset]})
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(walletbalance); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Composes an unsigned send from the watch only wallet so it can be signed offline by the holder of the keys.
+// If no sourceAddress is given the first address of the wallet holding enough of the asset is used.
+func WatchWalletCompose(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var compose enulib.WatchWalletCompose
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	compose.RequestId = requestId
+
+	wallet, ok := handlers.GetWatchWallet(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	compose.WalletId = wallet.WalletId
+	compose.BlockchainId = consts.CounterpartyBlockchainId
+	compose.DestinationAddress = m["destinationAddress"].(string)
+	compose.Asset = m["asset"].(string)
+	compose.Quantity = uint64(m["quantity"].(float64))
+	if m["sourceAddress"] != nil {
+		compose.SourceAddress = m["sourceAddress"].(string)
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "WatchWalletCompose: received request walletId: %s, sourceAddress: %s, destinationAddress: %s, asset: %s, quantity: %d from accessKey: %s\n", wallet.WalletId, compose.SourceAddress, compose.DestinationAddress, compose.Asset, compose.Quantity, c.Value(consts.AccessKeyKey).(string))
+
+	index := -1
+	for i, address := range wallet.Addresses {
+		if compose.SourceAddress != "" {
+			if address == compose.SourceAddress {
+				index = i
+				break
+			}
+			continue
+		}
+
+		var quantity uint64
+		if compose.Asset == "BTC" {
+			btcbalance, err := bitcoinapi.GetBalance(c, address)
+			if err != nil {
+				log.FluentfContext(consts.LOGERROR, c, "Unable to get BTC balance of %s: %s", address, err.Error())
+				handlers.ReturnServerError(c, w)
+
+				return nil
+			}
+			quantity = btcbalance
+		} else {
+			balances, errorCode, err := counterpartyapi.GetBalancesByAddress(c, address)
+			if err != nil {
+				handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+				return nil
+			}
+
+			for _, balance := range balances {
+				if balance.Asset == compose.Asset {
+					quantity = balance.Quantity
+				}
+			}
+		}
+
+		if quantity >= compose.Quantity {
+			index = i
+			compose.SourceAddress = address
+			break
+		}
+	}
+
+	if index == -1 {
+		if compose.SourceAddress != "" {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.AddressNotInWallet.Code, consts.GenericErrors.AddressNotInWallet.Description)
+		} else {
+			handlers.ReturnUnprocessableEntity(c, w, consts.CounterpartyErrors.InsufficientFunds.Code, errors.New(consts.CounterpartyErrors.InsufficientFunds.Description))
+		}
+
+		return nil
+	}
+
+	// The public key of the source address is needed by Counterparty to compose the transaction
+	publicKey, err := counterpartycrypto.PublicKeyFromXpub(wallet.Xpub, uint32(index))
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in PublicKeyFromXpub(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	unsignedTx, errorCode, err := counterpartyapi.CreateSend(c, compose.SourceAddress, compose.DestinationAddress, compose.Asset, compose.Quantity, publicKey)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in CreateSend(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+	compose.UnsignedTx = unsignedTx
+
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(compose); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
@@ -35,11 +35,15 @@ type blockchainFunction map[string]blockchainHandler
 var blockchainFunctions = map[string]blockchainFunction{
 	"counterparty": {
 		// Address handlers
-		"address":         counterpartyhandlers.AddressCreate,
-		"walletCreate":    counterpartyhandlers.WalletCreate,
-		"walletPayment":   counterpartyhandlers.WalletSend,
-		"walletBalance":   counterpartyhandlers.WalletBalance,
-		"activateaddress": counterpartyhandlers.ActivateAddress,
+		"address":           counterpartyhandlers.AddressCreate,
+		"walletCreate":      counterpartyhandlers.WalletCreate,
+		"walletPayment":     counterpartyhandlers.WalletSend,
+		"walletBalance":     counterpartyhandlers.WalletBalance,
+		"activateaddress":   counterpartyhandlers.ActivateAddress,
+		"walletDiscover":    counterpartyhandlers.WalletDiscover,
+		"walletNextAddress": counterpartyhandlers.WalletNextAddress,
+		"signMessage":       counterpartyhandlers.SignMessage,
+		"verifyMessage":     counterpartyhandlers.VerifyMessage,
 
 		// Asset handlers
 		"asset":       counterpartyhandlers.AssetCreate,
@@ -54,28 +58,138 @@ var blockchainFunctions = map[string]blockchainFunction{
 		"paymentretry":     counterpartyhandlers.PaymentRetry,
 		"getpayment":       generalhandlers.GetPayment,
 		"paymentbyaddress": generalhandlers.GetPaymentsByAddress,
+
+		// Activation handlers
+		"getactivation":        generalhandlers.GetActivation,
+		"activationsbyaddress": generalhandlers.GetActivationsByAddress,
+
+		// Automatic top up handlers
+		"topUpPolicyCreate":  generalhandlers.TopUpPolicyCreate,
+		"getTopUpPolicy":     generalhandlers.GetTopUpPolicy,
+		"topUpPolicyDisable": generalhandlers.TopUpPolicyDisable,
+		"topUpCapSet":        generalhandlers.TopUpCapSet,
+		"getTopUpCap":        generalhandlers.GetTopUpCap,
+
+		// Multisig handlers
+		"multisigWalletCreate": counterpartyhandlers.MultisigWalletCreate,
+		"getMultisigWallet":    counterpartyhandlers.GetMultisigWallet,
+		"multisigProposal":     counterpartyhandlers.MultisigProposalCreate,
+		"getMultisigProposal":  counterpartyhandlers.GetMultisigProposal,
+		"multisigSign":         counterpartyhandlers.MultisigProposalSign,
+		"multisigFinalise":     counterpartyhandlers.MultisigProposalFinalise,
+
+		// Keystore handlers
+		"getKeystoreWallet": generalhandlers.GetKeystoreWallet,
+		"keystoreUnlock":    generalhandlers.KeystoreUnlock,
+		"keystoreLock":      generalhandlers.KeystoreLock,
+		"keystorePolicy":    generalhandlers.KeystorePolicy,
+		"keystoreExport":    generalhandlers.KeystoreExport,
+		"getKeystoreAudit":  generalhandlers.GetKeystoreAudit,
+
+		// Watch only wallet handlers
+		"watchWalletCreate":   counterpartyhandlers.WatchWalletCreate,
+		"getWatchWallet":      generalhandlers.GetWatchWallet,
+		"watchWalletBalance":  counterpartyhandlers.WatchWalletBalance,
+		"watchWalletPayments": generalhandlers.WatchWalletPayments,
+		"watchWalletCompose":  counterpartyhandlers.WatchWalletCompose,
+
+		// Fuel wallet handlers
+		"getFuelStatus":    generalhandlers.GetFuelStatus,
+		"fuelWalletLock":   generalhandlers.FuelWalletLock,
+		"fuelWalletUnlock": generalhandlers.FuelWalletUnlock,
 	},
 	"ripple": {
 		// Address handlers
-		"walletCreate":    ripplehandlers.WalletCreate,
-		"walletPayment":   ripplehandlers.WalletSend,
-		"walletBalance":   ripplehandlers.WalletBalance,
-		"activateaddress": ripplehandlers.ActivateAddress,
+		"walletCreate":      ripplehandlers.WalletCreate,
+		"walletPayment":     ripplehandlers.WalletSend,
+		"walletBalance":     ripplehandlers.WalletBalance,
+		"activateaddress":   ripplehandlers.ActivateAddress,
+		"walletNextAddress": ripplehandlers.WalletNextAddress,
+		"signMessage":       ripplehandlers.SignMessage,
+		"verifyMessage":     ripplehandlers.VerifyMessage,
 
 		// Payment handlers
 		"getpayment":       generalhandlers.GetPayment,
 		"paymentbyaddress": generalhandlers.GetPaymentsByAddress,
 
+		// Activation handlers
+		"getactivation":        generalhandlers.GetActivation,
+		"activationsbyaddress": generalhandlers.GetActivationsByAddress,
+
+		// Automatic top up handlers
+		"topUpPolicyCreate":  generalhandlers.TopUpPolicyCreate,
+		"getTopUpPolicy":     generalhandlers.GetTopUpPolicy,
+		"topUpPolicyDisable": generalhandlers.TopUpPolicyDisable,
+		"topUpCapSet":        generalhandlers.TopUpCapSet,
+		"getTopUpCap":        generalhandlers.GetTopUpCap,
+
 		// Asset handlers
-		"asset":    ripplehandlers.AssetCreate,
-		"getasset": generalhandlers.GetAsset,
+		"asset":     ripplehandlers.AssetCreate,
+		"getasset":  generalhandlers.GetAsset,
+		"issuances": ripplehandlers.AssetIssuances,
+		"ledger":    ripplehandlers.AssetLedger,
+
+		// Keystore handlers
+		"getKeystoreWallet": generalhandlers.GetKeystoreWallet,
+		"keystoreUnlock":    generalhandlers.KeystoreUnlock,
+		"keystoreLock":      generalhandlers.KeystoreLock,
+		"keystorePolicy":    generalhandlers.KeystorePolicy,
+		"keystoreExport":    generalhandlers.KeystoreExport,
+		"getKeystoreAudit":  generalhandlers.GetKeystoreAudit,
+
+		// Watch only wallet handlers
+		"watchWalletCreate":   ripplehandlers.WatchWalletCreate,
+		"getWatchWallet":      generalhandlers.GetWatchWallet,
+		"watchWalletBalance":  ripplehandlers.WatchWalletBalance,
+		"watchWalletPayments": generalhandlers.WatchWalletPayments,
+		"watchWalletCompose":  ripplehandlers.WatchWalletCompose,
+
+		// Fuel wallet handlers
+		"getFuelStatus":    generalhandlers.GetFuelStatus,
+		"fuelWalletLock":   generalhandlers.FuelWalletLock,
+		"fuelWalletUnlock": generalhandlers.FuelWalletUnlock,
 
 		// Ripple specific
-		"getrippleledgerstatus": ripplehandlers.GetRippleLedgerStatus,
+		"getrippleledgerstatus":  ripplehandlers.GetRippleLedgerStatus,
+		"getTrustLines":          ripplehandlers.GetTrustLines,
+		"trustLineSet":           ripplehandlers.TrustLineSet,
+		"trustLineRemove":        ripplehandlers.TrustLineRemove,
+		"getAccountSettings":     ripplehandlers.GetAccountSettings,
+		"accountSettingsSet":     ripplehandlers.AccountSettingsSet,
+		"offerCreate":            ripplehandlers.OfferCreate,
+		"getOffer":               ripplehandlers.GetOffer,
+		"offerCancel":            ripplehandlers.OfferCancel,
+		"getOffers":              ripplehandlers.GetOffers,
+		"getOrderBook":           ripplehandlers.GetOrderBook,
+		"escrowCreate":           ripplehandlers.EscrowCreate,
+		"getEscrow":              ripplehandlers.GetEscrow,
+		"escrowFinish":           ripplehandlers.EscrowFinish,
+		"escrowCancel":           ripplehandlers.EscrowCancel,
+		"getEscrows":             ripplehandlers.GetEscrows,
+		"channelCreate":          ripplehandlers.ChannelCreate,
+		"getChannel":             ripplehandlers.GetChannel,
+		"getChannels":            ripplehandlers.GetChannels,
+		"channelFund":            ripplehandlers.ChannelFund,
+		"channelAuthorise":       ripplehandlers.ChannelAuthorise,
+		"channelVerify":          ripplehandlers.ChannelVerify,
+		"channelClaim":           ripplehandlers.ChannelClaim,
+		"multisigWalletCreate":   ripplehandlers.MultisigWalletCreate,
+		"getMultisigWallet":      ripplehandlers.GetMultisigWallet,
+		"multisigProposal":       ripplehandlers.MultisigProposalCreate,
+		"getMultisigProposal":    ripplehandlers.GetMultisigProposal,
+		"multisigSign":           ripplehandlers.MultisigProposalSign,
+		"multisigFinalise":       ripplehandlers.MultisigProposalFinalise,
+		"checkCreate":            ripplehandlers.CheckCreate,
+		"getCheck":               ripplehandlers.GetCheck,
+		"checkCash":              ripplehandlers.CheckCash,
+		"checkCancel":            ripplehandlers.CheckCancel,
+		"getChecks":              ripplehandlers.GetChecks,
+		"getAccountTransactions": ripplehandlers.GetAccountTransactions,
 
 		// Unsupported
-		"address":  ripplehandlers.Unhandled,
-		"dividend": ripplehandlers.Unhandled,
+		"address":        ripplehandlers.Unhandled,
+		"dividend":       ripplehandlers.Unhandled,
+		"walletDiscover": ripplehandlers.Unhandled,
 	},
 }
 
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Number of times an assignment is retried when another Enu instance assigns the same index first
+var maxAssignmentAttempts = 5
+
+// Serialises assignments per derivation key within this process. The unique key on addressassignments guarantees an
+// index is never assigned twice across instances.
+var assignmentMutexes = struct {
+	sync.Mutex
+	m map[string]*sync.Mutex
+}{m: make(map[string]*sync.Mutex)}
+
+// Returns the next derivation index of the derivation key, or firstIndex if no addresses have been assigned
+func getNextAddressIndex(c context.Context, accessKey string, blockchainId string, derivationKey string, firstIndex uint32) (uint32, error) {
+	stmt, err := Db.Prepare("select max(derivationIndex) from addressassignments where accessKey=? and blockchainId=? and derivationKey=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var maxIndex sql.NullInt64
+	if err := stmt.QueryRow(accessKey, blockchainId, derivationKey).Scan(&maxIndex); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return 0, err
+	}
+
+	// No addresses have been assigned
+	if maxIndex.Valid == false || uint32(maxIndex.Int64)+1 < firstIndex {
+		return firstIndex, nil
+	}
+
+	return uint32(maxIndex.Int64) + 1, nil
+}
+
+// Assigns the next unused derivation index of the wallet to the label. The derivation key identifies the derivation
+// path, ie the xpub or public generator, or the walletId of a custodial wallet. The address at the index is returned by
+// derive. An index is never assigned twice even if requests are made concurrently.
+func AssignNextAddress(c context.Context, accessKey string, blockchainId string, walletId string, derivationKey string, firstIndex uint32, label string, derive func(index uint32) (string, error)) (enulib.AddressAssignment, error) {
+	if isInit == false {
+		Init()
+	}
+
+	assignment := enulib.AddressAssignment{WalletId: walletId, BlockchainId: blockchainId, Label: label}
+
+	assignmentMutexes.Lock()
+	mutexKey := accessKey + blockchainId + derivationKey
+	if assignmentMutexes.m[mutexKey] == nil {
+		assignmentMutexes.m[mutexKey] = new(sync.Mutex)
+	}
+	mutex := assignmentMutexes.m[mutexKey]
+	assignmentMutexes.Unlock()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	stmt, err := Db.Prepare("insert into addressassignments(accessKey, blockchainId, walletId, derivationKey, derivationIndex, address, label) values(?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return assignment, err
+	}
+	defer stmt.Close()
+
+	for attempt := 0; attempt < maxAssignmentAttempts; attempt++ {
+		index, err := getNextAddressIndex(c, accessKey, blockchainId, derivationKey, firstIndex)
+		if err != nil {
+			return assignment, err
+		}
+
+		address, err := derive(index)
+		if err != nil {
+			return assignment, err
+		}
+
+		// The insert fails on the unique key if another instance has assigned the index since it was read
+		_, err = stmt.Exec(accessKey, blockchainId, walletId, derivationKey, index, address, label)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Failed to assign index %d of %s, retrying. Reason: %s", index, derivationKey, err.Error())
+			continue
+		}
+
+		assignment.Index = index
+		assignment.Address = address
+
+		return assignment, nil
+	}
+
+	return assignment, errors.New("Unable to assign the next address")
+}
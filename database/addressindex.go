@@ -0,0 +1,81 @@
+package database
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/log"
+)
+
+// Persistent store of HD wallet address indexes. Wallets are identified by a hash of the seed.
+// Implements counterpartycrypto.AddressIndexStore. Lookups are cached in memory since the mapping never changes.
+type AddressIndexStore struct {
+	sync.RWMutex
+	cache map[string]uint32
+}
+
+func NewAddressIndexStore() *AddressIndexStore {
+	return &AddressIndexStore{cache: make(map[string]uint32)}
+}
+
+func (s *AddressIndexStore) GetAddressIndex(seedHash string, addressType string, address string) (uint32, bool) {
+	s.RLock()
+	index, ok := s.cache[seedHash+addressType+address]
+	s.RUnlock()
+
+	if ok {
+		return index, true
+	}
+
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select addressIndex from addressindexes where seedHash=? and addressType=? and address=?")
+	if err != nil {
+		log.Fluentf(consts.LOGERROR, "Failed to prepare statement. Reason: %s", err.Error())
+		return 0, false
+	}
+	defer stmt.Close()
+
+	if err := stmt.QueryRow(seedHash, addressType, address).Scan(&index); err == sql.ErrNoRows {
+		return 0, false
+	} else if err != nil {
+		log.Fluentf(consts.LOGERROR, "Failed to Scan. Reason: %s", err.Error())
+		return 0, false
+	}
+
+	s.Lock()
+	s.cache[seedHash+addressType+address] = index
+	s.Unlock()
+
+	return index, true
+}
+
+func (s *AddressIndexStore) PutAddressIndex(seedHash string, addressType string, index uint32, address string) {
+	s.Lock()
+	_, ok := s.cache[seedHash+addressType+address]
+	s.cache[seedHash+addressType+address] = index
+	s.Unlock()
+
+	if ok {
+		return
+	}
+
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("insert ignore into addressindexes(seedHash, addressType, addressIndex, address) values(?, ?, ?, ?)")
+	if err != nil {
+		log.Fluentf(consts.LOGERROR, "Failed to prepare statement. Reason: %s", err.Error())
+		return
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(seedHash, addressType, index, address)
+	if err != nil {
+		log.Fluentf(consts.LOGERROR, "Failed to insert. Reason: %s", err.Error())
+	}
+}
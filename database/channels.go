@@ -0,0 +1,243 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+const channelColumns = "channelId, blockchainId, address, destination, destinationTag, publicKey, amount, authorisedAmount, claimSignature, claimedAmount, settleDelay, cancelAfter, expiration, txFee, broadcastTxId, claimTxId, closeTxId, status, errorCode, errorDescription"
+
+// An open payment channel together with the access key which created it and the custodial wallet, if any, which
+// signs for its address
+type ChannelRecord struct {
+	AccessKey string
+	WalletId  string
+	Channel   enulib.RippleChannel
+}
+
+func scanChannel(row offerScanner, extra ...interface{}) (enulib.RippleChannel, error) {
+	var channel enulib.RippleChannel
+	var channelId []byte
+	var blockchainId []byte
+	var address []byte
+	var destination []byte
+	var destinationTag sql.NullInt64
+	var publicKey []byte
+	var claimSignature []byte
+	var cancelAfter sql.NullInt64
+	var expiration sql.NullInt64
+	var txFee sql.NullInt64
+	var broadcastTxId []byte
+	var claimTxId []byte
+	var closeTxId []byte
+	var status []byte
+	var errorCode sql.NullInt64
+	var errorMessage []byte
+
+	dest := []interface{}{&channelId, &blockchainId, &address, &destination, &destinationTag, &publicKey, &channel.Quantity, &channel.AuthorisedQuantity, &claimSignature, &channel.ClaimedQuantity, &channel.SettleDelay, &cancelAfter, &expiration, &txFee, &broadcastTxId, &claimTxId, &closeTxId, &status, &errorCode, &errorMessage}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return channel, err
+	}
+
+	channel.ChannelId = string(channelId)
+	channel.BlockchainId = string(blockchainId)
+	channel.Address = string(address)
+	channel.Destination = string(destination)
+	channel.DestinationTag = nullTag(destinationTag)
+	channel.PublicKey = string(publicKey)
+	channel.ClaimSignature = string(claimSignature)
+	channel.CancelAfter = cancelAfter.Int64
+	channel.Expiration = expiration.Int64
+	channel.TxFee = txFee.Int64
+	channel.BroadcastTxId = string(broadcastTxId)
+	channel.ClaimTxId = string(claimTxId)
+	channel.CloseTxId = string(closeTxId)
+	channel.Status = string(status)
+	channel.ErrorCode = errorCode.Int64
+	channel.ErrorMessage = string(errorMessage)
+
+	return channel, nil
+}
+
+// Inserts a payment channel into the paymentChannels database. The walletId is the custodial wallet, if any, which
+// signs for the address so the channel can be closed automatically
+func InsertChannel(c context.Context, accessKey string, walletId string, channel enulib.RippleChannel) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("insert into paymentChannels(accessKey, blockchainId, channelId, walletId, address, destination, destinationTag, publicKey, amount, authorisedAmount, claimSignature, claimedAmount, settleDelay, cancelAfter, expiration, txFee, broadcastTxId, claimTxId, closeTxId, status, errorCode, errorDescription) values(?, ?, ?, ?, ?, ?, ?, ?, ?, 0, '', 0, ?, ?, 0, ?, ?, '', '', ?, ?, ?)")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(accessKey, channel.BlockchainId, channel.ChannelId, walletId, channel.Address, channel.Destination, tagValue(channel.DestinationTag), channel.PublicKey, channel.Quantity, channel.SettleDelay, channel.CancelAfter, channel.TxFee, channel.BroadcastTxId, channel.Status, channel.ErrorCode, channel.ErrorMessage)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to insert. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func GetChannelByChannelId(c context.Context, accessKey string, channelId string) enulib.RippleChannel {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select " + channelColumns + " from paymentChannels where accessKey=? and channelId=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return enulib.RippleChannel{ChannelId: channelId, Status: consts.NotFound}
+	}
+	defer stmt.Close()
+
+	channel, err := scanChannel(stmt.QueryRow(accessKey, channelId))
+	if err == sql.ErrNoRows {
+		return enulib.RippleChannel{ChannelId: channelId, Status: consts.NotFound}
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return enulib.RippleChannel{ChannelId: channelId, Status: consts.NotFound}
+	}
+
+	return channel
+}
+
+// Returns the payment channels created by the access key from the address, the most recent first
+func GetChannelsByAddress(c context.Context, accessKey string, address string) []enulib.RippleChannel {
+	var result []enulib.RippleChannel
+
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select " + channelColumns + " from paymentChannels where accessKey=? and address=? order by rowid desc")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return result
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(accessKey, address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to query. Reason: %s", err.Error())
+		return result
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		channel, err := scanChannel(rows)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+			return result
+		}
+
+		result = append(result, channel)
+	}
+
+	return result
+}
+
+// Returns the open and closing payment channels of every access key
+func GetOpenChannels(c context.Context) ([]ChannelRecord, error) {
+	var result []ChannelRecord
+
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select " + channelColumns + ", accessKey, walletId from paymentChannels where status in ('open', 'closing')")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return result, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query()
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to query. Reason: %s", err.Error())
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var accessKey []byte
+		var walletId []byte
+
+		channel, err := scanChannel(rows, &accessKey, &walletId)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+			return result, err
+		}
+
+		result = append(result, ChannelRecord{AccessKey: string(accessKey), WalletId: string(walletId), Channel: channel})
+	}
+
+	return result, nil
+}
+
+func updateChannel(c context.Context, accessKey string, channelId string, query string, args ...interface{}) error {
+	if isInit == false {
+		Init()
+	}
+
+	channel := GetChannelByChannelId(c, accessKey, channelId)
+
+	if channel.Status == consts.NotFound {
+		errorString := fmt.Sprintf("Payment channel does not exist or cannot be accessed by %s\n", accessKey)
+
+		return errors.New(errorString)
+	}
+
+	stmt, err := Db.Prepare(query + " where accessKey=? and channelId=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(append(args, accessKey, channelId)...)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to update. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Adds the quantity funded to the channel and, if given, records the new expiration
+func UpdateChannelFundedByChannelId(c context.Context, accessKey string, channelId string, quantity uint64, expiration int64) error {
+	if expiration != 0 {
+		return updateChannel(c, accessKey, channelId, "update paymentChannels set amount=amount+?, expiration=?", quantity, expiration)
+	}
+
+	return updateChannel(c, accessKey, channelId, "update paymentChannels set amount=amount+?", quantity)
+}
+
+// Records the cumulative quantity authorised by the latest claim signed off ledger and its signature
+func UpdateChannelAuthorisedByChannelId(c context.Context, accessKey string, channelId string, authorisedQuantity uint64, claimSignature string) error {
+	return updateChannel(c, accessKey, channelId, "update paymentChannels set authorisedAmount=?, claimSignature=?", authorisedQuantity, claimSignature)
+}
+
+// Records the cumulative quantity redeemed on the ledger by the claim tx
+func UpdateChannelClaimedByChannelId(c context.Context, accessKey string, channelId string, claimedQuantity uint64, claimTxId string) error {
+	return updateChannel(c, accessKey, channelId, "update paymentChannels set claimedAmount=?, claimTxId=?", claimedQuantity, claimTxId)
+}
+
+// Records the tx which requested the channel to close. The channel is closing until it is removed from the ledger
+func UpdateChannelClosingByChannelId(c context.Context, accessKey string, channelId string, closeTxId string, expiration int64) error {
+	return updateChannel(c, accessKey, channelId, "update paymentChannels set status='closing', closeTxId=?, expiration=?", closeTxId, expiration)
+}
+
+func UpdateChannelClosedByChannelId(c context.Context, accessKey string, channelId string) error {
+	return updateChannel(c, accessKey, channelId, "update paymentChannels set status='closed'")
+}
@@ -0,0 +1,160 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+const checkColumns = "checkId, blockchainId, address, destination, destinationTag, asset, issuer, amount, expiration, invoiceId, ledgerCheckId, txFee, broadcastTxId, cashTxId, cancelTxId, status, errorCode, errorDescription"
+
+func scanCheck(row offerScanner) (enulib.RippleCheck, error) {
+	var check enulib.RippleCheck
+	var checkId []byte
+	var blockchainId []byte
+	var address []byte
+	var destination []byte
+	var destinationTag sql.NullInt64
+	var asset []byte
+	var issuer []byte
+	var expiration sql.NullInt64
+	var invoiceId []byte
+	var ledgerCheckId []byte
+	var txFee sql.NullInt64
+	var broadcastTxId []byte
+	var cashTxId []byte
+	var cancelTxId []byte
+	var status []byte
+	var errorCode sql.NullInt64
+	var errorMessage []byte
+
+	if err := row.Scan(&checkId, &blockchainId, &address, &destination, &destinationTag, &asset, &issuer, &check.Quantity, &expiration, &invoiceId, &ledgerCheckId, &txFee, &broadcastTxId, &cashTxId, &cancelTxId, &status, &errorCode, &errorMessage); err != nil {
+		return check, err
+	}
+
+	check.CheckId = string(checkId)
+	check.BlockchainId = string(blockchainId)
+	check.Address = string(address)
+	check.Destination = string(destination)
+	check.DestinationTag = nullTag(destinationTag)
+	check.Asset = string(asset)
+	check.Issuer = string(issuer)
+	check.Expiration = expiration.Int64
+	check.InvoiceId = string(invoiceId)
+	check.LedgerCheckId = string(ledgerCheckId)
+	check.TxFee = txFee.Int64
+	check.BroadcastTxId = string(broadcastTxId)
+	check.CashTxId = string(cashTxId)
+	check.CancelTxId = string(cancelTxId)
+	check.Status = string(status)
+	check.ErrorCode = errorCode.Int64
+	check.ErrorMessage = string(errorMessage)
+
+	return check, nil
+}
+
+// Inserts a check into the checks database
+func InsertCheck(c context.Context, accessKey string, check enulib.RippleCheck) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("insert into checks(accessKey, blockchainId, checkId, address, destination, destinationTag, asset, issuer, amount, expiration, invoiceId, ledgerCheckId, txFee, broadcastTxId, cashTxId, cancelTxId, status) values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, '', ?, '', '', '', ?)")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(accessKey, check.BlockchainId, check.CheckId, check.Address, check.Destination, tagValue(check.DestinationTag), check.Asset, check.Issuer, check.Quantity, check.Expiration, check.InvoiceId, check.TxFee, check.Status)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to insert. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func getCheck(c context.Context, accessKey string, column string, id string) enulib.RippleCheck {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select " + checkColumns + " from checks where accessKey=? and " + column + "=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return enulib.RippleCheck{CheckId: id, Status: consts.NotFound}
+	}
+	defer stmt.Close()
+
+	check, err := scanCheck(stmt.QueryRow(accessKey, id))
+	if err == sql.ErrNoRows {
+		return enulib.RippleCheck{CheckId: id, Status: consts.NotFound}
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return enulib.RippleCheck{CheckId: id, Status: consts.NotFound}
+	}
+
+	return check
+}
+
+func GetCheckByCheckId(c context.Context, accessKey string, checkId string) enulib.RippleCheck {
+	return getCheck(c, accessKey, "checkId", checkId)
+}
+
+// Returns the check created by the access key with the id of the check in the ledger
+func GetCheckByLedgerCheckId(c context.Context, accessKey string, ledgerCheckId string) enulib.RippleCheck {
+	return getCheck(c, accessKey, "ledgerCheckId", ledgerCheckId)
+}
+
+func updateCheck(c context.Context, accessKey string, checkId string, query string, args ...interface{}) error {
+	if isInit == false {
+		Init()
+	}
+
+	check := GetCheckByCheckId(c, accessKey, checkId)
+
+	if check.Status == consts.NotFound {
+		errorString := fmt.Sprintf("Check does not exist or cannot be accessed by %s\n", accessKey)
+
+		return errors.New(errorString)
+	}
+
+	stmt, err := Db.Prepare(query + " where accessKey=? and checkId=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(append(args, accessKey, checkId)...)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to update. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Records the tx which created the check and the id of the check in the ledger
+func UpdateCheckCompleteByCheckId(c context.Context, accessKey string, checkId string, txId string, ledgerCheckId string) error {
+	return updateCheck(c, accessKey, checkId, "update checks set status='complete', broadcastTxId=?, ledgerCheckId=?", txId, ledgerCheckId)
+}
+
+func UpdateCheckWithErrorByCheckId(c context.Context, accessKey string, checkId string, txId string, errorCode int64, errorDescription string) error {
+	return updateCheck(c, accessKey, checkId, "update checks set status='error', broadcastTxId=?, errorCode=?, errorDescription=?", txId, errorCode, errorDescription)
+}
+
+func UpdateCheckCashedByCheckId(c context.Context, accessKey string, checkId string, cashTxId string) error {
+	return updateCheck(c, accessKey, checkId, "update checks set status='cashed', cashTxId=?", cashTxId)
+}
+
+func UpdateCheckCancelledByCheckId(c context.Context, accessKey string, checkId string, cancelTxId string) error {
+	return updateCheck(c, accessKey, checkId, "update checks set status='cancelled', cancelTxId=?", cancelTxId)
+}
@@ -448,7 +448,7 @@ func GetPaymentByPaymentId(c context.Context, accessKey string, paymentId string
 	}
 
 	//	 Query DB
-	stmt, err := Db.Prepare("select rowId, blockId, blockchainId, sourceTxId, sourceAddress, destinationAddress, outAsset, issuer, outAmount, status, lastUpdatedBlockId, txFee, broadcastTxId, paymentTag, errorCode, errorDescription from payments where sourceTxid=? and accessKey=?")
+	stmt, err := Db.Prepare("select rowId, blockId, blockchainId, sourceTxId, sourceAddress, destinationAddress, outAsset, issuer, outAmount, status, lastUpdatedBlockId, txFee, broadcastTxId, paymentTag, errorCode, errorDescription, sendAsset, sendIssuer, sendMax, deliveredAmount, destinationTag, sourceTag, memos from payments where sourceTxid=? and accessKey=?")
 	if err != nil {
 		log.Println("Failed to prepare statement. Reason: ")
 		panic(err.Error())
@@ -478,8 +478,15 @@ func GetPaymentByPaymentId(c context.Context, accessKey string, paymentId string
 	var paymentTag []byte
 	var errorCode sql.NullInt64
 	var errorMessage []byte
-
-	if err := row.Scan(&rowId, &blockId, &blockchainId, &sourceTxId, &sourceAddress, &destinationAddress, &asset, &issuer, &amount, &status, &lastUpdatedBlockId, &txFee, &broadcastTxId, &paymentTag, &errorCode, &errorMessage); err == sql.ErrNoRows {
+	var sendAsset []byte
+	var sendIssuer []byte
+	var sendMax sql.NullInt64
+	var deliveredAmount sql.NullInt64
+	var destinationTag sql.NullInt64
+	var sourceTag sql.NullInt64
+	var memos []byte
+
+	if err := row.Scan(&rowId, &blockId, &blockchainId, &sourceTxId, &sourceAddress, &destinationAddress, &asset, &issuer, &amount, &status, &lastUpdatedBlockId, &txFee, &broadcastTxId, &paymentTag, &errorCode, &errorMessage, &sendAsset, &sendIssuer, &sendMax, &deliveredAmount, &destinationTag, &sourceTag, &memos); err == sql.ErrNoRows {
 		payment = enulib.SimplePayment{}
 		if err.Error() == "sql: no rows in result set" {
 			payment.PaymentId = paymentId
@@ -491,7 +498,7 @@ func GetPaymentByPaymentId(c context.Context, accessKey string, paymentId string
 		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
 	}
 
-	payment = enulib.SimplePayment{BlockchainId: string(blockchainId), SourceAddress: string(sourceAddress), DestinationAddress: string(destinationAddress), Asset: string(asset), Amount: amount, PaymentId: string(sourceTxId), Status: string(status), BroadcastTxId: string(broadcastTxId), TxFee: txFee, ErrorCode: errorCode.Int64, ErrorMessage: string(errorMessage)}
+	payment = enulib.SimplePayment{BlockchainId: string(blockchainId), SourceAddress: string(sourceAddress), DestinationAddress: string(destinationAddress), Asset: string(asset), Amount: amount, PaymentId: string(sourceTxId), Status: string(status), BroadcastTxId: string(broadcastTxId), TxFee: txFee, ErrorCode: errorCode.Int64, ErrorMessage: string(errorMessage), SendAsset: string(sendAsset), SendIssuer: string(sendIssuer), SendMax: uint64(sendMax.Int64), DeliveredAmount: uint64(deliveredAmount.Int64), DestinationTag: nullTag(destinationTag), SourceTag: nullTag(sourceTag), Memos: decodeMemos(c, memos)}
 
 	return payment
 }
@@ -548,6 +555,29 @@ func GetPaymentByPaymentTag(c context.Context, accessKey string, paymentTag stri
 	return payment
 }
 
+// Returns true if the access key has already recorded a payment with the tx
+func PaymentExistsByBroadcastTxId(c context.Context, accessKey string, txId string) (bool, error) {
+	var count int64
+
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select count(*) from payments where accessKey=? and broadcastTxId=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return false, err
+	}
+	defer stmt.Close()
+
+	if err := stmt.QueryRow(accessKey, txId).Scan(&count); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
 func GetPaymentsByAddress(c context.Context, accessKey string, address string) []enulib.SimplePayment {
 	var result []enulib.SimplePayment
 
@@ -557,7 +587,7 @@ func GetPaymentsByAddress(c context.Context, accessKey string, address string) [
 
 	//	 Query DB
 	//	log.Fluentf(consts.LOGDEBUG, "select rowId, blockId, blockchainId, sourceTxId, sourceAddress, destinationAddress, outAsset, issuer, outAmount, status, lastUpdatedBlockId, txFee, broadcastTxId, paymentTag, errorDescription from payments where accessKey = %s and (sourceAddress = %s or destinationAddress = %s)", accessKey, address, address)
-	stmt, err := Db.Prepare("select rowId, blockId, blockchainId, sourceTxId, sourceAddress, destinationAddress, outAsset, outAmount, issuer, status, lastUpdatedBlockId, txFee, broadcastTxId, paymentTag, errorDescription from payments where accessKey = ? and (sourceAddress = ? or destinationAddress = ?)")
+	stmt, err := Db.Prepare("select rowId, blockId, blockchainId, sourceTxId, sourceAddress, destinationAddress, outAsset, outAmount, issuer, status, lastUpdatedBlockId, txFee, broadcastTxId, paymentTag, errorDescription, sendAsset, sendIssuer, sendMax, deliveredAmount, destinationTag, sourceTag, memos from payments where accessKey = ? and (sourceAddress = ? or destinationAddress = ?)")
 	if err != nil {
 		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
 		return result
@@ -589,8 +619,15 @@ func GetPaymentsByAddress(c context.Context, accessKey string, address string) [
 		var payment enulib.SimplePayment
 		var errorMessage []byte
 		var paymentTag []byte
-
-		if err := rows.Scan(&rowId, &blockId, &blockchainId, &sourceTxId, &sourceAddress, &destinationAddress, &asset, &amount, &issuer, &status, &lastUpdatedBlockId, &txFee, &broadcastTxId, &paymentTag, &errorMessage); err == sql.ErrNoRows {
+		var sendAsset []byte
+		var sendIssuer []byte
+		var sendMax sql.NullInt64
+		var deliveredAmount sql.NullInt64
+		var destinationTag sql.NullInt64
+		var sourceTag sql.NullInt64
+		var memos []byte
+
+		if err := rows.Scan(&rowId, &blockId, &blockchainId, &sourceTxId, &sourceAddress, &destinationAddress, &asset, &amount, &issuer, &status, &lastUpdatedBlockId, &txFee, &broadcastTxId, &paymentTag, &errorMessage, &sendAsset, &sendIssuer, &sendMax, &deliveredAmount, &destinationTag, &sourceTag, &memos); err == sql.ErrNoRows {
 			payment = enulib.SimplePayment{}
 			if err.Error() == "sql: no rows in result set" {
 				payment.Status = consts.NotFound
@@ -599,7 +636,7 @@ func GetPaymentsByAddress(c context.Context, accessKey string, address string) [
 			log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
 		}
 
-		payment = enulib.SimplePayment{BlockchainId: string(blockchainId), SourceAddress: string(sourceAddress), DestinationAddress: string(destinationAddress), Asset: string(asset), Issuer: string(issuer), Amount: amount, PaymentId: string(sourceTxId), Status: string(status), BroadcastTxId: string(broadcastTxId), TxFee: txFee, ErrorMessage: string(errorMessage), PaymentTag: string(paymentTag)}
+		payment = enulib.SimplePayment{BlockchainId: string(blockchainId), SourceAddress: string(sourceAddress), DestinationAddress: string(destinationAddress), Asset: string(asset), Issuer: string(issuer), Amount: amount, PaymentId: string(sourceTxId), Status: string(status), BroadcastTxId: string(broadcastTxId), TxFee: txFee, ErrorMessage: string(errorMessage), PaymentTag: string(paymentTag), SendAsset: string(sendAsset), SendIssuer: string(sendIssuer), SendMax: uint64(sendMax.Int64), DeliveredAmount: uint64(deliveredAmount.Int64), DestinationTag: nullTag(destinationTag), SourceTag: nullTag(sourceTag), Memos: decodeMemos(c, memos)}
 
 		result = append(result, payment)
 	}
@@ -715,6 +752,139 @@ func UpdatePaymentSignedRawTxByPaymentId(c context.Context, accessKey string, pa
 	return nil
 }
 
+// Records the asset the source pays in and the most it may pay for a payment which is exchanged along a path
+func UpdatePaymentSendMaxByPaymentId(c context.Context, accessKey string, paymentId string, sendAsset string, sendIssuer string, sendMax uint64) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("update payments set sendAsset=?, sendIssuer=?, sendMax=? where accessKey=? and sourceTxId = ?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(sendAsset, sendIssuer, sendMax, accessKey, paymentId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to update. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Records the amount the destination received according to the metadata of the validated tx
+func UpdatePaymentDeliveredAmountByPaymentId(c context.Context, accessKey string, paymentId string, deliveredAmount uint64) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("update payments set deliveredAmount=? where accessKey=? and sourceTxId = ?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(deliveredAmount, accessKey, paymentId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to update. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Records the fee paid by the tx of the payment
+func UpdatePaymentTxFeeByPaymentId(c context.Context, accessKey string, paymentId string, txFee uint64) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("update payments set txFee=? where accessKey=? and sourceTxId = ?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(txFee, accessKey, paymentId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to update. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Records the tags and memos given on a Ripple payment. The memos are stored as JSON
+func UpdatePaymentOptionsByPaymentId(c context.Context, accessKey string, paymentId string, destinationTag *uint32, sourceTag *uint32, memos []enulib.RippleMemo) error {
+	var memosJson []byte
+
+	if isInit == false {
+		Init()
+	}
+
+	if len(memos) > 0 {
+		var err error
+
+		memosJson, err = json.Marshal(memos)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Failed to marshal memos. Reason: %s", err.Error())
+			return err
+		}
+	}
+
+	stmt, err := Db.Prepare("update payments set destinationTag=?, sourceTag=?, memos=? where accessKey=? and sourceTxId = ?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(tagValue(destinationTag), tagValue(sourceTag), memosJson, accessKey, paymentId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to update. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Tags which aren't given are stored as NULL since 0 is a valid tag
+func tagValue(tag *uint32) interface{} {
+	if tag == nil {
+		return nil
+	}
+
+	return int64(*tag)
+}
+
+func nullTag(tag sql.NullInt64) *uint32 {
+	if !tag.Valid {
+		return nil
+	}
+
+	result := uint32(tag.Int64)
+
+	return &result
+}
+
+func decodeMemos(c context.Context, memosJson []byte) []enulib.RippleMemo {
+	var memos []enulib.RippleMemo
+
+	if len(memosJson) == 0 {
+		return memos
+	}
+
+	if err := json.Unmarshal(memosJson, &memos); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to unmarshal memos. Reason: %s", err.Error())
+	}
+
+	return memos
+}
+
 // create table userKeys (userId BIGINT, accessKey varchar(64), secret varchar(64), nonce bigint, assetId varchar(100), blockchainId varchar(100), sourceAddress varchar(100))
 // Used to verify if the current request has a nonce > the value stored in the DB
 func GetNonceByAccessKey(accessKey string) int64 {
@@ -1034,67 +1204,139 @@ func InsertActivation(c context.Context, accessKey string, activationId string,
 	defer stmt.Close()
 }
 
-func GetActivationByActivationId(c context.Context, accessKey string, activationId string) map[string]interface{} {
+// Columns of an activation joined with the payment which funded it. The payment doesn't exist until the funding is sent
+const activationColumns = "a.activationId, a.blockchainId, a.addressToActivate, a.amount, a.errorCode, a.errorDescription, p.sourceAddress, p.outAsset, p.outAmount, p.txFee, p.status, p.broadcastTxId, p.errorCode, p.errorDescription from activations a left join payments p on a.activationId = p.sourceTxid and a.accessKey = p.accessKey"
+
+type activationScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanActivation(row activationScanner) (enulib.Activation, error) {
+	var activationId []byte
+	var blockchainId []byte
+	var addressToActivate []byte
+	var amount uint64
+	var activationErrorCode sql.NullInt64
+	var activationErrorMessage []byte
+	var sourceAddress []byte
+	var outAsset []byte
+	var outAmount sql.NullInt64
+	var txFee sql.NullInt64
+	var status []byte
+	var broadcastTxId []byte
+	var errorCode sql.NullInt64
+	var errorMessage []byte
+
+	if err := row.Scan(&activationId, &blockchainId, &addressToActivate, &amount, &activationErrorCode, &activationErrorMessage, &sourceAddress, &outAsset, &outAmount, &txFee, &status, &broadcastTxId, &errorCode, &errorMessage); err != nil {
+		return enulib.Activation{}, err
+	}
+
+	activation := enulib.Activation{ActivationId: string(activationId), BlockchainId: string(blockchainId), Address: string(addressToActivate), Amount: amount, SourceAddress: string(sourceAddress), Asset: string(outAsset), FundingAmount: uint64(outAmount.Int64), TxFee: txFee.Int64, BroadcastTxId: string(broadcastTxId), Status: string(status), ErrorCode: errorCode.Int64, ErrorMessage: string(errorMessage)}
+
+	// The activation failed before the funding payment was created
+	if activation.Status == "" && activationErrorCode.Int64 != 0 {
+		activation.Status = "error"
+		activation.ErrorCode = activationErrorCode.Int64
+		activation.ErrorMessage = string(activationErrorMessage)
+	} else if activation.Status == "" {
+		activation.Status = "valid"
+	}
+
+	return activation, nil
+}
+
+func GetActivationByActivationId(c context.Context, accessKey string, activationId string) enulib.Activation {
 	if isInit == false {
 		Init()
 	}
 
-	requestId := c.Value(consts.RequestIdKey).(string)
-
 	//	 Query DB
-	//	log.FluentfContext(consts.LOGDEBUG, c, "select blockchainId, addressToActivate, amount, a.rowId, sourceAddress, outAsset, outAmount, status, broadcastTxId, errorDescription from activations a, payments p where a.activationId = p.sourceTxid and activationId=%s and a.accessKey=%s", activationId, accessKey)
-	stmt, err := Db.Prepare("select a.blockchainId, addressToActivate, amount, a.rowId, sourceAddress, outAsset, outAmount, status, broadcastTxId, errorDescription from activations a, payments p where a.activationId = p.sourceTxid and activationId=? and a.accessKey=?")
+	stmt, err := Db.Prepare("select " + activationColumns + " where a.activationId=? and a.accessKey=? order by p.rowId desc")
 	if err != nil {
-		log.FluentfContext(consts.LOGERROR, c, err.Error())
-		return map[string]interface{}{}
+		log.FluentfContext(consts.LOGERROR, c, "%s", err.Error())
+		return enulib.Activation{ActivationId: activationId, Status: consts.NotFound}
 	}
 	defer stmt.Close()
 
 	//	 Get row
-	row := stmt.QueryRow(activationId, accessKey)
+	activation, err := scanActivation(stmt.QueryRow(activationId, accessKey))
+	if err == sql.ErrNoRows {
+		return enulib.Activation{ActivationId: activationId, Status: consts.NotFound}
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+	}
+
+	activation.TrustLines = GetTrustAssetsByActivationId(c, accessKey, activationId)
+
+	return activation
+}
+
+// Returns the activations of the address, most recent first
+func GetActivationsByAddress(c context.Context, accessKey string, address string) []enulib.Activation {
+	var result []enulib.Activation
+	var found = make(map[string]bool)
+
+	if isInit == false {
+		Init()
+	}
+
+	//	 Query DB
+	stmt, err := Db.Prepare("select " + activationColumns + " where a.addressToActivate=? and a.accessKey=? order by a.rowId desc, p.rowId desc")
 	if err != nil {
-		log.FluentfContext(consts.LOGERROR, c, err.Error())
-		return map[string]interface{}{}
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return result
 	}
+	defer stmt.Close()
 
-	var blockchainId []byte
-	var addressToActivate []byte
-	var amount uint64
-	var rowId string
-	var sourceAddress []byte
-	var outAsset []byte
-	var outAmount int64
-	var status []byte
-	var broadcastTxId []byte
-	var errorMessage []byte
+	rows, err := stmt.Query(address, accessKey)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to query. Reason: %s", err.Error())
+		return result
+	}
+	defer rows.Close()
 
-	if err := row.Scan(&blockchainId, &addressToActivate, &amount, &rowId, &sourceAddress, &outAsset, &outAmount, &status, &broadcastTxId, &errorMessage); err == sql.ErrNoRows {
-		if err.Error() == "sql: no rows in result set" {
-			var result = map[string]interface{}{
-				"activationId": activationId,
-				"status":       consts.NotFound,
-			}
+	for rows.Next() {
+		activation, err := scanActivation(rows)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+			continue
+		}
 
-			return result
+		// Only the latest funding payment is returned if the funding was retried
+		if found[activation.ActivationId] {
+			continue
 		}
-	} else if err != nil {
-		log.FluentfContext(consts.LOGERROR, c, err.Error())
+		found[activation.ActivationId] = true
+
+		result = append(result, activation)
 	}
 
-	// Return the values
-	var result = map[string]interface{}{
-		"address":       string(addressToActivate),
-		"amount":        amount,
-		"activationId":  string(activationId),
-		"broadcastTxId": string(broadcastTxId),
-		"status":        string(status),
-		"errorMessage":  string(errorMessage),
-		"requestId":     string(requestId),
+	for i := range result {
+		result[i].TrustLines = GetTrustAssetsByActivationId(c, accessKey, result[i].ActivationId)
 	}
 
 	return result
 }
 
+// Records why an activation failed before its funding payment was created
+func UpdateActivationWithError(c context.Context, accessKey string, activationId string, errorCode int64, errorDescription string) {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("update activations set errorCode=?, errorDescription=? where accessKey=? and activationId=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "%s", err.Error())
+		return
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(errorCode, errorDescription, accessKey, activationId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "%s", err.Error())
+	}
+}
+
 // Inserts an activation request into the database
 func InsertTrustAsset(c context.Context, accessKey string, activationId string, blockchainId string, asset string, issuer string, amount uint64) {
 	if isInit == false {
@@ -1116,3 +1358,70 @@ func InsertTrustAsset(c context.Context, accessKey string, activationId string,
 	}
 	defer stmt.Close()
 }
+
+// Records the result of creating the trust line of the activation
+func UpdateTrustAsset(c context.Context, accessKey string, activationId string, asset string, issuer string, status string, broadcastTxId string, errorCode int64, errorDescription string) {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("update trustassets set status=?, broadcastTxId=?, errorCode=?, errorDescription=? where accessKey=? and activationId=? and asset=? and issuer=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "%s", err.Error())
+		return
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(status, broadcastTxId, errorCode, errorDescription, accessKey, activationId, asset, issuer)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "%s", err.Error())
+	}
+}
+
+// Returns the trust lines created by the activation
+func GetTrustAssetsByActivationId(c context.Context, accessKey string, activationId string) []enulib.TrustLine {
+	var result = make([]enulib.TrustLine, 0)
+
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select asset, issuer, trustAmount, status, broadcastTxId, errorCode, errorDescription from trustassets where accessKey=? and activationId=? order by rowId")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return result
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(accessKey, activationId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to query. Reason: %s", err.Error())
+		return result
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var asset []byte
+		var issuer []byte
+		var trustAmount sql.NullInt64
+		var status []byte
+		var broadcastTxId []byte
+		var errorCode sql.NullInt64
+		var errorMessage []byte
+
+		if err := rows.Scan(&asset, &issuer, &trustAmount, &status, &broadcastTxId, &errorCode, &errorMessage); err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+			continue
+		}
+
+		// The result is recorded once the trust line has been submitted
+		trustLine := enulib.TrustLine{Asset: string(asset), Issuer: string(issuer), TrustAmount: uint64(trustAmount.Int64), BroadcastTxId: string(broadcastTxId), Status: string(status), ErrorCode: errorCode.Int64, ErrorMessage: string(errorMessage)}
+		if trustLine.Status == "" {
+			trustLine.Status = "valid"
+		}
+
+		result = append(result, trustLine)
+	}
+
+	return result
+}
@@ -112,20 +112,20 @@ func TestInsertActivationandInsertPayment(t *testing.T) {
 
 	// Retrieve the activation
 	activation := GetActivationByActivationId(ctx, "TestAccessKey", activationId)
-	//		var result = map[string]interface{}{
-	//		"address":       addressToActivate,
-	//		"amount":        amount,
-	//		"activationId":  activationId,
-	//		"broadcastTxId": broadcastTxId,
-	//		"status":        status,
-	//		"errorMessage":  errorMessage,
-	//		"requestId":     requestId,
-	//	}
-	if activation["address"].(string) != "AddressToActive" {
-		t.Errorf("Expected: %s. Got: %s\n", "AddressToActive", activation["address"])
+	if activation.Address != "AddressToActive" {
+		t.Errorf("Expected: %s. Got: %s\n", "AddressToActive", activation.Address)
+	}
+	if activation.Status != "testing" {
+		t.Errorf("Expected: %s. Got: %s\n", "testing", activation.Status)
 	}
-	if activation["status"] != "testing" {
-		t.Errorf("Expected: %s. Got: %s\n", "valid", activation["status"])
+	if activation.SourceAddress != "InternalAddress" || activation.FundingAmount != 1 || activation.TxFee != 1500 {
+		t.Errorf("Expected: %s, %d, %d. Got: %s, %d, %d\n", "InternalAddress", 1, 1500, activation.SourceAddress, activation.FundingAmount, activation.TxFee)
+	}
+
+	// Retrieve the activations of the address
+	activations := GetActivationsByAddress(ctx, "TestAccessKey", "AddressToActive")
+	if len(activations) == 0 || activations[0].ActivationId != activationId {
+		t.Errorf("Expected activation %s to be the latest activation of %s\n", activationId, "AddressToActive")
 	}
 }
 
@@ -0,0 +1,185 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// condition is a reserved word in MySQL so the column is escrowCondition
+const escrowColumns = "escrowId, blockchainId, address, destination, destinationTag, amount, finishAfter, cancelAfter, escrowCondition, offerSequence, txFee, broadcastTxId, finishTxId, cancelTxId, status, errorCode, errorDescription"
+
+func scanEscrow(row offerScanner) (enulib.RippleEscrow, error) {
+	var escrow enulib.RippleEscrow
+	var escrowId []byte
+	var blockchainId []byte
+	var address []byte
+	var destination []byte
+	var destinationTag sql.NullInt64
+	var finishAfter sql.NullInt64
+	var cancelAfter sql.NullInt64
+	var condition []byte
+	var offerSequence sql.NullInt64
+	var txFee sql.NullInt64
+	var broadcastTxId []byte
+	var finishTxId []byte
+	var cancelTxId []byte
+	var status []byte
+	var errorCode sql.NullInt64
+	var errorMessage []byte
+
+	if err := row.Scan(&escrowId, &blockchainId, &address, &destination, &destinationTag, &escrow.Quantity, &finishAfter, &cancelAfter, &condition, &offerSequence, &txFee, &broadcastTxId, &finishTxId, &cancelTxId, &status, &errorCode, &errorMessage); err != nil {
+		return escrow, err
+	}
+
+	escrow.EscrowId = string(escrowId)
+	escrow.BlockchainId = string(blockchainId)
+	escrow.Address = string(address)
+	escrow.Destination = string(destination)
+	escrow.DestinationTag = nullTag(destinationTag)
+	escrow.FinishAfter = finishAfter.Int64
+	escrow.CancelAfter = cancelAfter.Int64
+	escrow.Condition = string(condition)
+	escrow.OfferSequence = uint32(offerSequence.Int64)
+	escrow.TxFee = txFee.Int64
+	escrow.BroadcastTxId = string(broadcastTxId)
+	escrow.FinishTxId = string(finishTxId)
+	escrow.CancelTxId = string(cancelTxId)
+	escrow.Status = string(status)
+	escrow.ErrorCode = errorCode.Int64
+	escrow.ErrorMessage = string(errorMessage)
+
+	return escrow, nil
+}
+
+// Inserts an escrow into the escrows database
+func InsertEscrow(c context.Context, accessKey string, escrow enulib.RippleEscrow) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("insert into escrows(accessKey, blockchainId, escrowId, address, destination, destinationTag, amount, finishAfter, cancelAfter, escrowCondition, txFee, broadcastTxId, finishTxId, cancelTxId, status) values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, '', '', '', ?)")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(accessKey, escrow.BlockchainId, escrow.EscrowId, escrow.Address, escrow.Destination, tagValue(escrow.DestinationTag), escrow.Quantity, escrow.FinishAfter, escrow.CancelAfter, escrow.Condition, escrow.TxFee, escrow.Status)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to insert. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func GetEscrowByEscrowId(c context.Context, accessKey string, escrowId string) enulib.RippleEscrow {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select " + escrowColumns + " from escrows where accessKey=? and escrowId=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return enulib.RippleEscrow{EscrowId: escrowId, Status: consts.NotFound}
+	}
+	defer stmt.Close()
+
+	escrow, err := scanEscrow(stmt.QueryRow(accessKey, escrowId))
+	if err == sql.ErrNoRows {
+		return enulib.RippleEscrow{EscrowId: escrowId, Status: consts.NotFound}
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return enulib.RippleEscrow{EscrowId: escrowId, Status: consts.NotFound}
+	}
+
+	return escrow
+}
+
+// Returns the escrows created by the access key from the address, the most recent first
+func GetEscrowsByAddress(c context.Context, accessKey string, address string) []enulib.RippleEscrow {
+	var result []enulib.RippleEscrow
+
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select " + escrowColumns + " from escrows where accessKey=? and address=? order by rowid desc")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return result
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(accessKey, address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to query. Reason: %s", err.Error())
+		return result
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		escrow, err := scanEscrow(rows)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+			return result
+		}
+
+		result = append(result, escrow)
+	}
+
+	return result
+}
+
+func updateEscrow(c context.Context, accessKey string, escrowId string, query string, args ...interface{}) error {
+	if isInit == false {
+		Init()
+	}
+
+	escrow := GetEscrowByEscrowId(c, accessKey, escrowId)
+
+	if escrow.Status == consts.NotFound {
+		errorString := fmt.Sprintf("Escrow does not exist or cannot be accessed by %s\n", accessKey)
+
+		return errors.New(errorString)
+	}
+
+	stmt, err := Db.Prepare(query + " where accessKey=? and escrowId=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(append(args, accessKey, escrowId)...)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to update. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Records the tx which created the escrow and the sequence which identifies the escrow in the ledger
+func UpdateEscrowCompleteByEscrowId(c context.Context, accessKey string, escrowId string, txId string, offerSequence uint32) error {
+	return updateEscrow(c, accessKey, escrowId, "update escrows set status='complete', broadcastTxId=?, offerSequence=?", txId, offerSequence)
+}
+
+func UpdateEscrowWithErrorByEscrowId(c context.Context, accessKey string, escrowId string, txId string, errorCode int64, errorDescription string) error {
+	return updateEscrow(c, accessKey, escrowId, "update escrows set status='error', broadcastTxId=?, errorCode=?, errorDescription=?", txId, errorCode, errorDescription)
+}
+
+func UpdateEscrowFinishedByEscrowId(c context.Context, accessKey string, escrowId string, finishTxId string) error {
+	return updateEscrow(c, accessKey, escrowId, "update escrows set status='finished', finishTxId=?", finishTxId)
+}
+
+func UpdateEscrowCancelledByEscrowId(c context.Context, accessKey string, escrowId string, cancelTxId string) error {
+	return updateEscrow(c, accessKey, escrowId, "update escrows set status='cancelled', cancelTxId=?", cancelTxId)
+}
@@ -0,0 +1,198 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// The encrypted material of a custodial wallet. This is never returned to clients
+type KeystoreSecrets struct {
+	EncryptedPassphrase string
+	EncryptedKey        string
+	PasswordHash        string
+}
+
+// Inserts a custodial wallet. The passphrase must already be encrypted under the key encryption key
+func InsertKeystoreWallet(c context.Context, accessKey string, wallet enulib.KeystoreWallet, secrets KeystoreSecrets) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("insert into keystorewallets(walletId, accessKey, blockchainId, addresses, encryptedPassphrase, encryptedKey, passwordHash, unlockPolicy, unlockSeconds, unlockedUntil, status) values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'valid')")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	// Perform the insert
+	_, err = stmt.Exec(wallet.WalletId, accessKey, wallet.BlockchainId, strings.Join(wallet.Addresses, ","), secrets.EncryptedPassphrase, secrets.EncryptedKey, secrets.PasswordHash, wallet.UnlockPolicy, wallet.UnlockSeconds, wallet.UnlockedUntil)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to insert. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func GetKeystoreWalletByWalletId(c context.Context, accessKey string, walletId string) (enulib.KeystoreWallet, KeystoreSecrets, error) {
+	var secrets KeystoreSecrets
+
+	if isInit == false {
+		Init()
+	}
+
+	// Set some initial values
+	var wallet = enulib.KeystoreWallet{}
+	wallet.WalletId = walletId
+	wallet.Status = consts.NotFound
+
+	//	 Query DB
+	stmt, err := Db.Prepare("select blockchainId, addresses, encryptedPassphrase, encryptedKey, passwordHash, unlockPolicy, unlockSeconds, unlockedUntil, status from keystorewallets where walletId=? and accessKey=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return wallet, secrets, err
+	}
+	defer stmt.Close()
+
+	//	 Get row
+	row := stmt.QueryRow(walletId, accessKey)
+
+	var blockchainId []byte
+	var addresses []byte
+	var encryptedPassphrase []byte
+	var encryptedKey []byte
+	var passwordHash []byte
+	var unlockPolicy []byte
+	var unlockSeconds int64
+	var unlockedUntil int64
+	var status []byte
+
+	if err := row.Scan(&blockchainId, &addresses, &encryptedPassphrase, &encryptedKey, &passwordHash, &unlockPolicy, &unlockSeconds, &unlockedUntil, &status); err == sql.ErrNoRows {
+		return wallet, secrets, nil
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return wallet, secrets, err
+	}
+
+	wallet = enulib.KeystoreWallet{WalletId: walletId, BlockchainId: string(blockchainId), Addresses: strings.Split(string(addresses), ","), UnlockPolicy: string(unlockPolicy), UnlockSeconds: unlockSeconds, UnlockedUntil: unlockedUntil, Status: string(status)}
+	secrets = KeystoreSecrets{EncryptedPassphrase: string(encryptedPassphrase), EncryptedKey: string(encryptedKey), PasswordHash: string(passwordHash)}
+
+	return wallet, secrets, nil
+}
+
+// Sets the time (unix seconds) until which a wallet with the timed unlock policy may sign. Zero locks the wallet
+func UpdateKeystoreWalletUnlockedUntil(c context.Context, accessKey string, walletId string, unlockedUntil int64) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("update keystorewallets set unlockedUntil=? where accessKey=? and walletId=?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err2 := stmt.Exec(unlockedUntil, accessKey, walletId)
+	if err2 != nil {
+		return err2
+	}
+
+	return nil
+}
+
+// Changes the unlock policy of the wallet. Any existing unlock is revoked
+func UpdateKeystoreWalletUnlockPolicy(c context.Context, accessKey string, walletId string, unlockPolicy string, unlockSeconds int64) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("update keystorewallets set unlockPolicy=?, unlockSeconds=?, unlockedUntil=0 where accessKey=? and walletId=?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err2 := stmt.Exec(unlockPolicy, unlockSeconds, accessKey, walletId)
+	if err2 != nil {
+		return err2
+	}
+
+	return nil
+}
+
+// Records an operation performed on a custodial wallet
+func InsertKeystoreAudit(c context.Context, accessKey string, walletId string, operation string, address string, reference string, result string, description string) error {
+	if isInit == false {
+		Init()
+	}
+
+	var requestId string
+	if c.Value(consts.RequestIdKey) != nil {
+		requestId = c.Value(consts.RequestIdKey).(string)
+	}
+
+	stmt, err := Db.Prepare("insert into keystoreaudit(walletId, accessKey, operation, address, reference, result, description, requestId) values(?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	// Perform the insert
+	_, err = stmt.Exec(walletId, accessKey, operation, address, reference, result, description, requestId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to insert. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Returns the audit trail of the wallet, oldest first
+func GetKeystoreAuditByWalletId(c context.Context, accessKey string, walletId string) ([]enulib.KeystoreAuditEntry, error) {
+	var result []enulib.KeystoreAuditEntry
+
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select operation, address, reference, result, description, requestId, created from keystoreaudit where walletId=? and accessKey=? order by rowid")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return result, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(walletId, accessKey)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to query. Reason: %s", err.Error())
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var operation []byte
+		var address []byte
+		var reference []byte
+		var auditResult []byte
+		var description []byte
+		var requestId []byte
+		var created []byte
+
+		if err := rows.Scan(&operation, &address, &reference, &auditResult, &description, &requestId, &created); err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+			return result, err
+		}
+
+		result = append(result, enulib.KeystoreAuditEntry{Operation: string(operation), Address: string(address), Reference: string(reference), Result: string(auditResult), Description: string(description), RequestId: string(requestId), Created: string(created)})
+	}
+
+	return result, nil
+}
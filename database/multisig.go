@@ -0,0 +1,345 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Inserts a multisig wallet. Only the public keys of the cosigners are stored. For Ripple the public keys are the
+// addresses of the signer accounts and the signer weights are given in the same order
+func InsertMultisigWallet(c context.Context, accessKey string, walletId string, blockchainId string, address string, redeemScript string, requiredSignatures int64, publicKeys []string, signerWeights []uint16) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("insert into multisigwallets(walletId, accessKey, blockchainId, address, redeemScript, requiredSignatures, publicKeys, signerWeights, status) values(?, ?, ?, ?, ?, ?, ?, ?, 'valid')")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	var weights []string
+	for _, weight := range signerWeights {
+		weights = append(weights, strconv.FormatUint(uint64(weight), 10))
+	}
+
+	// Perform the insert
+	_, err = stmt.Exec(walletId, accessKey, blockchainId, address, redeemScript, requiredSignatures, strings.Join(publicKeys, ","), strings.Join(weights, ","))
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to insert. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func GetMultisigWalletByWalletId(c context.Context, accessKey string, walletId string) (enulib.MultisigWallet, error) {
+	if isInit == false {
+		Init()
+	}
+
+	// Set some initial values
+	var wallet = enulib.MultisigWallet{}
+	wallet.WalletId = walletId
+	wallet.Status = consts.NotFound
+
+	//	 Query DB
+	stmt, err := Db.Prepare("select blockchainId, address, redeemScript, requiredSignatures, publicKeys, signerWeights, status from multisigwallets where walletId=? and accessKey=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return wallet, err
+	}
+	defer stmt.Close()
+
+	//	 Get row
+	row := stmt.QueryRow(walletId, accessKey)
+
+	var blockchainId []byte
+	var address []byte
+	var redeemScript []byte
+	var requiredSignatures int64
+	var publicKeys []byte
+	var signerWeights []byte
+	var status []byte
+
+	if err := row.Scan(&blockchainId, &address, &redeemScript, &requiredSignatures, &publicKeys, &signerWeights, &status); err == sql.ErrNoRows {
+		return wallet, nil
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return wallet, err
+	}
+
+	wallet = enulib.MultisigWallet{WalletId: walletId, BlockchainId: string(blockchainId), Address: string(address), RedeemScript: string(redeemScript), RequiredSignatures: requiredSignatures, PublicKeys: strings.Split(string(publicKeys), ","), Status: string(status)}
+
+	if len(signerWeights) > 0 {
+		for _, weight := range strings.Split(string(signerWeights), ",") {
+			w, err := strconv.ParseUint(weight, 10, 16)
+			if err != nil {
+				log.FluentfContext(consts.LOGERROR, c, "Invalid signer weight %s. Reason: %s", weight, err.Error())
+				return wallet, err
+			}
+
+			wallet.SignerWeights = append(wallet.SignerWeights, uint16(w))
+		}
+	}
+
+	return wallet, nil
+}
+
+// Inserts a multisig proposal which is awaiting signatures from the cosigners
+func InsertMultisigProposal(c context.Context, accessKey string, proposal enulib.MultisigProposal) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("insert into multisigproposals(proposalId, walletId, accessKey, proposalType, sourceAddress, destinationAddress, asset, description, quantity, divisible, unsignedTx, signatureHashes, requiredSignatures, status) values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	// Perform the insert
+	_, err = stmt.Exec(proposal.ProposalId, proposal.WalletId, accessKey, proposal.ProposalType, proposal.SourceAddress, proposal.DestinationAddress, proposal.Asset, proposal.Description, proposal.Quantity, proposal.Divisible, proposal.UnsignedTx, strings.Join(proposal.SignatureHashes, ","), proposal.RequiredSignatures, proposal.Status)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to insert. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Returns the proposal including the list of cosigners of the wallet and whether they have signed
+func GetMultisigProposalByProposalId(c context.Context, accessKey string, proposalId string) (enulib.MultisigProposal, error) {
+	if isInit == false {
+		Init()
+	}
+
+	// Set some initial values
+	var proposal = enulib.MultisigProposal{}
+	proposal.ProposalId = proposalId
+	proposal.Status = consts.NotFound
+
+	//	 Query DB
+	stmt, err := Db.Prepare("select walletId, proposalType, sourceAddress, destinationAddress, asset, description, quantity, divisible, unsignedTx, signatureHashes, requiredSignatures, status, broadcastTxId, errorCode, errorDescription from multisigproposals where proposalId=? and accessKey=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return proposal, err
+	}
+	defer stmt.Close()
+
+	//	 Get row
+	row := stmt.QueryRow(proposalId, accessKey)
+
+	var walletId []byte
+	var proposalType []byte
+	var sourceAddress []byte
+	var destinationAddress []byte
+	var asset []byte
+	var description []byte
+	var quantity uint64
+	var divisible bool
+	var unsignedTx []byte
+	var signatureHashes []byte
+	var requiredSignatures int64
+	var status []byte
+	var broadcastTxId []byte
+	var errorCode sql.NullInt64
+	var errorMessage []byte
+
+	if err := row.Scan(&walletId, &proposalType, &sourceAddress, &destinationAddress, &asset, &description, &quantity, &divisible, &unsignedTx, &signatureHashes, &requiredSignatures, &status, &broadcastTxId, &errorCode, &errorMessage); err == sql.ErrNoRows {
+		return proposal, nil
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return proposal, err
+	}
+
+	proposal = enulib.MultisigProposal{ProposalId: proposalId, WalletId: string(walletId), ProposalType: string(proposalType), SourceAddress: string(sourceAddress), DestinationAddress: string(destinationAddress), Asset: string(asset), Description: string(description), Quantity: quantity, Divisible: divisible, UnsignedTx: string(unsignedTx), SignatureHashes: strings.Split(string(signatureHashes), ","), RequiredSignatures: requiredSignatures, Status: string(status), BroadcastTxId: string(broadcastTxId), ErrorCode: errorCode.Int64, ErrorMessage: string(errorMessage)}
+
+	// Add the cosigners
+	wallet, err := GetMultisigWalletByWalletId(c, accessKey, proposal.WalletId)
+	if err != nil {
+		return proposal, err
+	}
+
+	signatures, err := GetMultisigSignaturesByProposalId(c, proposalId)
+	if err != nil {
+		return proposal, err
+	}
+
+	for i, publicKey := range wallet.PublicKeys {
+		_, signed := signatures[publicKey]
+		cosigner := enulib.MultisigCosigner{PublicKey: publicKey, Signed: signed}
+		if i < len(wallet.SignerWeights) {
+			cosigner.Weight = wallet.SignerWeights[i]
+		}
+
+		proposal.Cosigners = append(proposal.Cosigners, cosigner)
+	}
+
+	return proposal, nil
+}
+
+// Records the signatures of a cosigner. The signatures for each input are stored in input order
+func InsertMultisigSignatures(c context.Context, proposalId string, publicKey string, signatures []string) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("insert into multisigsignatures(proposalId, publicKey, signatures) values(?, ?, ?)")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	// Perform the insert
+	_, err = stmt.Exec(proposalId, publicKey, strings.Join(signatures, ","))
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to insert. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Returns the signatures collected for the proposal keyed by the public key of the cosigner
+func GetMultisigSignaturesByProposalId(c context.Context, proposalId string) (map[string][]string, error) {
+	var result = make(map[string][]string)
+
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select publicKey, signatures from multisigsignatures where proposalId=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return result, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(proposalId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to query. Reason: %s", err.Error())
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var publicKey []byte
+		var signatures []byte
+
+		if err := rows.Scan(&publicKey, &signatures); err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+			return result, err
+		}
+
+		result[string(publicKey)] = strings.Split(string(signatures), ",")
+	}
+
+	return result, nil
+}
+
+func UpdateMultisigProposalStatusByProposalId(c context.Context, accessKey string, proposalId string, status string) error {
+	if isInit == false {
+		Init()
+	}
+
+	proposal, err := GetMultisigProposalByProposalId(c, accessKey, proposalId)
+	if err != nil {
+		return err
+	}
+
+	if proposal.Status == consts.NotFound {
+		errorString := fmt.Sprintf("Proposal does not exist or cannot be accessed by %s\n", accessKey)
+
+		return errors.New(errorString)
+	}
+
+	stmt, err := Db.Prepare("update multisigproposals set status=? where accessKey=? and proposalId = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err2 := stmt.Exec(status, accessKey, proposalId)
+	if err2 != nil {
+		return err2
+	}
+
+	return nil
+}
+
+// Atomically moves the proposal from one status to another. Returns false if the proposal wasn't in the from status,
+// eg because a concurrent request has already claimed it
+func ClaimMultisigProposalByProposalId(c context.Context, accessKey string, proposalId string, fromStatus string, toStatus string) (bool, error) {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("update multisigproposals set status=? where accessKey=? and proposalId = ? and status=?")
+	if err != nil {
+		return false, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(toStatus, accessKey, proposalId, fromStatus)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+func UpdateMultisigProposalWithErrorByProposalId(c context.Context, accessKey string, proposalId string, errorCode int64, errorDescription string) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("update multisigproposals set status='error', errorCode=?, errorDescription=? where accessKey=? and proposalId = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err2 := stmt.Exec(errorCode, errorDescription, accessKey, proposalId)
+	if err2 != nil {
+		return err2
+	}
+
+	return nil
+}
+
+func UpdateMultisigProposalCompleteByProposalId(c context.Context, accessKey string, proposalId string, signedRawTx string, txId string) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("update multisigproposals set status='complete', signedRawTx=?, broadcastTxId=? where accessKey=? and proposalId = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err2 := stmt.Exec(signedRawTx, txId, accessKey, proposalId)
+	if err2 != nil {
+		return err2
+	}
+
+	return nil
+}
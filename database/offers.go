@@ -0,0 +1,180 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+const offerColumns = "offerId, blockchainId, address, takerGetsAsset, takerGetsIssuer, takerGetsAmount, takerPaysAsset, takerPaysIssuer, takerPaysAmount, flags, offerSequence, txFee, broadcastTxId, cancelTxId, status, errorCode, errorDescription"
+
+type offerScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOffer(row offerScanner) (enulib.RippleOffer, error) {
+	var offer enulib.RippleOffer
+	var offerId []byte
+	var blockchainId []byte
+	var address []byte
+	var takerGetsAsset []byte
+	var takerGetsIssuer []byte
+	var takerPaysAsset []byte
+	var takerPaysIssuer []byte
+	var offerSequence sql.NullInt64
+	var txFee sql.NullInt64
+	var broadcastTxId []byte
+	var cancelTxId []byte
+	var status []byte
+	var errorCode sql.NullInt64
+	var errorMessage []byte
+
+	if err := row.Scan(&offerId, &blockchainId, &address, &takerGetsAsset, &takerGetsIssuer, &offer.TakerGets.Quantity, &takerPaysAsset, &takerPaysIssuer, &offer.TakerPays.Quantity, &offer.Flags, &offerSequence, &txFee, &broadcastTxId, &cancelTxId, &status, &errorCode, &errorMessage); err != nil {
+		return offer, err
+	}
+
+	offer.OfferId = string(offerId)
+	offer.BlockchainId = string(blockchainId)
+	offer.Address = string(address)
+	offer.TakerGets.Asset = string(takerGetsAsset)
+	offer.TakerGets.Issuer = string(takerGetsIssuer)
+	offer.TakerPays.Asset = string(takerPaysAsset)
+	offer.TakerPays.Issuer = string(takerPaysIssuer)
+	offer.OfferSequence = uint32(offerSequence.Int64)
+	offer.TxFee = txFee.Int64
+	offer.BroadcastTxId = string(broadcastTxId)
+	offer.CancelTxId = string(cancelTxId)
+	offer.Status = string(status)
+	offer.ErrorCode = errorCode.Int64
+	offer.ErrorMessage = string(errorMessage)
+
+	return offer, nil
+}
+
+// Inserts an offer into the offers database
+func InsertOffer(c context.Context, accessKey string, offer enulib.RippleOffer) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("insert into offers(accessKey, blockchainId, offerId, address, takerGetsAsset, takerGetsIssuer, takerGetsAmount, takerPaysAsset, takerPaysIssuer, takerPaysAmount, flags, txFee, broadcastTxId, cancelTxId, status) values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, '', '', ?)")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(accessKey, offer.BlockchainId, offer.OfferId, offer.Address, offer.TakerGets.Asset, offer.TakerGets.Issuer, offer.TakerGets.Quantity, offer.TakerPays.Asset, offer.TakerPays.Issuer, offer.TakerPays.Quantity, offer.Flags, offer.TxFee, offer.Status)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to insert. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func GetOfferByOfferId(c context.Context, accessKey string, offerId string) enulib.RippleOffer {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select " + offerColumns + " from offers where accessKey=? and offerId=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return enulib.RippleOffer{OfferId: offerId, Status: consts.NotFound}
+	}
+	defer stmt.Close()
+
+	offer, err := scanOffer(stmt.QueryRow(accessKey, offerId))
+	if err == sql.ErrNoRows {
+		return enulib.RippleOffer{OfferId: offerId, Status: consts.NotFound}
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return enulib.RippleOffer{OfferId: offerId, Status: consts.NotFound}
+	}
+
+	return offer
+}
+
+// Returns the offers placed by the access key from the address, the most recent first
+func GetOffersByAddress(c context.Context, accessKey string, address string) []enulib.RippleOffer {
+	var result []enulib.RippleOffer
+
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select " + offerColumns + " from offers where accessKey=? and address=? order by rowid desc")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return result
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(accessKey, address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to query. Reason: %s", err.Error())
+		return result
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		offer, err := scanOffer(rows)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+			return result
+		}
+
+		result = append(result, offer)
+	}
+
+	return result
+}
+
+func updateOffer(c context.Context, accessKey string, offerId string, query string, args ...interface{}) error {
+	if isInit == false {
+		Init()
+	}
+
+	offer := GetOfferByOfferId(c, accessKey, offerId)
+
+	if offer.Status == consts.NotFound {
+		errorString := fmt.Sprintf("Offer does not exist or cannot be accessed by %s\n", accessKey)
+
+		return errors.New(errorString)
+	}
+
+	stmt, err := Db.Prepare(query + " where accessKey=? and offerId=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(append(args, accessKey, offerId)...)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to update. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Records the tx which placed the offer and the sequence which identifies the offer in the ledger
+func UpdateOfferCompleteByOfferId(c context.Context, accessKey string, offerId string, txId string, offerSequence uint32) error {
+	return updateOffer(c, accessKey, offerId, "update offers set status='complete', broadcastTxId=?, offerSequence=?", txId, offerSequence)
+}
+
+func UpdateOfferWithErrorByOfferId(c context.Context, accessKey string, offerId string, txId string, errorCode int64, errorDescription string) error {
+	return updateOffer(c, accessKey, offerId, "update offers set status='error', broadcastTxId=?, errorCode=?, errorDescription=?", txId, errorCode, errorDescription)
+}
+
+func UpdateOfferCancelledByOfferId(c context.Context, accessKey string, offerId string, cancelTxId string) error {
+	return updateOffer(c, accessKey, offerId, "update offers set status='cancelled', cancelTxId=?", cancelTxId)
+}
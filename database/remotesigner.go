@@ -0,0 +1,37 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Returns true if the access key may sign for the address with the remote signer. The addresses each access key may
+// sign for are granted by adding them to the remotesigneraddresses table
+func IsRemoteSignerAddress(c context.Context, accessKey string, address string) (bool, error) {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select rowid from remotesigneraddresses where accessKey=? and address=? and status='valid'")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return false, err
+	}
+	defer stmt.Close()
+
+	var rowId int64
+	err = stmt.QueryRow(accessKey, address).Scan(&rowId)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,284 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// An enabled top up policy together with the access key which registered it
+type TopUpPolicyRecord struct {
+	AccessKey string
+	Policy    enulib.TopUpPolicy
+}
+
+// Inserts the top up policy of the address or replaces the existing policy
+func UpsertTopUpPolicy(c context.Context, accessKey string, policy enulib.TopUpPolicy) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("insert into topuppolicies(accessKey, blockchainId, address, minimumTransactions, topUpAmount, status) values(?, ?, ?, ?, ?, ?) on duplicate key update minimumTransactions=values(minimumTransactions), topUpAmount=values(topUpAmount), status=values(status)")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(accessKey, policy.BlockchainId, policy.Address, policy.MinimumTransactions, policy.TopUpAmount, policy.Status)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to insert. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func UpdateTopUpPolicyStatus(c context.Context, accessKey string, blockchainId string, address string, status string) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("update topuppolicies set status=? where accessKey=? and blockchainId=? and address=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(status, accessKey, blockchainId, address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to update. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func GetTopUpPolicy(c context.Context, accessKey string, blockchainId string, address string) (enulib.TopUpPolicy, error) {
+	if isInit == false {
+		Init()
+	}
+
+	var policy = enulib.TopUpPolicy{BlockchainId: blockchainId, Address: address, Status: consts.NotFound}
+
+	stmt, err := Db.Prepare("select minimumTransactions, topUpAmount, status from topuppolicies where accessKey=? and blockchainId=? and address=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return policy, err
+	}
+	defer stmt.Close()
+
+	var minimumTransactions uint64
+	var topUpAmount uint64
+	var status []byte
+
+	if err := stmt.QueryRow(accessKey, blockchainId, address).Scan(&minimumTransactions, &topUpAmount, &status); err == sql.ErrNoRows {
+		return policy, nil
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return policy, err
+	}
+
+	policy.MinimumTransactions = minimumTransactions
+	policy.TopUpAmount = topUpAmount
+	policy.Status = string(status)
+
+	return policy, nil
+}
+
+// Returns the enabled top up policies of all access keys
+func GetEnabledTopUpPolicies(c context.Context) ([]TopUpPolicyRecord, error) {
+	var result []TopUpPolicyRecord
+
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select accessKey, blockchainId, address, minimumTransactions, topUpAmount, status from topuppolicies where status='valid' order by rowid")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return result, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query()
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to query. Reason: %s", err.Error())
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var accessKey []byte
+		var blockchainId []byte
+		var address []byte
+		var minimumTransactions uint64
+		var topUpAmount uint64
+		var status []byte
+
+		if err := rows.Scan(&accessKey, &blockchainId, &address, &minimumTransactions, &topUpAmount, &status); err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+			return result, err
+		}
+
+		policy := enulib.TopUpPolicy{BlockchainId: string(blockchainId), Address: string(address), MinimumTransactions: minimumTransactions, TopUpAmount: topUpAmount, Status: string(status)}
+		result = append(result, TopUpPolicyRecord{AccessKey: string(accessKey), Policy: policy})
+	}
+
+	return result, nil
+}
+
+// Sets the maximum number of transactions the access key may be topped up with in any 24 hours
+func UpsertTopUpCap(c context.Context, accessKey string, blockchainId string, dailyCap uint64) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("insert into topupcaps(accessKey, blockchainId, dailyCap) values(?, ?, ?) on duplicate key update dailyCap=values(dailyCap)")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(accessKey, blockchainId, dailyCap)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to insert. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Returns the daily cap of the access key. The second value is false if the access key hasn't set a cap
+func GetTopUpCap(c context.Context, accessKey string, blockchainId string) (uint64, bool, error) {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select dailyCap from topupcaps where accessKey=? and blockchainId=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return 0, false, err
+	}
+	defer stmt.Close()
+
+	var dailyCap uint64
+	if err := stmt.QueryRow(accessKey, blockchainId).Scan(&dailyCap); err == sql.ErrNoRows {
+		return 0, false, nil
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return 0, false, err
+	}
+
+	return dailyCap, true, nil
+}
+
+// Records an automatic top up which was sent as the activation
+func InsertTopUp(c context.Context, accessKey string, blockchainId string, address string, activationId string, amount uint64) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("insert into topups(accessKey, blockchainId, address, activationId, amount) values(?, ?, ?, ?, ?)")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(accessKey, blockchainId, address, activationId, amount)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to insert. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Returns the number of transactions the access key has been topped up with in the last 24 hours
+func GetTopUpSpend(c context.Context, accessKey string, blockchainId string) (uint64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select sum(amount) from topups where accessKey=? and blockchainId=? and created > now() - interval 1 day")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var spent sql.NullInt64
+	if err := stmt.QueryRow(accessKey, blockchainId).Scan(&spent); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return 0, err
+	}
+
+	return uint64(spent.Int64), nil
+}
+
+// Returns the number of seconds since the address was last topped up. The second value is false if it never has been
+func GetSecondsSinceLastTopUp(c context.Context, accessKey string, blockchainId string, address string) (int64, bool, error) {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select timestampdiff(second, max(created), now()) from topups where accessKey=? and blockchainId=? and address=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return 0, false, err
+	}
+	defer stmt.Close()
+
+	var seconds sql.NullInt64
+	if err := stmt.QueryRow(accessKey, blockchainId, address).Scan(&seconds); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return 0, false, err
+	}
+
+	return seconds.Int64, seconds.Valid, nil
+}
+
+// Returns the most recent top ups of the address
+func GetTopUpsByAddress(c context.Context, accessKey string, blockchainId string, address string, limit int) ([]enulib.TopUp, error) {
+	var result = make([]enulib.TopUp, 0)
+
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select activationId, amount, created from topups where accessKey=? and blockchainId=? and address=? order by rowid desc limit ?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return result, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(accessKey, blockchainId, address, limit)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to query. Reason: %s", err.Error())
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var activationId []byte
+		var amount uint64
+		var created []byte
+
+		if err := rows.Scan(&activationId, &amount, &created); err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+			return result, err
+		}
+
+		result = append(result, enulib.TopUp{ActivationId: string(activationId), Amount: amount, Created: string(created)})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Inserts a watch only wallet. Only the xpub or the addresses are stored, the wallet can't sign
+func InsertWatchWallet(c context.Context, accessKey string, wallet enulib.WatchWallet) error {
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("insert into watchwallets(walletId, accessKey, blockchainId, xpub, addresses, status) values(?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return err
+	}
+	defer stmt.Close()
+
+	// Perform the insert
+	_, err = stmt.Exec(wallet.WalletId, accessKey, wallet.BlockchainId, wallet.Xpub, strings.Join(wallet.Addresses, ","), wallet.Status)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to insert. Reason: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func GetWatchWalletByWalletId(c context.Context, accessKey string, walletId string) (enulib.WatchWallet, error) {
+	if isInit == false {
+		Init()
+	}
+
+	// Set some initial values
+	var wallet = enulib.WatchWallet{}
+	wallet.WalletId = walletId
+	wallet.Status = consts.NotFound
+
+	//	 Query DB
+	stmt, err := Db.Prepare("select blockchainId, xpub, addresses, status from watchwallets where walletId=? and accessKey=?")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return wallet, err
+	}
+	defer stmt.Close()
+
+	//	 Get row
+	row := stmt.QueryRow(walletId, accessKey)
+
+	var blockchainId []byte
+	var xpub []byte
+	var addresses []byte
+	var status []byte
+
+	if err := row.Scan(&blockchainId, &xpub, &addresses, &status); err == sql.ErrNoRows {
+		return wallet, nil
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+		return wallet, err
+	}
+
+	wallet = enulib.WatchWallet{WalletId: walletId, BlockchainId: string(blockchainId), Xpub: string(xpub), Addresses: strings.Split(string(addresses), ","), Status: string(status)}
+
+	return wallet, nil
+}
+
+// Returns the access keys which watch each address of the watch only wallets of the blockchain
+func GetWatchedAddresses(c context.Context, blockchainId string) (map[string][]string, error) {
+	var result = make(map[string][]string)
+
+	if isInit == false {
+		Init()
+	}
+
+	stmt, err := Db.Prepare("select accessKey, addresses from watchwallets where blockchainId=? and status='valid'")
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to prepare statement. Reason: %s", err.Error())
+		return result, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(blockchainId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Failed to query. Reason: %s", err.Error())
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var accessKey []byte
+		var addresses []byte
+
+		if err := rows.Scan(&accessKey, &addresses); err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Failed to Scan. Reason: %s", err.Error())
+			return result, err
+		}
+
+		for _, address := range strings.Split(string(addresses), ",") {
+			if address != "" {
+				result[address] = append(result[address], string(accessKey))
+			}
+		}
+	}
+
+	return result, rows.Err()
+}
@@ -4,6 +4,17 @@ import (
 	"log"
 	"net/http"
 	"os"
+
+	"github.com/vennd/enu/counterpartyapi"
+	"github.com/vennd/enu/counterpartycrypto"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/fuel"
+	"github.com/vennd/enu/keystore"
+	"github.com/vennd/enu/paychan"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/ripplewatch"
+	"github.com/vennd/enu/signer"
+	"github.com/vennd/enu/topup"
 )
 
 func main() {
@@ -18,6 +29,31 @@ func main() {
 		env = "unknown host"
 	}
 
+	// Record the index of HD wallet addresses in the database so signing doesn't need to search the derivation path
+	counterpartycrypto.SetAddressIndexStore(database.NewAddressIndexStore())
+	counterpartyapi.Init()
+
+	// Custodial wallets are only available if a key encryption key has been configured
+	keystore.Init()
+
+	// Requests which give neither a passphrase nor a walletId are signed by the remote signer if one is configured
+	signer.Init()
+
+	// Load the wallets which fund address activations and start tracking their balances
+	fuel.Init()
+
+	// Top up the fuel of addresses with an automatic top up policy
+	topup.Init()
+
+	// Close Ripple payment channels before they expire
+	paychan.Init()
+
+	// Follow Ripple ledgers and accounts over the rippled WebSocket API if one is configured
+	rippleapi.InitStream()
+
+	// Record the payments received by the addresses of Ripple watch only wallets
+	ripplewatch.Init()
+
 	router := NewRouter()
 
 	log.Printf("Enu %s API server started on %s", env, hostname)
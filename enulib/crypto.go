@@ -41,3 +41,23 @@ func GenerateRequestId() string {
 func GenerateActivationId() string {
 	return hex.EncodeToString(securecookie.GenerateRandomKey(16))
 }
+
+func GenerateWalletId() string {
+	return hex.EncodeToString(securecookie.GenerateRandomKey(16))
+}
+
+func GenerateOfferId() string {
+	return hex.EncodeToString(securecookie.GenerateRandomKey(16))
+}
+
+func GenerateEscrowId() string {
+	return hex.EncodeToString(securecookie.GenerateRandomKey(16))
+}
+
+func GenerateCheckId() string {
+	return hex.EncodeToString(securecookie.GenerateRandomKey(16))
+}
+
+func GenerateProposalId() string {
+	return hex.EncodeToString(securecookie.GenerateRandomKey(16))
+}
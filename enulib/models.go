@@ -29,6 +29,7 @@ type AddressAmount struct {
 	Address           string  `json:"address"`
 	Quantity          uint64  `json:"quantity"`
 	PercentageHolding float64 `json:"percentageHolding"`
+	Frozen            bool    `json:"frozen,omitempty"`
 }
 
 type AssetBalances struct {
@@ -38,6 +39,8 @@ type AssetBalances struct {
 	Divisibility uint64          `json:"divisibility"`
 	Description  string          `json:"description"`
 	Supply       uint64          `json:"quantity"`
+	Issuer       string          `json:"issuer,omitempty"`
+	Obligations  []Amount        `json:"obligations,omitempty"`
 	Balances     []AddressAmount `json:"balances"`
 	RequestId    string          `json:"requestId"`
 	Nonce        int64           `json:"nonce"`
@@ -63,23 +66,60 @@ type Payment struct {
 type Payments []Payment
 
 type SimplePayment struct {
-	BlockchainId            string `json:"blockchainId"`
-	SourceAddress           string `json:"sourceAddress"`
-	DestinationAddress      string `json:"destinationAddress"`
-	Asset                   string `json:"asset"`
-	Issuer                  string `json:"issuer"`
-	Amount                  uint64 `json:"amount"`
-	PaymentId               string `json:"paymentId"`
-	TxFee                   int64  `json:"txFee"`
-	BroadcastTxId           string `json:"broadcastTxId"`
-	BlockchainStatus        string `json:"blockchainStatus"`
-	BlockchainConfirmations uint64 `json:"blockchainConfirmations"`
-	PaymentTag              string `json:"paymentTag"`
-	Status                  string `json:"status"`
-	ErrorCode               int64  `json:"errorCode"`
-	ErrorMessage            string `json:"errorMessage"`
-	RequestId               string `json:"requestId"`
-	Nonce                   int64  `json:"nonce"`
+	BlockchainId            string       `json:"blockchainId"`
+	SourceAddress           string       `json:"sourceAddress"`
+	DestinationAddress      string       `json:"destinationAddress"`
+	Asset                   string       `json:"asset"`
+	Issuer                  string       `json:"issuer"`
+	Amount                  uint64       `json:"amount"`
+	PaymentId               string       `json:"paymentId"`
+	TxFee                   int64        `json:"txFee"`
+	BroadcastTxId           string       `json:"broadcastTxId"`
+	BlockchainStatus        string       `json:"blockchainStatus"`
+	BlockchainConfirmations uint64       `json:"blockchainConfirmations"`
+	PaymentTag              string       `json:"paymentTag"`
+	SendAsset               string       `json:"sendAsset,omitempty"`
+	SendIssuer              string       `json:"sendIssuer,omitempty"`
+	SendMax                 uint64       `json:"sendMax,omitempty"`
+	DeliveredAmount         uint64       `json:"deliveredAmount,omitempty"`
+	DestinationTag          *uint32      `json:"destinationTag,omitempty"`
+	SourceTag               *uint32      `json:"sourceTag,omitempty"`
+	Memos                   []RippleMemo `json:"memos,omitempty"`
+	Status                  string       `json:"status"`
+	ErrorCode               int64        `json:"errorCode"`
+	ErrorMessage            string       `json:"errorMessage"`
+	RequestId               string       `json:"requestId"`
+	Nonce                   int64        `json:"nonce"`
+}
+
+type TrustLine struct {
+	Asset         string `json:"asset"`
+	Issuer        string `json:"issuer"`
+	TrustAmount   uint64 `json:"trustAmount"`
+	BroadcastTxId string `json:"broadcastTxId"`
+	Status        string `json:"status"`
+	ErrorCode     int64  `json:"errorCode"`
+	ErrorMessage  string `json:"errorMessage"`
+}
+
+type Activation struct {
+	ActivationId            string      `json:"activationId"`
+	BlockchainId            string      `json:"blockchainId"`
+	Address                 string      `json:"address"`
+	Amount                  uint64      `json:"amount"`
+	SourceAddress           string      `json:"sourceAddress"`
+	Asset                   string      `json:"asset"`
+	FundingAmount           uint64      `json:"fundingAmount"`
+	TxFee                   int64       `json:"txFee"`
+	BroadcastTxId           string      `json:"broadcastTxId"`
+	BlockchainStatus        string      `json:"blockchainStatus"`
+	BlockchainConfirmations uint64      `json:"blockchainConfirmations"`
+	TrustLines              []TrustLine `json:"trustLines"`
+	Status                  string      `json:"status"`
+	ErrorCode               int64       `json:"errorCode"`
+	ErrorMessage            string      `json:"errorMessage"`
+	RequestId               string      `json:"requestId"`
+	Nonce                   int64       `json:"nonce"`
 }
 
 type Address struct {
@@ -143,10 +183,12 @@ type Dividend struct {
 }
 
 type Issuance struct {
-	BlockIndex uint64 `json:"block_index"`
-	Quantity   uint64 `json:"quantity"`
-	Issuer     string `json:"issuer"`
-	Transfer   bool   `json:"transfer"`
+	BlockIndex  uint64 `json:"block_index"`
+	Quantity    uint64 `json:"quantity"`
+	Issuer      string `json:"issuer"`
+	Transfer    bool   `json:"transfer"`
+	Destination string `json:"destination,omitempty"`
+	TxId        string `json:"txId,omitempty"`
 }
 type AssetIssuances struct {
 	Asset        string     `json:"asset"`
@@ -160,21 +202,32 @@ type AssetIssuances struct {
 }
 
 type WalletPayment struct {
-	Passphrase         string `json:"passphrase"`
-	SourceAddress      string `json:"sourceAddress"`
-	DestinationAddress string `json:"destinationAddress"`
-	Asset              string `json:"asset"`
-	Quantity           uint64 `json:"quantity"`
-	PaymentId          string `json:"paymentId"`
-	PaymentTag         string `json:"paymentTag"`
-	RequestId          string `json:"requestId"`
-	Nonce              int64  `json:"nonce"`
+	Passphrase         string       `json:"passphrase"`
+	SourceAddress      string       `json:"sourceAddress"`
+	DestinationAddress string       `json:"destinationAddress"`
+	Asset              string       `json:"asset"`
+	Quantity           uint64       `json:"quantity"`
+	PaymentId          string       `json:"paymentId"`
+	PaymentTag         string       `json:"paymentTag"`
+	DestinationTag     *uint32      `json:"destinationTag,omitempty"`
+	SourceTag          *uint32      `json:"sourceTag,omitempty"`
+	Memos              []RippleMemo `json:"memos,omitempty"`
+	RequestId          string       `json:"requestId"`
+	Nonce              int64        `json:"nonce"`
+}
+
+// A memo attached to a Ripple payment. The fields are given in plain text and hex encoded on the ledger
+type RippleMemo struct {
+	Type   string `json:"type,omitempty"`
+	Format string `json:"format,omitempty"`
+	Data   string `json:"data,omitempty"`
 }
 
 type Wallet struct {
 	Passphrase    string   `json:"passphrase"`
 	HexSeed       string   `json:"hexSeed"`
 	Addresses     []string `json:"addresses"`
+	WalletId      string   `json:"walletId,omitempty"`
 	RequestId     string   `json:"requestId"`
 	BlockchainId  string   `json:"blockchainId,omitempty"`
 	KeyType       string   `json:"key_type,omitempty"`
@@ -184,3 +237,484 @@ type Wallet struct {
 	PublicKey     string   `json:"public_key,omitempty"`
 	PublicKeyHex  string   `json:"public_key_hex,omitempty"`
 }
+
+type WalletDiscovery struct {
+	AddressType string   `json:"addressType"`
+	Addresses   []string `json:"addresses"`
+	NextIndex   uint32   `json:"nextIndex"`
+	GapLimit    uint32   `json:"gapLimit"`
+	RequestId   string   `json:"requestId"`
+	Nonce       int64    `json:"nonce"`
+}
+
+// For Ripple the PublicKey is the address of the signer account
+type MultisigCosigner struct {
+	PublicKey string `json:"publicKey"`
+	Weight    uint16 `json:"weight,omitempty"`
+	Signed    bool   `json:"signed"`
+}
+
+type MultisigWallet struct {
+	WalletId           string   `json:"walletId"`
+	BlockchainId       string   `json:"blockchainId"`
+	Address            string   `json:"address"`
+	RedeemScript       string   `json:"redeemScript"`
+	RequiredSignatures int64    `json:"requiredSignatures"`
+	PublicKeys         []string `json:"publicKeys"`
+	SignerWeights      []uint16 `json:"signerWeights,omitempty"`
+	Status             string   `json:"status"`
+	RequestId          string   `json:"requestId"`
+	Nonce              int64    `json:"nonce"`
+}
+
+type MultisigProposal struct {
+	ProposalId         string             `json:"proposalId"`
+	WalletId           string             `json:"walletId"`
+	ProposalType       string             `json:"proposalType"`
+	SourceAddress      string             `json:"sourceAddress"`
+	DestinationAddress string             `json:"destinationAddress,omitempty"`
+	Asset              string             `json:"asset"`
+	Description        string             `json:"description,omitempty"`
+	Quantity           uint64             `json:"quantity"`
+	Divisible          bool               `json:"divisible"`
+	UnsignedTx         string             `json:"unsignedTx"`
+	SignatureHashes    []string           `json:"signatureHashes"`
+	RequiredSignatures int64              `json:"requiredSignatures"`
+	Cosigners          []MultisigCosigner `json:"cosigners"`
+	BroadcastTxId      string             `json:"broadcastTxId"`
+	Status             string             `json:"status"`
+	ErrorCode          int64              `json:"errorCode"`
+	ErrorMessage       string             `json:"errorMessage"`
+	RequestId          string             `json:"requestId"`
+	Nonce              int64              `json:"nonce"`
+}
+
+type WatchWallet struct {
+	WalletId     string   `json:"walletId"`
+	BlockchainId string   `json:"blockchainId"`
+	Xpub         string   `json:"xpub,omitempty"`
+	Addresses    []string `json:"addresses"`
+	Status       string   `json:"status"`
+	RequestId    string   `json:"requestId"`
+	Nonce        int64    `json:"nonce"`
+}
+
+type WatchWalletBalances struct {
+	WalletId             string            `json:"walletId"`
+	BlockchainId         string            `json:"blockchainId"`
+	NumberOfTransactions uint64            `json:"numberOfTransactions"`
+	Balances             []Amount          `json:"balances"`
+	Addresses            []AddressBalances `json:"addresses"`
+	RequestId            string            `json:"requestId"`
+	Nonce                int64             `json:"nonce"`
+}
+
+type WatchWalletPayments struct {
+	WalletId     string          `json:"walletId"`
+	BlockchainId string          `json:"blockchainId"`
+	Payments     []SimplePayment `json:"payments"`
+	RequestId    string          `json:"requestId"`
+	Nonce        int64           `json:"nonce"`
+}
+
+type WatchWalletCompose struct {
+	WalletId           string      `json:"walletId"`
+	BlockchainId       string      `json:"blockchainId"`
+	SourceAddress      string      `json:"sourceAddress"`
+	DestinationAddress string      `json:"destinationAddress"`
+	Asset              string      `json:"asset"`
+	Issuer             string      `json:"issuer,omitempty"`
+	Quantity           uint64      `json:"quantity"`
+	UnsignedTx         interface{} `json:"unsignedTx"`
+	RequestId          string      `json:"requestId"`
+	Nonce              int64       `json:"nonce"`
+}
+
+type AddressAssignment struct {
+	WalletId        string `json:"walletId,omitempty"`
+	Xpub            string `json:"xpub,omitempty"`
+	PublicGenerator string `json:"publicGenerator,omitempty"`
+	BlockchainId    string `json:"blockchainId"`
+	Index           uint32 `json:"index"`
+	Address         string `json:"address"`
+	Label           string `json:"label"`
+	RequestId       string `json:"requestId"`
+	Nonce           int64  `json:"nonce"`
+}
+
+type SignedMessage struct {
+	BlockchainId string `json:"blockchainId"`
+	Address      string `json:"address"`
+	Message      string `json:"message"`
+	Signature    string `json:"signature"`
+	PublicKey    string `json:"publicKey,omitempty"`
+	RequestId    string `json:"requestId"`
+	Nonce        int64  `json:"nonce"`
+}
+
+type MessageVerification struct {
+	BlockchainId string `json:"blockchainId"`
+	Address      string `json:"address"`
+	Message      string `json:"message"`
+	Signature    string `json:"signature"`
+	PublicKey    string `json:"publicKey,omitempty"`
+	Valid        bool   `json:"valid"`
+	RequestId    string `json:"requestId"`
+	Nonce        int64  `json:"nonce"`
+}
+
+type KeystoreWallet struct {
+	WalletId      string   `json:"walletId"`
+	BlockchainId  string   `json:"blockchainId"`
+	Addresses     []string `json:"addresses"`
+	UnlockPolicy  string   `json:"unlockPolicy"`
+	UnlockSeconds int64    `json:"unlockSeconds"`
+	UnlockedUntil int64    `json:"unlockedUntil"`
+	Status        string   `json:"status"`
+	RequestId     string   `json:"requestId"`
+	Nonce         int64    `json:"nonce"`
+}
+
+type KeystoreExport struct {
+	WalletId   string `json:"walletId"`
+	Passphrase string `json:"passphrase"`
+	RequestId  string `json:"requestId"`
+	Nonce      int64  `json:"nonce"`
+}
+
+type KeystoreAuditEntry struct {
+	Operation   string `json:"operation"`
+	Address     string `json:"address,omitempty"`
+	Reference   string `json:"reference,omitempty"`
+	Result      string `json:"result"`
+	Description string `json:"description,omitempty"`
+	RequestId   string `json:"requestId"`
+	Created     string `json:"created"`
+}
+
+type KeystoreAuditTrail struct {
+	WalletId  string               `json:"walletId"`
+	Entries   []KeystoreAuditEntry `json:"entries"`
+	RequestId string               `json:"requestId"`
+	Nonce     int64                `json:"nonce"`
+}
+
+type FuelWallet struct {
+	BlockchainId  string `json:"blockchainId"`
+	Address       string `json:"address"`
+	Balance       uint64 `json:"balance"`
+	Locked        bool   `json:"locked"`
+	InUse         bool   `json:"inUse"`
+	Low           bool   `json:"low"`
+	LastUsed      int64  `json:"lastUsed"`
+	LastRefreshed int64  `json:"lastRefreshed"`
+	LastError     string `json:"lastError,omitempty"`
+	RequestId     string `json:"requestId,omitempty"`
+}
+
+type FuelPoolStatus struct {
+	BlockchainId     string       `json:"blockchainId"`
+	SelectionPolicy  string       `json:"selectionPolicy"`
+	TotalBalance     uint64       `json:"totalBalance"`
+	AvailableBalance uint64       `json:"availableBalance"`
+	MinimumBalance   uint64       `json:"minimumBalance"`
+	AlertThreshold   uint64       `json:"alertThreshold"`
+	BelowThreshold   bool         `json:"belowThreshold"`
+	Wallets          []FuelWallet `json:"wallets"`
+	RequestId        string       `json:"requestId"`
+}
+
+type TopUp struct {
+	ActivationId string `json:"activationId"`
+	Amount       uint64 `json:"amount"`
+	Created      string `json:"created"`
+}
+
+type TopUpPolicy struct {
+	BlockchainId        string  `json:"blockchainId"`
+	Address             string  `json:"address"`
+	MinimumTransactions uint64  `json:"minimumTransactions"`
+	TopUpAmount         uint64  `json:"topUpAmount"`
+	Status              string  `json:"status"`
+	TopUps              []TopUp `json:"topUps"`
+	RequestId           string  `json:"requestId"`
+	Nonce               int64   `json:"nonce"`
+}
+
+type TopUpCap struct {
+	BlockchainId string `json:"blockchainId"`
+	DailyCap     uint64 `json:"dailyCap"`
+	Spent        uint64 `json:"spent"`
+	RequestId    string `json:"requestId"`
+	Nonce        int64  `json:"nonce"`
+}
+
+type RippleTrustLine struct {
+	Asset        string `json:"asset"`
+	Issuer       string `json:"issuer"`
+	Balance      string `json:"balance"`
+	Limit        string `json:"limit"`
+	LimitPeer    string `json:"limitPeer"`
+	NoRipple     bool   `json:"noRipple"`
+	NoRipplePeer bool   `json:"noRipplePeer"`
+	Freeze       bool   `json:"freeze"`
+	FreezePeer   bool   `json:"freezePeer"`
+	QualityIn    uint   `json:"qualityIn"`
+	QualityOut   uint   `json:"qualityOut"`
+}
+
+type RippleTrustLines struct {
+	Address         string            `json:"address"`
+	Lines           []RippleTrustLine `json:"lines"`
+	OwnerCount      int               `json:"ownerCount"`
+	ReserveRequired uint64            `json:"reserveRequired"`
+	RequestId       string            `json:"requestId"`
+	Nonce           int64             `json:"nonce"`
+}
+
+type RippleTrustSet struct {
+	Address         string `json:"address"`
+	Asset           string `json:"asset"`
+	Issuer          string `json:"issuer"`
+	Limit           uint64 `json:"limit"`
+	Flags           uint32 `json:"flags"`
+	ReserveRequired uint64 `json:"reserveRequired"`
+	TxHash          string `json:"txHash"`
+	RequestId       string `json:"requestId"`
+	Nonce           int64  `json:"nonce"`
+}
+
+type RippleAccountSettings struct {
+	Address        string   `json:"address"`
+	RequireDestTag bool     `json:"requireDestTag"`
+	RequireAuth    bool     `json:"requireAuth"`
+	DisallowXRP    bool     `json:"disallowXRP"`
+	DisableMaster  bool     `json:"disableMaster"`
+	NoFreeze       bool     `json:"noFreeze"`
+	GlobalFreeze   bool     `json:"globalFreeze"`
+	DefaultRipple  bool     `json:"defaultRipple"`
+	TransferRate   uint32   `json:"transferRate"`
+	Domain         string   `json:"domain"`
+	EmailHash      string   `json:"emailHash"`
+	TxHashes       []string `json:"txHashes,omitempty"`
+	RequestId      string   `json:"requestId"`
+	Nonce          int64    `json:"nonce"`
+}
+
+type RippleOfferAmount struct {
+	Asset    string `json:"asset"`
+	Issuer   string `json:"issuer,omitempty"`
+	Quantity uint64 `json:"quantity"`
+}
+
+type RippleOffer struct {
+	OfferId           string            `json:"offerId"`
+	BlockchainId      string            `json:"blockchainId"`
+	Address           string            `json:"address"`
+	TakerGets         RippleOfferAmount `json:"takerGets"`
+	TakerPays         RippleOfferAmount `json:"takerPays"`
+	Passive           bool              `json:"passive"`
+	Sell              bool              `json:"sell"`
+	ImmediateOrCancel bool              `json:"immediateOrCancel"`
+	FillOrKill        bool              `json:"fillOrKill"`
+	Flags             uint32            `json:"flags"`
+	OfferSequence     uint32            `json:"offerSequence"`
+	TxFee             int64             `json:"txFee"`
+	BroadcastTxId     string            `json:"broadcastTxId"`
+	CancelTxId        string            `json:"cancelTxId"`
+	Status            string            `json:"status"`
+	ErrorCode         int64             `json:"errorCode"`
+	ErrorMessage      string            `json:"errorMessage"`
+	RequestId         string            `json:"requestId"`
+	Nonce             int64             `json:"nonce"`
+}
+
+// An offer in the ledger. The owner funds are the amount of takerGets the account holds to fill the offer
+type RippleLedgerOffer struct {
+	Address       string            `json:"address"`
+	OfferSequence uint32            `json:"offerSequence"`
+	TakerGets     RippleOfferAmount `json:"takerGets"`
+	TakerPays     RippleOfferAmount `json:"takerPays"`
+	Passive       bool              `json:"passive"`
+	Sell          bool              `json:"sell"`
+	Quality       string            `json:"quality"`
+	OwnerFunds    string            `json:"ownerFunds,omitempty"`
+}
+
+// An escrow created through the API. Amounts are XRP in the Enu denomination and times are unix times
+type RippleEscrow struct {
+	EscrowId       string  `json:"escrowId"`
+	BlockchainId   string  `json:"blockchainId"`
+	Address        string  `json:"address"`
+	Destination    string  `json:"destination"`
+	DestinationTag *uint32 `json:"destinationTag,omitempty"`
+	Quantity       uint64  `json:"quantity"`
+	FinishAfter    int64   `json:"finishAfter,omitempty"`
+	CancelAfter    int64   `json:"cancelAfter,omitempty"`
+	Condition      string  `json:"condition,omitempty"`
+	OfferSequence  uint32  `json:"offerSequence"`
+	TxFee          int64   `json:"txFee"`
+	BroadcastTxId  string  `json:"broadcastTxId"`
+	FinishTxId     string  `json:"finishTxId"`
+	CancelTxId     string  `json:"cancelTxId"`
+	Status         string  `json:"status"`
+	ErrorCode      int64   `json:"errorCode"`
+	ErrorMessage   string  `json:"errorMessage"`
+	RequestId      string  `json:"requestId"`
+	Nonce          int64   `json:"nonce"`
+}
+
+// An escrow in the ledger which has not yet been finished or cancelled
+type RippleLedgerEscrow struct {
+	Address        string  `json:"address"`
+	Destination    string  `json:"destination"`
+	DestinationTag *uint32 `json:"destinationTag,omitempty"`
+	Quantity       uint64  `json:"quantity"`
+	FinishAfter    int64   `json:"finishAfter,omitempty"`
+	CancelAfter    int64   `json:"cancelAfter,omitempty"`
+	Condition      string  `json:"condition,omitempty"`
+	PreviousTxId   string  `json:"previousTxId"`
+}
+
+type RippleLedgerEscrows struct {
+	Address   string               `json:"address"`
+	Escrows   []RippleLedgerEscrow `json:"escrows"`
+	RequestId string               `json:"requestId"`
+	Nonce     int64                `json:"nonce"`
+}
+
+// A payment channel created through the API. Amounts are XRP in the Enu denomination and times are unix times.
+// The authorised quantity is the cumulative amount of the claims signed off ledger and the claimed quantity the amount
+// redeemed on the ledger
+type RippleChannel struct {
+	ChannelId          string  `json:"channelId"`
+	BlockchainId       string  `json:"blockchainId"`
+	Address            string  `json:"address"`
+	Destination        string  `json:"destination"`
+	DestinationTag     *uint32 `json:"destinationTag,omitempty"`
+	PublicKey          string  `json:"publicKey"`
+	Quantity           uint64  `json:"quantity"`
+	AuthorisedQuantity uint64  `json:"authorisedQuantity"`
+	ClaimSignature     string  `json:"claimSignature,omitempty"`
+	ClaimedQuantity    uint64  `json:"claimedQuantity"`
+	SettleDelay        uint32  `json:"settleDelay"`
+	CancelAfter        int64   `json:"cancelAfter,omitempty"`
+	Expiration         int64   `json:"expiration,omitempty"`
+	TxFee              int64   `json:"txFee"`
+	BroadcastTxId      string  `json:"broadcastTxId"`
+	ClaimTxId          string  `json:"claimTxId"`
+	CloseTxId          string  `json:"closeTxId"`
+	Status             string  `json:"status"`
+	ErrorCode          int64   `json:"errorCode"`
+	ErrorMessage       string  `json:"errorMessage"`
+	RequestId          string  `json:"requestId"`
+	Nonce              int64   `json:"nonce"`
+}
+
+type RippleChannels struct {
+	Address   string          `json:"address"`
+	Channels  []RippleChannel `json:"channels"`
+	RequestId string          `json:"requestId"`
+	Nonce     int64           `json:"nonce"`
+}
+
+// A claim against a payment channel. The quantity is the cumulative amount the destination may redeem with the
+// signature. Valid is only given when a claim is verified
+type RippleChannelClaim struct {
+	ChannelId string `json:"channelId"`
+	Quantity  uint64 `json:"quantity"`
+	Signature string `json:"signature"`
+	PublicKey string `json:"publicKey"`
+	Valid     *bool  `json:"valid,omitempty"`
+	RequestId string `json:"requestId"`
+	Nonce     int64  `json:"nonce"`
+}
+
+// A check created through the API. The quantity is the most the destination may cash in the Enu denomination and the
+// expiration is a unix time. The ledgerCheckId identifies the check in the ledger once it has been created
+type RippleCheck struct {
+	CheckId        string  `json:"checkId"`
+	BlockchainId   string  `json:"blockchainId"`
+	Address        string  `json:"address"`
+	Destination    string  `json:"destination"`
+	DestinationTag *uint32 `json:"destinationTag,omitempty"`
+	Asset          string  `json:"asset"`
+	Issuer         string  `json:"issuer,omitempty"`
+	Quantity       uint64  `json:"quantity"`
+	Expiration     int64   `json:"expiration,omitempty"`
+	InvoiceId      string  `json:"invoiceId,omitempty"`
+	LedgerCheckId  string  `json:"ledgerCheckId"`
+	TxFee          int64   `json:"txFee"`
+	BroadcastTxId  string  `json:"broadcastTxId"`
+	CashTxId       string  `json:"cashTxId"`
+	CancelTxId     string  `json:"cancelTxId"`
+	Status         string  `json:"status"`
+	ErrorCode      int64   `json:"errorCode"`
+	ErrorMessage   string  `json:"errorMessage"`
+	RequestId      string  `json:"requestId"`
+	Nonce          int64   `json:"nonce"`
+}
+
+// A check in the ledger which has not yet been cashed or cancelled
+type RippleLedgerCheck struct {
+	LedgerCheckId  string  `json:"ledgerCheckId"`
+	Address        string  `json:"address"`
+	Destination    string  `json:"destination"`
+	DestinationTag *uint32 `json:"destinationTag,omitempty"`
+	Asset          string  `json:"asset"`
+	Issuer         string  `json:"issuer,omitempty"`
+	Quantity       uint64  `json:"quantity"`
+	Expiration     int64   `json:"expiration,omitempty"`
+	InvoiceId      string  `json:"invoiceId,omitempty"`
+	PreviousTxId   string  `json:"previousTxId"`
+}
+
+type RippleLedgerChecks struct {
+	Address   string              `json:"address"`
+	Checks    []RippleLedgerCheck `json:"checks"`
+	RequestId string              `json:"requestId"`
+	Nonce     int64               `json:"nonce"`
+}
+
+// A transaction in the history of a Ripple address normalised like a payment. Amounts are in the Enu denomination,
+// the txFee is in drops and the date is a unix time. The direction is "sent" if the address submitted the
+// transaction, otherwise "received"
+type RippleAccountTransaction struct {
+	BlockchainId       string       `json:"blockchainId"`
+	TransactionType    string       `json:"transactionType"`
+	Direction          string       `json:"direction"`
+	SourceAddress      string       `json:"sourceAddress"`
+	DestinationAddress string       `json:"destinationAddress,omitempty"`
+	Asset              string       `json:"asset,omitempty"`
+	Issuer             string       `json:"issuer,omitempty"`
+	Amount             uint64       `json:"amount,omitempty"`
+	DeliveredAmount    uint64       `json:"deliveredAmount,omitempty"`
+	TxFee              int64        `json:"txFee"`
+	BroadcastTxId      string       `json:"broadcastTxId"`
+	BlockchainStatus   string       `json:"blockchainStatus"`
+	LedgerIndex        uint64       `json:"ledgerIndex"`
+	Date               int64        `json:"date"`
+	DestinationTag     *uint32      `json:"destinationTag,omitempty"`
+	SourceTag          *uint32      `json:"sourceTag,omitempty"`
+	Memos              []RippleMemo `json:"memos,omitempty"`
+	TransactionResult  string       `json:"transactionResult"`
+}
+
+// A page of the transaction history of a Ripple address. The marker is given to read the next page and is omitted
+// on the last page
+type RippleAccountTransactions struct {
+	Address      string                     `json:"address"`
+	Transactions []RippleAccountTransaction `json:"transactions"`
+	Marker       string                     `json:"marker,omitempty"`
+	RequestId    string                     `json:"requestId"`
+	Nonce        int64                      `json:"nonce"`
+}
+
+type RippleLedgerOffers struct {
+	Address   string              `json:"address,omitempty"`
+	TakerGets *RippleOfferAmount  `json:"takerGets,omitempty"`
+	TakerPays *RippleOfferAmount  `json:"takerPays,omitempty"`
+	Offers    []RippleLedgerOffer `json:"offers"`
+	RequestId string              `json:"requestId"`
+	Nonce     int64               `json:"nonce"`
+}
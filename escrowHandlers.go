@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func EscrowCreate(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "escrowCreate")
+
+	return handle(c, w, r)
+}
+
+func GetEscrow(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getEscrow")
+
+	return handle(c, w, r)
+}
+
+func EscrowFinish(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "escrowFinish")
+
+	return handle(c, w, r)
+}
+
+func EscrowCancel(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "escrowCancel")
+
+	return handle(c, w, r)
+}
+
+func GetEscrows(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getEscrows")
+
+	return handle(c, w, r)
+}
@@ -0,0 +1,548 @@
+// Package fuel manages the pool of internal wallets which fund ("fuel") the activation of addresses.
+//
+// The wallets are configured with "fuelwallets" in enuapi.json. Passphrases of fuel wallets are never kept in the clear.
+// Each wallet either gives its passphrase encrypted under the keystore key encryption key, ie the "encryptedPassphrase"
+// and "encryptedKey" returned by keystore.Encrypt() with the address of the wallet as the owner, or omits them to be
+// signed for by the remote signer:
+//
+//	"fuelwallets": [{"blockchainId": "ripple", "address": "r...", "encryptedPassphrase": "...", "encryptedKey": "..."}],
+//	"fuelselectionpolicy": "roundrobin",
+//	"fuelminimumbalance": {"counterparty": 100000, "ripple": 25000000},
+//	"fuelalertthreshold": {"counterparty": 10000000, "ripple": 1000000000},
+//	"fuelrefreshseconds": 300,
+//	"fueloperatorkeys": ["<access key>"]
+//
+// Balances are in the smallest unit of the blockchain, ie satoshis for Counterparty and drops for Ripple. A wallet is
+// only selected if it isn't locked by an operator, isn't already sending an activation and would hold at least the
+// minimum balance after the activation. The legacy "rippleWallets" list holds its passphrases in the clear so is
+// rejected unless "fuelallowcleartextpassphrases" is set to true while its wallets are moved to "fuelwallets".
+package fuel
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vennd/enu/bitcoinapi"
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/keystore"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/signer"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Policies used to pick the wallet to send an activation from
+const (
+	SelectRoundRobin = "roundrobin" // Rotate through the eligible wallets
+	SelectBalance    = "balance"    // Use the eligible wallet with the highest balance
+)
+
+// Default number of seconds between refreshes of the wallet balances
+const DefaultRefreshSeconds = 300
+
+type wallet struct {
+	enulib.FuelWallet
+
+	passphrase string // Empty if the wallet is signed for by the remote signer
+}
+
+type pool struct {
+	sync.Mutex
+
+	wallets        []*wallet
+	policy         string
+	minimumBalance map[string]uint64
+	alertThreshold map[string]uint64
+	next           map[string]int  // Round robin position for each blockchain
+	alerting       map[string]bool // True while the blockchain's pool is below the alert threshold
+
+	// Returns the balance of the address. Replaced in tests
+	balance func(c context.Context, blockchainId string, address string) (uint64, error)
+
+	// Raises the alerts and logs of the pool. Replaced in tests
+	alert func(errorLevel string, c context.Context, format string, a ...interface{})
+}
+
+func newPool(policy string) *pool {
+	if policy != SelectBalance {
+		policy = SelectRoundRobin
+	}
+
+	return &pool{
+		policy:         policy,
+		minimumBalance: make(map[string]uint64),
+		alertThreshold: make(map[string]uint64),
+		next:           make(map[string]int),
+		alerting:       make(map[string]bool),
+		balance:        getBalance,
+		alert:          log.FluentfContext,
+	}
+}
+
+var fuelPool = newPool(SelectRoundRobin)
+var operatorKeys = make(map[string]bool)
+var refreshSeconds = DefaultRefreshSeconds
+var isInit bool = false
+
+// Loads the fuel wallets from enuapi.json and starts refreshing their balances
+func Init() {
+	var configFilePath string
+
+	if isInit == true {
+		return
+	}
+
+	if _, err := os.Stat("./enuapi.json"); err == nil {
+		configFilePath = "./enuapi.json"
+	} else {
+		if _, err := os.Stat(os.Getenv("GOPATH") + "/bin/enuapi.json"); err == nil {
+			configFilePath = os.Getenv("GOPATH") + "/bin/enuapi.json"
+		} else {
+			if _, err := os.Stat(os.Getenv("GOPATH") + "/src/github.com/vennd/enu/enuapi.json"); err == nil {
+				configFilePath = os.Getenv("GOPATH") + "/src/github.com/vennd/enu/enuapi.json"
+			} else {
+				log.Println("Cannot find enuapi.json")
+				os.Exit(-100)
+			}
+		}
+	}
+
+	InitWithConfigPath(configFilePath)
+}
+
+func InitWithConfigPath(configFilePath string) {
+	var configuration interface{}
+
+	if isInit == true {
+		return
+	}
+
+	// Read configuration from file
+	file, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		log.Println("Unable to read configuration file enuapi.json")
+		log.Println(err.Error())
+		os.Exit(-101)
+	}
+
+	err = json.Unmarshal(file, &configuration)
+	if err != nil {
+		log.Println("Unable to parse enuapi.json")
+		log.Println(err.Error())
+		os.Exit(-101)
+	}
+
+	m := configuration.(map[string]interface{})
+
+	// Passphrases of fuel wallets are encrypted under the keystore key encryption key
+	keystore.Init()
+
+	var policy string
+	if m["fuelselectionpolicy"] != nil {
+		policy = m["fuelselectionpolicy"].(string)
+	}
+	fuelPool = newPool(policy)
+
+	if m["fuelminimumbalance"] != nil {
+		for blockchainId, amount := range m["fuelminimumbalance"].(map[string]interface{}) {
+			fuelPool.minimumBalance[blockchainId] = uint64(amount.(float64))
+		}
+	}
+
+	if m["fuelalertthreshold"] != nil {
+		for blockchainId, amount := range m["fuelalertthreshold"].(map[string]interface{}) {
+			fuelPool.alertThreshold[blockchainId] = uint64(amount.(float64))
+		}
+	}
+
+	if m["fuelrefreshseconds"] != nil && m["fuelrefreshseconds"].(float64) > 0 {
+		refreshSeconds = int(m["fuelrefreshseconds"].(float64))
+	}
+
+	if m["fueloperatorkeys"] != nil {
+		for _, accessKey := range m["fueloperatorkeys"].([]interface{}) {
+			operatorKeys[accessKey.(string)] = true
+		}
+	}
+
+	if m["fuelwallets"] != nil {
+		for _, w := range m["fuelwallets"].([]interface{}) {
+			var wmap = w.(map[string]interface{})
+			var fuelWallet wallet
+
+			fuelWallet.BlockchainId = wmap["blockchainId"].(string)
+			fuelWallet.Address = wmap["address"].(string)
+			if wmap["locked"] != nil {
+				fuelWallet.Locked = wmap["locked"].(bool)
+			}
+
+			if wmap["encryptedPassphrase"] != nil && wmap["encryptedKey"] != nil {
+				passphrase, err := keystore.Decrypt(wmap["encryptedPassphrase"].(string), wmap["encryptedKey"].(string), fuelWallet.Address)
+				if err != nil {
+					log.Printf("Unable to decrypt the passphrase of fuel wallet %s: %s. The wallet is not used\n", fuelWallet.Address, err.Error())
+					continue
+				}
+				fuelWallet.passphrase = string(passphrase)
+			}
+
+			fuelPool.add(&fuelWallet)
+		}
+	}
+
+	if m["rippleWallets"] != nil {
+		if m["fuelallowcleartextpassphrases"] != true {
+			log.Println("rippleWallets holds fuel wallet passphrases in the clear. Move the wallets to fuelwallets or set fuelallowcleartextpassphrases")
+			os.Exit(-101)
+		}
+
+		for _, w := range m["rippleWallets"].([]interface{}) {
+			var wmap = w.(map[string]interface{})
+			var fuelWallet wallet
+
+			fuelWallet.BlockchainId = consts.RippleBlockchainId
+			fuelWallet.Address = wmap["address"].(string)
+			fuelWallet.passphrase = wmap["passphrase"].(string)
+
+			log.Printf("Fuel wallet %s is configured in rippleWallets with its passphrase in the clear. Move it to fuelwallets\n", fuelWallet.Address)
+			fuelPool.add(&fuelWallet)
+		}
+	}
+
+	isInit = true
+
+	go refreshLoop()
+}
+
+func refreshLoop() {
+	for {
+		Refresh(context.TODO())
+
+		time.Sleep(time.Duration(refreshSeconds) * time.Second)
+	}
+}
+
+// Returns the balance of the address in the smallest unit of the blockchain
+func getBalance(c context.Context, blockchainId string, address string) (uint64, error) {
+	switch blockchainId {
+	case consts.CounterpartyBlockchainId:
+		return bitcoinapi.GetBalance(c, address)
+
+	case consts.RippleBlockchainId:
+		accountInfo, _, err := rippleapi.GetAccountInfo(c, address)
+		if err != nil {
+			return 0, err
+		}
+
+		// The account hasn't been created
+		if accountInfo.Balance == "" {
+			return 0, nil
+		}
+
+		return strconv.ParseUint(accountInfo.Balance, 10, 64)
+	}
+
+	return 0, errors.New("Unsupported blockchain: " + blockchainId)
+}
+
+func (p *pool) add(w *wallet) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.wallets = append(p.wallets, w)
+}
+
+func (p *pool) find(blockchainId string, address string) *wallet {
+	for _, w := range p.wallets {
+		if w.BlockchainId == blockchainId && w.Address == address {
+			return w
+		}
+	}
+
+	return nil
+}
+
+// Sets whether the wallet would fall below the minimum balance. Must be called with the pool locked
+func (p *pool) updateLow(w *wallet) {
+	w.Low = w.Balance < p.minimumBalance[w.BlockchainId]
+}
+
+// Logs an alert when the total balance of the blockchain's wallets falls below the alert threshold. The alert is only
+// raised again once the pool has been topped up above the threshold. Must be called with the pool locked
+func (p *pool) checkThreshold(c context.Context, blockchainId string) {
+	var total uint64
+
+	threshold, ok := p.alertThreshold[blockchainId]
+	if !ok {
+		return
+	}
+
+	for _, w := range p.wallets {
+		if w.BlockchainId == blockchainId {
+			total += w.Balance
+		}
+	}
+
+	if total < threshold && !p.alerting[blockchainId] {
+		p.alert(consts.LOGERROR, c, "ALERT: %s fuel wallets hold %d which is below the alert threshold of %d. Top up the fuel wallets", blockchainId, total, threshold)
+	} else if total >= threshold && p.alerting[blockchainId] {
+		p.alert(consts.LOGINFO, c, "%s fuel wallets hold %d which is above the alert threshold of %d", blockchainId, total, threshold)
+	}
+
+	p.alerting[blockchainId] = total < threshold
+}
+
+func (p *pool) refresh(c context.Context) {
+	p.Lock()
+	wallets := make([]*wallet, len(p.wallets))
+	copy(wallets, p.wallets)
+	p.Unlock()
+
+	// Balances are retrieved without holding the lock so selection isn't blocked by slow nodes
+	blockchains := make(map[string]bool)
+	for _, w := range wallets {
+		balance, err := p.balance(c, w.BlockchainId, w.Address)
+
+		p.Lock()
+		if err != nil {
+			p.alert(consts.LOGERROR, c, "Unable to refresh the balance of fuel wallet %s: %s", w.Address, err.Error())
+			w.LastError = err.Error()
+		} else {
+			w.Balance = balance
+			w.LastError = ""
+			w.LastRefreshed = time.Now().Unix()
+			p.updateLow(w)
+		}
+		p.Unlock()
+
+		blockchains[w.BlockchainId] = true
+	}
+
+	p.Lock()
+	for blockchainId := range blockchains {
+		p.checkThreshold(c, blockchainId)
+	}
+	p.Unlock()
+}
+
+func (p *pool) selectWallet(c context.Context, blockchainId string, amount uint64) (*wallet, error) {
+	var eligible []int
+	var selected = -1
+
+	p.Lock()
+	defer p.Unlock()
+
+	for i, w := range p.wallets {
+		if w.BlockchainId != blockchainId || w.Locked || w.InUse {
+			continue
+		}
+
+		// The wallet must still hold the minimum balance once the activation has been sent
+		if w.Balance < amount || w.Balance-amount < p.minimumBalance[blockchainId] {
+			continue
+		}
+
+		eligible = append(eligible, i)
+	}
+
+	if len(eligible) == 0 {
+		return nil, errors.New(consts.GenericErrors.FuelUnavailable.Description)
+	}
+
+	switch p.policy {
+	case SelectBalance:
+		for _, i := range eligible {
+			if selected == -1 || p.wallets[i].Balance > p.wallets[selected].Balance {
+				selected = i
+			}
+		}
+
+	default:
+		// The first eligible wallet at or after the wallet following the last one used
+		for _, i := range eligible {
+			if i >= p.next[blockchainId] {
+				selected = i
+				break
+			}
+		}
+		if selected == -1 {
+			selected = eligible[0]
+		}
+		p.next[blockchainId] = selected + 1
+	}
+
+	w := p.wallets[selected]
+	w.InUse = true
+	w.LastUsed = time.Now().Unix()
+
+	return w, nil
+}
+
+func (p *pool) release(c context.Context, blockchainId string, address string, spent uint64) {
+	p.Lock()
+	defer p.Unlock()
+
+	w := p.find(blockchainId, address)
+	if w == nil {
+		return
+	}
+
+	w.InUse = false
+
+	// Account for the spend until the next refresh
+	if spent > w.Balance {
+		w.Balance = 0
+	} else {
+		w.Balance -= spent
+	}
+	p.updateLow(w)
+
+	p.checkThreshold(c, blockchainId)
+}
+
+func (p *pool) setLocked(blockchainId string, address string, locked bool) (enulib.FuelWallet, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	w := p.find(blockchainId, address)
+	if w == nil {
+		return enulib.FuelWallet{}, errors.New(consts.GenericErrors.InvalidAddress.Description)
+	}
+
+	w.Locked = locked
+
+	return w.FuelWallet, nil
+}
+
+func (p *pool) status(blockchainId string) enulib.FuelPoolStatus {
+	var result enulib.FuelPoolStatus
+
+	p.Lock()
+	defer p.Unlock()
+
+	result.BlockchainId = blockchainId
+	result.SelectionPolicy = p.policy
+	result.MinimumBalance = p.minimumBalance[blockchainId]
+	result.AlertThreshold = p.alertThreshold[blockchainId]
+	result.BelowThreshold = p.alerting[blockchainId]
+	result.Wallets = make([]enulib.FuelWallet, 0)
+
+	for _, w := range p.wallets {
+		if w.BlockchainId != blockchainId {
+			continue
+		}
+
+		result.TotalBalance += w.Balance
+		if !w.Locked && !w.Low && w.Balance > result.MinimumBalance {
+			result.AvailableBalance += w.Balance - result.MinimumBalance
+		}
+
+		result.Wallets = append(result.Wallets, w.FuelWallet)
+	}
+
+	return result
+}
+
+// Refreshes the balances of all fuel wallets and raises an alert for any blockchain whose pool is below its threshold
+func Refresh(c context.Context) {
+	if isInit == false {
+		Init()
+	}
+
+	fuelPool.refresh(c)
+}
+
+// Selects a fuel wallet of the blockchain which can send the amount and returns it with the signer for the wallet.
+// The wallet isn't selected again until Release() is called so only one activation is sent from a wallet at a time.
+func Select(c context.Context, blockchainId string, amount uint64) (enulib.FuelWallet, signer.Signer, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	w, err := fuelPool.selectWallet(c, blockchainId, amount)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "No %s fuel wallet can send %d", blockchainId, amount)
+		return enulib.FuelWallet{}, nil, consts.GenericErrors.FuelUnavailable.Code, err
+	}
+
+	if w.passphrase != "" {
+		return w.FuelWallet, signer.NewPassphraseSigner(w.passphrase), 0, nil
+	}
+
+	// Wallets without a passphrase are signed for by the remote signer. The fuel wallets are internal so aren't granted
+	// to the access key of the request
+	txSigner, errorCode, err := signer.InternalRemoteSigner()
+	if err != nil {
+		fuelPool.release(c, blockchainId, w.Address, 0)
+		log.FluentfContext(consts.LOGERROR, c, "Fuel wallet %s has no passphrase and no remote signer is configured", w.Address)
+		return enulib.FuelWallet{}, nil, errorCode, err
+	}
+
+	return w.FuelWallet, txSigner, 0, nil
+}
+
+// Returns the fuel wallet to the pool once the activation has been sent. The amount spent, which is zero if the send
+// failed, is deducted from the wallet's balance until the balance is next refreshed.
+func Release(c context.Context, blockchainId string, address string, spent uint64) {
+	if isInit == false {
+		Init()
+	}
+
+	fuelPool.release(c, blockchainId, address, spent)
+}
+
+// Stops the fuel wallet from being selected, eg while it is being topped up or investigated
+func Lock(c context.Context, blockchainId string, address string) (enulib.FuelWallet, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	w, err := fuelPool.setLocked(blockchainId, address, true)
+	if err != nil {
+		return w, consts.GenericErrors.InvalidAddress.Code, err
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Fuel wallet %s locked", address)
+
+	return w, 0, nil
+}
+
+// Allows a locked fuel wallet to be selected again
+func Unlock(c context.Context, blockchainId string, address string) (enulib.FuelWallet, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	w, err := fuelPool.setLocked(blockchainId, address, false)
+	if err != nil {
+		return w, consts.GenericErrors.InvalidAddress.Code, err
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Fuel wallet %s unlocked", address)
+
+	return w, 0, nil
+}
+
+// Returns the balances and state of the blockchain's fuel wallets
+func Status(c context.Context, blockchainId string) enulib.FuelPoolStatus {
+	if isInit == false {
+		Init()
+	}
+
+	return fuelPool.status(blockchainId)
+}
+
+// Returns true if the access key may view and manage the fuel wallets
+func IsOperator(accessKey string) bool {
+	if isInit == false {
+		Init()
+	}
+
+	return operatorKeys[accessKey]
+}
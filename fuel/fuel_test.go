@@ -0,0 +1,113 @@
+package fuel
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Alerts raised by the pools under test
+var alerts []string
+
+func testPool(policy string, balances map[string]uint64) *pool {
+	p := newPool(policy)
+	p.alert = func(errorLevel string, c context.Context, format string, a ...interface{}) {
+		alerts = append(alerts, fmt.Sprintf(format, a...))
+	}
+	p.minimumBalance["ripple"] = 100
+	p.alertThreshold["ripple"] = 1000
+	p.balance = func(c context.Context, blockchainId string, address string) (uint64, error) {
+		return balances[address], nil
+	}
+
+	for _, address := range []string{"r1", "r2", "r3"} {
+		var w wallet
+		w.BlockchainId = "ripple"
+		w.Address = address
+		p.add(&w)
+	}
+
+	p.refresh(context.TODO())
+
+	return p
+}
+
+func TestSelectRoundRobin(t *testing.T) {
+	c := context.TODO()
+	p := testPool(SelectRoundRobin, map[string]uint64{"r1": 500, "r2": 500, "r3": 500})
+
+	for _, expected := range []string{"r1", "r2", "r3", "r1"} {
+		w, err := p.selectWallet(c, "ripple", 10)
+		if err != nil {
+			t.Fatalf("Unable to select wallet: %s\n", err.Error())
+		}
+		if w.Address != expected {
+			t.Errorf("Expected: %s, got: %s\n", expected, w.Address)
+		}
+		p.release(c, "ripple", w.Address, 0)
+	}
+}
+
+func TestSelectExcludesIneligible(t *testing.T) {
+	c := context.TODO()
+	p := testPool(SelectBalance, map[string]uint64{"r1": 150, "r2": 900, "r3": 600})
+
+	// r1 would fall below the minimum balance so the wallet with the highest balance is used first
+	w, err := p.selectWallet(c, "ripple", 100)
+	if err != nil || w.Address != "r2" {
+		t.Fatalf("Expected r2 to be selected, got: %#v, %v\n", w, err)
+	}
+
+	// r2 is in use until it is released
+	w, err = p.selectWallet(c, "ripple", 100)
+	if err != nil || w.Address != "r3" {
+		t.Fatalf("Expected r3 to be selected, got: %#v, %v\n", w, err)
+	}
+
+	if _, err = p.setLocked("ripple", "r2", true); err != nil {
+		t.Fatalf("Unable to lock wallet: %s\n", err.Error())
+	}
+	p.release(c, "ripple", "r2", 100)
+	p.release(c, "ripple", "r3", 100)
+
+	// r2 is locked by an operator
+	w, err = p.selectWallet(c, "ripple", 100)
+	if err != nil || w.Address != "r3" {
+		t.Fatalf("Expected r3 to be selected, got: %#v, %v\n", w, err)
+	}
+
+	if _, err = p.selectWallet(c, "ripple", 100); err == nil {
+		t.Errorf("Expected no wallet to be available\n")
+	}
+
+	if _, err = p.setLocked("ripple", "unknown", true); err == nil {
+		t.Errorf("Expected an error locking an unknown wallet\n")
+	}
+}
+
+func TestThreshold(t *testing.T) {
+	c := context.TODO()
+	p := testPool(SelectRoundRobin, map[string]uint64{"r1": 500, "r2": 400, "r3": 300})
+
+	status := p.status("ripple")
+	if status.TotalBalance != 1200 || status.BelowThreshold {
+		t.Errorf("Expected a total of 1200 above the threshold, got: %d, %t\n", status.TotalBalance, status.BelowThreshold)
+	}
+
+	w, err := p.selectWallet(c, "ripple", 300)
+	if err != nil {
+		t.Fatalf("Unable to select wallet: %s\n", err.Error())
+	}
+	p.release(c, "ripple", w.Address, 300)
+
+	status = p.status("ripple")
+	if status.TotalBalance != 900 || !status.BelowThreshold {
+		t.Errorf("Expected a total of 900 below the threshold, got: %d, %t\n", status.TotalBalance, status.BelowThreshold)
+	}
+
+	if len(alerts) == 0 || !strings.HasPrefix(alerts[len(alerts)-1], "ALERT: ripple fuel wallets hold 900") {
+		t.Errorf("Expected an alert once below the threshold, got: %v\n", alerts)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func GetFuelStatus(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getFuelStatus")
+
+	return handle(c, w, r)
+}
+
+func FuelWalletLock(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "fuelWalletLock")
+
+	return handle(c, w, r)
+}
+
+func FuelWalletUnlock(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "fuelWalletUnlock")
+
+	return handle(c, w, r)
+}
@@ -0,0 +1,108 @@
+package generalhandlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vennd/enu/bitcoinapi"
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Adds the confirmations of the funding transaction for Counterparty activations
+func addActivationBlockchainStatus(activation *enulib.Activation) {
+	if activation.BroadcastTxId == "" || activation.BlockchainId != consts.CounterpartyBlockchainId {
+		return
+	}
+
+	confirmations, err := bitcoinapi.GetConfirmations(activation.BroadcastTxId)
+	if err != nil || confirmations == 0 {
+		activation.BlockchainStatus = "unconfirmed"
+		activation.BlockchainConfirmations = 0
+
+		return
+	}
+
+	activation.BlockchainStatus = "confirmed"
+	activation.BlockchainConfirmations = confirmations
+}
+
+func GetActivation(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	vars := mux.Vars(r)
+	activationId := vars["activationId"]
+
+	if activationId == "" || len(activationId) < 16 {
+		log.FluentfContext(consts.LOGERROR, c, "Invalid activationId")
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidActivationId.Code, consts.GenericErrors.InvalidActivationId.Description)
+
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "GetActivation called for '%s' by '%s'\n", activationId, c.Value(consts.AccessKeyKey).(string))
+
+	activation := database.GetActivationByActivationId(c, c.Value(consts.AccessKeyKey).(string), activationId)
+	if activation.Status == consts.NotFound {
+		log.FluentfContext(consts.LOGINFO, c, "Activation %s not found", activationId)
+		handlers.ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidActivationId.Code, consts.GenericErrors.InvalidActivationId.Description)
+
+		return nil
+	}
+
+	addActivationBlockchainStatus(&activation)
+	activation.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(activation); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Returns the activations of the address given in the address query parameter, most recent first
+func GetActivationsByAddress(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	address := r.URL.Query().Get("address")
+
+	if address == "" {
+		log.FluentfContext(consts.LOGERROR, c, "Invalid address")
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "GetActivationsByAddress called for '%s' by '%s'\n", address, c.Value(consts.AccessKeyKey).(string))
+
+	activations := database.GetActivationsByAddress(c, c.Value(consts.AccessKeyKey).(string), address)
+	if activations == nil {
+		activations = make([]enulib.Activation, 0)
+	}
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	for i := range activations {
+		addActivationBlockchainStatus(&activations[i])
+		activations[i].RequestId = requestId
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(activations); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package generalhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/fuel"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Only operator access keys configured in "fueloperatorkeys" may view or manage the fuel wallets
+func checkFuelOperator(c context.Context, w http.ResponseWriter) bool {
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+
+	if !fuel.IsOperator(accessKey) {
+		log.FluentfContext(consts.LOGERROR, c, "Access key '%s' is not a fuel operator", accessKey)
+		handlers.ReturnUnauthorised(c, w, consts.GenericErrors.NotOperator.Code, errors.New(consts.GenericErrors.NotOperator.Description))
+
+		return false
+	}
+
+	return true
+}
+
+func returnFuelWallet(c context.Context, w http.ResponseWriter, wallet enulib.FuelWallet) {
+	wallet.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(wallet); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+	}
+}
+
+// Returns the balances and state of the fuel wallets used to activate addresses
+func GetFuelStatus(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	if !checkFuelOperator(c, w) {
+		return nil
+	}
+
+	blockchainId := c.Value(consts.BlockchainIdKey).(string)
+	log.FluentfContext(consts.LOGINFO, c, "GetFuelStatus called for '%s' by '%s'\n", blockchainId, c.Value(consts.AccessKeyKey).(string))
+
+	status := fuel.Status(c, blockchainId)
+	status.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+func FuelWalletLock(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	address := mux.Vars(r)["address"]
+
+	if !checkFuelOperator(c, w) {
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "FuelWalletLock called for '%s' by '%s'\n", address, c.Value(consts.AccessKeyKey).(string))
+
+	wallet, errorCode, err := fuel.Lock(c, c.Value(consts.BlockchainIdKey).(string), address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in fuel.Lock(): %s", err.Error())
+		handlers.ReturnNotFoundWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	returnFuelWallet(c, w, wallet)
+
+	return nil
+}
+
+func FuelWalletUnlock(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	address := mux.Vars(r)["address"]
+
+	if !checkFuelOperator(c, w) {
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "FuelWalletUnlock called for '%s' by '%s'\n", address, c.Value(consts.AccessKeyKey).(string))
+
+	wallet, errorCode, err := fuel.Unlock(c, c.Value(consts.BlockchainIdKey).(string), address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in fuel.Unlock(): %s", err.Error())
+		handlers.ReturnNotFoundWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	returnFuelWallet(c, w, wallet)
+
+	return nil
+}
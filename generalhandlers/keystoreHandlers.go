@@ -0,0 +1,175 @@
+package generalhandlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/keystore"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func returnKeystoreWallet(c context.Context, w http.ResponseWriter, wallet enulib.KeystoreWallet) {
+	wallet.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(wallet); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+	}
+}
+
+func GetKeystoreWallet(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	walletId := mux.Vars(r)["walletId"]
+
+	log.FluentfContext(consts.LOGINFO, c, "GetKeystoreWallet called for '%s' by '%s'\n", walletId, c.Value(consts.AccessKeyKey).(string))
+
+	wallet, errorCode, err := keystore.GetWallet(c, c.Value(consts.AccessKeyKey).(string), walletId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in keystore.GetWallet(): %s", err.Error())
+		handlers.ReturnKeystoreError(c, w, errorCode, err)
+
+		return nil
+	}
+
+	returnKeystoreWallet(c, w, wallet)
+
+	return nil
+}
+
+func KeystoreUnlock(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var unlockSeconds int64
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	walletId := mux.Vars(r)["walletId"]
+
+	if m["unlockSeconds"] != nil {
+		unlockSeconds = int64(m["unlockSeconds"].(float64))
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "KeystoreUnlock called for '%s' by '%s'\n", walletId, c.Value(consts.AccessKeyKey).(string))
+
+	wallet, errorCode, err := keystore.Unlock(c, c.Value(consts.AccessKeyKey).(string), walletId, m["walletPassword"].(string), unlockSeconds)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in keystore.Unlock(): %s", err.Error())
+		handlers.ReturnKeystoreError(c, w, errorCode, err)
+
+		return nil
+	}
+
+	returnKeystoreWallet(c, w, wallet)
+
+	return nil
+}
+
+func KeystoreLock(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	walletId := mux.Vars(r)["walletId"]
+
+	log.FluentfContext(consts.LOGINFO, c, "KeystoreLock called for '%s' by '%s'\n", walletId, c.Value(consts.AccessKeyKey).(string))
+
+	wallet, errorCode, err := keystore.Lock(c, c.Value(consts.AccessKeyKey).(string), walletId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in keystore.Lock(): %s", err.Error())
+		handlers.ReturnKeystoreError(c, w, errorCode, err)
+
+		return nil
+	}
+
+	returnKeystoreWallet(c, w, wallet)
+
+	return nil
+}
+
+func KeystorePolicy(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var unlockSeconds int64
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	walletId := mux.Vars(r)["walletId"]
+
+	if m["unlockSeconds"] != nil {
+		unlockSeconds = int64(m["unlockSeconds"].(float64))
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "KeystorePolicy called for '%s' by '%s'\n", walletId, c.Value(consts.AccessKeyKey).(string))
+
+	wallet, errorCode, err := keystore.SetUnlockPolicy(c, c.Value(consts.AccessKeyKey).(string), walletId, m["walletPassword"].(string), m["unlockPolicy"].(string), unlockSeconds)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in keystore.SetUnlockPolicy(): %s", err.Error())
+		handlers.ReturnKeystoreError(c, w, errorCode, err)
+
+		return nil
+	}
+
+	returnKeystoreWallet(c, w, wallet)
+
+	return nil
+}
+
+func KeystoreExport(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var export enulib.KeystoreExport
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	walletId := mux.Vars(r)["walletId"]
+
+	log.FluentfContext(consts.LOGINFO, c, "KeystoreExport called for '%s' by '%s'\n", walletId, c.Value(consts.AccessKeyKey).(string))
+
+	passphrase, errorCode, err := keystore.Export(c, c.Value(consts.AccessKeyKey).(string), walletId, m["walletPassword"].(string))
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in keystore.Export(): %s", err.Error())
+		handlers.ReturnKeystoreError(c, w, errorCode, err)
+
+		return nil
+	}
+
+	export.WalletId = walletId
+	export.Passphrase = passphrase
+	export.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(export); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+func GetKeystoreAudit(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var trail enulib.KeystoreAuditTrail
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	walletId := mux.Vars(r)["walletId"]
+
+	log.FluentfContext(consts.LOGINFO, c, "GetKeystoreAudit called for '%s' by '%s'\n", walletId, c.Value(consts.AccessKeyKey).(string))
+
+	entries, errorCode, err := keystore.GetAuditTrail(c, c.Value(consts.AccessKeyKey).(string), walletId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in keystore.GetAuditTrail(): %s", err.Error())
+		handlers.ReturnKeystoreError(c, w, errorCode, err)
+
+		return nil
+	}
+
+	trail.WalletId = walletId
+	trail.Entries = entries
+	trail.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(trail); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
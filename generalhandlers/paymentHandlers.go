@@ -10,11 +10,26 @@ import (
 	"github.com/vennd/enu/enulib"
 	"github.com/vennd/enu/handlers"
 	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
 
 	"github.com/vennd/enu/internal/github.com/gorilla/mux"
 	"github.com/vennd/enu/internal/golang.org/x/net/context"
 )
 
+// Ripple txs are final once validated. Confirmations are the number of ledgers validated since, including its own
+func rippleBlockchainStatus(c context.Context, txId string) (string, uint64) {
+	validated, confirmations, _, err := rippleapi.GetConfirmations(c, txId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetConfirmations(): %s", err.Error())
+	}
+
+	if !validated {
+		return "unconfirmed", 0
+	}
+
+	return "confirmed", confirmations
+}
+
 func GetPayment(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
 
 	var payment enulib.SimplePayment
@@ -50,6 +65,8 @@ func GetPayment(c context.Context, w http.ResponseWriter, r *http.Request, m map
 
 		payment.BlockchainStatus = "confirmed"
 		payment.BlockchainConfirmations = confirmations
+	} else if payment.BroadcastTxId != "" && payment.BlockchainId == consts.RippleBlockchainId {
+		payment.BlockchainStatus, payment.BlockchainConfirmations = rippleBlockchainStatus(c, payment.BroadcastTxId)
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -97,6 +114,8 @@ func GetPaymentsByAddress(c context.Context, w http.ResponseWriter, r *http.Requ
 
 			payments[i].BlockchainStatus = "confirmed"
 			payments[i].BlockchainConfirmations = confirmations
+		} else if p.BroadcastTxId != "" && p.BlockchainId == consts.RippleBlockchainId {
+			payments[i].BlockchainStatus, payments[i].BlockchainConfirmations = rippleBlockchainStatus(c, p.BroadcastTxId)
 		}
 	}
 
@@ -0,0 +1,176 @@
+package generalhandlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/topup"
+
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func returnTopUpPolicy(c context.Context, w http.ResponseWriter, status int, policy enulib.TopUpPolicy) {
+	topUps, err := database.GetTopUpsByAddress(c, c.Value(consts.AccessKeyKey).(string), policy.BlockchainId, policy.Address, topup.RecentTopUps)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return
+	}
+
+	policy.TopUps = topUps
+	policy.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(policy); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+	}
+}
+
+// Returns the top up policy of the address in the URL. Returns false if the policy doesn't exist or can't be read
+func getTopUpPolicy(c context.Context, w http.ResponseWriter, r *http.Request) (enulib.TopUpPolicy, bool) {
+	address := mux.Vars(r)["address"]
+	blockchainId := c.Value(consts.BlockchainIdKey).(string)
+
+	policy, err := database.GetTopUpPolicy(c, c.Value(consts.AccessKeyKey).(string), blockchainId, address)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return policy, false
+	}
+
+	if policy.Status == consts.NotFound {
+		log.FluentfContext(consts.LOGINFO, c, "Top up policy for %s not found", address)
+		handlers.ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return policy, false
+	}
+
+	return policy, true
+}
+
+// Registers or replaces the automatic top up policy of an address
+func TopUpPolicyCreate(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var policy enulib.TopUpPolicy
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	policy.BlockchainId = c.Value(consts.BlockchainIdKey).(string)
+	policy.Address = m["address"].(string)
+	policy.MinimumTransactions = uint64(m["minimumTransactions"].(float64))
+	policy.TopUpAmount = uint64(m["topUpAmount"].(float64))
+	policy.Status = "valid"
+
+	log.FluentfContext(consts.LOGINFO, c, "TopUpPolicyCreate called for '%s' by '%s'\n", policy.Address, c.Value(consts.AccessKeyKey).(string))
+
+	if err := database.UpsertTopUpPolicy(c, c.Value(consts.AccessKeyKey).(string), policy); err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	returnTopUpPolicy(c, w, http.StatusCreated, policy)
+
+	return nil
+}
+
+func GetTopUpPolicy(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	policy, ok := getTopUpPolicy(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "GetTopUpPolicy called for '%s' by '%s'\n", policy.Address, c.Value(consts.AccessKeyKey).(string))
+
+	returnTopUpPolicy(c, w, http.StatusOK, policy)
+
+	return nil
+}
+
+// Stops the address being topped up automatically. The policy can be enabled again by registering it again
+func TopUpPolicyDisable(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	policy, ok := getTopUpPolicy(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "TopUpPolicyDisable called for '%s' by '%s'\n", policy.Address, c.Value(consts.AccessKeyKey).(string))
+
+	if err := database.UpdateTopUpPolicyStatus(c, c.Value(consts.AccessKeyKey).(string), policy.BlockchainId, policy.Address, "disabled"); err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+	policy.Status = "disabled"
+
+	returnTopUpPolicy(c, w, http.StatusOK, policy)
+
+	return nil
+}
+
+func returnTopUpCap(c context.Context, w http.ResponseWriter) {
+	var topUpCap enulib.TopUpCap
+	var err error
+
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+	topUpCap.BlockchainId = c.Value(consts.BlockchainIdKey).(string)
+	topUpCap.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	if topUpCap.DailyCap, err = topup.DailyCap(c, accessKey, topUpCap.BlockchainId); err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return
+	}
+
+	if topUpCap.Spent, err = database.GetTopUpSpend(c, accessKey, topUpCap.BlockchainId); err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(topUpCap); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+	}
+}
+
+// Sets the maximum number of transactions the access key may be topped up with in any 24 hours
+func TopUpCapSet(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	dailyCap := uint64(m["dailyCap"].(float64))
+
+	log.FluentfContext(consts.LOGINFO, c, "TopUpCapSet called with %d by '%s'\n", dailyCap, c.Value(consts.AccessKeyKey).(string))
+
+	if err := database.UpsertTopUpCap(c, c.Value(consts.AccessKeyKey).(string), c.Value(consts.BlockchainIdKey).(string), dailyCap); err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	returnTopUpCap(c, w)
+
+	return nil
+}
+
+// Returns the daily cap of the access key and the number of transactions topped up in the last 24 hours
+func GetTopUpCap(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	log.FluentfContext(consts.LOGINFO, c, "GetTopUpCap called by '%s'\n", c.Value(consts.AccessKeyKey).(string))
+
+	returnTopUpCap(c, w)
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package generalhandlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func GetWatchWallet(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	wallet, ok := handlers.GetWatchWallet(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "GetWatchWallet called for '%s' by '%s'\n", wallet.WalletId, c.Value(consts.AccessKeyKey).(string))
+
+	wallet.RequestId = c.Value(consts.RequestIdKey).(string)
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(wallet); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Returns the payments Enu has made from or to any of the addresses of the watch only wallet. Payments received by the
+// addresses of Ripple watch only wallets are also recorded while the rippled WebSocket stream is configured
+func WatchWalletPayments(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var result enulib.WatchWalletPayments
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	wallet, ok := handlers.GetWatchWallet(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "WatchWalletPayments called for '%s' by '%s'\n", wallet.WalletId, c.Value(consts.AccessKeyKey).(string))
+
+	result.WalletId = wallet.WalletId
+	result.BlockchainId = wallet.BlockchainId
+	result.RequestId = c.Value(consts.RequestIdKey).(string)
+	result.Payments = []enulib.SimplePayment{}
+
+	// Payments between two addresses of the wallet are only returned once
+	seen := make(map[string]bool)
+	for _, address := range wallet.Addresses {
+		for _, payment := range database.GetPaymentsByAddress(c, c.Value(consts.AccessKeyKey).(string), address) {
+			if seen[payment.PaymentId] {
+				continue
+			}
+			seen[payment.PaymentId] = true
+
+			result.Payments = append(result.Payments, payment)
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
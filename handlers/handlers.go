@@ -17,6 +17,7 @@ import (
 	"github.com/vennd/enu/consts"
 	"github.com/vennd/enu/database"
 	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
 	"github.com/vennd/enu/internal/github.com/xeipuuv/gojsonschema"
 	"github.com/vennd/enu/internal/golang.org/x/net/context"
 	"github.com/vennd/enu/log"
@@ -124,6 +125,20 @@ func ReturnUnprocessableEntity(c context.Context, w http.ResponseWriter, errorCo
 	}
 }
 
+// Returns the keystore error with the appropriate HTTP status
+func ReturnKeystoreError(c context.Context, w http.ResponseWriter, errorCode int64, err error) {
+	switch errorCode {
+	case consts.GenericErrors.InvalidWalletId.Code:
+		ReturnNotFoundWithCustomError(c, w, errorCode, err.Error())
+	case consts.GenericErrors.InvalidWalletPassword.Code:
+		ReturnUnauthorised(c, w, errorCode, err)
+	case consts.GenericErrors.InvalidDocument.Code, consts.GenericErrors.WalletLocked.Code:
+		ReturnBadRequest(c, w, errorCode, err.Error())
+	default:
+		ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+	}
+}
+
 func ReturnCreated(c context.Context, w http.ResponseWriter) {
 	returnCode := enulib.ReturnCode{Code: 0, Description: "Success", RequestId: c.Value(consts.RequestIdKey).(string)}
 
@@ -326,6 +341,20 @@ func CheckAndParseJsonCTX(c context.Context, w http.ResponseWriter, r *http.Requ
 		c2 = c
 	}
 
+	// The maximum fee the request will pay for each tx, in the smallest unit of the blockchain's fee asset
+	if m["maxFee"] != nil {
+		maxFee, ok := m["maxFee"].(float64)
+		if !ok || maxFee < 1 || maxFee != float64(uint64(maxFee)) {
+			returnErr := errors.New("The maxFee must be a positive whole number")
+			log.FluentfContext(consts.LOGERROR, c, "%s", returnErr.Error())
+			ReturnBadRequest(c, w, consts.GenericErrors.InvalidDocument.Code, returnErr.Error())
+
+			return c, m, returnErr
+		}
+
+		c2 = context.WithValue(c2, consts.MaxFeeKey, uint64(maxFee))
+	}
+
 	err = ValidateParameters(c2, payload)
 	if err != nil {
 		log.FluentfContext(consts.LOGERROR, c, err.Error())
@@ -374,3 +403,24 @@ func ValidateParameters(c context.Context, parameters interface{}) error {
 	// shouldn't reach here...
 	return nil
 }
+
+// Looks up the watch only wallet given in the path of the request. If the wallet doesn't exist for the access key and
+// blockchain the error is returned to the client and ok is false.
+func GetWatchWallet(c context.Context, w http.ResponseWriter, r *http.Request) (enulib.WatchWallet, bool) {
+	walletId := mux.Vars(r)["walletId"]
+
+	wallet, err := database.GetWatchWalletByWalletId(c, c.Value(consts.AccessKeyKey).(string), walletId)
+	if err != nil {
+		ReturnServerError(c, w)
+
+		return wallet, false
+	}
+
+	if wallet.Status == consts.NotFound || wallet.BlockchainId != c.Value(consts.BlockchainIdKey).(string) {
+		ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidWalletId.Code, consts.GenericErrors.InvalidWalletId.Description)
+
+		return wallet, false
+	}
+
+	return wallet, true
+}
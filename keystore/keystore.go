@@ -0,0 +1,249 @@
+// Package keystore holds the passphrases of custodial wallets encrypted at rest.
+//
+// Envelope encryption is used. Each wallet's passphrase is encrypted with its own randomly generated data key using
+// AES-256-GCM. The data key is in turn encrypted with the key encryption key (KEK) and stored alongside the encrypted
+// passphrase. The KEK is never stored in the database and is read from the ENU_KEYSTORE_KEK environment variable or
+// the "keystorekek" value in enuapi.json. If neither is set the keystore is disabled.
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/vennd/enu/internal/golang.org/x/crypto/pbkdf2"
+)
+
+// Length in bytes of the key encryption key and the data keys
+const KeyLength = 32
+
+// Number of PBKDF2-HMAC-SHA256 iterations used to hash wallet passwords
+var PasswordIterations = 100000
+
+var kek []byte
+var isInit bool = false
+
+// Initialises the key encryption key from the environment or enuapi.json
+func Init() {
+	var configFilePath string
+
+	if isInit == true {
+		return
+	}
+
+	if _, err := os.Stat("./enuapi.json"); err == nil {
+		log.Println("Found and using configuration file ./enuapi.json")
+		configFilePath = "./enuapi.json"
+	} else {
+		if _, err := os.Stat(os.Getenv("GOPATH") + "/bin/enuapi.json"); err == nil {
+			configFilePath = os.Getenv("GOPATH") + "/bin/enuapi.json"
+			log.Printf("Found and using configuration file from GOPATH: %s\n", configFilePath)
+
+		} else {
+			if _, err := os.Stat(os.Getenv("GOPATH") + "/src/github.com/vennd/enu/enuapi.json"); err == nil {
+				configFilePath = os.Getenv("GOPATH") + "/src/github.com/vennd/enu/enuapi.json"
+				log.Printf("Found and using configuration file from GOPATH: %s\n", configFilePath)
+
+			} else {
+				log.Println("Cannot find enuapi.json")
+				os.Exit(-100)
+			}
+		}
+	}
+
+	InitWithConfigPath(configFilePath)
+}
+
+func InitWithConfigPath(configFilePath string) {
+	var configuration interface{}
+	var kekHex string
+
+	if isInit == true {
+		return
+	}
+
+	// Read configuration from file
+	log.Printf("Reading %s\n", configFilePath)
+	file, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		log.Println("Unable to read configuration file enuapi.json")
+		log.Println(err.Error())
+		os.Exit(-100)
+	}
+
+	err = json.Unmarshal(file, &configuration)
+
+	m := configuration.(map[string]interface{})
+
+	// The environment takes precedence so the KEK doesn't need to be kept in the configuration file
+	if os.Getenv("ENU_KEYSTORE_KEK") != "" {
+		kekHex = os.Getenv("ENU_KEYSTORE_KEK")
+	} else if m["keystorekek"] != nil {
+		kekHex = m["keystorekek"].(string)
+	}
+
+	if kekHex == "" {
+		log.Println("No keystore key encryption key configured. The custodial keystore is disabled")
+	} else if err := SetKeyEncryptionKey(kekHex); err != nil {
+		log.Printf("Invalid keystore key encryption key: %s. The custodial keystore is disabled\n", err.Error())
+	}
+
+	isInit = true
+}
+
+// Sets the key encryption key from its hex encoding
+func SetKeyEncryptionKey(kekHex string) error {
+	key, err := hex.DecodeString(kekHex)
+	if err != nil {
+		return err
+	}
+
+	if len(key) != KeyLength {
+		return fmt.Errorf("The key encryption key must be %d bytes", KeyLength)
+	}
+
+	kek = key
+
+	return nil
+}
+
+// Returns true if a key encryption key has been configured
+func IsEnabled() bool {
+	if isInit == false {
+		Init()
+	}
+
+	return len(kek) == KeyLength
+}
+
+// Encrypts the plaintext with AES-256-GCM. The result is the hex encoded nonce followed by the ciphertext. The
+// additional data isn't encrypted but must be given again to open the result
+func seal(key []byte, plaintext []byte, additionalData []byte) (string, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(gcm.Seal(nonce, nonce, plaintext, additionalData)), nil
+}
+
+// Decrypts and authenticates a value produced by seal() with the same additional data
+func open(key []byte, sealed string, additionalData []byte) ([]byte, error) {
+	data, err := hex.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("Encrypted value is too short")
+	}
+
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], additionalData)
+}
+
+// Encrypts the plaintext under a new data key. Returns the encrypted plaintext and the data key encrypted under the KEK.
+// Both are bound to the owner, eg the walletId, so they can't be decrypted as the secrets of another owner
+func Encrypt(plaintext []byte, owner string) (string, string, error) {
+	if !IsEnabled() {
+		return "", "", errors.New("No key encryption key configured")
+	}
+
+	dataKey := make([]byte, KeyLength)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", "", err
+	}
+
+	encryptedData, err := seal(dataKey, plaintext, []byte(owner))
+	if err != nil {
+		return "", "", err
+	}
+
+	encryptedKey, err := seal(kek, dataKey, []byte(owner))
+	if err != nil {
+		return "", "", err
+	}
+
+	return encryptedData, encryptedKey, nil
+}
+
+// Decrypts a value produced by Encrypt() for the same owner
+func Decrypt(encryptedData string, encryptedKey string, owner string) ([]byte, error) {
+	if !IsEnabled() {
+		return nil, errors.New("No key encryption key configured")
+	}
+
+	dataKey, err := open(kek, encryptedKey, []byte(owner))
+	if err != nil {
+		return nil, err
+	}
+
+	return open(dataKey, encryptedData, []byte(owner))
+}
+
+// Returns a salted hash of the password in the form pbkdf2-sha256$<iterations>$<salt>$<hash>
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	hash := pbkdf2.Key([]byte(password), salt, PasswordIterations, KeyLength, sha256.New)
+
+	return fmt.Sprintf("pbkdf2-sha256$%d$%s$%s", PasswordIterations, hex.EncodeToString(salt), hex.EncodeToString(hash)), nil
+}
+
+// Returns true if the password matches the hash produced by HashPassword()
+func CheckPassword(password string, passwordHash string) bool {
+	parts := strings.Split(passwordHash, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 {
+		return false
+	}
+
+	salt, err := hex.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+
+	expected, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(pbkdf2.Key([]byte(password), salt, iterations, len(expected), sha256.New), expected)
+}
@@ -0,0 +1,113 @@
+package keystore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/vennd/enu/enulib"
+
+	"github.com/vennd/enu/internal/golang.org/x/crypto/pbkdf2"
+)
+
+func init() {
+	isInit = true
+	PasswordIterations = 1000
+}
+
+func TestPbkdf2(t *testing.T) {
+	// PBKDF2-HMAC-SHA256 test vectors
+	var vectors = []struct {
+		iterations int
+		expected   string
+	}{
+		{1, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"},
+		{2, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43"},
+		{4096, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"},
+	}
+
+	for _, v := range vectors {
+		result := hex.EncodeToString(pbkdf2.Key([]byte("password"), []byte("salt"), v.iterations, 32, sha256.New))
+		if result != v.expected {
+			t.Errorf("Iterations %d. Expected: %s, got: %s\n", v.iterations, v.expected, result)
+		}
+	}
+}
+
+func TestPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("Unable to hash password: %s\n", err.Error())
+	}
+
+	if !CheckPassword("correct horse", hash) {
+		t.Errorf("Expected password to match\n")
+	}
+
+	if CheckPassword("battery staple", hash) {
+		t.Errorf("Expected wrong password not to match\n")
+	}
+
+	if CheckPassword("correct horse", "") {
+		t.Errorf("Expected malformed hash not to match\n")
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	if err := SetKeyEncryptionKey("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"); err != nil {
+		t.Fatalf("Unable to set KEK: %s\n", err.Error())
+	}
+
+	passphrase := "attention stranger fate plain huge poetry view precious drug world try age"
+	encryptedPassphrase, encryptedKey, err := Encrypt([]byte(passphrase), "walletA")
+	if err != nil {
+		t.Fatalf("Unable to encrypt: %s\n", err.Error())
+	}
+
+	decrypted, err := Decrypt(encryptedPassphrase, encryptedKey, "walletA")
+	if err != nil {
+		t.Fatalf("Unable to decrypt: %s\n", err.Error())
+	}
+
+	if string(decrypted) != passphrase {
+		t.Errorf("Expected: %s, got: %s\n", passphrase, string(decrypted))
+	}
+
+	// Secrets copied to the row of another wallet must not decrypt
+	if _, err := Decrypt(encryptedPassphrase, encryptedKey, "walletB"); err == nil {
+		t.Errorf("Expected decryption for another walletId to fail\n")
+	}
+
+	// A different KEK must not be able to unwrap the data key
+	SetKeyEncryptionKey("1f1e1d1c1b1a191817161514131211100f0e0d0c0b0a09080706050403020100")
+	if _, err := Decrypt(encryptedPassphrase, encryptedKey, "walletA"); err == nil {
+		t.Errorf("Expected decryption with the wrong KEK to fail\n")
+	}
+
+	if err := SetKeyEncryptionKey("0001"); err == nil {
+		t.Errorf("Expected short KEK to be rejected\n")
+	}
+}
+
+func TestCheckUnlockPolicy(t *testing.T) {
+	now := time.Now()
+
+	var tests = []struct {
+		wallet  enulib.KeystoreWallet
+		permits bool
+	}{
+		{enulib.KeystoreWallet{UnlockPolicy: UnlockPolicyAlways}, true},
+		{enulib.KeystoreWallet{UnlockPolicy: UnlockPolicyLocked, UnlockedUntil: now.Unix() + 60}, false},
+		{enulib.KeystoreWallet{UnlockPolicy: UnlockPolicyTimed, UnlockedUntil: now.Unix() + 60}, true},
+		{enulib.KeystoreWallet{UnlockPolicy: UnlockPolicyTimed, UnlockedUntil: now.Unix() - 1}, false},
+		{enulib.KeystoreWallet{UnlockPolicy: UnlockPolicyTimed}, false},
+	}
+
+	for _, test := range tests {
+		err := checkUnlockPolicy(test.wallet, now)
+		if (err == nil) != test.permits {
+			t.Errorf("Policy %s unlocked until %d. Expected permitted: %t\n", test.wallet.UnlockPolicy, test.wallet.UnlockedUntil, test.permits)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package keystore
+
+import (
+	"errors"
+	"time"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Unlock policies of a custodial wallet
+const (
+	UnlockPolicyAlways = "always" // The wallet may sign any request made by the access key that owns it
+	UnlockPolicyTimed  = "timed"  // The wallet must be unlocked with the wallet password and stays unlocked for unlockSeconds
+	UnlockPolicyLocked = "locked" // The wallet may not sign until the policy is changed with the wallet password
+)
+
+var UnlockPolicies = []string{UnlockPolicyAlways, UnlockPolicyTimed, UnlockPolicyLocked}
+
+// Default and maximum length of time a wallet with the timed policy stays unlocked
+const DefaultUnlockSeconds = 300
+const MaxUnlockSeconds = 86400
+
+// Operations recorded in the audit trail other than signing, which is recorded under the request type
+const (
+	AuditOperationCreate = "create"
+	AuditOperationUnlock = "unlock"
+	AuditOperationLock   = "lock"
+	AuditOperationPolicy = "policy"
+	AuditOperationExport = "export"
+)
+
+const (
+	AuditResultGranted = "granted"
+	AuditResultDenied  = "denied"
+)
+
+func IsValidUnlockPolicy(policy string) bool {
+	for _, p := range UnlockPolicies {
+		if p == policy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns nil if the wallet's unlock policy permits signing at the given time
+func checkUnlockPolicy(wallet enulib.KeystoreWallet, now time.Time) error {
+	switch wallet.UnlockPolicy {
+	case UnlockPolicyAlways:
+		return nil
+	case UnlockPolicyTimed:
+		if now.Unix() < wallet.UnlockedUntil {
+			return nil
+		}
+	}
+
+	return errors.New(consts.GenericErrors.WalletLocked.Description)
+}
+
+func audit(c context.Context, accessKey string, walletId string, operation string, address string, reference string, result string, description string) {
+	if err := database.InsertKeystoreAudit(c, accessKey, walletId, operation, address, reference, result, description); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Unable to record keystore audit for walletId %s, operation %s: %s", walletId, operation, err.Error())
+	}
+}
+
+// Retrieves the wallet and checks it belongs to the access key
+func getWallet(c context.Context, accessKey string, walletId string) (enulib.KeystoreWallet, database.KeystoreSecrets, int64, error) {
+	if !IsEnabled() {
+		return enulib.KeystoreWallet{}, database.KeystoreSecrets{}, consts.GenericErrors.KeystoreUnavailable.Code, errors.New(consts.GenericErrors.KeystoreUnavailable.Description)
+	}
+
+	wallet, secrets, err := database.GetKeystoreWalletByWalletId(c, accessKey, walletId)
+	if err != nil {
+		return wallet, secrets, consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
+	}
+
+	if wallet.Status == consts.NotFound {
+		return wallet, secrets, consts.GenericErrors.InvalidWalletId.Code, errors.New(consts.GenericErrors.InvalidWalletId.Description)
+	}
+
+	return wallet, secrets, 0, nil
+}
+
+// Retrieves the wallet and checks the wallet password. Failed attempts are recorded in the audit trail
+func authenticate(c context.Context, accessKey string, walletId string, password string, operation string) (enulib.KeystoreWallet, database.KeystoreSecrets, int64, error) {
+	wallet, secrets, errorCode, err := getWallet(c, accessKey, walletId)
+	if err != nil {
+		return wallet, secrets, errorCode, err
+	}
+
+	if !CheckPassword(password, secrets.PasswordHash) {
+		audit(c, accessKey, walletId, operation, "", "", AuditResultDenied, consts.GenericErrors.InvalidWalletPassword.Description)
+		return wallet, secrets, consts.GenericErrors.InvalidWalletPassword.Code, errors.New(consts.GenericErrors.InvalidWalletPassword.Description)
+	}
+
+	return wallet, secrets, 0, nil
+}
+
+func normaliseUnlockSeconds(unlockSeconds int64) int64 {
+	if unlockSeconds <= 0 {
+		return DefaultUnlockSeconds
+	}
+
+	if unlockSeconds > MaxUnlockSeconds {
+		return MaxUnlockSeconds
+	}
+
+	return unlockSeconds
+}
+
+// Encrypts and stores the passphrase of a newly created wallet. The wallet password is required to unlock, change the
+// policy of, or export the wallet.
+func StoreWallet(c context.Context, accessKey string, blockchainId string, passphrase string, addresses []string, password string, unlockPolicy string, unlockSeconds int64) (enulib.KeystoreWallet, int64, error) {
+	var wallet enulib.KeystoreWallet
+
+	if !IsEnabled() {
+		return wallet, consts.GenericErrors.KeystoreUnavailable.Code, errors.New(consts.GenericErrors.KeystoreUnavailable.Description)
+	}
+
+	if unlockPolicy == "" {
+		unlockPolicy = UnlockPolicyAlways
+	}
+
+	if !IsValidUnlockPolicy(unlockPolicy) {
+		return wallet, consts.GenericErrors.InvalidDocument.Code, errors.New("Invalid unlock policy: " + unlockPolicy)
+	}
+
+	wallet.WalletId = enulib.GenerateWalletId()
+
+	encryptedPassphrase, encryptedKey, err := Encrypt([]byte(passphrase), wallet.WalletId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in keystore.Encrypt(): %s", err.Error())
+		return wallet, consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
+	}
+
+	passwordHash, err := HashPassword(password)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in keystore.HashPassword(): %s", err.Error())
+		return wallet, consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
+	}
+
+	wallet.BlockchainId = blockchainId
+	wallet.Addresses = addresses
+	wallet.UnlockPolicy = unlockPolicy
+	wallet.UnlockSeconds = normaliseUnlockSeconds(unlockSeconds)
+	wallet.Status = "valid"
+
+	secrets := database.KeystoreSecrets{EncryptedPassphrase: encryptedPassphrase, EncryptedKey: encryptedKey, PasswordHash: passwordHash}
+	if err := database.InsertKeystoreWallet(c, accessKey, wallet, secrets); err != nil {
+		return wallet, consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
+	}
+
+	audit(c, accessKey, wallet.WalletId, AuditOperationCreate, "", "", AuditResultGranted, "")
+
+	return wallet, 0, nil
+}
+
+// Returns the custodial wallet without any of its encrypted material
+func GetWallet(c context.Context, accessKey string, walletId string) (enulib.KeystoreWallet, int64, error) {
+	wallet, _, errorCode, err := getWallet(c, accessKey, walletId)
+
+	return wallet, errorCode, err
+}
+
+// Returns the decrypted passphrase for signing if the unlock policy of the wallet permits it. Every request, whether
+// granted or denied, is recorded in the audit trail against the operation, the address signing and the reference of
+// the payment, asset or dividend.
+func GetPassphrase(c context.Context, accessKey string, walletId string, operation string, address string, reference string) (string, int64, error) {
+	wallet, secrets, errorCode, err := getWallet(c, accessKey, walletId)
+	if err != nil {
+		return "", errorCode, err
+	}
+
+	if err := checkUnlockPolicy(wallet, time.Now()); err != nil {
+		audit(c, accessKey, walletId, operation, address, reference, AuditResultDenied, err.Error())
+		return "", consts.GenericErrors.WalletLocked.Code, err
+	}
+
+	passphrase, err := Decrypt(secrets.EncryptedPassphrase, secrets.EncryptedKey, walletId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in keystore.Decrypt() for walletId %s: %s", walletId, err.Error())
+		audit(c, accessKey, walletId, operation, address, reference, AuditResultDenied, "Unable to decrypt passphrase")
+		return "", consts.GenericErrors.KeystoreUnavailable.Code, errors.New(consts.GenericErrors.KeystoreUnavailable.Description)
+	}
+
+	audit(c, accessKey, walletId, operation, address, reference, AuditResultGranted, "")
+
+	return string(passphrase), 0, nil
+}
+
+// Checks the wallet exists and its unlock policy currently permits signing so a request can be rejected before it is
+// accepted for processing. Denials are recorded in the audit trail.
+func CanSign(c context.Context, accessKey string, walletId string, operation string, address string, reference string) (int64, error) {
+	wallet, _, errorCode, err := getWallet(c, accessKey, walletId)
+	if err != nil {
+		return errorCode, err
+	}
+
+	if err := checkUnlockPolicy(wallet, time.Now()); err != nil {
+		audit(c, accessKey, walletId, operation, address, reference, AuditResultDenied, err.Error())
+		return consts.GenericErrors.WalletLocked.Code, err
+	}
+
+	return 0, nil
+}
+
+// Unlocks a wallet with the timed unlock policy for the given number of seconds, or the wallet's default if zero
+func Unlock(c context.Context, accessKey string, walletId string, password string, unlockSeconds int64) (enulib.KeystoreWallet, int64, error) {
+	wallet, _, errorCode, err := authenticate(c, accessKey, walletId, password, AuditOperationUnlock)
+	if err != nil {
+		return wallet, errorCode, err
+	}
+
+	if wallet.UnlockPolicy != UnlockPolicyTimed {
+		return wallet, consts.GenericErrors.InvalidDocument.Code, errors.New("Only wallets with the timed unlock policy can be unlocked")
+	}
+
+	if unlockSeconds <= 0 {
+		unlockSeconds = wallet.UnlockSeconds
+	}
+	wallet.UnlockedUntil = time.Now().Unix() + normaliseUnlockSeconds(unlockSeconds)
+
+	if err := database.UpdateKeystoreWalletUnlockedUntil(c, accessKey, walletId, wallet.UnlockedUntil); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in database.UpdateKeystoreWalletUnlockedUntil(): %s", err.Error())
+		return wallet, consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
+	}
+
+	audit(c, accessKey, walletId, AuditOperationUnlock, "", "", AuditResultGranted, "")
+
+	return wallet, 0, nil
+}
+
+// Revokes any current unlock of the wallet. The wallet password isn't required to lock a wallet
+func Lock(c context.Context, accessKey string, walletId string) (enulib.KeystoreWallet, int64, error) {
+	wallet, _, errorCode, err := getWallet(c, accessKey, walletId)
+	if err != nil {
+		return wallet, errorCode, err
+	}
+
+	wallet.UnlockedUntil = 0
+	if err := database.UpdateKeystoreWalletUnlockedUntil(c, accessKey, walletId, 0); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in database.UpdateKeystoreWalletUnlockedUntil(): %s", err.Error())
+		return wallet, consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
+	}
+
+	audit(c, accessKey, walletId, AuditOperationLock, "", "", AuditResultGranted, "")
+
+	return wallet, 0, nil
+}
+
+// Changes the unlock policy of the wallet. Any current unlock is revoked
+func SetUnlockPolicy(c context.Context, accessKey string, walletId string, password string, unlockPolicy string, unlockSeconds int64) (enulib.KeystoreWallet, int64, error) {
+	if !IsValidUnlockPolicy(unlockPolicy) {
+		return enulib.KeystoreWallet{}, consts.GenericErrors.InvalidDocument.Code, errors.New("Invalid unlock policy: " + unlockPolicy)
+	}
+
+	wallet, _, errorCode, err := authenticate(c, accessKey, walletId, password, AuditOperationPolicy)
+	if err != nil {
+		return wallet, errorCode, err
+	}
+
+	wallet.UnlockPolicy = unlockPolicy
+	wallet.UnlockSeconds = normaliseUnlockSeconds(unlockSeconds)
+	wallet.UnlockedUntil = 0
+
+	if err := database.UpdateKeystoreWalletUnlockPolicy(c, accessKey, walletId, wallet.UnlockPolicy, wallet.UnlockSeconds); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in database.UpdateKeystoreWalletUnlockPolicy(): %s", err.Error())
+		return wallet, consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
+	}
+
+	audit(c, accessKey, walletId, AuditOperationPolicy, "", "", AuditResultGranted, unlockPolicy)
+
+	return wallet, 0, nil
+}
+
+// Returns the decrypted passphrase so custody can be taken back by the client. The wallet password is always required
+// regardless of the unlock policy.
+func Export(c context.Context, accessKey string, walletId string, password string) (string, int64, error) {
+	_, secrets, errorCode, err := authenticate(c, accessKey, walletId, password, AuditOperationExport)
+	if err != nil {
+		return "", errorCode, err
+	}
+
+	passphrase, err := Decrypt(secrets.EncryptedPassphrase, secrets.EncryptedKey, walletId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in keystore.Decrypt() for walletId %s: %s", walletId, err.Error())
+		return "", consts.GenericErrors.KeystoreUnavailable.Code, errors.New(consts.GenericErrors.KeystoreUnavailable.Description)
+	}
+
+	audit(c, accessKey, walletId, AuditOperationExport, "", "", AuditResultGranted, "")
+
+	return string(passphrase), 0, nil
+}
+
+// Returns the audit trail of the wallet
+func GetAuditTrail(c context.Context, accessKey string, walletId string) ([]enulib.KeystoreAuditEntry, int64, error) {
+	_, _, errorCode, err := getWallet(c, accessKey, walletId)
+	if err != nil {
+		return nil, errorCode, err
+	}
+
+	entries, err := database.GetKeystoreAuditByWalletId(c, accessKey, walletId)
+	if err != nil {
+		return nil, consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
+	}
+
+	return entries, 0, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func GetKeystoreWallet(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getKeystoreWallet")
+
+	return handle(c, w, r)
+}
+
+func KeystoreUnlock(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "keystoreUnlock")
+
+	return handle(c, w, r)
+}
+
+func KeystoreLock(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "keystoreLock")
+
+	return handle(c, w, r)
+}
+
+func KeystorePolicy(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "keystorePolicy")
+
+	return handle(c, w, r)
+}
+
+func KeystoreExport(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "keystoreExport")
+
+	return handle(c, w, r)
+}
+
+func GetKeystoreAudit(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getKeystoreAudit")
+
+	return handle(c, w, r)
+}
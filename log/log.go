@@ -87,6 +87,13 @@ func InitWithConfigPath(configFilePath string) {
 	isInit = true
 }
 
+// Initialises logging without reading enuapi.json so packages can be unit tested without a configuration file.
+// Nothing is sent to Fluent, entries are only written to stdout
+func InitWithoutConfig() {
+	fluentHost = ""
+	isInit = true
+}
+
 // Compatibility function with existing logger.
 // Writes a copy of the string to format to stdout but also sends a copy to Fluent
 // Uses a default tag of 'enu.$ENV.$HOSTNAME'
@@ -285,6 +292,11 @@ func object(tag string, object interface{}, errorString string, suppressErrors b
 		log.Println(logString)
 	}
 
+	// No Fluent host when initialised without a configuration file
+	if fluentHost == "" {
+		return
+	}
+
 	//	_, err2 := sendToFluent(fluentHost+"/"+tag, payloadJsonBytes)
 	go sendToFluent(fluentHost+"/"+tag, payloadJsonBytes)
 
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func MultisigWalletCreate(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "multisigWalletCreate")
+
+	return handle(c, w, r)
+}
+
+func GetMultisigWallet(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getMultisigWallet")
+
+	return handle(c, w, r)
+}
+
+func MultisigProposalCreate(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "multisigProposal")
+
+	return handle(c, w, r)
+}
+
+func GetMultisigProposal(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getMultisigProposal")
+
+	return handle(c, w, r)
+}
+
+func MultisigProposalSign(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "multisigSign")
+
+	return handle(c, w, r)
+}
+
+func MultisigProposalFinalise(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "multisigFinalise")
+
+	return handle(c, w, r)
+}
@@ -0,0 +1,168 @@
+// Package paychan automatically closes Ripple payment channels before they expire.
+//
+// Once a payment channel expires it is closed by the next transaction which touches it and the destination loses any
+// XRP authorised by claims it hasn't yet redeemed. The job periodically checks the open payment channels created
+// through the API and closes those about to expire from their address, delivering the quantity authorised off
+// ledger to the destination. Channels which have been removed from the ledger are marked closed. Optional settings in
+// enuapi.json are:
+//
+//	"channelintervalseconds": 300,      // How often the channels are checked
+//	"channelclosemarginseconds": 3600   // How long before a channel expires it is closed
+//
+// The close is signed with the custodial wallet the channel was created with or otherwise the remote signer.
+package paychan
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/ripplehandlers"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+var intervalSeconds = 300
+var closeMarginSeconds int64 = 3600
+var isInit bool = false
+
+// Reads the payment channel settings from enuapi.json and starts the job which closes expiring channels
+func Init() {
+	var configFilePath string
+
+	if isInit == true {
+		return
+	}
+
+	if _, err := os.Stat("./enuapi.json"); err == nil {
+		configFilePath = "./enuapi.json"
+	} else {
+		if _, err := os.Stat(os.Getenv("GOPATH") + "/bin/enuapi.json"); err == nil {
+			configFilePath = os.Getenv("GOPATH") + "/bin/enuapi.json"
+		} else {
+			if _, err := os.Stat(os.Getenv("GOPATH") + "/src/github.com/vennd/enu/enuapi.json"); err == nil {
+				configFilePath = os.Getenv("GOPATH") + "/src/github.com/vennd/enu/enuapi.json"
+			} else {
+				log.Println("Cannot find enuapi.json")
+				os.Exit(-100)
+			}
+		}
+	}
+
+	InitWithConfigPath(configFilePath)
+}
+
+func InitWithConfigPath(configFilePath string) {
+	var configuration interface{}
+
+	if isInit == true {
+		return
+	}
+
+	// Read configuration from file
+	file, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		log.Println("Unable to read configuration file enuapi.json")
+		log.Println(err.Error())
+		os.Exit(-101)
+	}
+
+	err = json.Unmarshal(file, &configuration)
+	if err != nil {
+		log.Println("Unable to parse enuapi.json")
+		log.Println(err.Error())
+		os.Exit(-101)
+	}
+
+	m := configuration.(map[string]interface{})
+
+	if m["channelintervalseconds"] != nil && m["channelintervalseconds"].(float64) > 0 {
+		intervalSeconds = int(m["channelintervalseconds"].(float64))
+	}
+
+	if m["channelclosemarginseconds"] != nil {
+		closeMarginSeconds = int64(m["channelclosemarginseconds"].(float64))
+	}
+
+	isInit = true
+
+	go run()
+}
+
+func run() {
+	for {
+		CheckChannels(context.TODO())
+
+		time.Sleep(time.Duration(intervalSeconds) * time.Second)
+	}
+}
+
+// Decides whether the channel should be closed. Open channels are closed within the margin of their expiry so the
+// destination is paid before the channel expires. Channels already closing are closed once they have expired, which
+// removes them from the ledger
+func shouldClose(status string, expiry int64, now int64) bool {
+	if expiry == 0 {
+		return false
+	}
+
+	if status == "closing" {
+		return now >= expiry
+	}
+
+	return now >= expiry-closeMarginSeconds
+}
+
+// Checks every open payment channel and closes those about to expire
+func CheckChannels(c context.Context) {
+	channels, err := database.GetOpenChannels(c)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetOpenChannels(): %s", err.Error())
+		return
+	}
+
+	env := os.Getenv("ENV")
+	if env == "" {
+		env = "dev"
+	}
+
+	for _, record := range channels {
+		// Build the context the handlers expect as if the access key had requested the close
+		channelContext := context.WithValue(c, consts.RequestIdKey, enulib.GenerateRequestId())
+		channelContext = context.WithValue(channelContext, consts.EnvKey, env)
+		channelContext = context.WithValue(channelContext, consts.AccessKeyKey, record.AccessKey)
+		channelContext = context.WithValue(channelContext, consts.BlockchainIdKey, consts.RippleBlockchainId)
+		channelContext = context.WithValue(channelContext, consts.RequestTypeKey, "channelClose")
+
+		checkChannel(channelContext, record)
+	}
+}
+
+func checkChannel(c context.Context, record database.ChannelRecord) {
+	channel := record.Channel
+
+	ledgerChannel, errorCode, err := rippleapi.GetChannel(c, channel.ChannelId)
+	if errorCode == consts.RippleErrors.ChannelNotOpen.Code {
+		log.FluentfContext(consts.LOGINFO, c, "Payment channel %s has been removed from the ledger. Closed.", channel.ChannelId)
+		database.UpdateChannelClosedByChannelId(c, record.AccessKey, channel.ChannelId)
+
+		return
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Unable to get payment channel %s: %s", channel.ChannelId, err.Error())
+
+		return
+	}
+
+	if !shouldClose(channel.Status, ripplehandlers.ChannelExpiry(ledgerChannel), time.Now().Unix()) {
+		return
+	}
+
+	if _, err := ripplehandlers.CloseChannel(c, record.AccessKey, record.WalletId, channel, ledgerChannel); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Unable to close payment channel %s: %s", channel.ChannelId, err.Error())
+	}
+}
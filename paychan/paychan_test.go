@@ -0,0 +1,31 @@
+package paychan
+
+import (
+	"testing"
+)
+
+func TestShouldClose(t *testing.T) {
+	closeMarginSeconds = 3600
+	var now int64 = 1500000000
+
+	var testData = []struct {
+		Status          string
+		Expiry          int64
+		Expected        bool
+		CaseDescription string
+	}{
+		{"open", 0, false, "Channel which never expires"},
+		{"open", now + 7200, false, "Channel expiring after the margin"},
+		{"open", now + 3600, true, "Channel expiring at the margin"},
+		{"open", now - 60, true, "Channel which has expired"},
+		{"closing", now + 60, false, "Closing channel still settling"},
+		{"closing", now, true, "Closing channel which has expired"},
+	}
+
+	for _, s := range testData {
+		result := shouldClose(s.Status, s.Expiry, now)
+		if result != s.Expected {
+			t.Errorf("%s. Expected: %t. Got: %t\n", s.CaseDescription, s.Expected, result)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package rippleapi
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/ripplecrypto"
+)
+
+// Payment channel claim flags (on the transaction)
+const TfRenew = 65536
+const TfClose = 131072
+
+// Payment channels only hold XRP so Amount is the drops of XRP in a string. PublicKey is the hex of the key which signs
+// claims against the channel. SettleDelay is the number of seconds the source must wait to close the channel while it
+// still holds XRP and CancelAfter is the Ripple time after which the channel expires
+type PaymentChannelCreateTx struct {
+	// Common fields
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          *uint32       `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
+
+	Amount         string  `json:",omitempty"`
+	Destination    string  `json:",omitempty"`
+	DestinationTag *uint32 `json:",omitempty"`
+	SettleDelay    uint32  `json:",omitempty"`
+	PublicKey      string  `json:",omitempty"`
+	CancelAfter    uint32  `json:",omitempty"`
+}
+
+// Adds Amount drops of XRP to the channel. Expiration is the Ripple time after which the channel expires
+type PaymentChannelFundTx struct {
+	// Common fields
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          *uint32       `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
+
+	Channel    string `json:",omitempty"`
+	Amount     string `json:",omitempty"`
+	Expiration uint32 `json:",omitempty"`
+}
+
+// Balance is the total drops of XRP delivered to the destination once the claim is processed. The destination must
+// give the Signature and PublicKey of a claim of at least Balance, given as Amount. The source doesn't need a claim
+type PaymentChannelClaimTx struct {
+	// Common fields
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          *uint32       `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
+
+	Channel   string `json:",omitempty"`
+	Balance   string `json:",omitempty"`
+	Amount    string `json:",omitempty"`
+	Signature string `json:",omitempty"`
+	PublicKey string `json:",omitempty"`
+}
+
+// A payment channel in the ledger. Amount is the drops of XRP held by the channel and Balance the drops already
+// delivered to the destination. Expiration and CancelAfter are Ripple times
+type Channel struct {
+	ChannelId      string
+	Account        string
+	Destination    string
+	DestinationTag *uint32
+	Amount         string
+	Balance        string
+	PublicKey      string
+	SettleDelay    uint32
+	Expiration     uint32
+	CancelAfter    uint32
+}
+
+// Creates a payment channel holding the drops of XRP for the destination. Returns the tx hash and the channel id
+func CreateChannel(c context.Context, account string, destination string, amount string, destinationTag *uint32, settleDelay uint32, publicKey string, cancelAfter uint32, signer TxSigner) (string, string, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", "", errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return "", "", errCode, err
+	}
+
+	// The channel id is derived from the source, destination and sequence of the tx which creates it
+	channelId, err := ripplecrypto.ChannelId(account, destination, sequence)
+	if err != nil {
+		return "", "", consts.GenericErrors.InvalidAddress.Code, errors.New(consts.GenericErrors.InvalidAddress.Description)
+	}
+
+	tx := PaymentChannelCreateTx{
+		// Common fields
+		TransactionType: "PaymentChannelCreate",
+		Account:         account,
+		Flags:           2147483648, // require canonical signature
+		Fee:             fee,
+		Sequence:        sequence,
+
+		Amount:         amount,
+		Destination:    destination,
+		DestinationTag: destinationTag,
+		SettleDelay:    settleDelay,
+		PublicKey:      publicKey,
+		CancelAfter:    cancelAfter,
+	}
+
+	txHash, errCode, err := signAndSubmit(c, account, tx, signer)
+
+	return txHash, channelId, errCode, err
+}
+
+// Adds the drops of XRP to the channel. If expiration is not zero the expiration of the channel is changed to it
+func FundChannel(c context.Context, account string, channelId string, amount string, expiration uint32, signer TxSigner) (string, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	tx := PaymentChannelFundTx{
+		// Common fields
+		TransactionType: "PaymentChannelFund",
+		Account:         account,
+		Flags:           2147483648, // require canonical signature
+		Fee:             fee,
+		Sequence:        sequence,
+
+		Channel:    channelId,
+		Amount:     amount,
+		Expiration: expiration,
+	}
+
+	return signAndSubmit(c, account, tx, signer)
+}
+
+// Delivers balance drops of XRP in total to the destination of the channel and/or closes it with the TfClose flag.
+// The signature and public key of a claim of amount drops must be given when the destination submits the claim
+func ClaimChannel(c context.Context, account string, channelId string, balance string, amount string, signature string, publicKey string, flags uint32, signer TxSigner) (string, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	tx := PaymentChannelClaimTx{
+		// Common fields
+		TransactionType: "PaymentChannelClaim",
+		Account:         account,
+		Flags:           2147483648 | flags, // require canonical signature
+		Fee:             fee,
+		Sequence:        sequence,
+
+		Channel:   channelId,
+		Balance:   balance,
+		Amount:    amount,
+		Signature: signature,
+		PublicKey: publicKey,
+	}
+
+	return signAndSubmit(c, account, tx, signer)
+}
+
+// Gets the payment channel from the validated ledger. Returns ChannelNotOpen once the channel has been closed
+func GetChannel(c context.Context, channelId string) (Channel, int64, error) {
+	var payload = make(map[string]interface{})
+	var params = make(map[string]interface{})
+	var paramsArray []map[string]interface{}
+	var result Channel
+
+	if isInit == false {
+		Init()
+	}
+
+	// Build parameters
+	params["payment_channel"] = channelId
+	params["ledger_index"] = "validated"
+	paramsArray = append(paramsArray, params)
+
+	// Build payload
+	payload["method"] = "ledger_entry"
+	payload["params"] = paramsArray
+
+	payloadJsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Marshal(): %s", err.Error())
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	responseData, errCode, err := postRPCAPI(c, payloadJsonBytes)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in postRPCAPI(): %s", err.Error())
+		return result, errCode, err
+	}
+
+	if responseData["result"] == nil {
+		log.FluentfContext(consts.LOGERROR, c, "Didn't receive a result from RPC server")
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	r := responseData["result"].(map[string]interface{})
+
+	if r["error"] != nil {
+		if r["error"].(string) == "entryNotFound" {
+			return result, consts.RippleErrors.ChannelNotOpen.Code, errors.New(consts.RippleErrors.ChannelNotOpen.Description)
+		}
+
+		return result, consts.RippleErrors.MiscError.Code, errors.New(r["error"].(string))
+	}
+
+	if r["node"] == nil {
+		return result, consts.RippleErrors.ChannelNotOpen.Code, errors.New(consts.RippleErrors.ChannelNotOpen.Description)
+	}
+
+	node := r["node"].(map[string]interface{})
+	result.ChannelId = channelId
+	result.Account, _ = node["Account"].(string)
+	result.Destination, _ = node["Destination"].(string)
+	result.Amount, _ = node["Amount"].(string)
+	result.Balance, _ = node["Balance"].(string)
+	result.PublicKey, _ = node["PublicKey"].(string)
+
+	if node["DestinationTag"] != nil {
+		tag := uint32(node["DestinationTag"].(float64))
+		result.DestinationTag = &tag
+	}
+
+	if node["SettleDelay"] != nil {
+		result.SettleDelay = uint32(node["SettleDelay"].(float64))
+	}
+
+	if node["Expiration"] != nil {
+		result.Expiration = uint32(node["Expiration"].(float64))
+	}
+
+	if node["CancelAfter"] != nil {
+		result.CancelAfter = uint32(node["CancelAfter"].(float64))
+	}
+
+	return result, 0, nil
+}
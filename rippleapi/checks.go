@@ -0,0 +1,286 @@
+package rippleapi
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/ripplecrypto"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// SendMax is the most the destination may cash, either drops of XRP in a string or an Amount of another currency.
+// Expiration is a Ripple time and InvoiceID the hex of a 256 bit id the sender may use to identify the check
+type CheckCreateTx struct {
+	// Common fields
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          *uint32       `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
+
+	Destination    string      `json:",omitempty"`
+	DestinationTag *uint32     `json:",omitempty"`
+	SendMax        interface{} `json:",omitempty"`
+	Expiration     uint32      `json:",omitempty"`
+	InvoiceID      string      `json:",omitempty"`
+}
+
+// Exactly one of Amount, to cash an exact amount, or DeliverMin, to cash as much as possible but at least the
+// amount, must be given
+type CheckCashTx struct {
+	// Common fields
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          *uint32       `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
+
+	CheckID    string      `json:",omitempty"`
+	Amount     interface{} `json:",omitempty"`
+	DeliverMin interface{} `json:",omitempty"`
+}
+
+type CheckCancelTx struct {
+	// Common fields
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          *uint32       `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
+
+	CheckID string `json:",omitempty"`
+}
+
+// A check in the ledger as returned by ledger_entry or account_objects
+type Check struct {
+	CheckId        string
+	Account        string
+	Destination    string
+	DestinationTag *uint32
+	SendMax        Amount
+	Expiration     uint32
+	InvoiceID      string
+	PreviousTxnID  string
+}
+
+// Creates a check which the destination can cash for up to sendMax, given by NewAmount(). expiration is a Ripple time
+// and may be zero. Returns the tx hash and the id of the check in the ledger
+func CreateCheck(c context.Context, account string, destination string, sendMax interface{}, destinationTag *uint32, expiration uint32, invoiceId string, signer TxSigner) (string, string, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", "", errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return "", "", errCode, err
+	}
+
+	// The check id is derived from the source and sequence of the tx which creates it
+	checkId, err := ripplecrypto.CheckId(account, sequence)
+	if err != nil {
+		return "", "", consts.GenericErrors.InvalidAddress.Code, errors.New(consts.GenericErrors.InvalidAddress.Description)
+	}
+
+	tx := CheckCreateTx{
+		// Common fields
+		TransactionType: "CheckCreate",
+		Account:         account,
+		Flags:           2147483648, // require canonical signature
+		Fee:             fee,
+		Sequence:        sequence,
+
+		Destination:    destination,
+		DestinationTag: destinationTag,
+		SendMax:        sendMax,
+		Expiration:     expiration,
+		InvoiceID:      invoiceId,
+	}
+
+	txHash, errCode, err := signAndSubmit(c, account, tx, signer)
+
+	return txHash, checkId, errCode, err
+}
+
+// Cashes the check into the account, which must be the destination of the check. Either the exact amount or
+// deliverMin, given by NewAmount(), must be given and the other nil
+func CashCheck(c context.Context, account string, checkId string, amount interface{}, deliverMin interface{}, signer TxSigner) (string, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	tx := CheckCashTx{
+		// Common fields
+		TransactionType: "CheckCash",
+		Account:         account,
+		Flags:           2147483648, // require canonical signature
+		Fee:             fee,
+		Sequence:        sequence,
+
+		CheckID:    checkId,
+		Amount:     amount,
+		DeliverMin: deliverMin,
+	}
+
+	return signAndSubmit(c, account, tx, signer)
+}
+
+// Removes the check from the ledger. Checks can be cancelled by the sender or the destination, or by anyone once
+// they have expired
+func CancelCheck(c context.Context, account string, checkId string, signer TxSigner) (string, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	tx := CheckCancelTx{
+		// Common fields
+		TransactionType: "CheckCancel",
+		Account:         account,
+		Flags:           2147483648, // require canonical signature
+		Fee:             fee,
+		Sequence:        sequence,
+
+		CheckID: checkId,
+	}
+
+	return signAndSubmit(c, account, tx, signer)
+}
+
+func parseCheck(checkId string, object map[string]interface{}) Check {
+	var result Check
+
+	result.CheckId = checkId
+	result.Account, _ = object["Account"].(string)
+	result.Destination, _ = object["Destination"].(string)
+	result.InvoiceID, _ = object["InvoiceID"].(string)
+	result.PreviousTxnID, _ = object["PreviousTxnID"].(string)
+	result.SendMax = parseAmount(object["SendMax"])
+
+	if object["DestinationTag"] != nil {
+		tag := uint32(object["DestinationTag"].(float64))
+		result.DestinationTag = &tag
+	}
+
+	if object["Expiration"] != nil {
+		result.Expiration = uint32(object["Expiration"].(float64))
+	}
+
+	return result
+}
+
+// Gets the check from the validated ledger. Returns the CheckNotOpen error if the check is not in the ledger
+func GetCheck(c context.Context, checkId string) (Check, int64, error) {
+	var payload = make(map[string]interface{})
+	var params = make(map[string]interface{})
+	var paramsArray []map[string]interface{}
+	var result Check
+
+	if isInit == false {
+		Init()
+	}
+
+	// Build parameters
+	params["check"] = checkId
+	params["ledger_index"] = "validated"
+	paramsArray = append(paramsArray, params)
+
+	// Build payload
+	payload["method"] = "ledger_entry"
+	payload["params"] = paramsArray
+
+	payloadJsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Marshal(): %s", err.Error())
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	responseData, errCode, err := postRPCAPI(c, payloadJsonBytes)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in postRPCAPI(): %s", err.Error())
+		return result, errCode, err
+	}
+
+	if responseData["result"] == nil {
+		log.FluentfContext(consts.LOGERROR, c, "Didn't receive a result from RPC server")
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	r := responseData["result"].(map[string]interface{})
+
+	if r["error"] != nil {
+		if r["error"].(string) == "entryNotFound" {
+			return result, consts.RippleErrors.CheckNotOpen.Code, errors.New(consts.RippleErrors.CheckNotOpen.Description)
+		}
+
+		return result, consts.RippleErrors.MiscError.Code, errors.New(r["error"].(string))
+	}
+
+	if r["node"] == nil {
+		return result, consts.RippleErrors.CheckNotOpen.Code, errors.New(consts.RippleErrors.CheckNotOpen.Description)
+	}
+
+	return parseCheck(checkId, r["node"].(map[string]interface{})), 0, nil
+}
+
+// Gets the checks in the ledger which the account either sent or is the destination of
+func GetAccountChecks(c context.Context, account string) ([]Check, int64, error) {
+	var result []Check
+
+	objects, errCode, err := getAccountObjects(c, account, "check")
+	if err != nil {
+		return result, errCode, err
+	}
+
+	for _, object := range objects {
+		checkId, _ := object["index"].(string)
+
+		result = append(result, parseCheck(checkId, object))
+	}
+
+	return result, 0, nil
+}
@@ -0,0 +1,267 @@
+package rippleapi
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Ripple times are given in seconds since the Ripple epoch, 2000-01-01T00:00:00Z
+const RippleEpoch = 946684800
+
+// Converts a unix time to a Ripple time
+func ToRippleTime(unixTime int64) uint32 {
+	if unixTime <= RippleEpoch {
+		return 0
+	}
+
+	return uint32(unixTime - RippleEpoch)
+}
+
+// Converts a Ripple time to a unix time
+func FromRippleTime(rippleTime uint32) int64 {
+	if rippleTime == 0 {
+		return 0
+	}
+
+	return int64(rippleTime) + RippleEpoch
+}
+
+// Returns the current time as a Ripple time
+func RippleNow() uint32 {
+	return ToRippleTime(time.Now().Unix())
+}
+
+// Escrows only hold XRP so the Amount is always the drops of XRP in a string. FinishAfter and CancelAfter are Ripple
+// times. Condition is the hex of a crypto-condition which must be fulfilled to finish the escrow
+type EscrowCreateTx struct {
+	// Common fields
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          *uint32       `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
+
+	Amount         string  `json:",omitempty"`
+	Destination    string  `json:",omitempty"`
+	DestinationTag *uint32 `json:",omitempty"`
+	CancelAfter    uint32  `json:",omitempty"`
+	FinishAfter    uint32  `json:",omitempty"`
+	Condition      string  `json:",omitempty"`
+}
+
+// The escrow is identified by its Owner and the Sequence of the EscrowCreate, given as OfferSequence
+type EscrowFinishTx struct {
+	// Common fields
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          *uint32       `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
+
+	Owner         string `json:",omitempty"`
+	OfferSequence uint32 `json:",omitempty"`
+	Condition     string `json:",omitempty"`
+	Fulfillment   string `json:",omitempty"`
+}
+
+type EscrowCancelTx struct {
+	// Common fields
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          *uint32       `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
+
+	Owner         string `json:",omitempty"`
+	OfferSequence uint32 `json:",omitempty"`
+}
+
+// An escrow in the ledger as returned by account_objects
+type Escrow struct {
+	Account        string
+	Destination    string
+	DestinationTag *uint32
+	Amount         string
+	Condition      string
+	CancelAfter    uint32
+	FinishAfter    uint32
+	PreviousTxnID  string
+}
+
+// Holds the XRP amount, given in drops, in escrow until it is finished by the destination or cancelled. finishAfter
+// and cancelAfter are Ripple times and condition is the hex of a crypto-condition, any of which may be zero or empty.
+// Returns the tx hash and the sequence of the tx which identifies the escrow in the ledger
+func CreateEscrow(c context.Context, account string, destination string, amount string, destinationTag *uint32, finishAfter uint32, cancelAfter uint32, condition string, signer TxSigner) (string, uint32, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", 0, errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return "", 0, errCode, err
+	}
+
+	tx := EscrowCreateTx{
+		// Common fields
+		TransactionType: "EscrowCreate",
+		Account:         account,
+		Flags:           2147483648, // require canonical signature
+		Fee:             fee,
+		Sequence:        sequence,
+
+		Amount:         amount,
+		Destination:    destination,
+		DestinationTag: destinationTag,
+		FinishAfter:    finishAfter,
+		CancelAfter:    cancelAfter,
+		Condition:      condition,
+	}
+
+	txHash, errCode, err := signAndSubmit(c, account, tx, signer)
+
+	return txHash, sequence, errCode, err
+}
+
+// Releases the escrow created by the owner's tx with the given sequence to its destination. The condition and
+// fulfillment must be given if the escrow was created with a condition
+func FinishEscrow(c context.Context, account string, owner string, offerSequence uint32, condition string, fulfillment string, signer TxSigner) (string, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	fee, errCode, err := CurrentFee(c)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	tx := EscrowFinishTx{
+		// Common fields
+		TransactionType: "EscrowFinish",
+		Account:         account,
+		Flags:           2147483648, // require canonical signature
+		Fee:             escrowFinishFee(fee, fulfillment),
+		Sequence:        sequence,
+
+		Owner:         owner,
+		OfferSequence: offerSequence,
+		Condition:     condition,
+		Fulfillment:   fulfillment,
+	}
+
+	return signAndSubmit(c, account, tx, signer)
+}
+
+// Returns the XRP held by the escrow created by the owner's tx with the given sequence to the owner. Escrows can
+// only be cancelled after their CancelAfter time
+func CancelEscrow(c context.Context, account string, owner string, offerSequence uint32, signer TxSigner) (string, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	tx := EscrowCancelTx{
+		// Common fields
+		TransactionType: "EscrowCancel",
+		Account:         account,
+		Flags:           2147483648, // require canonical signature
+		Fee:             fee,
+		Sequence:        sequence,
+
+		Owner:         owner,
+		OfferSequence: offerSequence,
+	}
+
+	return signAndSubmit(c, account, tx, signer)
+}
+
+// Finishing an escrow with a fulfillment costs 330 drops plus 10 drops for every 16 bytes of the fulfillment. The
+// fee is never less than the fee for other txs
+func escrowFinishFee(fee uint64, fulfillment string) string {
+	if fulfillment == "" {
+		return strconv.FormatUint(fee, 10)
+	}
+
+	bytes := uint64(len(fulfillment) / 2)
+	fulfillmentFee := 330 + 10*((bytes+15)/16)
+	if fulfillmentFee < fee {
+		return strconv.FormatUint(fee, 10)
+	}
+
+	return strconv.FormatUint(fulfillmentFee, 10)
+}
+
+// Gets the escrows in the ledger which the account either holds or is the destination of
+func GetAccountEscrows(c context.Context, account string) ([]Escrow, int64, error) {
+	var result []Escrow
+
+	objects, errCode, err := getAccountObjects(c, account, "escrow")
+	if err != nil {
+		return result, errCode, err
+	}
+
+	for _, object := range objects {
+		var escrow Escrow
+
+		escrow.Account, _ = object["Account"].(string)
+		escrow.Destination, _ = object["Destination"].(string)
+		escrow.Amount, _ = object["Amount"].(string)
+		escrow.Condition, _ = object["Condition"].(string)
+		escrow.PreviousTxnID, _ = object["PreviousTxnID"].(string)
+
+		if object["DestinationTag"] != nil {
+			tag := uint32(object["DestinationTag"].(float64))
+			escrow.DestinationTag = &tag
+		}
+
+		if object["CancelAfter"] != nil {
+			escrow.CancelAfter = uint32(object["CancelAfter"].(float64))
+		}
+
+		if object["FinishAfter"] != nil {
+			escrow.FinishAfter = uint32(object["FinishAfter"].(float64))
+		}
+
+		result = append(result, escrow)
+	}
+
+	return result, 0, nil
+}
@@ -0,0 +1,235 @@
+package rippleapi
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+	"github.com/vennd/enu/log"
+)
+
+// How long the fee levels of the server are reused before they are requested again. Ledgers close every few seconds
+const feeLevelsCacheTime = 5 * time.Second
+
+// The fees, in drops, required by the server for a tx to be applied to the current open ledger. When the open ledger
+// is full the open ledger fee escalates and txs paying less are queued until a later ledger or are dropped
+type FeeLevels struct {
+	BaseFee          uint64  `json:"baseFee"`
+	MedianFee        uint64  `json:"medianFee,omitempty"`
+	MinimumFee       uint64  `json:"minimumFee"`
+	OpenLedgerFee    uint64  `json:"openLedgerFee"`
+	LoadFactor       float64 `json:"loadFactor"`
+	CurrentQueueSize uint64  `json:"currentQueueSize"`
+	MaxQueueSize     uint64  `json:"maxQueueSize,omitempty"`
+	LedgerIndex      uint64  `json:"ledgerIndex,omitempty"`
+}
+
+var feeLevelsCache = struct {
+	sync.Mutex
+	levels    FeeLevels
+	retrieved time.Time
+}{}
+
+// Returns the fee levels of the server from the fee method. Servers which don't support the fee method have their
+// fee levels calculated from the load factor of server_info
+func GetFeeLevels(c context.Context) (FeeLevels, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	feeLevelsCache.Lock()
+	defer feeLevelsCache.Unlock()
+
+	if !feeLevelsCache.retrieved.IsZero() && time.Since(feeLevelsCache.retrieved) < feeLevelsCacheTime {
+		return feeLevelsCache.levels, 0, nil
+	}
+
+	r, errCode, err := feeRPC(c, "fee")
+	if err != nil {
+		return FeeLevels{}, errCode, err
+	}
+
+	levels, ok := parseFee(r)
+	if !ok {
+		log.FluentfContext(consts.LOGINFO, c, "The fee method is unavailable, using the load factor from server_info")
+
+		r, errCode, err = feeRPC(c, "server_info")
+		if err != nil {
+			return FeeLevels{}, errCode, err
+		}
+
+		if levels, ok = parseServerInfoFee(r); !ok {
+			log.FluentfContext(consts.LOGERROR, c, "Unable to read the fee levels from server_info. Got: %#v", r)
+			return FeeLevels{}, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+		}
+	}
+
+	feeLevelsCache.levels = levels
+	feeLevelsCache.retrieved = time.Now()
+
+	return levels, 0, nil
+}
+
+func feeRPC(c context.Context, method string) (map[string]interface{}, int64, error) {
+	var payload = make(map[string]interface{})
+
+	payload["method"] = method
+	payload["params"] = []map[string]interface{}{{}}
+
+	payloadJsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Marshal(): %s", err.Error())
+		return nil, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	responseData, errCode, err := postRPCAPI(c, payloadJsonBytes)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in postRPCAPI(): %s", err.Error())
+		return nil, errCode, err
+	}
+
+	if responseData["result"] == nil {
+		log.FluentfContext(consts.LOGERROR, c, "Didn't receive a result from RPC server")
+		return nil, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	return responseData["result"].(map[string]interface{}), 0, nil
+}
+
+// Reads numbers which rippled returns either as strings or as JSON numbers
+func toUint64(value interface{}) uint64 {
+	switch v := value.(type) {
+	case string:
+		result, _ := strconv.ParseUint(v, 10, 64)
+		return result
+	case float64:
+		return uint64(v)
+	}
+
+	return 0
+}
+
+// Parses the result of the fee method. Returns false if the server doesn't support it
+func parseFee(r map[string]interface{}) (FeeLevels, bool) {
+	var result FeeLevels
+
+	drops, ok := r["drops"].(map[string]interface{})
+	if !ok || r["error"] != nil {
+		return result, false
+	}
+
+	result.BaseFee = toUint64(drops["base_fee"])
+	result.MedianFee = toUint64(drops["median_fee"])
+	result.MinimumFee = toUint64(drops["minimum_fee"])
+	result.OpenLedgerFee = toUint64(drops["open_ledger_fee"])
+	result.CurrentQueueSize = toUint64(r["current_queue_size"])
+	result.MaxQueueSize = toUint64(r["max_queue_size"])
+	result.LedgerIndex = toUint64(r["ledger_current_index"])
+
+	if levels, ok := r["levels"].(map[string]interface{}); ok {
+		if reference := toUint64(levels["reference_level"]); reference > 0 {
+			result.LoadFactor = float64(toUint64(levels["open_ledger_level"])) / float64(reference)
+		}
+	}
+
+	return result, result.BaseFee > 0
+}
+
+// Calculates the fee levels from the base fee of the validated ledger and the load factor in the result of server_info
+func parseServerInfoFee(r map[string]interface{}) (FeeLevels, bool) {
+	var result FeeLevels
+
+	info, ok := r["info"].(map[string]interface{})
+	if !ok {
+		return result, false
+	}
+
+	ledger, ok := info["validated_ledger"].(map[string]interface{})
+	if !ok {
+		return result, false
+	}
+
+	baseFeeXrp, _ := ledger["base_fee_xrp"].(float64)
+	result.BaseFee = uint64(baseFeeXrp*1000000 + 0.5)
+	result.LedgerIndex = toUint64(ledger["seq"])
+
+	result.LoadFactor, _ = info["load_factor"].(float64)
+	if result.LoadFactor < 1 {
+		result.LoadFactor = 1
+	}
+
+	result.MinimumFee = result.BaseFee
+	result.OpenLedgerFee = uint64(float64(result.BaseFee)*result.LoadFactor + 0.5)
+
+	return result, result.BaseFee > 0
+}
+
+// Returns the maximum fee in drops the request will pay for each tx, or the maximum set with rippleMaxFee in
+// enuapi.json. Zero if neither is set
+func maxFee(c context.Context) uint64 {
+	if requestMaxFee, ok := c.Value(consts.MaxFeeKey).(uint64); ok && requestMaxFee > 0 {
+		return requestMaxFee
+	}
+
+	return rippleMaxFee
+}
+
+// Chooses the fee for a tx. The fee is never less than DefaultFee so txs are applied as soon as possible while the
+// server isn't loaded, and is raised to the open ledger fee when fees escalate. If the fee exceeds the maximum fee it
+// is lowered to the maximum, provided the open ledger still accepts it
+func selectFee(levels FeeLevels, maxFee uint64) (uint64, error) {
+	required := levels.OpenLedgerFee
+	if levels.MinimumFee > required {
+		required = levels.MinimumFee
+	}
+
+	fee := DefaultFeeI
+	if required > fee {
+		fee = required
+	}
+
+	if maxFee > 0 && fee > maxFee {
+		if required > maxFee {
+			return required, errors.New(consts.RippleErrors.FeeTooHigh.Description)
+		}
+
+		fee = maxFee
+	}
+
+	return fee, nil
+}
+
+// Returns the fee in drops to pay for a tx given the current load of the server and the maximum fee of the request
+func CurrentFee(c context.Context) (uint64, int64, error) {
+	levels, errCode, err := GetFeeLevels(c)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetFeeLevels(): %s", err.Error())
+		return 0, errCode, err
+	}
+
+	fee, err := selectFee(levels, maxFee(c))
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "The open ledger fee of %d drops is more than the maximum fee of %d drops", fee, maxFee(c))
+		return fee, consts.RippleErrors.FeeTooHigh.Code, err
+	}
+
+	if fee > DefaultFeeI {
+		log.FluentfContext(consts.LOGINFO, c, "Fees have escalated. Paying %d drops with a load factor of %f", fee, levels.LoadFactor)
+	}
+
+	return fee, 0, nil
+}
+
+// As CurrentFee() but returns the fee as a string for the Fee field of a tx
+func currentFeeString(c context.Context) (string, int64, error) {
+	fee, errCode, err := CurrentFee(c)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	return strconv.FormatUint(fee, 10), 0, nil
+}
@@ -0,0 +1,100 @@
+package rippleapi
+
+import (
+	"testing"
+)
+
+func TestSelectFee(t *testing.T) {
+	idle := FeeLevels{BaseFee: 10, MinimumFee: 10, OpenLedgerFee: 10}
+	escalated := FeeLevels{BaseFee: 10, MinimumFee: 10, OpenLedgerFee: 25000}
+
+	var testData = []struct {
+		Levels          FeeLevels
+		MaxFee          uint64
+		Expected        uint64
+		ExpectError     bool
+		CaseDescription string
+	}{
+		{idle, 0, DefaultFeeI, false, "Server isn't loaded"},
+		{escalated, 0, 25000, false, "Fees have escalated"},
+		{escalated, 30000, 25000, false, "Escalated fee below the maximum"},
+		{escalated, 20000, 25000, true, "Escalated fee above the maximum"},
+		{idle, 100, 100, false, "Maximum below the default fee"},
+		{idle, 5, 10, true, "Maximum below the minimum fee"},
+	}
+
+	for _, s := range testData {
+		fee, err := selectFee(s.Levels, s.MaxFee)
+		if fee != s.Expected || (err != nil) != s.ExpectError {
+			t.Errorf("%s. Expected: %d, error: %t, got: %d, %v\n", s.CaseDescription, s.Expected, s.ExpectError, fee, err)
+		}
+	}
+}
+
+func TestParseFee(t *testing.T) {
+	result := map[string]interface{}{
+		"current_queue_size":   "12",
+		"max_queue_size":       "2000",
+		"ledger_current_index": float64(35000000),
+		"drops": map[string]interface{}{
+			"base_fee":        "10",
+			"median_fee":      "5000",
+			"minimum_fee":     "10",
+			"open_ledger_fee": "2560",
+		},
+		"levels": map[string]interface{}{
+			"reference_level":   "256",
+			"open_ledger_level": "65536",
+		},
+	}
+
+	levels, ok := parseFee(result)
+	if !ok {
+		t.Fatalf("Expected the fee levels to be parsed\n")
+	}
+
+	if levels.BaseFee != 10 || levels.MedianFee != 5000 || levels.OpenLedgerFee != 2560 || levels.CurrentQueueSize != 12 || levels.MaxQueueSize != 2000 || levels.LedgerIndex != 35000000 {
+		t.Errorf("Unexpected fee levels: %+v\n", levels)
+	}
+
+	if levels.LoadFactor != 256 {
+		t.Errorf("Expected a load factor of 256, got: %f\n", levels.LoadFactor)
+	}
+
+	if _, ok := parseFee(map[string]interface{}{"error": "unknownCmd"}); ok {
+		t.Errorf("Expected servers without the fee method not to be parsed\n")
+	}
+}
+
+func TestParseServerInfoFee(t *testing.T) {
+	result := map[string]interface{}{
+		"info": map[string]interface{}{
+			"load_factor": float64(1000),
+			"validated_ledger": map[string]interface{}{
+				"base_fee_xrp": 0.00001,
+				"seq":          float64(35000000),
+			},
+		},
+	}
+
+	levels, ok := parseServerInfoFee(result)
+	if !ok {
+		t.Fatalf("Expected the fee levels to be calculated\n")
+	}
+
+	if levels.BaseFee != 10 || levels.MinimumFee != 10 || levels.OpenLedgerFee != 10000 || levels.LedgerIndex != 35000000 {
+		t.Errorf("Unexpected fee levels: %+v\n", levels)
+	}
+}
+
+func TestEscrowFinishFee(t *testing.T) {
+	if fee := escrowFinishFee(25000, ""); fee != "25000" {
+		t.Errorf("Expected the current fee without a fulfillment, got: %s\n", fee)
+	}
+
+	// 330 drops plus 10 drops for each 16 bytes of a 1000 byte fulfillment
+	fulfillment := make([]byte, 2000)
+	if fee := escrowFinishFee(10, string(fulfillment)); fee != "960" {
+		t.Errorf("Expected the fulfillment fee, got: %s\n", fee)
+	}
+}
@@ -0,0 +1,137 @@
+package rippleapi
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// The balances of an issuing account as returned by gateway_balances. Obligations is the total amount issued per
+// currency, excluding amounts held by the hot wallets. Balances and FrozenBalances are keyed by the holding account
+type GatewayBalances struct {
+	Account        string
+	Obligations    map[string]string
+	Balances       map[string][]Amount
+	FrozenBalances map[string][]Amount
+	Assets         map[string][]Amount
+}
+
+func parseAccountAmounts(object interface{}) map[string][]Amount {
+	result := make(map[string][]Amount)
+
+	if object == nil {
+		return result
+	}
+
+	for account, amounts := range object.(map[string]interface{}) {
+		for _, amount := range amounts.([]interface{}) {
+			result[account] = append(result[account], parseAmount(amount))
+		}
+	}
+
+	return result
+}
+
+// Gets the obligations of the issuing account in the validated ledger. Amounts held by the hot wallets given are
+// returned in Balances rather than counted as obligations
+func GetGatewayBalances(c context.Context, account string, hotWallets []string) (GatewayBalances, int64, error) {
+	var payload = make(map[string]interface{})
+	var params = make(map[string]interface{})
+	var paramsArray []map[string]interface{}
+	var result GatewayBalances
+
+	if isInit == false {
+		Init()
+	}
+
+	result.Account = account
+	result.Obligations = make(map[string]string)
+
+	// Build parameters
+	params["account"] = account
+	params["strict"] = true
+	params["ledger_index"] = "validated"
+	if len(hotWallets) > 0 {
+		params["hotwallet"] = hotWallets
+	}
+	paramsArray = append(paramsArray, params)
+
+	// Build payload
+	payload["method"] = "gateway_balances"
+	payload["params"] = paramsArray
+
+	payloadJsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Marshal(): %s", err.Error())
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	responseData, errCode, err := postRPCAPI(c, payloadJsonBytes)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in postRPCAPI(): %s", err.Error())
+		return result, errCode, err
+	}
+
+	if responseData["result"] == nil {
+		log.FluentfContext(consts.LOGERROR, c, "Didn't receive a result from RPC server")
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	r := responseData["result"].(map[string]interface{})
+
+	// Result returned but with an error
+	if r["error"] != nil && r["error"].(string) == "actNotFound" {
+		// account not found, we won't raise an error but return an empty structure
+		return result, 0, nil
+	} else if r["error"] != nil {
+		return result, consts.RippleErrors.MiscError.Code, errors.New(r["error"].(string))
+	}
+
+	if r["obligations"] != nil {
+		for currency, value := range r["obligations"].(map[string]interface{}) {
+			result.Obligations[currency] = value.(string)
+		}
+	}
+
+	result.Balances = parseAccountAmounts(r["balances"])
+	result.FrozenBalances = parseAccountAmounts(r["frozen_balances"])
+	result.Assets = parseAccountAmounts(r["assets"])
+
+	return result, 0, nil
+}
+
+// Returns true if the transaction is a successful payment by the issuer of its own currency
+func isIssuance(tx AccountTransaction, issuer string, currency string) bool {
+	return tx.TransactionType == "Payment" && tx.TransactionResult == "tesSUCCESS" && tx.Account == issuer && tx.DeliveredAmount.Issuer == issuer && tx.DeliveredAmount.Currency == currency
+}
+
+// Gets every payment by which the issuer issued the currency, oldest first
+func GetIssuances(c context.Context, issuer string, currency string) ([]AccountTransaction, int64, error) {
+	var result []AccountTransaction
+	var marker interface{}
+
+	for {
+		page, errCode, err := GetAccountTransactions(c, issuer, -1, -1, 0, true, marker)
+		if err != nil {
+			return result, errCode, err
+		}
+
+		for _, tx := range page.Transactions {
+			if isIssuance(tx, issuer, currency) {
+				result = append(result, tx)
+			}
+		}
+
+		if page.Marker == nil {
+			break
+		}
+
+		marker = page.Marker
+	}
+
+	return result, 0, nil
+}
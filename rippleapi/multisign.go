@@ -0,0 +1,145 @@
+package rippleapi
+
+import (
+	"strconv"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/ripplecrypto"
+)
+
+// Maximum number of signers in a signer list
+const MaxSigners = 8
+
+type SignerEntry struct {
+	Account      string `json:",omitempty"`
+	SignerWeight uint16 `json:",omitempty"`
+}
+
+type SignerEntryWrapper struct {
+	SignerEntry SignerEntry
+}
+
+// Sets the signers which may multisign for the account. The multisigned tx is authorised once the weights of its
+// signers reach the SignerQuorum. A SignerQuorum of zero, without SignerEntries, removes the signer list
+type SignerListSetTx struct {
+	// Common fields
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          *uint32       `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
+
+	SignerQuorum  uint32
+	SignerEntries []SignerEntryWrapper `json:",omitempty"`
+}
+
+// Replaces the signer list of the account
+func SetSignerList(c context.Context, account string, quorum uint32, entries []SignerEntry, signer TxSigner) (string, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	tx := SignerListSetTx{
+		// Common fields
+		TransactionType: "SignerListSet",
+		Account:         account,
+		Flags:           2147483648, // require canonical signature
+		Fee:             fee,
+		Sequence:        sequence,
+
+		SignerQuorum: quorum,
+	}
+
+	for _, entry := range entries {
+		tx.SignerEntries = append(tx.SignerEntries, SignerEntryWrapper{SignerEntry: entry})
+	}
+
+	return signAndSubmit(c, account, tx, signer)
+}
+
+// Creates the unsigned AccountSet with the Sequence filled so it can be signed offline. See SubmitAccountSet()
+func ComposeAccountSet(c context.Context, account string, settings AccountSet) (AccountSet, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return settings, errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return settings, errCode, err
+	}
+
+	tx := settings
+
+	// Common fields
+	tx.TransactionType = "AccountSet"
+	tx.Account = account
+	tx.Flags = 2147483648 // require canonical signature
+	tx.Fee = fee
+	tx.Sequence = sequence
+
+	return tx, 0, nil
+}
+
+// Prepares a composed tx to be multisigned by up to the given number of signers. The fee of a multisigned tx is the
+// fee of the composed tx for each signature plus the tx itself. The LastLedgerSequence is removed as collecting the
+// signatures may take longer than a few ledgers
+func PrepareMultisigned(tx interface{}, signers int) (map[string]interface{}, error) {
+	txMap, err := ripplecrypto.TransactionToMap(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	fee := DefaultFeeI
+	if txFee, ok := txMap["Fee"].(string); ok {
+		if parsed, err := strconv.ParseUint(txFee, 10, 64); err == nil {
+			fee = parsed
+		}
+	}
+
+	delete(txMap, "LastLedgerSequence")
+	delete(txMap, "TxnSignature")
+	txMap["SigningPubKey"] = ""
+	txMap["Fee"] = strconv.FormatUint(fee*uint64(1+signers), 10)
+
+	return txMap, nil
+}
+
+// Combines the signatures of the signers into the multisigned tx and submits it
+func SubmitMultisigned(c context.Context, tx interface{}, signers []ripplecrypto.MultiSigner) (string, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	txBlob, txHash, err := ripplecrypto.AssembleMultiSigned(tx, signers)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in ripplecrypto.AssembleMultiSigned(): %s", err.Error())
+		return "", consts.RippleErrors.SigningError.Code, err
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Multisigned tx: %s", txHash)
+
+	return Submit(c, txBlob)
+}
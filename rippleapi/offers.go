@@ -0,0 +1,327 @@
+package rippleapi
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+	"github.com/vennd/enu/log"
+)
+
+// Offer create flags (on the transaction)
+const TfPassive = 65536
+const TfImmediateOrCancel = 131072
+const TfFillOrKill = 262144
+const TfSell = 524288
+
+// Offer flags (in the ledger)
+const LsfPassive = 65536
+const LsfSell = 131072
+
+// TakerGets and TakerPays are either the drops of XRP in a string or an Amount for other currencies, see NewAmount()
+type OfferCreateTx struct {
+	// Common fields
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          uint32        `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
+
+	Expiration    uint32      `json:",omitempty"`
+	OfferSequence uint32      `json:",omitempty"`
+	TakerGets     interface{} `json:",omitempty"`
+	TakerPays     interface{} `json:",omitempty"`
+}
+
+type OfferCancelTx struct {
+	// Common fields
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          uint32        `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
+
+	OfferSequence uint32 `json:",omitempty"`
+}
+
+// An offer in the ledger as returned by account_offers and book_offers
+type Offer struct {
+	Account    string `json:",omitempty"`
+	Sequence   uint32 `json:"seq,omitempty"`
+	Flags      uint32 `json:"flags,omitempty"`
+	TakerGets  Amount `json:"taker_gets,omitempty"`
+	TakerPays  Amount `json:"taker_pays,omitempty"`
+	Quality    string `json:"quality,omitempty"`
+	OwnerFunds string `json:"owner_funds,omitempty"`
+}
+
+// Returns the amount as it is given in a transaction. XRP is given in drops as a string and other currencies as an Amount
+func NewAmount(currency string, issuer string, value string) interface{} {
+	if strings.ToUpper(currency) == "XRP" {
+		return value
+	}
+
+	return Amount{Value: value, Currency: currency, Issuer: issuer}
+}
+
+// Parses an amount returned by rippled. XRP amounts are returned with the currency XRP and the value in drops
+func parseAmount(amount interface{}) Amount {
+	switch a := amount.(type) {
+	case string:
+		return Amount{Value: a, Currency: "XRP"}
+	case map[string]interface{}:
+		var result Amount
+
+		if a["value"] != nil {
+			result.Value = a["value"].(string)
+		}
+		if a["currency"] != nil {
+			result.Currency = a["currency"].(string)
+		}
+		if a["issuer"] != nil {
+			result.Issuer = a["issuer"].(string)
+		}
+
+		return result
+	}
+
+	return Amount{}
+}
+
+func parseOffer(offer map[string]interface{}) Offer {
+	var result Offer
+
+	// account_offers and book_offers use different names for the same fields
+	if offer["Account"] != nil {
+		result.Account = offer["Account"].(string)
+	}
+
+	if offer["seq"] != nil {
+		result.Sequence = uint32(offer["seq"].(float64))
+	} else if offer["Sequence"] != nil {
+		result.Sequence = uint32(offer["Sequence"].(float64))
+	}
+
+	if offer["flags"] != nil {
+		result.Flags = uint32(offer["flags"].(float64))
+	} else if offer["Flags"] != nil {
+		result.Flags = uint32(offer["Flags"].(float64))
+	}
+
+	if offer["taker_gets"] != nil {
+		result.TakerGets = parseAmount(offer["taker_gets"])
+	} else {
+		result.TakerGets = parseAmount(offer["TakerGets"])
+	}
+
+	if offer["taker_pays"] != nil {
+		result.TakerPays = parseAmount(offer["taker_pays"])
+	} else {
+		result.TakerPays = parseAmount(offer["TakerPays"])
+	}
+
+	if offer["quality"] != nil {
+		result.Quality = offer["quality"].(string)
+	}
+
+	if offer["owner_funds"] != nil {
+		result.OwnerFunds = offer["owner_funds"].(string)
+	}
+
+	return result
+}
+
+// Places an offer in the decentralised exchange. The account gives takerGets in exchange for takerPays.
+// Returns the tx hash and the sequence of the tx which identifies the offer in the ledger
+func CreateOffer(c context.Context, account string, takerGets interface{}, takerPays interface{}, flags uint32, signer TxSigner) (string, uint32, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", 0, errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return "", 0, errCode, err
+	}
+
+	tx := OfferCreateTx{
+		// Common fields
+		TransactionType: "OfferCreate",
+		Account:         account,
+		Flags:           2147483648 | flags, // require canonical signature
+		Fee:             fee,
+		Sequence:        sequence,
+
+		TakerGets: takerGets,
+		TakerPays: takerPays,
+	}
+
+	txHash, errCode, err := signAndSubmit(c, account, tx, signer)
+
+	return txHash, sequence, errCode, err
+}
+
+// Removes the offer placed by the tx with the given sequence from the decentralised exchange
+func CancelOffer(c context.Context, account string, offerSequence uint32, signer TxSigner) (string, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	tx := OfferCancelTx{
+		// Common fields
+		TransactionType: "OfferCancel",
+		Account:         account,
+		Flags:           2147483648, // require canonical signature
+		Fee:             fee,
+		Sequence:        sequence,
+
+		OfferSequence: offerSequence,
+	}
+
+	return signAndSubmit(c, account, tx, signer)
+}
+
+// Gets the offers placed by the account which are still in the ledger
+func GetAccountOffers(c context.Context, account string) ([]Offer, int64, error) {
+	var payload = make(map[string]interface{})
+	var params = make(map[string]interface{})
+	var paramsArray []map[string]interface{}
+	var result []Offer
+
+	if isInit == false {
+		Init()
+	}
+
+	// Build parameters
+	params["account"] = account
+	params["ledger_index"] = "validated"
+	paramsArray = append(paramsArray, params)
+
+	// Build payload
+	payload["method"] = "account_offers"
+	payload["params"] = paramsArray
+
+	payloadJsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Marshal(): %s", err.Error())
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	responseData, errCode, err := postRPCAPI(c, payloadJsonBytes)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in postRPCAPI(): %s", err.Error())
+		return result, errCode, err
+	}
+
+	if responseData["result"] == nil {
+		log.FluentfContext(consts.LOGERROR, c, "Didn't receive a result from RPC server")
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	r := responseData["result"].(map[string]interface{})
+
+	// Result returned but with an error
+	if r["error"] != nil && r["error_code"].(float64) == 18 {
+		// account not found, we won't raise an error but return an empty structure
+		return result, 0, nil
+	} else if r["error"] != nil {
+		return result, consts.RippleErrors.MiscError.Code, errors.New(r["error_message"].(string))
+	}
+
+	if r["offers"] != nil {
+		for _, offer := range r["offers"].([]interface{}) {
+			parsed := parseOffer(offer.(map[string]interface{}))
+			parsed.Account = account
+
+			result = append(result, parsed)
+		}
+	}
+
+	return result, 0, nil
+}
+
+// Gets the offers in the order book where the taker gets takerGets and pays takerPays. The currency of XRP is XRP and
+// has no issuer. At most limit offers are returned, the best offers first.
+func GetBookOffers(c context.Context, takerGets Amount, takerPays Amount, limit uint) ([]Offer, int64, error) {
+	var payload = make(map[string]interface{})
+	var params = make(map[string]interface{})
+	var paramsArray []map[string]interface{}
+	var result []Offer
+
+	if isInit == false {
+		Init()
+	}
+
+	// Build parameters
+	params["taker_gets"] = Amount{Currency: takerGets.Currency, Issuer: takerGets.Issuer}
+	params["taker_pays"] = Amount{Currency: takerPays.Currency, Issuer: takerPays.Issuer}
+	params["ledger_index"] = "validated"
+	if limit > 0 {
+		params["limit"] = limit
+	}
+	paramsArray = append(paramsArray, params)
+
+	// Build payload
+	payload["method"] = "book_offers"
+	payload["params"] = paramsArray
+
+	payloadJsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Marshal(): %s", err.Error())
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	responseData, errCode, err := postRPCAPI(c, payloadJsonBytes)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in postRPCAPI(): %s", err.Error())
+		return result, errCode, err
+	}
+
+	if responseData["result"] == nil {
+		log.FluentfContext(consts.LOGERROR, c, "Didn't receive a result from RPC server")
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	r := responseData["result"].(map[string]interface{})
+
+	if r["error"] != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error from book_offers: %s", r["error"].(string))
+		return result, consts.RippleErrors.InvalidCurrency.Code, errors.New(consts.RippleErrors.InvalidCurrency.Description)
+	}
+
+	if r["offers"] != nil {
+		for _, offer := range r["offers"].([]interface{}) {
+			result = append(result, parseOffer(offer.(map[string]interface{})))
+		}
+	}
+
+	return result, 0, nil
+}
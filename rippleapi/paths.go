@@ -0,0 +1,253 @@
+package rippleapi
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+	"github.com/vennd/enu/log"
+)
+
+// Payment flags (on the transaction)
+const TfNoDirectRipple = 65536
+const TfPartialPayment = 131072
+const TfLimitQuality = 262144
+
+// A step in a payment path. Each step is either an account to ripple through or a currency and issuer to exchange into
+type PathStep struct {
+	Account  string `json:"account,omitempty"`
+	Currency string `json:"currency,omitempty"`
+	Issuer   string `json:"issuer,omitempty"`
+}
+
+// A way of paying the destination amount found by ripple_path_find. SourceAmount is the amount the source pays
+type PathAlternative struct {
+	Paths        [][]PathStep
+	SourceAmount Amount
+}
+
+// Structure for payments where the source pays in a different currency to the one delivered. Amount, SendMax and
+// DeliverMin are either the drops of XRP in a string or an Amount for other currencies, see NewAmount()
+type PathPaymentTx struct {
+	// Common fields
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint64        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          *uint32       `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
+
+	// Payment specific fields
+	Amount         interface{}  `json:",omitempty"`
+	SendMax        interface{}  `json:",omitempty"`
+	DeliverMin     interface{}  `json:",omitempty"`
+	Paths          [][]PathStep `json:",omitempty"`
+	Destination    string       `json:",omitempty"`
+	DestinationTag *uint32      `json:",omitempty"`
+	InvoiceID      string       `json:",omitempty"`
+}
+
+// Finds the ways the source can pay the destination amount with the send currency. The issuer of the send currency
+// should be empty for XRP. The alternatives are returned in the order given by rippled.
+func FindPaths(c context.Context, source string, destination string, destinationAmount interface{}, sendCurrency Amount) ([]PathAlternative, int64, error) {
+	var payload = make(map[string]interface{})
+	var params = make(map[string]interface{})
+	var paramsArray []map[string]interface{}
+	var result []PathAlternative
+
+	if isInit == false {
+		Init()
+	}
+
+	// Build parameters
+	params["source_account"] = source
+	params["destination_account"] = destination
+	params["destination_amount"] = destinationAmount
+	params["source_currencies"] = []Amount{{Currency: sendCurrency.Currency, Issuer: sendCurrency.Issuer}}
+	paramsArray = append(paramsArray, params)
+
+	// Build payload
+	payload["method"] = "ripple_path_find"
+	payload["params"] = paramsArray
+
+	payloadJsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Marshal(): %s", err.Error())
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	responseData, errCode, err := postRPCAPI(c, payloadJsonBytes)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in postRPCAPI(): %s", err.Error())
+		return result, errCode, err
+	}
+
+	if responseData["result"] == nil {
+		log.FluentfContext(consts.LOGERROR, c, "Didn't receive a result from RPC server")
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	r := responseData["result"].(map[string]interface{})
+
+	if r["error"] != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error from ripple_path_find: %s", r["error"].(string))
+		return result, consts.RippleErrors.NoPathFound.Code, errors.New(consts.RippleErrors.NoPathFound.Description)
+	}
+
+	if r["alternatives"] != nil {
+		for _, a := range r["alternatives"].([]interface{}) {
+			var alternative PathAlternative
+
+			alternativeMap := a.(map[string]interface{})
+			alternative.SourceAmount = parseAmount(alternativeMap["source_amount"])
+
+			if alternativeMap["paths_computed"] != nil {
+				for _, p := range alternativeMap["paths_computed"].([]interface{}) {
+					var path []PathStep
+
+					for _, s := range p.([]interface{}) {
+						var step PathStep
+
+						stepMap := s.(map[string]interface{})
+						if stepMap["account"] != nil {
+							step.Account = stepMap["account"].(string)
+						}
+						if stepMap["currency"] != nil {
+							step.Currency = stepMap["currency"].(string)
+						}
+						if stepMap["issuer"] != nil {
+							step.Issuer = stepMap["issuer"].(string)
+						}
+
+						path = append(path, step)
+					}
+
+					alternative.Paths = append(alternative.Paths, path)
+				}
+			}
+
+			result = append(result, alternative)
+		}
+	}
+
+	if len(result) == 0 {
+		return result, consts.RippleErrors.NoPathFound.Code, errors.New(consts.RippleErrors.NoPathFound.Description)
+	}
+
+	return result, 0, nil
+}
+
+// Creates and signs a payment which delivers amount to the destination through the paths, costing the source no more
+// than sendMax. If deliverMin is given the payment is a partial payment which succeeds if at least deliverMin is
+// delivered. Returns the signed tx and the fee in drops it pays.
+func CreatePathPayment(c context.Context, account string, destination string, amount interface{}, sendMax interface{}, deliverMin interface{}, paths [][]PathStep, options PaymentOptions, signer TxSigner) (string, uint64, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	// Set LastLedgerSequence
+	latestLedger, errCode, err := GetLatestValidatedLedger(c)
+	if err != nil {
+		return "", 0, errCode, err
+	}
+
+	latestLedgerSequence, err := strconv.ParseUint(latestLedger.LedgerIndex, 10, 64)
+	if err != nil {
+		return "", 0, consts.RippleErrors.UnableToGetLatestLedger.Code, errors.New(consts.RippleErrors.UnableToGetLatestLedger.Description)
+	}
+
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", 0, errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return "", 0, errCode, err
+	}
+
+	tx := PathPaymentTx{
+		TransactionType:    "Payment",
+		Account:            account,
+		Destination:        destination,
+		Amount:             amount,
+		SendMax:            sendMax,
+		Paths:              paths,
+		Flags:              2147483648, // require canonical signature
+		Fee:                fee,
+		LastLedgerSequence: latestLedgerSequence + uint64(rippleLastLedgerSequenceOffset),
+		Sequence:           sequence,
+		DestinationTag:     options.DestinationTag,
+		SourceTag:          options.SourceTag,
+		Memos:              options.memos(),
+	}
+
+	if deliverMin != nil {
+		tx.DeliverMin = deliverMin
+		tx.Flags |= TfPartialPayment
+	}
+
+	signedTx, errCode, err := signer.SignRippleTransaction(c, account, tx)
+	if err != nil {
+		return "", 0, errCode, err
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "signed! tx_blob: %s", signedTx)
+
+	txFee, _ := strconv.ParseUint(fee, 10, 64)
+
+	return signedTx, txFee, 0, nil
+}
+
+// Waits until the tx submitted by the account is validated or the LastLedgerSequence offset has passed. Returns the tx
+// as last seen
+func WaitForValidation(c context.Context, txHash string, account string) (Transaction, int64, error) {
+	var tx Transaction
+	var errCode int64
+	var err error
+
+	if isInit == false {
+		Init()
+	}
+
+	// Transactions aren't submitted in dev mode so there is nothing to wait for
+	if c.Value(consts.EnvKey).(string) == "dev" {
+		log.FluentfContext(consts.LOGINFO, c, "In dev mode, not waiting for tx to be validated.")
+		return Transaction{Hash: txHash, Validated: true}, 0, nil
+	}
+
+	// Wait for the outcome on the stream if it is connected rather than polling
+	if ledgerIndex, _, err := latestValidatedLedgerIndex(c); err == nil {
+		if tx, ok := waitForTransaction(c, txHash, account, ledgerIndex+uint64(rippleLastLedgerSequenceOffset)); ok {
+			if tx.Validated {
+				return tx, 0, nil
+			}
+
+			return tx, consts.RippleErrors.TxNotValidated.Code, errors.New(consts.RippleErrors.TxNotValidated.Description)
+		}
+	}
+
+	// A ledger closes every few seconds so allow a few seconds per ledger
+	for i := uint(0); i < (rippleLastLedgerSequenceOffset+1)*5; i++ {
+		tx, errCode, err = GetTx(c, txHash)
+		if err != nil {
+			return tx, errCode, err
+		}
+
+		if tx.Validated {
+			return tx, 0, nil
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	return tx, consts.RippleErrors.TxNotValidated.Code, errors.New(consts.RippleErrors.TxNotValidated.Description)
+}
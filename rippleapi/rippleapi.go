@@ -15,8 +15,10 @@ import (
 	"time"
 
 	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
 	"github.com/vennd/enu/internal/golang.org/x/net/context"
 	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/ripplecrypto"
 )
 
 var DefaultFee = "10000"
@@ -37,8 +39,19 @@ const AsfGlobalFreeze = 7
 const AsfDefaultRipple = 8
 
 // AccountRoot Flags
+const LsfPasswordSpent = 65536
+const LsfRequireDestTag = 131072
+const LsfRequireAuth = 262144
+const LsfDisallowXRP = 524288
+const LsfDisableMaster = 1048576
+const LsfNoFreeze = 2097152
+const LsfGlobalFreeze = 4194304
 const LsfDefaultRipple = 8388608
 
+// Transfer rate which charges no fee. Transfer rates are in billionths of a unit, ie a rate of 2000000000 is a 100% fee
+const TransferRateNoFee = 1000000000
+const TransferRateMaximum = 2000000000
+
 // Trust set flags (on the transaction)
 const TfSetfAuth = 65536
 const TfSetNoRipple = 131072
@@ -55,60 +68,110 @@ type Amount struct {
 // Structure for payment transactions for custom currencies
 type PaymentAssetTx struct {
 	// Common fields
-	Account            string `json:",omitempty"`
-	AccountTxnID       string `json:",omitempty"`
-	Fee                string `json:",omitempty"`
-	Flags              uint32 `json:",omitempty"`
-	LastLedgerSequence uint64 `json:",omitempty"`
-	Memos              []Memo
-	Sequence           uint32 `json:",omitempty"`
-	SigningPubKey      string `json:",omitempty"`
-	SourceTag          uint32 `json:",omitempty"`
-	TransactionType    string `json:",omitempty"`
-	TxnSignature       string `json:",omitempty"`
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint64        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          *uint32       `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
 
 	// Payment specific fields
 	Amount         Amount // Note the Amount field is different between sending XRP or a custom currency
 	SendMax        Amount
 	Destination    string
-	DestinationTag uint32
-	InvoiceID      string
-	//	Paths
-	//	SendMax Currency
-	//	DeliverMin Currency
+	DestinationTag *uint32 `json:",omitempty"`
+	InvoiceID      string  `json:",omitempty"`
+	// Paths and DeliverMin are only used by path payments, see PathPaymentTx
 }
 
 // Structure for payment transactions for xrp
 type PaymentXrpTx struct {
 	// Common fields
-	Account            string `json:",omitempty"`
-	AccountTxnID       string `json:",omitempty"`
-	Fee                string `json:",omitempty"`
-	Flags              uint32 `json:",omitempty"`
-	LastLedgerSequence uint64 `json:",omitempty"`
-	Memos              []Memo
-	Sequence           uint32 `json:",omitempty"`
-	SigningPubKey      string `json:",omitempty"`
-	SourceTag          uint32 `json:",omitempty"`
-	TransactionType    string `json:",omitempty"`
-	TxnSignature       string `json:",omitempty"`
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint64        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          *uint32       `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
 
 	// Payment specific fields
-	Amount         string `json:",omitempty"`
-	Destination    string `json:",omitempty"`
-	DestinationTag uint32 `json:",omitempty"`
-	InvoiceID      string `json:",omitempty"`
-	//	Paths
-	//	SendMax Currency
-	//	DeliverMin Currency
+	Amount         string  `json:",omitempty"`
+	Destination    string  `json:",omitempty"`
+	DestinationTag *uint32 `json:",omitempty"`
+	InvoiceID      string  `json:",omitempty"`
+	// SendMax, Paths and DeliverMin are only used by path payments, see PathPaymentTx
 }
 
+// The fields of a memo are hex encoded, see NewMemo()
 type Memo struct {
 	MemoData   string `json:",omitempty"`
 	MemoFormat string `json:",omitempty"`
 	MemoType   string `json:",omitempty"`
 }
 
+// Memos are given in a transaction as an array of objects which each contain a Memo, ie [{"Memo": {...}}]
+type MemoWrapper struct {
+	Memo Memo
+}
+
+// The optional fields of a payment. Tags are pointers because 0 is a valid tag
+type PaymentOptions struct {
+	DestinationTag *uint32
+	SourceTag      *uint32
+	Memos          []Memo
+}
+
+// The maximum size of the memos of a transaction in bytes
+const MaxMemoSize = 1024
+
+// Creates a memo from the plain text type, format and data
+func NewMemo(memoType string, memoFormat string, memoData string) Memo {
+	return Memo{
+		MemoType:   strings.ToUpper(hex.EncodeToString([]byte(memoType))),
+		MemoFormat: strings.ToUpper(hex.EncodeToString([]byte(memoFormat))),
+		MemoData:   strings.ToUpper(hex.EncodeToString([]byte(memoData))),
+	}
+}
+
+// Returns the plain text type, format and data of a memo given in hex in a transaction. Fields which are not valid
+// hex are returned as they were given
+func DecodeMemo(memo Memo) (string, string, string) {
+	var result [3]string
+
+	for i, field := range []string{memo.MemoType, memo.MemoFormat, memo.MemoData} {
+		decoded, err := hex.DecodeString(field)
+		if err != nil {
+			result[i] = field
+			continue
+		}
+
+		result[i] = string(decoded)
+	}
+
+	return result[0], result[1], result[2]
+}
+
+// Returns the memos wrapped as they are given in a transaction
+func (o PaymentOptions) memos() []MemoWrapper {
+	var result []MemoWrapper
+
+	for _, memo := range o.Memos {
+		result = append(result, MemoWrapper{Memo: memo})
+	}
+
+	return result
+}
+
 type Wallet struct {
 	AccountId     string `json:"account_id"`
 	KeyType       string `json:"key_type"`
@@ -196,17 +259,17 @@ type Currency struct {
 
 type AccountSet struct {
 	// Common fields
-	Account            string `json:",omitempty"`
-	AccountTxnID       string `json:",omitempty"`
-	Fee                string `json:",omitempty"`
-	Flags              uint32 `json:",omitempty"`
-	LastLedgerSequence uint32 `json:",omitempty"`
-	Memos              []Memo
-	Sequence           uint32 `json:",omitempty"`
-	SigningPubKey      string `json:",omitempty"`
-	SourceTag          uint32 `json:",omitempty"`
-	TransactionType    string `json:",omitempty"`
-	TxnSignature       string `json:",omitempty"`
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          uint32        `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
 
 	ClearFlag    uint32 `json:",omitempty"`
 	Domain       string `json:",omitempty"`
@@ -224,17 +287,17 @@ type LimitAmount struct {
 
 type TrustSetStruct struct {
 	// Common fields
-	Account            string `json:",omitempty"`
-	AccountTxnID       string `json:",omitempty"`
-	Fee                string `json:",omitempty"`
-	Flags              uint32 `json:",omitempty"`
-	LastLedgerSequence uint32 `json:",omitempty"`
-	Memos              []Memo `json:",omitempty"`
-	Sequence           uint32 `json:",omitempty"`
-	SigningPubKey      string `json:",omitempty"`
-	SourceTag          uint32 `json:",omitempty"`
-	TransactionType    string `json:",omitempty"`
-	TxnSignature       string `json:",omitempty"`
+	Account            string        `json:",omitempty"`
+	AccountTxnID       string        `json:",omitempty"`
+	Fee                string        `json:",omitempty"`
+	Flags              uint32        `json:",omitempty"`
+	LastLedgerSequence uint32        `json:",omitempty"`
+	Memos              []MemoWrapper `json:",omitempty"`
+	Sequence           uint32        `json:",omitempty"`
+	SigningPubKey      string        `json:",omitempty"`
+	SourceTag          uint32        `json:",omitempty"`
+	TransactionType    string        `json:",omitempty"`
+	TxnSignature       string        `json:",omitempty"`
 
 	LimitAmount LimitAmount `json:",omitempty"`
 	QualityIn   uint32      `json:",omitempty"`
@@ -249,6 +312,8 @@ type Line struct {
 	LimitPeer    string `json:"limit_peer,omitempty"`
 	NoRipple     bool   `json:"no_ripple,omitempty"`
 	NoRipplePeer bool   `json:"no_ripple_peer,omitempty"`
+	Freeze       bool   `json:"freeze,omitempty"`
+	FreezePeer   bool   `json:"freeze_peer,omitempty"`
 	QualityIn    uint   `json:"quality_in,omitempty"`
 	QualityOut   uint   `json:"quality_out,omitempty"`
 }
@@ -284,6 +349,10 @@ type AccountInfo struct {
 	PreviousTxnID   string `json:",omitempty"`
 	Sequence        int    `json:",omitempty"`
 	Index           string `json:"index,omitempty"`
+	Domain          string `json:",omitempty"`
+	EmailHash       string `json:",omitempty"`
+	TransferRate    uint32 `json:",omitempty"`
+	RegularKey      string `json:",omitempty"`
 }
 
 type LedgerValue struct {
@@ -315,22 +384,19 @@ type LedgerResult struct {
 }
 
 type Transaction struct {
-	Account     string `json:",omitempty"`
-	Hash        string `json:"hash,omitempty"`
-	LedgerIndex uint64 `json:"ledger_index,omitempty"`
-	Validated   bool   `json:"validated,omitempty"`
-}
-
-// Used to store the internal wallets
-type MasterWallet struct {
-	Address    string `json:"address"`
-	Passphrase string `json:"passphrase"`
+	Account           string `json:",omitempty"`
+	Hash              string `json:"hash,omitempty"`
+	LedgerIndex       uint64 `json:"ledger_index,omitempty"`
+	Validated         bool   `json:"validated,omitempty"`
+	TransactionResult string `json:",omitempty"`
+	DeliveredAmount   Amount `json:"delivered_amount,omitempty"`
 }
 
 // Initialises global variables and database connection for all handlers
 var isInit bool = false // set to true only after the init sequence is complete
 var rippleHost string
-var RippleWallets []MasterWallet
+var rippleWebsocketHost string
+var rippleMaxFee uint64
 var rippleLastLedgerSequenceOffset uint
 
 func Init() {
@@ -392,14 +458,14 @@ func InitWithConfigPath(configFilePath string) {
 	rippleHost = m["rippleHost"].(string) // End point for JSON RPC server
 	rippleLastLedgerSequenceOffset = uint(m["rippleLastLedgerSequenceOffset"].(float64))
 
-	for _, w := range m["rippleWallets"].([]interface{}) {
-		var wallet MasterWallet
-		var wmap = w.(map[string]interface{})
-
-		wallet.Address = wmap["address"].(string)
-		wallet.Passphrase = wmap["passphrase"].(string)
+	// Optional end point for the WebSocket API, eg wss://s1.ripple.com:443
+	if m["rippleWebsocketHost"] != nil {
+		rippleWebsocketHost = m["rippleWebsocketHost"].(string)
+	}
 
-		RippleWallets = append(RippleWallets, wallet)
+	// Optional maximum fee in drops paid for a tx when fees escalate, unless the request gives its own maxFee
+	if m["rippleMaxFee"] != nil {
+		rippleMaxFee = uint64(m["rippleMaxFee"].(float64))
 	}
 
 	isInit = true
@@ -507,11 +573,16 @@ func Submit(c context.Context, txHexString string) (string, int64, error) {
 	if responseData["result"] != nil {
 		r := responseData["result"].(map[string]interface{})
 
-		if r["engine_result"] != nil && r["engine_result"] == "tesSUCCESS" {
-			result = r["tx_json"].(map[string]interface{})["hash"].(string)
-		} else {
-			result = r["tx_json"].(map[string]interface{})["hash"].(string) // attempt to return the tx_hash such that we can query on later
+		// The hash is returned even if the tx failed so it can be queried later
+		txJson, _ := r["tx_json"].(map[string]interface{})
+		hash, ok := txJson["hash"].(string)
+		if !ok {
+			log.FluentfContext(consts.LOGERROR, c, "No tx_json hash in the submit result. Got: %#v", r)
+			return "", consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+		}
+		result = hash
 
+		if r["engine_result"] != "tesSUCCESS" {
 			var engineResult string
 			var engineResultCode int64
 			var engineResultMessage string
@@ -541,6 +612,20 @@ func Submit(c context.Context, txHexString string) (string, int64, error) {
 					return "", errorCode, err
 				}
 
+				// Wait for the outcome on the stream if it is connected rather than polling
+				account, _ := txJson["Account"].(string)
+				if cutOff, err := strconv.ParseUint(currentLedger.LedgerIndex, 10, 64); err == nil {
+					if tx, ok := waitForTransaction(c, result, account, cutOff+uint64(rippleLastLedgerSequenceOffset)); ok {
+						if tx.Validated != true {
+							log.FluentfContext(consts.LOGERROR, c, "Transaction was not accepted due to esclation of transaction fees!")
+							return "", consts.RippleErrors.QueuedNotAccepted.Code, errors.New(consts.RippleErrors.QueuedNotAccepted.Description)
+						}
+
+						log.FluentfContext(consts.LOGINFO, c, "Warning - transaction was queued due to escalated fees but subsequently accepted")
+						return tx.Hash, 0, nil
+					}
+				}
+
 				interval := time.Duration(1) * time.Second
 				for i := 1; i <= 10; i++ {
 					time.Sleep(interval) // throttle a second
@@ -605,14 +690,8 @@ func Submit(c context.Context, txHexString string) (string, int64, error) {
 
 			// tec* codes indicates the fee was lost
 			if strings.HasPrefix(engineResult, "tec") {
-				if engineResult == "tecPATH_DRY" {
-					return result, consts.RippleErrors.InvalidCurrencyOrNoTrustline.Code, errors.New(consts.RippleErrors.InvalidCurrencyOrNoTrustline.Description)
-				}
-
-				if engineResult == "tecUNFUNDED_PAYMENT" {
-					return result, consts.RippleErrors.InsufficientXRP.Code, errors.New(consts.RippleErrors.InsufficientXRP.Description)
-				}
-				return result, consts.RippleErrors.SubmitErrorFeeLost.Code, errors.New(consts.RippleErrors.SubmitErrorFeeLost.Description)
+				errCode, err := TxResultError(engineResult)
+				return result, errCode, err
 			}
 
 			return result, consts.RippleErrors.SubmitError.Code, errors.New(consts.RippleErrors.SubmitError.Description)
@@ -631,74 +710,141 @@ func Submit(c context.Context, txHexString string) (string, int64, error) {
 	return result, 0, nil
 }
 
-// Signs a tx with the given secret. The tx should be a struct containing the tx to be marshalled into JSON and then signed
+// Returns the error for a tec* result, ie a tx which was applied to a ledger, so its fee was lost, but which failed
+func TxResultError(transactionResult string) (int64, error) {
+	switch transactionResult {
+	case "tecPATH_DRY":
+		return consts.RippleErrors.InvalidCurrencyOrNoTrustline.Code, errors.New(consts.RippleErrors.InvalidCurrencyOrNoTrustline.Description)
+	case "tecUNFUNDED_PAYMENT":
+		return consts.RippleErrors.InsufficientXRP.Code, errors.New(consts.RippleErrors.InsufficientXRP.Description)
+	case "tecUNFUNDED_OFFER":
+		return consts.RippleErrors.OfferUnfunded.Code, errors.New(consts.RippleErrors.OfferUnfunded.Description)
+	case "tecKILLED":
+		return consts.RippleErrors.OfferKilled.Code, errors.New(consts.RippleErrors.OfferKilled.Description)
+	case "tecCRYPTOCONDITION_ERROR":
+		return consts.RippleErrors.InvalidCondition.Code, errors.New(consts.RippleErrors.InvalidCondition.Description)
+	case "tecDST_TAG_NEEDED":
+		return consts.RippleErrors.DestinationTagRequired.Code, errors.New(consts.RippleErrors.DestinationTagRequired.Description)
+	case "tecPATH_PARTIAL":
+		return consts.RippleErrors.PathPartial.Code, errors.New(consts.RippleErrors.PathPartial.Description)
+	case "tecOWNERS":
+		return consts.RippleErrors.RequireAuthHasOwners.Code, errors.New(consts.RippleErrors.RequireAuthHasOwners.Description)
+	}
+
+	return consts.RippleErrors.SubmitErrorFeeLost.Code, errors.New(consts.RippleErrors.SubmitErrorFeeLost.Description)
+}
+
+// Signs Ripple transactions on behalf of an account. The tx is a struct containing the tx to be marshalled into JSON.
+// Returns the signed tx blob. Implemented by the signers in the signer package.
+type TxSigner interface {
+	SignRippleTransaction(c context.Context, account string, tx interface{}) (string, int64, error)
+}
+
+// Signs a tx offline with the given secret. The tx should be a struct containing the tx to be marshalled into JSON and then signed.
+// The secret is never sent to rippled. Sequence and Fee must be set on the tx, see NextSequence()
 func Sign(c context.Context, tx interface{}, secret string) (string, int64, error) {
-	if isInit == false {
-		Init()
+	txBlob, txHash, err := ripplecrypto.SignTransaction(tx, secret)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in ripplecrypto.SignTransaction(): %s", err.Error())
+		return "", consts.RippleErrors.SigningError.Code, errors.New(consts.RippleErrors.SigningError.Description)
 	}
 
+	log.FluentfContext(consts.LOGINFO, c, "Signed tx: %s", txHash)
+
+	return txBlob, 0, nil
+}
+
+// Signs the tx with the signer of the account and submits it. Sequence and Fee must be set on the tx, see NextSequence()
+func signAndSubmit(c context.Context, account string, tx interface{}, signer TxSigner) (string, int64, error) {
+	signedTx, errCode, err := signer.SignRippleTransaction(c, account, tx)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in SignRippleTransaction(): %s", err.Error())
+		return "", errCode, err
+	}
+
+	txHash, errCode, err := Submit(c, signedTx)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Submit(): %s", err.Error())
+	}
+
+	return txHash, errCode, err
+}
+
+// Returns the sequence number to use for the next transaction from the account. Transactions are signed offline
+// so the sequence must be set on the tx rather than autofilled by rippled. The sequence is read from the current open
+// ledger and the account's queued txs, not the last validated ledger, so txs sent one after another before the
+// earlier ones are validated don't reuse a sequence
+func NextSequence(c context.Context, account string) (uint32, int64, error) {
 	var payload = make(map[string]interface{})
 	var params = make(map[string]interface{})
-	var paramsArray []map[string]interface{}
-	var result string
 
-	// Build parameters
-	params["offline"] = false
-	params["secret"] = secret
-	params["tx_json"] = tx
-	paramsArray = append(paramsArray, params)
+	if isInit == false {
+		Init()
+	}
 
-	// Build payload
-	payload["method"] = "sign"
-	payload["params"] = paramsArray
+	params["account"] = account
+	params["ledger_index"] = "current"
+	params["queue"] = true
+
+	payload["method"] = "account_info"
+	payload["params"] = []map[string]interface{}{params}
 
 	payloadJsonBytes, err := json.Marshal(payload)
 	if err != nil {
 		log.FluentfContext(consts.LOGERROR, c, "Error in Marshal(): %s", err.Error())
-		return "", consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+		return 0, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
 	}
 
-	responseData, errorCode, err := postRPCAPI(c, payloadJsonBytes)
+	responseData, errCode, err := postRPCAPI(c, payloadJsonBytes)
 	if err != nil {
-		return "", errorCode, err
+		log.FluentfContext(consts.LOGERROR, c, "Error in postRPCAPI(): %s", err.Error())
+		return 0, errCode, err
 	}
 
-	log.Printf("%#v", responseData)
+	r, _ := responseData["result"].(map[string]interface{})
 
-	if responseData["result"] != nil {
-		r := responseData["result"].(map[string]interface{})
+	sequence := nextSequence(r)
+	if sequence == 0 {
+		log.FluentfContext(consts.LOGERROR, c, "Account %s has not been activated. Got: %#v", account, r)
+		return 0, consts.RippleErrors.InvalidSource.Code, errors.New(consts.RippleErrors.InvalidSource.Description)
+	}
 
-		if r["status"] != nil && r["status"] == "success" {
-			result = r["tx_blob"].(string)
-		} else {
-			var errorMessage string
-			var errorCode int64
+	return sequence, 0, nil
+}
 
-			if r["error_message"] != nil {
-				errorMessage = r["error_message"].(string)
-			}
+// Returns the sequence after the account's queued txs, or the sequence of the account in the open ledger if it has
+// none queued. Zero if the account isn't found
+func nextSequence(r map[string]interface{}) uint32 {
+	accountData, ok := r["account_data"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
 
-			if r["error_code"] != nil {
-				errorCode = int64(r["error_code"].(float64))
-			}
-			log.FluentfContext(consts.LOGERROR, c, "Error from signing: %s, errorCode: %d", errorMessage, errorCode)
+	sequence, _ := accountData["Sequence"].(float64)
 
-			// Invalid source
-			if errorCode == 55 || errorCode == 63 {
-				return "", consts.RippleErrors.InvalidSource.Code, errors.New(consts.RippleErrors.InvalidSource.Description)
-			}
+	if queueData, ok := r["queue_data"].(map[string]interface{}); ok {
+		if highest, ok := queueData["highest_sequence"].(float64); ok && highest >= sequence {
+			sequence = highest + 1
+		}
+	}
 
-			// Invalid destination
-			if errorCode == 29 {
-				return "", consts.RippleErrors.InvalidDestination.Code, errors.New(consts.RippleErrors.InvalidDestination.Description)
-			}
+	return uint32(sequence)
+}
 
-			// do some errorhandling here
-			return "", consts.RippleErrors.SigningError.Code, errors.New(consts.RippleErrors.SigningError.Description)
-		}
+// Returns the LastLedgerSequence to set on a tx so that it expires if it isn't validated within
+// rippleLastLedgerSequenceOffset ledgers, rather than lingering and applying later
+func lastLedgerSequence(c context.Context) (uint32, int64, error) {
+	latestLedger, errCode, err := GetLatestValidatedLedger(c)
+	if err != nil {
+		return 0, errCode, err
 	}
 
-	return result, 0, nil
+	latestLedgerSequence, err := strconv.ParseUint(latestLedger.LedgerIndex, 10, 32)
+	if err != nil {
+		return 0, consts.RippleErrors.UnableToGetLatestLedger.Code, errors.New(consts.RippleErrors.UnableToGetLatestLedger.Description)
+	}
+
+	return uint32(latestLedgerSequence) + uint32(rippleLastLedgerSequenceOffset), 0, nil
 }
 
 // Creates a Ripple account offline. ie doesn't use the REST or RPC
@@ -964,44 +1110,105 @@ func GetTx(c context.Context, txhash string) (Transaction, int64, error) {
 
 	// map reply...
 	r := responseData["result"].(map[string]interface{})
+
+	// The tx isn't in a ledger yet
+	if r["error"] != nil && r["error"].(string) == "txnNotFound" {
+		result.Hash = txhash
+		return result, 0, nil
+	} else if r["error"] != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error from tx: %s", r["error"].(string))
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
 	result.Account = r["Account"].(string)
 	result.Hash = r["hash"].(string)
-	result.LedgerIndex = uint64(r["ledger_index"].(float64))
-	result.Validated = r["validated"].(bool)
+	if r["ledger_index"] != nil {
+		result.LedgerIndex = uint64(r["ledger_index"].(float64))
+	}
+	if r["validated"] != nil {
+		result.Validated = r["validated"].(bool)
+	}
+
+	// The amount actually delivered is only known from the metadata once the tx is in a ledger
+	if r["meta"] != nil {
+		meta := r["meta"].(map[string]interface{})
+
+		if meta["TransactionResult"] != nil {
+			result.TransactionResult = meta["TransactionResult"].(string)
+		}
+
+		if meta["delivered_amount"] != nil {
+			result.DeliveredAmount = parseAmount(meta["delivered_amount"])
+		}
+	}
 
 	return result, errorCode, nil
 }
 
 // Creates and signs the payment for the custom currency that is specified.
 // If XRP is specified, then the amount MUST be specifed in droplets
-// Returns the tx string if successful
-func CreatePayment(c context.Context, account string, destination string, quantity string, currency string, issuer string, secret string) (string, int64, error) {
+// Returns the tx string and the fee in drops it pays if successful
+func CreatePayment(c context.Context, account string, destination string, quantity string, currency string, issuer string, options PaymentOptions, signer TxSigner) (string, uint64, int64, error) {
+	tx, errCode, err := ComposePayment(c, account, destination, quantity, currency, issuer, options)
+	if err != nil {
+		return "", 0, errCode, err
+	}
+
+	var fee string
+	switch payment := tx.(type) {
+	case PaymentXrpTx:
+		fee = payment.Fee
+	case PaymentAssetTx:
+		fee = payment.Fee
+	}
+	txFee, _ := strconv.ParseUint(fee, 10, 64)
+
+	signedTx, errCode, err := signer.SignRippleTransaction(c, account, tx)
+	if err != nil {
+		return "", 0, errCode, err
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "signed! tx_blob: %s", signedTx)
+
+	return signedTx, txFee, errCode, err
+}
+
+// Creates the unsigned payment with the Sequence, Fee and LastLedgerSequence filled so it can be signed offline.
+// If XRP is specified, then the amount MUST be specifed in droplets
+// Returns a PaymentXrpTx for XRP or a PaymentAssetTx for a custom currency
+func ComposePayment(c context.Context, account string, destination string, quantity string, currency string, issuer string, options PaymentOptions) (interface{}, int64, error) {
 	if isInit == false {
 		Init()
 	}
 
-	var signedTx string
-	var errCode int64
-	var err error
-
 	// Set LastLedgerSequence
 	latestLedger, errCode, err := GetLatestValidatedLedger(c)
 	if err != nil {
-		return "", errCode, err
+		return nil, errCode, err
 	}
 
 	if latestLedger.Accepted != true || latestLedger.Closed != true {
 		log.Fluentf(consts.LOGERROR, "Unable to retrieve latest closed and accepted ledger. Got: %+v", latestLedger)
-		return "", consts.RippleErrors.UnableToGetLatestLedger.Code, errors.New(consts.RippleErrors.UnableToGetLatestLedger.Description)
+		return nil, consts.RippleErrors.UnableToGetLatestLedger.Code, errors.New(consts.RippleErrors.UnableToGetLatestLedger.Description)
 	}
 
 	LatestLedgerSequence, err := strconv.ParseUint(latestLedger.LedgerIndex, 10, 64)
 	if err != nil {
-		return "", errCode, err
+		return nil, errCode, err
 	}
 
 	LastLedgerSequence := LatestLedgerSequence + uint64(rippleLastLedgerSequenceOffset)
 
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return nil, errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return nil, errCode, err
+	}
+
 	if strings.ToUpper(currency) == "XRP" {
 		tx := PaymentXrpTx{
 			TransactionType:    "Payment",
@@ -1009,85 +1216,119 @@ func CreatePayment(c context.Context, account string, destination string, quanti
 			Destination:        destination,
 			Amount:             quantity,
 			Flags:              2147483648, // require canonical signature
-			Fee:                DefaultFee,
+			Fee:                fee,
 			LastLedgerSequence: LastLedgerSequence,
+			Sequence:           sequence,
+			DestinationTag:     options.DestinationTag,
+			SourceTag:          options.SourceTag,
+			Memos:              options.memos(),
 		}
 
-		signedTx, errCode, err = Sign(c, tx, secret)
-	} else {
-		tx := PaymentAssetTx{
-			TransactionType: "Payment",
-			Account:         account,
-			Destination:     destination,
-			Amount: Amount{
-				Value:    quantity,
-				Currency: currency,
-				Issuer:   issuer,
-			},
-			// When working with the Enu API, we don't allow any slippage
-			SendMax: Amount{
-				Value:    quantity,
-				Currency: currency,
-				Issuer:   issuer,
-			},
-			Flags:              2147483648, // require canonical signature
-			Fee:                DefaultFee,
-			LastLedgerSequence: LastLedgerSequence,
-		}
+		return tx, 0, nil
+	}
 
-		signedTx, errCode, err = Sign(c, tx, secret)
+	tx := PaymentAssetTx{
+		TransactionType: "Payment",
+		Account:         account,
+		Destination:     destination,
+		Amount: Amount{
+			Value:    quantity,
+			Currency: currency,
+			Issuer:   issuer,
+		},
+		// When working with the Enu API, we don't allow any slippage
+		SendMax: Amount{
+			Value:    quantity,
+			Currency: currency,
+			Issuer:   issuer,
+		},
+		Flags:              2147483648, // require canonical signature
+		Fee:                fee,
+		LastLedgerSequence: LastLedgerSequence,
+		Sequence:           sequence,
+		DestinationTag:     options.DestinationTag,
+		SourceTag:          options.SourceTag,
+		Memos:              options.memos(),
 	}
 
+	return tx, 0, nil
+}
+
+// Sets a specific flag on an account
+func AccountSetFlag(c context.Context, account string, flag uint32, signer TxSigner) (string, int64, error) {
+	return SubmitAccountSet(c, account, AccountSet{SetFlag: flag}, signer)
+}
+
+// Changes the settings of an account. The settings to change are given in the AccountSet fields, ie SetFlag, ClearFlag,
+// Domain, EmailHash and TransferRate. Only one flag can be set and one cleared in each AccountSet. The common fields of
+// the tx are filled in here.
+func SubmitAccountSet(c context.Context, account string, settings AccountSet, signer TxSigner) (string, int64, error) {
+	txHashes, errCode, err := SubmitAccountSets(c, account, []AccountSet{settings}, signer)
 	if err != nil {
 		return "", errCode, err
 	}
 
-	log.FluentfContext(consts.LOGINFO, c, "signed! tx_blob: %s", signedTx)
-
-	return signedTx, errCode, err
+	return txHashes[0], 0, nil
 }
 
-// Sets a specific flag on an account
-func AccountSetFlag(c context.Context, account string, flag uint32, secret string) (string, int64, error) {
+// Submits several AccountSet txs from the account one after another, as needed to set or clear more than one flag.
+// The txs are given consecutive sequences. Submission stops at the first tx which fails, returning the hashes of the
+// txs submitted before it along with the error
+func SubmitAccountSets(c context.Context, account string, txs []AccountSet, signer TxSigner) ([]string, int64, error) {
 	if isInit == false {
 		Init()
 	}
 
-	var signedTx string
-	var errCode int64
-	var err error
-	var txHash string
-
-	tx := AccountSet{
-		// Common fields
-		TransactionType: "AccountSet",
-		Account:         account,
-		Flags:           2147483648, // require canonical signature
-		Fee:             DefaultFee,
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return nil, errCode, err
+	}
 
-		SetFlag: flag,
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return nil, errCode, err
 	}
 
-	signedTx, errCode, err = Sign(c, tx, secret)
+	lastLedger, errCode, err := lastLedgerSequence(c)
 	if err != nil {
-		log.FluentfContext(consts.LOGERROR, c, "Error in Sign(): %s", err.Error())
-		return "", errCode, err
+		return nil, errCode, err
 	}
 
-	log.Printf("signed! tx_blob: %s", signedTx)
+	return submitAccountSets(account, txs, sequence, fee, lastLedger, func(tx AccountSet) (string, int64, error) {
+		return signAndSubmit(c, account, tx, signer)
+	})
+}
 
-	txHash, errCode, err = Submit(c, signedTx)
-	if err != nil {
-		log.FluentfContext(consts.LOGERROR, c, "Error in Submit(): %s", err.Error())
+// Fills the common fields of each AccountSet, starting from the given sequence, and submits them in order
+func submitAccountSets(account string, txs []AccountSet, sequence uint32, fee string, lastLedger uint32, submit func(AccountSet) (string, int64, error)) ([]string, int64, error) {
+	var txHashes []string
+
+	for i, settings := range txs {
+		tx := settings
+
+		// Common fields
+		tx.TransactionType = "AccountSet"
+		tx.Account = account
+		tx.Flags = 2147483648 // require canonical signature
+		tx.Fee = fee
+		tx.LastLedgerSequence = lastLedger
+		tx.Sequence = sequence + uint32(i)
+
+		txHash, errCode, err := submit(tx)
+		if err != nil {
+			return txHashes, errCode, err
+		}
+
+		txHashes = append(txHashes, txHash)
 	}
 
-	return txHash, errCode, err
+	return txHashes, 0, nil
 }
 
 // Modifies a trust line between two accounts
 // The trust line is directional - the given account trusts the issuer account for value amount of currency
-// A trust line occupies space in the Ripple ledger and therefore requires a fee to be paid and consequently the secret of the source account
-func TrustSet(c context.Context, account string, currency string, value string, issuerAccount string, flag uint32, secret string) (string, int64, error) {
+// A trust line occupies space in the Ripple ledger and therefore requires a fee to be paid and consequently the signature of the source account
+func TrustSet(c context.Context, account string, currency string, value string, issuerAccount string, flag uint32, signer TxSigner) (string, int64, error) {
 	if isInit == false {
 		Init()
 	}
@@ -1097,12 +1338,29 @@ func TrustSet(c context.Context, account string, currency string, value string,
 	var err error
 	var txHash string
 
+	sequence, errCode, err := NextSequence(c, account)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	fee, errCode, err := currentFeeString(c)
+	if err != nil {
+		return "", errCode, err
+	}
+
+	lastLedger, errCode, err := lastLedgerSequence(c)
+	if err != nil {
+		return "", errCode, err
+	}
+
 	tx := TrustSetStruct{
 		// Common fields
-		TransactionType: "TrustSet",
-		Account:         account,
-		Flags:           2147483648 & flag, // require canonical signature
-		Fee:             DefaultFee,
+		TransactionType:    "TrustSet",
+		Account:            account,
+		Flags:              2147483648 | flag, // require canonical signature
+		Fee:                fee,
+		LastLedgerSequence: lastLedger,
+		Sequence:           sequence,
 
 		// Set the limit
 		LimitAmount: LimitAmount{
@@ -1112,9 +1370,9 @@ func TrustSet(c context.Context, account string, currency string, value string,
 		},
 	}
 
-	signedTx, errCode, err = Sign(c, tx, secret)
+	signedTx, errCode, err = signer.SignRippleTransaction(c, account, tx)
 	if err != nil {
-		log.FluentfContext(consts.LOGERROR, c, "Error in Sign(): %s", err.Error())
+		log.FluentfContext(consts.LOGERROR, c, "Error in SignRippleTransaction(): %s", err.Error())
 		return "", errCode, err
 	}
 
@@ -1128,12 +1386,105 @@ func TrustSet(c context.Context, account string, currency string, value string,
 	return txHash, errCode, err
 }
 
-// Gets the trust lines for a given account
+// Gets the trust lines for a given account. Follows the marker until every line has been returned as issuers
+// may have more lines than rippled returns in one page
 func GetAccountLines(c context.Context, account string) (Lines, int64, error) {
 	var payload = make(map[string]interface{})
 	var params = make(map[string]interface{})
 	var paramsArray []map[string]interface{}
 	var result Lines
+
+	if isInit == false {
+		Init()
+	}
+
+	// Build parameters
+	params["account"] = account
+	params["ledger_index"] = "validated"
+	paramsArray = append(paramsArray, params)
+
+	// Build payload
+	payload["method"] = "account_lines"
+	payload["params"] = paramsArray
+
+	for {
+		payloadJsonBytes, err := json.Marshal(payload)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in Marshal(): %s", err.Error())
+			return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+		}
+
+		responseData, errCode, err := postRPCAPI(c, payloadJsonBytes)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in postRPCAPI(): %s", err.Error())
+			return result, errCode, err
+		}
+
+		if responseData["result"] == nil {
+			log.FluentfContext(consts.LOGERROR, c, "Didn't receive a result from RPC server")
+			log.FluentfContext(consts.LOGERROR, c, "Got: %#v", responseData["result"])
+			return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+		}
+
+		r := responseData["result"].(map[string]interface{})
+
+		// Result returned but with an error
+		if r["error"] != nil && r["error_code"].(float64) == 18 {
+			// account not found, we won't raise an error but return an empty structure
+			return result, 0, nil
+		} else if r["error"] != nil {
+			return result, consts.RippleErrors.MiscError.Code, errors.New(r["error"].(string))
+		}
+
+		if r["lines"] != nil {
+			for _, line := range r["lines"].([]interface{}) {
+				outputLine := Line{
+					Account:    line.(map[string]interface{})["account"].(string),
+					Balance:    line.(map[string]interface{})["balance"].(string),
+					Currency:   line.(map[string]interface{})["currency"].(string),
+					Limit:      line.(map[string]interface{})["limit"].(string),
+					LimitPeer:  line.(map[string]interface{})["limit_peer"].(string),
+					QualityIn:  uint(line.(map[string]interface{})["quality_in"].(float64)),
+					QualityOut: uint(line.(map[string]interface{})["quality_out"].(float64)),
+				}
+
+				if line.(map[string]interface{})["no_ripple"] != nil {
+					outputLine.NoRipple = line.(map[string]interface{})["no_ripple"].(bool)
+				}
+
+				if line.(map[string]interface{})["no_ripple_peer"] != nil {
+					outputLine.NoRipplePeer = line.(map[string]interface{})["no_ripple_peer"].(bool)
+				}
+
+				if line.(map[string]interface{})["freeze"] != nil {
+					outputLine.Freeze = line.(map[string]interface{})["freeze"].(bool)
+				}
+
+				if line.(map[string]interface{})["freeze_peer"] != nil {
+					outputLine.FreezePeer = line.(map[string]interface{})["freeze_peer"].(bool)
+				}
+
+				result = append(result, outputLine)
+			}
+		}
+
+		if r["marker"] == nil {
+			break
+		}
+
+		// Subsequent pages must be read from the same ledger as the first
+		params["marker"] = r["marker"]
+		params["ledger_index"] = r["ledger_index"]
+	}
+
+	return result, 0, nil
+}
+
+func GetAccountInfo(c context.Context, account string) (AccountInfo, int64, error) {
+	var payload = make(map[string]interface{})
+	var params = make(map[string]interface{})
+	var paramsArray []map[string]interface{}
+	var result AccountInfo
 	var responseData map[string]interface{}
 
 	if isInit == false {
@@ -1146,7 +1497,7 @@ func GetAccountLines(c context.Context, account string) (Lines, int64, error) {
 	paramsArray = append(paramsArray, params)
 
 	// Build payload
-	payload["method"] = "account_lines"
+	payload["method"] = "account_info"
 	payload["params"] = paramsArray
 
 	payloadJsonBytes, err := json.Marshal(payload)
@@ -1173,39 +1524,48 @@ func GetAccountLines(c context.Context, account string) (Lines, int64, error) {
 	if r["error"] != nil && r["error_code"].(float64) == 18 {
 		// account not found, we won't raise an error but return an empty structure
 		return result, 0, nil
-	} else {
-		for _, line := range r["lines"].([]interface{}) {
-			outputLine := Line{
-				Account:    line.(map[string]interface{})["account"].(string),
-				Balance:    line.(map[string]interface{})["balance"].(string),
-				Currency:   line.(map[string]interface{})["currency"].(string),
-				Limit:      line.(map[string]interface{})["limit"].(string),
-				LimitPeer:  line.(map[string]interface{})["limit_peer"].(string),
-				QualityIn:  uint(line.(map[string]interface{})["quality_in"].(float64)),
-				QualityOut: uint(line.(map[string]interface{})["quality_out"].(float64)),
-			}
+	} else if r["error"] != nil {
+		// Otherwise we'll raise an error with the error message
+		return result, 0, errors.New(r["error_message"].(string))
+	}
 
-			if line.(map[string]interface{})["no_ripple"] != nil {
-				outputLine.NoRipple = line.(map[string]interface{})["no_ripple"].(bool)
-			}
+	// Map results
+	accountData := r["account_data"].(map[string]interface{})
+	result.Account = accountData["Account"].(string)
+	result.Balance = accountData["Balance"].(string)
+	result.Flags = uint32(accountData["Flags"].(float64))
+	result.LedgerEntryType = accountData["LedgerEntryType"].(string)
+	result.OwnerCount = int(accountData["OwnerCount"].(float64))
+	result.PreviousTxnID = accountData["PreviousTxnID"].(string)
+	result.Sequence = int(accountData["Sequence"].(float64))
+	result.Index = accountData["index"].(string)
 
-			if line.(map[string]interface{})["no_ripple_peer"] != nil {
-				outputLine.NoRipplePeer = line.(map[string]interface{})["no_ripple_peer"].(bool)
-			}
+	// Optional fields which are only present once they have been set with an AccountSet
+	if accountData["Domain"] != nil {
+		result.Domain = accountData["Domain"].(string)
+	}
 
-			result = append(result, outputLine)
-		}
+	if accountData["EmailHash"] != nil {
+		result.EmailHash = accountData["EmailHash"].(string)
+	}
+
+	if accountData["TransferRate"] != nil {
+		result.TransferRate = uint32(accountData["TransferRate"].(float64))
+	}
+
+	if accountData["RegularKey"] != nil {
+		result.RegularKey = accountData["RegularKey"].(string)
 	}
 
 	return result, 0, nil
 }
 
-func GetAccountInfo(c context.Context, account string) (AccountInfo, int64, error) {
+// Gets the objects of the given type owned by the account in the validated ledger, eg escrow, payment_channel or check
+func getAccountObjects(c context.Context, account string, objectType string) ([]map[string]interface{}, int64, error) {
 	var payload = make(map[string]interface{})
 	var params = make(map[string]interface{})
 	var paramsArray []map[string]interface{}
-	var result AccountInfo
-	var responseData map[string]interface{}
+	var result []map[string]interface{}
 
 	if isInit == false {
 		Init()
@@ -1213,11 +1573,12 @@ func GetAccountInfo(c context.Context, account string) (AccountInfo, int64, erro
 
 	// Build parameters
 	params["account"] = account
-	params["ledger"] = "validated"
+	params["type"] = objectType
+	params["ledger_index"] = "validated"
 	paramsArray = append(paramsArray, params)
 
 	// Build payload
-	payload["method"] = "account_info"
+	payload["method"] = "account_objects"
 	payload["params"] = paramsArray
 
 	payloadJsonBytes, err := json.Marshal(payload)
@@ -1234,7 +1595,6 @@ func GetAccountInfo(c context.Context, account string) (AccountInfo, int64, erro
 
 	if responseData["result"] == nil {
 		log.FluentfContext(consts.LOGERROR, c, "Didn't receive a result from RPC server")
-		log.FluentfContext(consts.LOGERROR, c, "Got: %#v", responseData["result"])
 		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
 	}
 
@@ -1245,20 +1605,14 @@ func GetAccountInfo(c context.Context, account string) (AccountInfo, int64, erro
 		// account not found, we won't raise an error but return an empty structure
 		return result, 0, nil
 	} else if r["error"] != nil {
-		// Otherwise we'll raise an error with the error message
-		return result, 0, errors.New(r["error_message"].(string))
+		return result, consts.RippleErrors.MiscError.Code, errors.New(r["error_message"].(string))
 	}
 
-	// Map results
-	accountData := r["account_data"].(map[string]interface{})
-	result.Account = accountData["Account"].(string)
-	result.Balance = accountData["Balance"].(string)
-	result.Flags = uint32(accountData["Flags"].(float64))
-	result.LedgerEntryType = accountData["LedgerEntryType"].(string)
-	result.OwnerCount = int(accountData["OwnerCount"].(float64))
-	result.PreviousTxnID = accountData["PreviousTxnID"].(string)
-	result.Sequence = int(accountData["Sequence"].(float64))
-	result.Index = accountData["index"].(string)
+	if r["account_objects"] != nil {
+		for _, object := range r["account_objects"].([]interface{}) {
+			result = append(result, object.(map[string]interface{}))
+		}
+	}
 
 	return result, 0, nil
 }
@@ -1302,6 +1656,35 @@ func Uint64ToAmount(amount uint64) (string, error) {
 	return result, nil
 }
 
+// Converts an amount returned by rippled to the Enu API denomination. XRP is given in drops by rippled so is multiplied
+// by 100, other assets are decimal values so are converted with AmountToUint64()
+func ToEnuAmount(c context.Context, amount Amount) enulib.RippleOfferAmount {
+	var result enulib.RippleOfferAmount
+
+	if amount.Currency == "XRP" {
+		drops, err := strconv.ParseUint(amount.Value, 10, 64)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in ParseUint(): %s", err.Error())
+		}
+
+		result.Asset = "XRP"
+		result.Quantity = drops * 100
+
+		return result
+	}
+
+	asset, err := FromCurrency(amount.Currency)
+	if err != nil {
+		asset = amount.Currency
+	}
+
+	result.Asset = asset
+	result.Issuer = amount.Issuer
+	result.Quantity, _ = AmountToUint64(amount.Value)
+
+	return result
+}
+
 // We allow currency names up to 19 characters long
 func ValidCurrencyName(currency string) (bool, error) {
 	return true, nil
@@ -1364,7 +1747,7 @@ func FromCurrency(currency string) (string, error) {
 	return string(decoded), nil
 }
 
-// Returns the total XRP that is required for the given number of transactions
+// Returns the total XRP that is required for the given number of transactions at the current fee
 func CalculateFeeAmount(c context.Context, amount uint64) (uint64, string, error) {
 	// Get env and blockchain from context
 	blockchainId := c.Value(consts.BlockchainIdKey).(string)
@@ -1385,7 +1768,7 @@ func CalculateFeeAmount(c context.Context, amount uint64) (uint64, string, error
 		return 0, "", errors.New(errorString)
 	}
 
-	quantity, err := strconv.ParseUint(DefaultFee, 10, 64)
+	quantity, _, err := CurrentFee(c)
 	if err != nil {
 		errorString := fmt.Sprintf("Unable to calculate the amount of XRP required")
 		log.FluentfContext(consts.LOGERROR, c, errorString)
@@ -1404,7 +1787,7 @@ func CalculateReserve(c context.Context, accountLines uint64) uint64 {
 	return uint64(BaseReserve) + (accountLines * uint64(OwnerReserve))
 }
 
-// Returns the number of transactions that can be performed with the given amount of XRP
+// Returns the number of transactions that can be performed with the given amount of XRP at the current fee
 func CalculateNumberOfTransactions(c context.Context, amount uint64) (uint64, error) {
 	blockchainId := c.Value(consts.BlockchainIdKey).(string)
 
@@ -1415,5 +1798,10 @@ func CalculateNumberOfTransactions(c context.Context, amount uint64) (uint64, er
 		return 0, errors.New(errorString)
 	}
 
-	return amount / DefaultFeeI, nil
+	fee, _, err := CurrentFee(c)
+	if err != nil {
+		return 0, err
+	}
+
+	return amount / fee, nil
 }
@@ -0,0 +1,99 @@
+package rippleapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func TestNextSequence(t *testing.T) {
+	var testData = []struct {
+		Result          map[string]interface{}
+		Expected        uint32
+		CaseDescription string
+	}{
+		{map[string]interface{}{"account_data": map[string]interface{}{"Sequence": float64(20)}}, 20, "No queued txs"},
+		{map[string]interface{}{"account_data": map[string]interface{}{"Sequence": float64(20)}, "queue_data": map[string]interface{}{"txn_count": float64(0)}}, 20, "Empty queue"},
+		{map[string]interface{}{"account_data": map[string]interface{}{"Sequence": float64(20)}, "queue_data": map[string]interface{}{"txn_count": float64(2), "lowest_sequence": float64(20), "highest_sequence": float64(21)}}, 22, "Queued txs"},
+		{map[string]interface{}{"error": "actNotFound"}, 0, "Account not found"},
+	}
+
+	for _, s := range testData {
+		if result := nextSequence(s.Result); result != s.Expected {
+			t.Errorf("%s. Expected: %d, got: %d\n", s.CaseDescription, s.Expected, result)
+		}
+	}
+}
+
+func TestSubmitAccountSets(t *testing.T) {
+	txs := []AccountSet{{SetFlag: AsfRequireDest}, {SetFlag: AsfRequireAuth}, {SetFlag: AsfGlobalFreeze}}
+
+	var submitted []AccountSet
+	submit := func(tx AccountSet) (string, int64, error) {
+		submitted = append(submitted, tx)
+		return fmt.Sprintf("hash%d", tx.Sequence), 0, nil
+	}
+
+	txHashes, _, err := submitAccountSets("rAccount", txs, 20, "12", 100, submit)
+	if err != nil || len(txHashes) != 3 || txHashes[2] != "hash22" {
+		t.Fatalf("Expected 3 transactions to be submitted, got: %v, %v\n", txHashes, err)
+	}
+
+	for i, tx := range submitted {
+		if tx.Sequence != uint32(20+i) || tx.Account != "rAccount" || tx.Fee != "12" || tx.LastLedgerSequence != 100 || tx.SetFlag != txs[i].SetFlag {
+			t.Errorf("Expected transaction %d to have sequence %d, got: %+v\n", i, 20+i, tx)
+		}
+	}
+
+	// Submission stops at the first failure
+	submitted = nil
+	failing := func(tx AccountSet) (string, int64, error) {
+		submitted = append(submitted, tx)
+		if tx.Sequence == 21 {
+			return "", 2000, errors.New("tefPAST_SEQ")
+		}
+
+		return fmt.Sprintf("hash%d", tx.Sequence), 0, nil
+	}
+
+	txHashes, errCode, err := submitAccountSets("rAccount", txs, 20, "12", 100, failing)
+	if err == nil || errCode != 2000 {
+		t.Errorf("Expected the failure to be returned, got: %d, %v\n", errCode, err)
+	}
+
+	if len(txHashes) != 1 || txHashes[0] != "hash20" || len(submitted) != 2 {
+		t.Errorf("Expected only the first transaction to be submitted, got: %v, %d attempts\n", txHashes, len(submitted))
+	}
+}
+
+func TestToEnuAmount(t *testing.T) {
+	var testData = []struct {
+		Amount          Amount
+		Expected        enulib.RippleOfferAmount
+		CaseDescription string
+	}{
+		{Amount{Value: "1000000", Currency: "XRP"}, enulib.RippleOfferAmount{Asset: "XRP", Quantity: 100000000}, "1 XRP"},
+		{Amount{Value: "12.5", Currency: "USD", Issuer: "rIssuer"}, enulib.RippleOfferAmount{Asset: "USD", Issuer: "rIssuer", Quantity: 1250000000}, "12.5 USD"},
+	}
+
+	for _, s := range testData {
+		if result := ToEnuAmount(context.TODO(), s.Amount); result != s.Expected {
+			t.Errorf("%s. Expected: %#v, got: %#v\n", s.CaseDescription, s.Expected, result)
+		}
+	}
+}
+
+func TestTxResultError(t *testing.T) {
+	if errCode, err := TxResultError("tecPATH_PARTIAL"); err == nil || errCode != consts.RippleErrors.PathPartial.Code {
+		t.Errorf("Expected a partial path error, got: %d, %v\n", errCode, err)
+	}
+
+	if errCode, err := TxResultError("tecNO_DST_INSUF_XRP"); err == nil || errCode != consts.RippleErrors.SubmitErrorFeeLost.Code {
+		t.Errorf("Expected other tec results to lose the fee, got: %d, %v\n", errCode, err)
+	}
+}
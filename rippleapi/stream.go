@@ -0,0 +1,509 @@
+package rippleapi
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/github.com/btcsuite/websocket"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// The number of validated transactions of subscribed accounts remembered so that waiters which subscribe after a
+// transaction was validated, and confirmation lookups, don't need to query rippled
+const streamValidatedCacheSize = 10000
+
+// The longest to wait before reconnecting to the stream
+const streamMaxBackoff = 60 * time.Second
+
+// How long to wait for a message, or the pong to a ping, before the connection is treated as dead and reconnected.
+// Without it a half open connection blocks reads forever
+const streamReadTimeout = 60 * time.Second
+
+// How often rippled is pinged while the stream is otherwise idle
+const streamPingInterval = 20 * time.Second
+
+// The number of validated transactions queued for the handlers. Reading from the stream only waits for the handlers
+// once the queue is full
+const streamHandlerQueueSize = 1000
+
+// A subscription to the rippled WebSocket API. Ledger closes are always followed and accounts are followed while
+// anything watches them. Subscriptions are made again after reconnecting
+type stream struct {
+	sync.Mutex
+	writeMutex sync.Mutex
+
+	conn      *websocket.Conn
+	connected bool
+
+	ledgerIndex  uint64
+	ledgerClosed chan struct{} // closed and replaced each time a ledger is validated
+
+	accounts  map[string]int // the number of watchers of each account
+	txWaiters map[string][]chan Transaction
+	handlers  []func(c context.Context, tx AccountTransaction)
+	handled   chan AccountTransaction // validated transactions waiting for the handlers
+
+	validated      map[string]Transaction
+	validatedOrder []string
+}
+
+var rippleStream = &stream{
+	ledgerClosed: make(chan struct{}),
+	accounts:     make(map[string]int),
+	txWaiters:    make(map[string][]chan Transaction),
+	validated:    make(map[string]Transaction),
+	handled:      make(chan AccountTransaction, streamHandlerQueueSize),
+}
+
+var isStreamInit bool = false
+
+// Connects to the rippled WebSocket API given by rippleWebsocketHost in enuapi.json. Submissions, confirmations and
+// the handlers of validated transactions are then driven by the stream. Without a WebSocket host rippled is polled
+func InitStream() {
+	if isInit == false {
+		Init()
+	}
+
+	if isStreamInit == true {
+		return
+	}
+	isStreamInit = true
+
+	if rippleWebsocketHost == "" {
+		log.Println("rippleWebsocketHost is not configured. Polling rippled for transaction outcomes")
+		return
+	}
+
+	go rippleStream.runHandlers()
+	go rippleStream.run()
+}
+
+// Returns true if the stream is connected. Accounts watched while it is connected are subscribed straight away
+func StreamConnected() bool {
+	rippleStream.Lock()
+	defer rippleStream.Unlock()
+
+	return rippleStream.connected
+}
+
+// Follows the transactions of the account until UnwatchAccount() is called as many times as WatchAccount()
+func WatchAccount(account string) {
+	rippleStream.Lock()
+	rippleStream.accounts[account]++
+	subscribe := rippleStream.accounts[account] == 1 && rippleStream.connected
+	rippleStream.Unlock()
+
+	if subscribe {
+		rippleStream.send(map[string]interface{}{"command": "subscribe", "accounts": []string{account}})
+	}
+}
+
+func UnwatchAccount(account string) {
+	rippleStream.Lock()
+	if rippleStream.accounts[account] == 0 {
+		rippleStream.Unlock()
+		return
+	}
+
+	rippleStream.accounts[account]--
+	unsubscribe := rippleStream.accounts[account] == 0 && rippleStream.connected
+	if rippleStream.accounts[account] == 0 {
+		delete(rippleStream.accounts, account)
+	}
+	rippleStream.Unlock()
+
+	if unsubscribe {
+		rippleStream.send(map[string]interface{}{"command": "unsubscribe", "accounts": []string{account}})
+	}
+}
+
+// Adds a handler which is called with every validated transaction of the watched accounts. Transactions validated
+// while the stream was disconnected are read from account_tx after reconnecting so handlers may see a transaction
+// more than once
+func OnValidatedTransaction(handler func(c context.Context, tx AccountTransaction)) {
+	rippleStream.Lock()
+	defer rippleStream.Unlock()
+
+	rippleStream.handlers = append(rippleStream.handlers, handler)
+}
+
+func (s *stream) send(command map[string]interface{}) error {
+	s.Lock()
+	conn := s.conn
+	s.Unlock()
+
+	if conn == nil {
+		return errors.New("Not connected to the rippled WebSocket API")
+	}
+
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	return conn.WriteJSON(command)
+}
+
+// Connects, subscribes and reads until the connection fails, then reconnects with an increasing backoff
+func (s *stream) run() {
+	backoff := time.Second
+
+	for {
+		conn, _, err := websocket.DefaultDialer.Dial(rippleWebsocketHost, nil)
+		if err != nil {
+			log.Printf("Unable to connect to rippled WebSocket API %s: %s. Retrying in %s", rippleWebsocketHost, err.Error(), backoff.String())
+
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > streamMaxBackoff {
+				backoff = streamMaxBackoff
+			}
+
+			continue
+		}
+		backoff = time.Second
+
+		conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		})
+
+		// Accounts watched after the snapshot see connected and subscribe themselves
+		s.Lock()
+		s.conn = conn
+		s.connected = true
+		lastLedger := s.ledgerIndex
+		var accounts []string
+		for account := range s.accounts {
+			accounts = append(accounts, account)
+		}
+		s.Unlock()
+
+		subscription := map[string]interface{}{"command": "subscribe", "streams": []string{"ledger"}}
+		if len(accounts) > 0 {
+			subscription["accounts"] = accounts
+		}
+
+		if err := s.send(subscription); err != nil {
+			log.Printf("Unable to subscribe to rippled WebSocket API: %s", err.Error())
+
+			s.Lock()
+			s.conn = nil
+			s.connected = false
+			s.Unlock()
+
+			conn.Close()
+
+			continue
+		}
+
+		log.Printf("Connected to rippled WebSocket API %s following %d accounts", rippleWebsocketHost, len(accounts))
+
+		// Catch up on what was missed while disconnected
+		if lastLedger > 0 {
+			go s.catchUp(accounts, lastLedger)
+		}
+
+		done := make(chan struct{})
+		go s.keepAlive(conn, done)
+
+		s.read(conn)
+		close(done)
+
+		s.Lock()
+		s.conn = nil
+		s.connected = false
+		s.Unlock()
+
+		conn.Close()
+		log.Printf("Disconnected from rippled WebSocket API %s", rippleWebsocketHost)
+	}
+}
+
+func (s *stream) read(conn *websocket.Conn) {
+	for {
+		var message map[string]interface{}
+
+		if err := conn.ReadJSON(&message); err != nil {
+			log.Printf("Error reading from rippled WebSocket API: %s", err.Error())
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+
+		s.handleMessage(message)
+	}
+}
+
+// Pings rippled until done is closed. The pongs extend the read deadline so an idle but healthy connection isn't
+// dropped. If the ping can't be written the connection is closed, which ends the read
+func (s *stream) keepAlive(conn *websocket.Conn, done chan struct{}) {
+	ticker := time.NewTicker(streamPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+
+		case <-ticker.C:
+			s.writeMutex.Lock()
+			err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamPingInterval))
+			s.writeMutex.Unlock()
+
+			if err != nil {
+				log.Printf("Unable to ping rippled WebSocket API: %s", err.Error())
+				conn.Close()
+
+				return
+			}
+		}
+	}
+}
+
+func (s *stream) handleMessage(message map[string]interface{}) {
+	switch message["type"] {
+	case "ledgerClosed":
+		if message["ledger_index"] != nil {
+			s.setLedgerIndex(uint64(message["ledger_index"].(float64)))
+		}
+
+	case "transaction":
+		tx := parseStreamTransaction(message)
+		if tx.Validated {
+			s.transactionValidated(tx)
+		}
+
+	case "response":
+		if message["status"] == "error" {
+			log.Printf("Error from rippled WebSocket API: %v", message["error"])
+			return
+		}
+
+		// The subscription to the ledger stream returns the last validated ledger
+		if result, ok := message["result"].(map[string]interface{}); ok && result["ledger_index"] != nil {
+			s.setLedgerIndex(uint64(result["ledger_index"].(float64)))
+		}
+	}
+}
+
+// Transactions in the stream give the ledger outside of the transaction
+func parseStreamTransaction(message map[string]interface{}) AccountTransaction {
+	tx := parseAccountTransaction(map[string]interface{}{"tx": message["transaction"], "meta": message["meta"], "validated": message["validated"]})
+
+	if message["ledger_index"] != nil {
+		tx.LedgerIndex = uint64(message["ledger_index"].(float64))
+	}
+
+	return tx
+}
+
+func (s *stream) setLedgerIndex(ledgerIndex uint64) {
+	s.Lock()
+	defer s.Unlock()
+
+	if ledgerIndex <= s.ledgerIndex {
+		return
+	}
+
+	s.ledgerIndex = ledgerIndex
+
+	// Wake everything waiting for a ledger to close
+	close(s.ledgerClosed)
+	s.ledgerClosed = make(chan struct{})
+}
+
+func (s *stream) transactionValidated(accountTx AccountTransaction) {
+	tx := Transaction{Account: accountTx.Account, Hash: accountTx.Hash, LedgerIndex: accountTx.LedgerIndex, Validated: true, TransactionResult: accountTx.TransactionResult, DeliveredAmount: accountTx.DeliveredAmount}
+
+	s.Lock()
+	if _, ok := s.validated[tx.Hash]; !ok {
+		s.validated[tx.Hash] = tx
+		s.validatedOrder = append(s.validatedOrder, tx.Hash)
+
+		if len(s.validatedOrder) > streamValidatedCacheSize {
+			delete(s.validated, s.validatedOrder[0])
+			s.validatedOrder = s.validatedOrder[1:]
+		}
+	}
+
+	waiters := s.txWaiters[tx.Hash]
+	delete(s.txWaiters, tx.Hash)
+	s.Unlock()
+
+	for _, waiter := range waiters {
+		waiter <- tx
+	}
+
+	// The handlers write to the database so are run by runHandlers() rather than holding up the read
+	s.handled <- accountTx
+}
+
+// Calls the handlers with each validated transaction in the order they were validated
+func (s *stream) runHandlers() {
+	for accountTx := range s.handled {
+		s.Lock()
+		handlers := make([]func(c context.Context, tx AccountTransaction), len(s.handlers))
+		copy(handlers, s.handlers)
+		s.Unlock()
+
+		for _, handler := range handlers {
+			handler(context.TODO(), accountTx)
+		}
+	}
+}
+
+// Reads the transactions of the accounts validated since the last ledger seen before the stream disconnected
+func (s *stream) catchUp(accounts []string, lastLedger uint64) {
+	for _, account := range accounts {
+		var marker interface{}
+
+		for {
+			page, _, err := GetAccountTransactions(context.TODO(), account, int64(lastLedger+1), -1, 0, true, marker)
+			if err != nil {
+				log.Printf("Unable to catch up on transactions of %s: %s", account, err.Error())
+				break
+			}
+
+			for _, tx := range page.Transactions {
+				if tx.Validated {
+					s.transactionValidated(tx)
+				}
+			}
+
+			if page.Marker == nil {
+				break
+			}
+			marker = page.Marker
+		}
+	}
+}
+
+// Waits for the tx submitted by the account to be validated or for the ledger to pass lastLedger. Returns false if
+// the outcome couldn't be decided from the stream, in which case the caller should poll rippled instead
+func waitForTransaction(c context.Context, txHash string, account string, lastLedger uint64) (Transaction, bool) {
+	if !StreamConnected() {
+		return Transaction{}, false
+	}
+
+	waiter := make(chan Transaction, 1)
+
+	WatchAccount(account)
+	defer UnwatchAccount(account)
+
+	rippleStream.Lock()
+	if tx, ok := rippleStream.validated[txHash]; ok {
+		rippleStream.Unlock()
+		return tx, true
+	}
+	rippleStream.txWaiters[txHash] = append(rippleStream.txWaiters[txHash], waiter)
+	rippleStream.Unlock()
+
+	defer func() {
+		rippleStream.Lock()
+		defer rippleStream.Unlock()
+
+		for i, w := range rippleStream.txWaiters[txHash] {
+			if w == waiter {
+				rippleStream.txWaiters[txHash] = append(rippleStream.txWaiters[txHash][:i], rippleStream.txWaiters[txHash][i+1:]...)
+				break
+			}
+		}
+
+		if len(rippleStream.txWaiters[txHash]) == 0 {
+			delete(rippleStream.txWaiters, txHash)
+		}
+	}()
+
+	// The tx may have been validated before the account was subscribed
+	if tx, _, err := GetTx(c, txHash); err == nil && tx.Validated {
+		return tx, true
+	}
+
+	// Give up on the stream if ledgers stop closing, eg because it has disconnected
+	timeout := time.After(time.Duration(rippleLastLedgerSequenceOffset+2) * 10 * time.Second)
+
+	for {
+		rippleStream.Lock()
+		ledgerIndex := rippleStream.ledgerIndex
+		ledgerClosed := rippleStream.ledgerClosed
+		rippleStream.Unlock()
+
+		if ledgerIndex > lastLedger {
+			// The tx can no longer be validated. Check once more in case its ledger raced with ours
+			tx, _, err := GetTx(c, txHash)
+			if err != nil {
+				return tx, false
+			}
+
+			return tx, true
+		}
+
+		select {
+		case tx := <-waiter:
+			log.FluentfContext(consts.LOGINFO, c, "Tx %s validated in ledger %d", txHash, tx.LedgerIndex)
+			return tx, true
+		case <-ledgerClosed:
+		case <-timeout:
+			return Transaction{Hash: txHash}, false
+		}
+	}
+}
+
+// Returns the last validated ledger seen on the stream, otherwise from rippled
+func latestValidatedLedgerIndex(c context.Context) (uint64, int64, error) {
+	rippleStream.Lock()
+	ledgerIndex := rippleStream.ledgerIndex
+	connected := rippleStream.connected
+	rippleStream.Unlock()
+
+	if connected && ledgerIndex > 0 {
+		return ledgerIndex, 0, nil
+	}
+
+	ledger, errCode, err := GetLatestValidatedLedger(c)
+	if err != nil {
+		return 0, errCode, err
+	}
+
+	ledgerIndex, err = strconv.ParseUint(ledger.LedgerIndex, 10, 64)
+	if err != nil {
+		return 0, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	return ledgerIndex, 0, nil
+}
+
+// Returns whether the tx has been validated and the number of ledgers validated since, including its own. Txs seen
+// on the stream are answered without querying rippled
+func GetConfirmations(c context.Context, txHash string) (bool, uint64, int64, error) {
+	rippleStream.Lock()
+	tx, ok := rippleStream.validated[txHash]
+	rippleStream.Unlock()
+
+	if !ok {
+		var errCode int64
+		var err error
+
+		tx, errCode, err = GetTx(c, txHash)
+		if err != nil {
+			return false, 0, errCode, err
+		}
+	}
+
+	if !tx.Validated {
+		return false, 0, 0, nil
+	}
+
+	ledgerIndex, errCode, err := latestValidatedLedgerIndex(c)
+	if err != nil {
+		return true, 0, errCode, err
+	}
+
+	if ledgerIndex < tx.LedgerIndex {
+		return true, 1, 0, nil
+	}
+
+	return true, ledgerIndex - tx.LedgerIndex + 1, 0, nil
+}
@@ -0,0 +1,65 @@
+package rippleapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func TestTransactionValidatedHandlers(t *testing.T) {
+	s := &stream{
+		ledgerClosed: make(chan struct{}),
+		accounts:     make(map[string]int),
+		txWaiters:    make(map[string][]chan Transaction),
+		validated:    make(map[string]Transaction),
+		handled:      make(chan AccountTransaction, streamHandlerQueueSize),
+	}
+
+	// A slow handler, eg a slow database write
+	release := make(chan struct{})
+	received := make(chan string, 2)
+	s.handlers = append(s.handlers, func(c context.Context, tx AccountTransaction) {
+		<-release
+		received <- tx.Hash
+	})
+
+	go s.runHandlers()
+
+	waiter := make(chan Transaction, 1)
+	s.txWaiters["HASH2"] = append(s.txWaiters["HASH2"], waiter)
+
+	returned := make(chan struct{})
+	go func() {
+		s.transactionValidated(AccountTransaction{Hash: "HASH1", Validated: true})
+		s.transactionValidated(AccountTransaction{Hash: "HASH2", Validated: true})
+		close(returned)
+	}()
+
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the stream not to wait for the handlers\n")
+	}
+
+	select {
+	case tx := <-waiter:
+		if tx.Hash != "HASH2" {
+			t.Errorf("Expected the waiter to receive HASH2, got: %s\n", tx.Hash)
+		}
+	default:
+		t.Errorf("Expected the waiter to be woken before the handlers finish\n")
+	}
+
+	close(release)
+	for _, expected := range []string{"HASH1", "HASH2"} {
+		select {
+		case hash := <-received:
+			if hash != expected {
+				t.Errorf("Expected the handlers to be called in order. Expected: %s, got: %s\n", expected, hash)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected the handlers to be called with %s\n", expected)
+		}
+	}
+}
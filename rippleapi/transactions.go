@@ -0,0 +1,177 @@
+package rippleapi
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// A transaction affecting an account as returned by account_tx. DeliveredAmount is the amount actually received
+// by the destination of a payment, which may be less than Amount for partial payments
+type AccountTransaction struct {
+	Hash              string
+	LedgerIndex       uint64
+	Date              uint32
+	TransactionType   string
+	Account           string
+	Destination       string
+	DestinationTag    *uint32
+	SourceTag         *uint32
+	Amount            Amount
+	DeliveredAmount   Amount
+	Fee               string
+	Sequence          uint32
+	Memos             []Memo
+	TransactionResult string
+	Validated         bool
+}
+
+// A page of transactions. Marker is nil on the last page, otherwise it is passed back to read the next page
+type AccountTransactions struct {
+	Transactions []AccountTransaction
+	Marker       interface{}
+}
+
+func parseAccountTransaction(item map[string]interface{}) AccountTransaction {
+	var result AccountTransaction
+
+	if item["validated"] != nil {
+		result.Validated = item["validated"].(bool)
+	}
+
+	if item["tx"] != nil {
+		tx := item["tx"].(map[string]interface{})
+
+		result.Hash, _ = tx["hash"].(string)
+		result.TransactionType, _ = tx["TransactionType"].(string)
+		result.Account, _ = tx["Account"].(string)
+		result.Destination, _ = tx["Destination"].(string)
+		result.Fee, _ = tx["Fee"].(string)
+
+		if tx["ledger_index"] != nil {
+			result.LedgerIndex = uint64(tx["ledger_index"].(float64))
+		}
+
+		if tx["date"] != nil {
+			result.Date = uint32(tx["date"].(float64))
+		}
+
+		if tx["Sequence"] != nil {
+			result.Sequence = uint32(tx["Sequence"].(float64))
+		}
+
+		if tx["DestinationTag"] != nil {
+			tag := uint32(tx["DestinationTag"].(float64))
+			result.DestinationTag = &tag
+		}
+
+		if tx["SourceTag"] != nil {
+			tag := uint32(tx["SourceTag"].(float64))
+			result.SourceTag = &tag
+		}
+
+		if tx["Amount"] != nil {
+			result.Amount = parseAmount(tx["Amount"])
+		}
+
+		if tx["Memos"] != nil {
+			for _, wrapper := range tx["Memos"].([]interface{}) {
+				var memo Memo
+
+				m, _ := wrapper.(map[string]interface{})["Memo"].(map[string]interface{})
+				memo.MemoType, _ = m["MemoType"].(string)
+				memo.MemoFormat, _ = m["MemoFormat"].(string)
+				memo.MemoData, _ = m["MemoData"].(string)
+
+				result.Memos = append(result.Memos, memo)
+			}
+		}
+	}
+
+	if item["meta"] != nil {
+		meta := item["meta"].(map[string]interface{})
+
+		result.TransactionResult, _ = meta["TransactionResult"].(string)
+
+		// Payments from before the delivered amount was recorded return "unavailable"
+		if meta["delivered_amount"] != nil && meta["delivered_amount"] != "unavailable" {
+			result.DeliveredAmount = parseAmount(meta["delivered_amount"])
+		} else if result.TransactionType == "Payment" {
+			result.DeliveredAmount = result.Amount
+		}
+	}
+
+	return result
+}
+
+// Gets a page of the validated transactions which affected the account between the given ledgers. -1 may be given
+// for either ledger to use the earliest or latest ledger available. Transactions are returned newest first unless
+// forward is true. marker is nil for the first page
+func GetAccountTransactions(c context.Context, account string, ledgerIndexMin int64, ledgerIndexMax int64, limit uint32, forward bool, marker interface{}) (AccountTransactions, int64, error) {
+	var payload = make(map[string]interface{})
+	var params = make(map[string]interface{})
+	var paramsArray []map[string]interface{}
+	var result AccountTransactions
+
+	if isInit == false {
+		Init()
+	}
+
+	// Build parameters
+	params["account"] = account
+	params["ledger_index_min"] = ledgerIndexMin
+	params["ledger_index_max"] = ledgerIndexMax
+	params["forward"] = forward
+	if limit > 0 {
+		params["limit"] = limit
+	}
+	if marker != nil {
+		params["marker"] = marker
+	}
+	paramsArray = append(paramsArray, params)
+
+	// Build payload
+	payload["method"] = "account_tx"
+	payload["params"] = paramsArray
+
+	payloadJsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Marshal(): %s", err.Error())
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	responseData, errCode, err := postRPCAPI(c, payloadJsonBytes)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in postRPCAPI(): %s", err.Error())
+		return result, errCode, err
+	}
+
+	if responseData["result"] == nil {
+		log.FluentfContext(consts.LOGERROR, c, "Didn't receive a result from RPC server")
+		return result, consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	r := responseData["result"].(map[string]interface{})
+
+	// Result returned but with an error
+	if r["error"] != nil && r["error"].(string) == "actNotFound" {
+		// account not found, we won't raise an error but return an empty structure
+		return result, 0, nil
+	} else if r["error"] != nil {
+		return result, consts.RippleErrors.MiscError.Code, errors.New(r["error"].(string))
+	}
+
+	if r["transactions"] != nil {
+		for _, item := range r["transactions"].([]interface{}) {
+			result.Transactions = append(result.Transactions, parseAccountTransaction(item.(map[string]interface{})))
+		}
+	}
+
+	result.Marker = r["marker"]
+
+	return result, 0, nil
+}
@@ -0,0 +1,126 @@
+package ripplecrypto
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/vennd/enu/internal/bitbucket.org/dchapes/ripple/crypto/sha512half"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/btcec"
+)
+
+var (
+	// Prefix of the message signed by a payment channel claim
+	HashPrefixPaymentChannelClaim = []byte{0x43, 0x4C, 0x4D, 0x00} // CLM\0
+
+	// Ledger space of payment channels, used to compute the channel id
+	ledgerSpacePaymentChannel = []byte{0x00, 0x78} // x
+)
+
+// Returns the id of the payment channel created from the account to the destination by the tx with the sequence
+func ChannelId(account string, destination string, sequence uint32) (string, error) {
+	accountId, err := DecodeAccountId(account)
+	if err != nil {
+		return "", err
+	}
+
+	destinationId, err := DecodeAccountId(destination)
+	if err != nil {
+		return "", err
+	}
+
+	message := append(append([]byte{}, ledgerSpacePaymentChannel...), accountId...)
+	message = append(message, destinationId...)
+
+	sequenceBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(sequenceBytes, sequence)
+	message = append(message, sequenceBytes...)
+
+	hash := sha512half.Sum256(message)
+
+	return strings.ToUpper(hex.EncodeToString(hash[:])), nil
+}
+
+// Returns the message signed to authorise the destination to claim drops of XRP from the channel
+func claimMessage(channelId string, drops uint64) ([]byte, error) {
+	channel, err := hex.DecodeString(channelId)
+	if err != nil || len(channel) != 32 {
+		return nil, errors.New("Invalid channel id")
+	}
+
+	message := append(append([]byte{}, HashPrefixPaymentChannelClaim...), channel...)
+
+	dropsBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(dropsBytes, drops)
+
+	return append(message, dropsBytes...), nil
+}
+
+// Signs a claim of drops of XRP from the channel with the master key of the secret. The claim can be redeemed by the
+// destination of the channel, which must be created with the public key of the secret.
+// Returns the hex encoded DER signature and the public key which verifies it
+func SignClaim(secret string, channelId string, drops uint64) (string, string, error) {
+	privateKey, _, err := KeyFromSecret(secret)
+	if err != nil {
+		return "", "", err
+	}
+
+	message, err := claimMessage(channelId, drops)
+	if err != nil {
+		return "", "", err
+	}
+
+	hash := sha512half.Sum256(message)
+	signature, err := privateKey.Sign(hash[:])
+	if err != nil {
+		return "", "", err
+	}
+
+	publicKey := strings.ToUpper(hex.EncodeToString(privateKey.PubKey().SerializeCompressed()))
+
+	return strings.ToUpper(hex.EncodeToString(signature.Serialize())), publicKey, nil
+}
+
+// Verifies the hex encoded DER signature of a claim of drops of XRP from the channel was made by the public key of the
+// channel
+func VerifyClaim(publicKey string, channelId string, drops uint64, signature string) (bool, error) {
+	message, err := claimMessage(channelId, drops)
+	if err != nil {
+		return false, err
+	}
+
+	publicKeyBytes, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return false, err
+	}
+
+	pubKey, err := btcec.ParsePubKey(publicKeyBytes, btcec.S256())
+	if err != nil {
+		return false, err
+	}
+
+	signatureBytes, err := hex.DecodeString(signature)
+	if err != nil {
+		return false, err
+	}
+
+	sig, err := btcec.ParseDERSignature(signatureBytes, btcec.S256())
+	if err != nil {
+		return false, err
+	}
+
+	hash := sha512half.Sum256(message)
+
+	return sig.Verify(hash[:], pubKey), nil
+}
+
+// Returns the public key of the master key of the secret, as given when creating a payment channel
+func PublicKeyFromSecret(secret string) (string, error) {
+	privateKey, _, err := KeyFromSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToUpper(hex.EncodeToString(privateKey.PubKey().SerializeCompressed())), nil
+}
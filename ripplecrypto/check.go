@@ -0,0 +1,30 @@
+package ripplecrypto
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+
+	"github.com/vennd/enu/internal/bitbucket.org/dchapes/ripple/crypto/sha512half"
+)
+
+// Ledger space of checks, used to compute the check id
+var ledgerSpaceCheck = []byte{0x00, 0x43} // C
+
+// Returns the id of the check created by the account with the tx with the sequence
+func CheckId(account string, sequence uint32) (string, error) {
+	accountId, err := DecodeAccountId(account)
+	if err != nil {
+		return "", err
+	}
+
+	message := append(append([]byte{}, ledgerSpaceCheck...), accountId...)
+
+	sequenceBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(sequenceBytes, sequence)
+	message = append(message, sequenceBytes...)
+
+	hash := sha512half.Sum256(message)
+
+	return strings.ToUpper(hex.EncodeToString(hash[:])), nil
+}
@@ -0,0 +1,666 @@
+package ripplecrypto
+
+// Serialises transactions in the JSON format used by rippled into the Ripple binary format.
+// See https://ripple.com/build/serialization-format/
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vennd/enu/internal/bitbucket.org/dchapes/ripple/crypto/rkey"
+	"github.com/vennd/enu/internal/bitbucket.org/dchapes/ripple/crypto/sha512half"
+)
+
+// Type codes of the serialised types
+const (
+	typeUInt16    = 1
+	typeUInt32    = 2
+	typeUInt64    = 3
+	typeHash128   = 4
+	typeHash256   = 5
+	typeAmount    = 6
+	typeBlob      = 7
+	typeAccountID = 8
+	typeSTObject  = 14
+	typeSTArray   = 15
+	typeUInt8     = 16
+	typeHash160   = 17
+	typePathSet   = 18
+	typeVector256 = 19
+)
+
+// Prefixes which are added to the serialised transaction before it is hashed
+var (
+	HashPrefixTransactionSign      = []byte{0x53, 0x54, 0x58, 0x00} // STX\0
+	HashPrefixTransactionMultiSign = []byte{0x53, 0x4D, 0x54, 0x00} // SMT\0
+	HashPrefixTransactionId        = []byte{0x54, 0x58, 0x4E, 0x00} // TXN\0
+)
+
+type fieldDefinition struct {
+	typeCode  int
+	fieldCode int
+	signing   bool // false if the field is excluded when the transaction is serialised for signing
+}
+
+var fields = map[string]fieldDefinition{
+	// UInt8
+	"TickSize": {typeUInt8, 16, true},
+
+	// UInt16
+	"TransactionType": {typeUInt16, 2, true},
+	"SignerWeight":    {typeUInt16, 3, true},
+
+	// UInt32
+	"Flags":              {typeUInt32, 2, true},
+	"SourceTag":          {typeUInt32, 3, true},
+	"Sequence":           {typeUInt32, 4, true},
+	"Expiration":         {typeUInt32, 10, true},
+	"TransferRate":       {typeUInt32, 11, true},
+	"DestinationTag":     {typeUInt32, 14, true},
+	"QualityIn":          {typeUInt32, 20, true},
+	"QualityOut":         {typeUInt32, 21, true},
+	"OfferSequence":      {typeUInt32, 25, true},
+	"LastLedgerSequence": {typeUInt32, 27, true},
+	"SetFlag":            {typeUInt32, 33, true},
+	"ClearFlag":          {typeUInt32, 34, true},
+	"SignerQuorum":       {typeUInt32, 35, true},
+	"CancelAfter":        {typeUInt32, 36, true},
+	"FinishAfter":        {typeUInt32, 37, true},
+	"SettleDelay":        {typeUInt32, 39, true},
+
+	// Hash128
+	"EmailHash": {typeHash128, 1, true},
+
+	// Hash256
+	"AccountTxnID": {typeHash256, 9, true},
+	"InvoiceID":    {typeHash256, 17, true},
+	"Channel":      {typeHash256, 22, true},
+	"CheckID":      {typeHash256, 24, true},
+
+	// Amount
+	"Amount":      {typeAmount, 1, true},
+	"Balance":     {typeAmount, 2, true},
+	"LimitAmount": {typeAmount, 3, true},
+	"TakerPays":   {typeAmount, 4, true},
+	"TakerGets":   {typeAmount, 5, true},
+	"Fee":         {typeAmount, 8, true},
+	"SendMax":     {typeAmount, 9, true},
+	"DeliverMin":  {typeAmount, 10, true},
+
+	// Blob
+	"PublicKey":     {typeBlob, 1, true},
+	"MessageKey":    {typeBlob, 2, true},
+	"SigningPubKey": {typeBlob, 3, true},
+	"TxnSignature":  {typeBlob, 4, false},
+	"Signature":     {typeBlob, 6, false},
+	"Domain":        {typeBlob, 7, true},
+	"MemoType":      {typeBlob, 12, true},
+	"MemoData":      {typeBlob, 13, true},
+	"MemoFormat":    {typeBlob, 14, true},
+	"Fulfillment":   {typeBlob, 16, true},
+	"Condition":     {typeBlob, 17, true},
+
+	// AccountID
+	"Account":     {typeAccountID, 1, true},
+	"Owner":       {typeAccountID, 2, true},
+	"Destination": {typeAccountID, 3, true},
+	"Issuer":      {typeAccountID, 4, true},
+	"Authorize":   {typeAccountID, 5, true},
+	"Unauthorize": {typeAccountID, 6, true},
+	"RegularKey":  {typeAccountID, 8, true},
+
+	// STObject
+	"Memo":        {typeSTObject, 10, true},
+	"SignerEntry": {typeSTObject, 11, true},
+	"Signer":      {typeSTObject, 16, true},
+
+	// STArray
+	"Signers":       {typeSTArray, 3, false},
+	"SignerEntries": {typeSTArray, 4, true},
+	"Memos":         {typeSTArray, 9, true},
+
+	// PathSet
+	"Paths": {typePathSet, 1, true},
+}
+
+var transactionTypes = map[string]uint16{
+	"Payment":              0,
+	"EscrowCreate":         1,
+	"EscrowFinish":         2,
+	"AccountSet":           3,
+	"EscrowCancel":         4,
+	"SetRegularKey":        5,
+	"OfferCreate":          7,
+	"OfferCancel":          8,
+	"SignerListSet":        12,
+	"PaymentChannelCreate": 13,
+	"PaymentChannelFund":   14,
+	"PaymentChannelClaim":  15,
+	"CheckCreate":          16,
+	"CheckCash":            17,
+	"CheckCancel":          18,
+	"TrustSet":             20,
+}
+
+const objectEndMarker = 0xE1
+const arrayEndMarker = 0xF1
+
+// Path step types
+const (
+	pathStepAccount  = 0x01
+	pathStepCurrency = 0x10
+	pathStepIssuer   = 0x20
+	pathSeparator    = 0xFF
+	pathSetEnd       = 0x00
+)
+
+// Converts a transaction struct into the map representation of its JSON. Numbers are kept as json.Number
+func TransactionToMap(tx interface{}) (map[string]interface{}, error) {
+	var result map[string]interface{}
+
+	if m, ok := tx.(map[string]interface{}); ok {
+		return m, nil
+	}
+
+	txJson, err := json.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(txJson))
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Serialises the transaction. If signing is true, the fields which are not signed (eg TxnSignature) are excluded
+func EncodeTransaction(tx map[string]interface{}, signing bool) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := encodeObject(&buf, tx, signing); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Returns the hash of the transaction which is signed
+func SigningHash(tx map[string]interface{}) ([]byte, error) {
+	encoded, err := EncodeTransaction(tx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha512half.Sum256(append(append([]byte{}, HashPrefixTransactionSign...), encoded...))
+
+	return hash[:], nil
+}
+
+// Returns the transaction id (hash) of a signed transaction blob
+func TransactionHash(txBlob []byte) string {
+	hash := sha512half.Sum256(append(append([]byte{}, HashPrefixTransactionId...), txBlob...))
+
+	return strings.ToUpper(hex.EncodeToString(hash[:]))
+}
+
+// Decodes a Ripple address into its 20 byte account id
+func DecodeAccountId(address string) ([]byte, error) {
+	// rkey doesn't handle an empty address
+	if address == "" {
+		return nil, errors.New("Invalid Ripple address: the address is empty")
+	}
+
+	accountId, err := rkey.NewAccountId(address)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Ripple address %s: %s", address, err.Error())
+	}
+
+	raw := accountId.Id.Bytes()
+	if len(raw) > 20 {
+		return nil, fmt.Errorf("Invalid Ripple address %s", address)
+	}
+
+	return append(make([]byte, 20-len(raw)), raw...), nil
+}
+
+type encodedField struct {
+	name       string
+	definition fieldDefinition
+	value      interface{}
+}
+
+// Fields are serialised in canonical order of type code then field code
+type canonicalOrder []encodedField
+
+func (s canonicalOrder) Len() int      { return len(s) }
+func (s canonicalOrder) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s canonicalOrder) Less(i, j int) bool {
+	if s[i].definition.typeCode != s[j].definition.typeCode {
+		return s[i].definition.typeCode < s[j].definition.typeCode
+	}
+	return s[i].definition.fieldCode < s[j].definition.fieldCode
+}
+
+func encodeObject(buf *bytes.Buffer, object map[string]interface{}, signing bool) error {
+	var toEncode canonicalOrder
+
+	for name, value := range object {
+		definition, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("Unsupported field %s", name)
+		}
+
+		if signing && definition.signing == false {
+			continue
+		}
+
+		// Absent values. Blobs may legitimately be empty, eg SigningPubKey in a multisigned transaction
+		if value == nil {
+			continue
+		}
+		if s, ok := value.(string); ok && s == "" && definition.typeCode != typeBlob {
+			continue
+		}
+		if a, ok := value.([]interface{}); ok && len(a) == 0 {
+			continue
+		}
+
+		toEncode = append(toEncode, encodedField{name: name, definition: definition, value: value})
+	}
+
+	sort.Sort(toEncode)
+
+	for _, f := range toEncode {
+		writeFieldId(buf, f.definition.typeCode, f.definition.fieldCode)
+
+		if err := encodeValue(buf, f, signing); err != nil {
+			return fmt.Errorf("%s: %s", f.name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func writeFieldId(buf *bytes.Buffer, typeCode int, fieldCode int) {
+	switch {
+	case typeCode < 16 && fieldCode < 16:
+		buf.WriteByte(byte(typeCode<<4 | fieldCode))
+	case typeCode < 16:
+		buf.WriteByte(byte(typeCode << 4))
+		buf.WriteByte(byte(fieldCode))
+	case fieldCode < 16:
+		buf.WriteByte(byte(fieldCode))
+		buf.WriteByte(byte(typeCode))
+	default:
+		buf.WriteByte(0)
+		buf.WriteByte(byte(typeCode))
+		buf.WriteByte(byte(fieldCode))
+	}
+}
+
+func writeLength(buf *bytes.Buffer, length int) error {
+	switch {
+	case length <= 192:
+		buf.WriteByte(byte(length))
+	case length <= 12480:
+		length -= 193
+		buf.WriteByte(byte(193 + (length >> 8)))
+		buf.WriteByte(byte(length & 0xff))
+	case length <= 918744:
+		length -= 12481
+		buf.WriteByte(byte(241 + (length >> 16)))
+		buf.WriteByte(byte((length >> 8) & 0xff))
+		buf.WriteByte(byte(length & 0xff))
+	default:
+		return errors.New("Variable length field is too long")
+	}
+
+	return nil
+}
+
+func encodeValue(buf *bytes.Buffer, f encodedField, signing bool) error {
+	switch f.definition.typeCode {
+	case typeUInt8:
+		n, err := toUint(f.value, 8)
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(byte(n))
+
+	case typeUInt16:
+		var n uint64
+		var err error
+
+		if f.name == "TransactionType" {
+			name, _ := f.value.(string)
+			code, ok := transactionTypes[name]
+			if !ok {
+				return fmt.Errorf("Unsupported transaction type %v", f.value)
+			}
+			n = uint64(code)
+		} else if n, err = toUint(f.value, 16); err != nil {
+			return err
+		}
+		binary.Write(buf, binary.BigEndian, uint16(n))
+
+	case typeUInt32:
+		n, err := toUint(f.value, 32)
+		if err != nil {
+			return err
+		}
+		binary.Write(buf, binary.BigEndian, uint32(n))
+
+	case typeUInt64:
+		// UInt64 values are given as hex strings
+		s, _ := f.value.(string)
+		n, err := strconv.ParseUint(s, 16, 64)
+		if err != nil {
+			return err
+		}
+		binary.Write(buf, binary.BigEndian, n)
+
+	case typeHash128, typeHash256, typeHash160:
+		size := 32
+		if f.definition.typeCode == typeHash128 {
+			size = 16
+		} else if f.definition.typeCode == typeHash160 {
+			size = 20
+		}
+		s, _ := f.value.(string)
+		b, err := hex.DecodeString(s)
+		if err != nil || len(b) != size {
+			return fmt.Errorf("Expected a %d byte hex string", size)
+		}
+		buf.Write(b)
+
+	case typeAmount:
+		return encodeAmount(buf, f.value)
+
+	case typeBlob:
+		s, _ := f.value.(string)
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return errors.New("Expected a hex string")
+		}
+		if err := writeLength(buf, len(b)); err != nil {
+			return err
+		}
+		buf.Write(b)
+
+	case typeAccountID:
+		s, _ := f.value.(string)
+		accountId, err := DecodeAccountId(s)
+		if err != nil {
+			return err
+		}
+		writeLength(buf, len(accountId))
+		buf.Write(accountId)
+
+	case typeSTObject:
+		object, ok := f.value.(map[string]interface{})
+		if !ok {
+			return errors.New("Expected an object")
+		}
+		if err := encodeObject(buf, object, signing); err != nil {
+			return err
+		}
+		buf.WriteByte(objectEndMarker)
+
+	case typeSTArray:
+		array, ok := f.value.([]interface{})
+		if !ok {
+			return errors.New("Expected an array")
+		}
+		for _, element := range array {
+			// Each element is an object which wraps a single inner object, eg {"Memo": {...}}
+			wrapper, ok := element.(map[string]interface{})
+			if !ok || len(wrapper) != 1 {
+				return errors.New("Array elements must contain a single object")
+			}
+			if err := encodeObject(buf, wrapper, signing); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(arrayEndMarker)
+
+	case typePathSet:
+		return encodePathSet(buf, f.value)
+
+	default:
+		return fmt.Errorf("Unsupported type %d", f.definition.typeCode)
+	}
+
+	return nil
+}
+
+func toUint(value interface{}, bits int) (uint64, error) {
+	var s string
+
+	switch v := value.(type) {
+	case json.Number:
+		s = v.String()
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		s = strconv.Itoa(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case uint32:
+		return uint64(v), nil
+	case uint64:
+		s = strconv.FormatUint(v, 10)
+	case string:
+		s = v
+	default:
+		return 0, fmt.Errorf("Expected a number but got %v", value)
+	}
+
+	return strconv.ParseUint(s, 10, bits)
+}
+
+// Serialises the currency code. Three character codes are placed at bytes 12-14, XRP is all zeros and
+// 40 character hex strings are used as is
+func EncodeCurrency(currency string) ([]byte, error) {
+	result := make([]byte, 20)
+
+	switch {
+	case currency == "XRP":
+		return result, nil
+	case len(currency) == 3:
+		copy(result[12:], currency)
+		return result, nil
+	case len(currency) == 40:
+		b, err := hex.DecodeString(currency)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid currency %s", currency)
+		}
+		return b, nil
+	}
+
+	return nil, fmt.Errorf("Invalid currency %s", currency)
+}
+
+func encodeAmount(buf *bytes.Buffer, value interface{}) error {
+	// XRP amounts are given as a string of drops
+	if drops, ok := value.(string); ok {
+		negative := strings.HasPrefix(drops, "-")
+		n, err := strconv.ParseUint(strings.TrimPrefix(drops, "-"), 10, 64)
+		if err != nil || n > 100000000000000000 {
+			return fmt.Errorf("Invalid XRP amount %s", drops)
+		}
+
+		if negative == false {
+			n |= 0x4000000000000000
+		}
+		binary.Write(buf, binary.BigEndian, n)
+
+		return nil
+	}
+
+	amount, ok := value.(map[string]interface{})
+	if !ok {
+		return errors.New("Expected a string of drops or an amount object")
+	}
+
+	v, _ := amount["value"].(string)
+	currency, _ := amount["currency"].(string)
+	issuer, _ := amount["issuer"].(string)
+
+	encodedValue, err := EncodeIssuedValue(v)
+	if err != nil {
+		return err
+	}
+
+	encodedCurrency, err := EncodeCurrency(currency)
+	if err != nil {
+		return err
+	}
+
+	encodedIssuer, err := DecodeAccountId(issuer)
+	if err != nil {
+		return err
+	}
+
+	buf.Write(encodedValue)
+	buf.Write(encodedCurrency)
+	buf.Write(encodedIssuer)
+
+	return nil
+}
+
+// Serialises the value of an issued currency amount. The value is normalised to a 54 bit mantissa between
+// 10^15 and 10^16-1 and an exponent between -96 and 80. At most 16 significant digits are allowed.
+func EncodeIssuedValue(value string) ([]byte, error) {
+	var exponent int
+
+	result := make([]byte, 8)
+	s := strings.TrimSpace(value)
+
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+
+	// Scientific notation
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid amount %s", value)
+		}
+		exponent = e
+		s = s[:i]
+	}
+
+	// Remove the decimal point adjusting the exponent
+	if i := strings.Index(s, "."); i >= 0 {
+		exponent -= len(s) - i - 1
+		s = s[:i] + s[i+1:]
+	}
+
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return nil, fmt.Errorf("Invalid amount %s", value)
+	}
+
+	s = strings.TrimLeft(s, "0")
+	if s == "" {
+		// Zero has a special encoding
+		result[0] = 0x80
+		return result, nil
+	}
+
+	trimmed := strings.TrimRight(s, "0")
+	exponent += len(s) - len(trimmed)
+	if len(trimmed) > 16 {
+		return nil, fmt.Errorf("Amount %s has more than 16 significant digits", value)
+	}
+
+	mantissa, err := strconv.ParseUint(trimmed, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid amount %s", value)
+	}
+
+	for mantissa < 1000000000000000 {
+		mantissa *= 10
+		exponent--
+	}
+
+	if exponent < -96 || exponent > 80 {
+		return nil, fmt.Errorf("Amount %s is out of range", value)
+	}
+
+	n := uint64(0x8000000000000000) | uint64(exponent+97)<<54 | mantissa
+	if negative == false {
+		n |= 0x4000000000000000
+	}
+	binary.BigEndian.PutUint64(result, n)
+
+	return result, nil
+}
+
+func encodePathSet(buf *bytes.Buffer, value interface{}) error {
+	paths, ok := value.([]interface{})
+	if !ok {
+		return errors.New("Expected an array of paths")
+	}
+
+	for i, p := range paths {
+		steps, ok := p.([]interface{})
+		if !ok {
+			return errors.New("Expected a path to be an array of steps")
+		}
+
+		if i > 0 {
+			buf.WriteByte(pathSeparator)
+		}
+
+		for _, s := range steps {
+			var stepType byte
+			var data bytes.Buffer
+
+			step, ok := s.(map[string]interface{})
+			if !ok {
+				return errors.New("Expected a path step to be an object")
+			}
+
+			if account, ok := step["account"].(string); ok && account != "" {
+				b, err := DecodeAccountId(account)
+				if err != nil {
+					return err
+				}
+				stepType |= pathStepAccount
+				data.Write(b)
+			}
+			if currency, ok := step["currency"].(string); ok && currency != "" {
+				b, err := EncodeCurrency(currency)
+				if err != nil {
+					return err
+				}
+				stepType |= pathStepCurrency
+				data.Write(b)
+			}
+			if issuer, ok := step["issuer"].(string); ok && issuer != "" {
+				b, err := DecodeAccountId(issuer)
+				if err != nil {
+					return err
+				}
+				stepType |= pathStepIssuer
+				data.Write(b)
+			}
+
+			buf.WriteByte(stepType)
+			buf.Write(data.Bytes())
+		}
+	}
+
+	buf.WriteByte(pathSetEnd)
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package ripplecrypto
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestEncodeIssuedValue(t *testing.T) {
+	var vectors = []struct {
+		value    string
+		expected string
+	}{
+		{"0", "8000000000000000"},
+		{"1", "D4838D7EA4C68000"},
+		{"-1", "94838D7EA4C68000"},
+		{"1000", "D5438D7EA4C68000"},
+		{"0.001", "D3C38D7EA4C68000"},
+	}
+
+	for _, v := range vectors {
+		result, err := EncodeIssuedValue(v.value)
+		if err != nil {
+			t.Errorf("EncodeIssuedValue(%s) failed: %s", v.value, err.Error())
+			continue
+		}
+
+		if strings.ToUpper(hex.EncodeToString(result)) != v.expected {
+			t.Errorf("EncodeIssuedValue(%s): expected %s, got %X", v.value, v.expected, result)
+		}
+	}
+
+	if _, err := EncodeIssuedValue("1.2345678901234567"); err == nil {
+		t.Errorf("Expected an error for a value with more than 16 significant digits")
+	}
+}
+
+func TestKeyFromSecret(t *testing.T) {
+	privateKey, address, err := KeyFromSecret("snoPBrXtMeMyMHUVTgbuqAfg1SUTb")
+	if err != nil {
+		t.Fatalf("KeyFromSecret() failed: %s", err.Error())
+	}
+
+	if address != "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh" {
+		t.Errorf("Expected address rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh, got %s", address)
+	}
+
+	publicKey := strings.ToUpper(hex.EncodeToString(privateKey.PubKey().SerializeCompressed()))
+	if publicKey != "0330E7FC9D56BB25D6893BA3F317AE5BCF33B3291BD63DB32654A313222F7FD020" {
+		t.Errorf("Unexpected public key %s", publicKey)
+	}
+}
+
+func TestSignTransaction(t *testing.T) {
+	tx := map[string]interface{}{
+		"Flags":              2147483648,
+		"TransactionType":    "AccountSet",
+		"Account":            "r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59",
+		"Domain":             "726970706C652E636F6D",
+		"LastLedgerSequence": 8820051,
+		"Fee":                "12",
+		"Sequence":           23,
+	}
+
+	txBlob, hash, err := SignTransaction(tx, "shsWGZcmZz6YsWWmcnpfr6fLTdtFV")
+	if err != nil {
+		t.Fatalf("SignTransaction() failed: %s", err.Error())
+	}
+
+	expected := "12000322800000002400000017201B0086955368400000000000000C732102F89EAEC7667B30F33D0687BBA86C3FE2A08CCA40A9186C5BDE2DAA6FA97A37D874473045022100BDE09A1F6670403F341C21A77CF35BA47E45CDE974096E1AA5FC39811D8269E702203D60291B9A27F1DCABA9CF5DED307B4F23223E0B6F156991DB601DFB9C41CE1C770A726970706C652E636F6D81145E7B112523F68D2F5E879DB4EAC51C6698A69304"
+	if txBlob != expected {
+		t.Errorf("Expected tx_blob %s, got %s", expected, txBlob)
+	}
+
+	if hash != "02ACE87F1996E3A23690A5BB7F1774BF71CCBA68F79805831B42ABAD5913D6F4" {
+		t.Errorf("Unexpected hash %s", hash)
+	}
+}
+
+func TestEncodeTransaction(t *testing.T) {
+	// Signed transaction from the Ripple serialization format documentation
+	tx := map[string]interface{}{
+		"Account":       "rMBzp8CgpE441cp5PVyA9rpVV7oT8hP3ys",
+		"Expiration":    595640108,
+		"Fee":           "10",
+		"Flags":         524288,
+		"OfferSequence": 1752791,
+		"Sequence":      1752792,
+		"SigningPubKey": "03EE83BB432547885C219634A1BC407A9DB0474145D69737D09CCDC63E1DEE7FE3",
+		"TakerGets":     "15000000000",
+		"TakerPays": map[string]interface{}{
+			"currency": "USD",
+			"issuer":   "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B",
+			"value":    "7072.8",
+		},
+		"TransactionType": "OfferCreate",
+		"TxnSignature":    "30440220143759437C04F7B61F012563AFE90D8DAFC46E86035E1D965A9CED282C97D4CE02204CFD241E86F17E011298FC1A39B63386C74306A5DE047E213B0F29EFA4571C2C",
+	}
+
+	txBlob, err := EncodeTransaction(tx, false)
+	if err != nil {
+		t.Fatalf("EncodeTransaction() failed: %s", err.Error())
+	}
+
+	if hash := TransactionHash(txBlob); hash != "73734B611DDA23D3F5F62E20A173B78AB8406AC5015094DA53F53D39B9EDB06C" {
+		t.Errorf("Unexpected hash %s for tx_blob %X", hash, txBlob)
+	}
+}
+
+func TestSignClaim(t *testing.T) {
+	channelId, err := ChannelId("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", "r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59", 5)
+	if err != nil || len(channelId) != 64 {
+		t.Fatalf("ChannelId() failed: %s, %v", channelId, err)
+	}
+
+	signature, publicKey, err := SignClaim("snoPBrXtMeMyMHUVTgbuqAfg1SUTb", channelId, 1000000)
+	if err != nil {
+		t.Fatalf("SignClaim() failed: %s", err.Error())
+	}
+
+	if publicKey != "0330E7FC9D56BB25D6893BA3F317AE5BCF33B3291BD63DB32654A313222F7FD020" {
+		t.Errorf("Unexpected public key %s", publicKey)
+	}
+
+	if ok, err := VerifyClaim(publicKey, channelId, 1000000, signature); !ok || err != nil {
+		t.Errorf("Expected the claim to verify, got: %t, %v", ok, err)
+	}
+
+	// A claim for a different amount or channel must not verify with the same signature
+	if ok, _ := VerifyClaim(publicKey, channelId, 2000000, signature); ok {
+		t.Errorf("Expected a claim for a different amount not to verify")
+	}
+
+	otherChannelId, _ := ChannelId("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", "r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59", 6)
+	if ok, _ := VerifyClaim(publicKey, otherChannelId, 1000000, signature); ok {
+		t.Errorf("Expected a claim for a different channel not to verify")
+	}
+}
+
+func TestMultiSign(t *testing.T) {
+	tx := map[string]interface{}{
+		"Flags":           2147483648,
+		"TransactionType": "AccountSet",
+		"Account":         "r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59",
+		"SetFlag":         4,
+		"Fee":             "30000",
+		"Sequence":        23,
+	}
+
+	signer, err := MultiSign(tx, "snoPBrXtMeMyMHUVTgbuqAfg1SUTb", "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	if err != nil {
+		t.Fatalf("MultiSign() failed: %s", err.Error())
+	}
+
+	if signer.SigningPubKey != "0330E7FC9D56BB25D6893BA3F317AE5BCF33B3291BD63DB32654A313222F7FD020" {
+		t.Errorf("Unexpected public key %s", signer.SigningPubKey)
+	}
+
+	if ok, err := VerifyMultiSignature(tx, signer); !ok || err != nil {
+		t.Errorf("Expected the signature to verify, got: %t, %v", ok, err)
+	}
+
+	// The signature is only valid for the signer account it was made for
+	other := signer
+	other.Account = "r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59"
+	if ok, _ := VerifyMultiSignature(tx, other); ok {
+		t.Errorf("Expected the signature not to verify for another signer account")
+	}
+
+	txBlob, hash, err := AssembleMultiSigned(tx, []MultiSigner{signer})
+	if err != nil {
+		t.Fatalf("AssembleMultiSigned() failed: %s", err.Error())
+	}
+
+	// The empty SigningPubKey and the Signers array must be present
+	if !strings.Contains(txBlob, "7300") || !strings.Contains(txBlob, "F3E0107321"+signer.SigningPubKey) || len(hash) != 64 {
+		t.Errorf("Unexpected multisigned tx_blob %s", txBlob)
+	}
+}
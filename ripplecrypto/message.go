@@ -0,0 +1,66 @@
+package ripplecrypto
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/vennd/enu/internal/bitbucket.org/dchapes/ripple/crypto/rkey"
+	"github.com/vennd/enu/internal/bitbucket.org/dchapes/ripple/crypto/sha512half"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/btcec"
+)
+
+// Signs the SHA512Half of the message with the master key of the secret, as ripple-keypairs does.
+// Returns the hex encoded DER signature, the public key which verifies it and the address of the key.
+func SignMessage(secret string, message string) (string, string, string, error) {
+	privateKey, address, err := KeyFromSecret(secret)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	hash := sha512half.Sum256([]byte(message))
+	signature, err := privateKey.Sign(hash[:])
+	if err != nil {
+		return "", "", "", err
+	}
+
+	publicKey := strings.ToUpper(hex.EncodeToString(privateKey.PubKey().SerializeCompressed()))
+
+	return strings.ToUpper(hex.EncodeToString(signature.Serialize())), publicKey, address, nil
+}
+
+// Verifies the hex encoded DER signature of the message was made by the public key and the public key is the key of
+// the address. Ripple signatures don't allow the public key to be recovered so it must be given.
+func VerifyMessage(address string, message string, signature string, publicKey string) (bool, error) {
+	var accountKey rkey.AcctPublicKey
+	if err := accountKey.UnmarshalJSON([]byte(publicKey)); err != nil {
+		return false, err
+	}
+
+	if accountKey.Address() != address {
+		return false, nil
+	}
+
+	publicKeyBytes, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return false, err
+	}
+
+	pubKey, err := btcec.ParsePubKey(publicKeyBytes, btcec.S256())
+	if err != nil {
+		return false, err
+	}
+
+	signatureBytes, err := hex.DecodeString(signature)
+	if err != nil {
+		return false, err
+	}
+
+	sig, err := btcec.ParseDERSignature(signatureBytes, btcec.S256())
+	if err != nil {
+		return false, err
+	}
+
+	hash := sha512half.Sum256([]byte(message))
+
+	return sig.Verify(hash[:], pubKey), nil
+}
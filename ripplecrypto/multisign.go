@@ -0,0 +1,175 @@
+package ripplecrypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/vennd/enu/internal/bitbucket.org/dchapes/ripple/crypto/sha512half"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/btcec"
+)
+
+// The signature of one signer of a multisigned transaction, as it appears in the Signers array of the transaction
+type MultiSigner struct {
+	Account       string
+	SigningPubKey string
+	TxnSignature  string
+}
+
+// Returns the hash of the transaction signed by the signer account. Each signer signs a different hash as the account
+// of the signer is appended to the transaction
+func MultiSigningHash(tx map[string]interface{}, signerAccount string) ([]byte, error) {
+	accountId, err := DecodeAccountId(signerAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Multisigned transactions are signed with an empty SigningPubKey
+	m := make(map[string]interface{}, len(tx)+1)
+	for k, v := range tx {
+		m[k] = v
+	}
+	m["SigningPubKey"] = ""
+
+	encoded, err := EncodeTransaction(m, true)
+	if err != nil {
+		return nil, err
+	}
+
+	message := append(append([]byte{}, HashPrefixTransactionMultiSign...), encoded...)
+	hash := sha512half.Sum256(append(message, accountId...))
+
+	return hash[:], nil
+}
+
+// Signs the transaction for the signer account with the secret, which is either the master key or regular key of the
+// signer account. Returns the signature of the signer
+func MultiSign(tx interface{}, secret string, signerAccount string) (MultiSigner, error) {
+	privateKey, _, err := KeyFromSecret(secret)
+	if err != nil {
+		return MultiSigner{}, err
+	}
+
+	txMap, err := TransactionToMap(tx)
+	if err != nil {
+		return MultiSigner{}, err
+	}
+
+	hash, err := MultiSigningHash(txMap, signerAccount)
+	if err != nil {
+		return MultiSigner{}, err
+	}
+
+	signature, err := privateKey.Sign(hash)
+	if err != nil {
+		return MultiSigner{}, err
+	}
+
+	return MultiSigner{
+		Account:       signerAccount,
+		SigningPubKey: strings.ToUpper(hex.EncodeToString(privateKey.PubKey().SerializeCompressed())),
+		TxnSignature:  strings.ToUpper(hex.EncodeToString(signature.Serialize())),
+	}, nil
+}
+
+// Verifies the signature was made over the transaction for the signer account by the public key. Whether the public
+// key belongs to the signer account can only be checked against the ledger
+func VerifyMultiSignature(tx interface{}, signer MultiSigner) (bool, error) {
+	txMap, err := TransactionToMap(tx)
+	if err != nil {
+		return false, err
+	}
+
+	hash, err := MultiSigningHash(txMap, signer.Account)
+	if err != nil {
+		return false, err
+	}
+
+	publicKeyBytes, err := hex.DecodeString(signer.SigningPubKey)
+	if err != nil {
+		return false, err
+	}
+
+	pubKey, err := btcec.ParsePubKey(publicKeyBytes, btcec.S256())
+	if err != nil {
+		return false, err
+	}
+
+	signatureBytes, err := hex.DecodeString(signer.TxnSignature)
+	if err != nil {
+		return false, err
+	}
+
+	sig, err := btcec.ParseDERSignature(signatureBytes, btcec.S256())
+	if err != nil {
+		return false, err
+	}
+
+	return sig.Verify(hash, pubKey), nil
+}
+
+type signersByAccountId struct {
+	signers    []MultiSigner
+	accountIds [][]byte
+}
+
+func (s signersByAccountId) Len() int { return len(s.signers) }
+func (s signersByAccountId) Swap(i, j int) {
+	s.signers[i], s.signers[j] = s.signers[j], s.signers[i]
+	s.accountIds[i], s.accountIds[j] = s.accountIds[j], s.accountIds[i]
+}
+func (s signersByAccountId) Less(i, j int) bool {
+	return bytes.Compare(s.accountIds[i], s.accountIds[j]) < 0
+}
+
+// Combines the signatures of the signers into the multisigned transaction.
+// Returns the hex encoded tx_blob ready for submission and the transaction hash.
+func AssembleMultiSigned(tx interface{}, signers []MultiSigner) (string, string, error) {
+	if len(signers) == 0 {
+		return "", "", errors.New("A multisigned transaction must have at least one signer")
+	}
+
+	txMap, err := TransactionToMap(tx)
+	if err != nil {
+		return "", "", err
+	}
+
+	// rippled requires the signers to be sorted by account id
+	sorted := signersByAccountId{signers: append([]MultiSigner{}, signers...)}
+	for _, signer := range sorted.signers {
+		accountId, err := DecodeAccountId(signer.Account)
+		if err != nil {
+			return "", "", err
+		}
+		sorted.accountIds = append(sorted.accountIds, accountId)
+	}
+	sort.Sort(sorted)
+
+	var signersArray []interface{}
+	for _, signer := range sorted.signers {
+		signersArray = append(signersArray, map[string]interface{}{
+			"Signer": map[string]interface{}{
+				"Account":       signer.Account,
+				"SigningPubKey": signer.SigningPubKey,
+				"TxnSignature":  signer.TxnSignature,
+			},
+		})
+	}
+
+	m := make(map[string]interface{}, len(txMap)+2)
+	for k, v := range txMap {
+		m[k] = v
+	}
+	m["SigningPubKey"] = ""
+	m["Signers"] = signersArray
+	delete(m, "TxnSignature")
+
+	txBlob, err := EncodeTransaction(m, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	return strings.ToUpper(hex.EncodeToString(txBlob)), TransactionHash(txBlob), nil
+}
@@ -122,3 +122,29 @@ func CreateWallet(numberOfAddressesToGenerate int) (RippleWallet, error) {
 
 	return wallet, nil
 }
+
+// Returns the public generator of the family seed of the secret. Like an xpub it derives the account addresses of
+// the seed without the private keys.
+func PublicGeneratorFromSecret(secret string) (string, error) {
+	s, err := rkey.NewFamilySeed(secret)
+	if err != nil {
+		return "", err
+	}
+
+	generator, err := s.PrivateGenerator.PublicGenerator.MarshalText()
+	if err != nil {
+		return "", err
+	}
+
+	return string(generator), nil
+}
+
+// Returns the address of the account at the position of the public generator, as derived by CreateWallet()
+func AddressFromPublicGenerator(publicGenerator string, position uint32) (string, error) {
+	g, err := rkey.NewPublicGenerator(publicGenerator)
+	if err != nil {
+		return "", err
+	}
+
+	return g.Generate(position).Address(), nil
+}
@@ -0,0 +1,60 @@
+package ripplecrypto
+
+import (
+	"testing"
+)
+
+func TestAddressFromPublicGenerator(t *testing.T) {
+	wallet, err := CreateWallet(5)
+	if err != nil {
+		t.Fatalf("Unable to create wallet: %s\n", err.Error())
+	}
+
+	secret, err := ToSecret(wallet.HexSeed)
+	if err != nil {
+		t.Fatalf("Unable to convert seed to secret: %s\n", err.Error())
+	}
+
+	generator, err := PublicGeneratorFromSecret(secret)
+	if err != nil {
+		t.Fatalf("Unable to get public generator: %s\n", err.Error())
+	}
+
+	// Addresses derived from the public generator must be the same as those derived from the seed
+	for i, expected := range wallet.Addresses {
+		address, err := AddressFromPublicGenerator(generator, uint32(i))
+		if err != nil {
+			t.Fatalf("Unable to derive address %d: %s\n", i, err.Error())
+		}
+
+		if address != expected {
+			t.Errorf("Expected: %s, got: %s\n", expected, address)
+		}
+	}
+}
+
+func TestSignVerifyMessage(t *testing.T) {
+	// Genesis account secret
+	secret := "snoPBrXtMeMyMHUVTgbuqAfg1SUTb"
+
+	signature, publicKey, address, err := SignMessage(secret, "Proof of ownership")
+	if err != nil {
+		t.Fatalf("Unable to sign message: %s\n", err.Error())
+	}
+
+	if address != "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh" {
+		t.Errorf("Expected: rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh, got: %s\n", address)
+	}
+
+	if valid, err := VerifyMessage(address, "Proof of ownership", signature, publicKey); err != nil || !valid {
+		t.Errorf("Expected signature to verify, got: %t, %v\n", valid, err)
+	}
+
+	if valid, _ := VerifyMessage(address, "Another message", signature, publicKey); valid {
+		t.Errorf("Expected signature of a different message to be rejected\n")
+	}
+
+	if valid, _ := VerifyMessage("r9cZA1mLK5R5Am25ArfXFmqgNwjZgnfk59", "Proof of ownership", signature, publicKey); valid {
+		t.Errorf("Expected signature to be rejected for a different address\n")
+	}
+}
@@ -0,0 +1,74 @@
+package ripplecrypto
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/vennd/enu/internal/bitbucket.org/dchapes/ripple/crypto/rkey"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/btcec"
+)
+
+// Returns the secp256k1 key pair and address of the master account of the Ripple secret
+func KeyFromSecret(secret string) (*btcec.PrivateKey, string, error) {
+	s, err := rkey.NewFamilySeed(secret)
+	if err != nil {
+		return nil, "", err
+	}
+
+	accountKey := s.PrivateGenerator.Generate(0)
+	privateKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), accountKey.D.Bytes())
+	address := s.PrivateGenerator.PublicGenerator.Generate(0).Address()
+
+	return privateKey, address, nil
+}
+
+// Signs the transaction offline with the secret. The tx is either a struct which marshals to the rippled tx_json or
+// the tx_json as a map. Sequence and Fee must already be set as there is no rippled to autofill them.
+// Returns the hex encoded tx_blob ready for submission and the transaction hash.
+func SignTransaction(tx interface{}, secret string) (string, string, error) {
+	// The account isn't checked against the secret's address as the secret may be the account's regular key
+	privateKey, _, err := KeyFromSecret(secret)
+	if err != nil {
+		return "", "", err
+	}
+
+	txMap, err := TransactionToMap(tx)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Copy so the signature fields aren't added to the caller's map
+	m := make(map[string]interface{}, len(txMap)+2)
+	for k, v := range txMap {
+		m[k] = v
+	}
+
+	for _, required := range []string{"Account", "TransactionType", "Fee", "Sequence"} {
+		if m[required] == nil || m[required] == "" {
+			return "", "", fmt.Errorf("Transaction is missing required field %s", required)
+		}
+	}
+
+	m["SigningPubKey"] = strings.ToUpper(hex.EncodeToString(privateKey.PubKey().SerializeCompressed()))
+	delete(m, "TxnSignature")
+
+	hash, err := SigningHash(m)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Signatures are deterministic (RFC6979) and fully canonical (low S) as required by rippled
+	signature, err := privateKey.Sign(hash)
+	if err != nil {
+		return "", "", err
+	}
+	m["TxnSignature"] = strings.ToUpper(hex.EncodeToString(signature.Serialize()))
+
+	txBlob, err := EncodeTransaction(m, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	return strings.ToUpper(hex.EncodeToString(txBlob)), TransactionHash(txBlob), nil
+}
@@ -0,0 +1,293 @@
+package ripplehandlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/ripplecrypto"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// The account flags which may be changed through the API, keyed by the name of the setting in the request
+var accountSettingFlags = []struct {
+	name    string
+	asf     uint32
+	current func(settings *enulib.RippleAccountSettings) *bool
+}{
+	{"requireDestTag", rippleapi.AsfRequireDest, func(s *enulib.RippleAccountSettings) *bool { return &s.RequireDestTag }},
+	{"requireAuth", rippleapi.AsfRequireAuth, func(s *enulib.RippleAccountSettings) *bool { return &s.RequireAuth }},
+	{"disallowXRP", rippleapi.AsfDisallowXRP, func(s *enulib.RippleAccountSettings) *bool { return &s.DisallowXRP }},
+	{"globalFreeze", rippleapi.AsfGlobalFreeze, func(s *enulib.RippleAccountSettings) *bool { return &s.GlobalFreeze }},
+}
+
+// Decodes the flags and optional fields of the AccountRoot into the named settings
+func decodeAccountSettings(accountInfo rippleapi.AccountInfo) enulib.RippleAccountSettings {
+	var settings enulib.RippleAccountSettings
+
+	settings.Address = accountInfo.Account
+	settings.RequireDestTag = accountInfo.Flags&rippleapi.LsfRequireDestTag != 0
+	settings.RequireAuth = accountInfo.Flags&rippleapi.LsfRequireAuth != 0
+	settings.DisallowXRP = accountInfo.Flags&rippleapi.LsfDisallowXRP != 0
+	settings.DisableMaster = accountInfo.Flags&rippleapi.LsfDisableMaster != 0
+	settings.NoFreeze = accountInfo.Flags&rippleapi.LsfNoFreeze != 0
+	settings.GlobalFreeze = accountInfo.Flags&rippleapi.LsfGlobalFreeze != 0
+	settings.DefaultRipple = accountInfo.Flags&rippleapi.LsfDefaultRipple != 0
+	settings.TransferRate = accountInfo.TransferRate
+	settings.EmailHash = accountInfo.EmailHash
+
+	// Ripple treats a missing transfer rate as no fee
+	if settings.TransferRate == 0 {
+		settings.TransferRate = rippleapi.TransferRateNoFee
+	}
+
+	// The domain is stored as the hex of the ASCII domain
+	if domain, err := hex.DecodeString(accountInfo.Domain); err == nil {
+		settings.Domain = string(domain)
+	}
+
+	return settings
+}
+
+// Returns the AccountSet transactions needed to make the changes. Each AccountSet can set one flag and clear one flag
+// so the flag changes are spread over as many transactions as needed. The other fields are sent with the first.
+func accountSetTransactions(sets []uint32, clears []uint32, fields rippleapi.AccountSet, hasFields bool) []rippleapi.AccountSet {
+	var txs []rippleapi.AccountSet
+
+	for i := 0; i < len(sets) || i < len(clears) || (i == 0 && hasFields); i++ {
+		var tx rippleapi.AccountSet
+
+		if i == 0 && hasFields {
+			tx = fields
+		}
+
+		if i < len(sets) {
+			tx.SetFlag = sets[i]
+		}
+
+		if i < len(clears) {
+			tx.ClearFlag = clears[i]
+		}
+
+		txs = append(txs, tx)
+	}
+
+	return txs
+}
+
+func getAccountSettings(c context.Context, w http.ResponseWriter, address string) (rippleapi.AccountInfo, bool) {
+	if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in DecodeAccountId(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return rippleapi.AccountInfo{}, false
+	}
+
+	accountInfo, errorCode, err := rippleapi.GetAccountInfo(c, address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetAccountInfo(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return accountInfo, false
+	}
+
+	// The account has not been activated so has no settings
+	if accountInfo.Account == "" {
+		log.FluentfContext(consts.LOGERROR, c, "Account %s has not been activated", address)
+		handlers.ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return accountInfo, false
+	}
+
+	return accountInfo, true
+}
+
+// Returns the settings of the account given by the address query parameter
+func GetAccountSettings(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	address := r.URL.Query().Get("address")
+
+	log.FluentfContext(consts.LOGINFO, c, "GetAccountSettings called for '%s' by '%s'\n", address, c.Value(consts.AccessKeyKey).(string))
+
+	accountInfo, ok := getAccountSettings(c, w, address)
+	if !ok {
+		return nil
+	}
+
+	settings := decodeAccountSettings(accountInfo)
+	settings.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(settings); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Changes the settings of an account. Only the settings given are changed and only if they differ from the current
+// settings of the account. Returns the settings of the account after the change and the hashes of the AccountSets.
+func AccountSettingsSet(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var sets []uint32
+	var clears []uint32
+	var fields rippleapi.AccountSet
+	var hasFields bool
+	var given bool
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	address := m["address"].(string)
+
+	log.FluentfContext(consts.LOGINFO, c, "AccountSettingsSet called for '%s' by '%s'\n", address, c.Value(consts.AccessKeyKey).(string))
+
+	txSigner, ok := signerFromRequest(c, w, m, address, "")
+	if !ok {
+		return nil
+	}
+
+	accountInfo, ok := getAccountSettings(c, w, address)
+	if !ok {
+		return nil
+	}
+
+	settings := decodeAccountSettings(accountInfo)
+
+	for _, flag := range accountSettingFlags {
+		if m[flag.name] == nil {
+			continue
+		}
+		given = true
+
+		value := m[flag.name].(bool)
+		current := flag.current(&settings)
+		if value == *current {
+			continue
+		}
+
+		if value {
+			sets = append(sets, flag.asf)
+		} else {
+			clears = append(clears, flag.asf)
+		}
+		*current = value
+	}
+
+	// RequireAuth can't be enabled once the account owns trust lines or offers
+	if settings.RequireAuth && accountInfo.Flags&rippleapi.LsfRequireAuth == 0 && accountInfo.OwnerCount > 0 {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.RequireAuthHasOwners.Code, errors.New(consts.RippleErrors.RequireAuthHasOwners.Description))
+
+		return nil
+	}
+
+	// NoFreeze is permanent and stops a global freeze from being lifted
+	if !settings.GlobalFreeze && accountInfo.Flags&rippleapi.LsfGlobalFreeze != 0 && settings.NoFreeze {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.GlobalFreezePermanent.Code, errors.New(consts.RippleErrors.GlobalFreezePermanent.Description))
+
+		return nil
+	}
+
+	if m["transferRate"] != nil {
+		given = true
+		transferRate := uint32(m["transferRate"].(float64))
+
+		// 0 is accepted as no fee
+		if transferRate == 0 {
+			transferRate = rippleapi.TransferRateNoFee
+		}
+
+		if m["transferRate"].(float64) > rippleapi.TransferRateMaximum || transferRate < rippleapi.TransferRateNoFee {
+			handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidTransferRate.Code, consts.RippleErrors.InvalidTransferRate.Description)
+
+			return nil
+		}
+
+		if transferRate != settings.TransferRate {
+			fields.TransferRate = transferRate
+			settings.TransferRate = transferRate
+			hasFields = true
+		}
+	}
+
+	if m["domain"] != nil {
+		given = true
+		domain := strings.ToLower(m["domain"].(string))
+
+		if len(domain) == 0 || len(domain) > 256 {
+			handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidDomain.Code, consts.RippleErrors.InvalidDomain.Description)
+
+			return nil
+		}
+
+		if domain != settings.Domain {
+			fields.Domain = strings.ToUpper(hex.EncodeToString([]byte(domain)))
+			settings.Domain = domain
+			hasFields = true
+		}
+	}
+
+	if m["emailHash"] != nil {
+		given = true
+		emailHash := strings.ToUpper(m["emailHash"].(string))
+
+		if _, err := hex.DecodeString(emailHash); err != nil || len(emailHash) != 32 {
+			handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidEmailHash.Code, consts.RippleErrors.InvalidEmailHash.Description)
+
+			return nil
+		}
+
+		if emailHash != settings.EmailHash {
+			fields.EmailHash = emailHash
+			settings.EmailHash = emailHash
+			hasFields = true
+		}
+	}
+
+	if !given {
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.NoSettingsGiven.Code, consts.RippleErrors.NoSettingsGiven.Description)
+
+		return nil
+	}
+
+	// The txs are given consecutive sequences. If one fails the txs after it aren't submitted
+	txs := accountSetTransactions(sets, clears, fields, hasFields)
+	if len(txs) > 0 {
+		txHashes, errorCode, err := rippleapi.SubmitAccountSets(c, address, txs, txSigner)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in SubmitAccountSets() after submitting %d of %d transactions %v: %s", len(txHashes), len(txs), txHashes, err.Error())
+
+			if errorCode == consts.RippleErrors.RequireAuthHasOwners.Code {
+				handlers.ReturnUnprocessableEntity(c, w, errorCode, err)
+			} else {
+				handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+			}
+
+			return nil
+		}
+
+		settings.TxHashes = txHashes
+	}
+
+	settings.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(settings); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
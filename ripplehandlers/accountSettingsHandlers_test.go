@@ -0,0 +1,61 @@
+package ripplehandlers
+
+import (
+	"testing"
+
+	"github.com/vennd/enu/rippleapi"
+)
+
+func TestDecodeAccountSettings(t *testing.T) {
+	accountInfo := rippleapi.AccountInfo{Account: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", Flags: rippleapi.LsfRequireDestTag | rippleapi.LsfGlobalFreeze | rippleapi.LsfDefaultRipple, Domain: "726970706C652E636F6D"}
+
+	settings := decodeAccountSettings(accountInfo)
+
+	if !settings.RequireDestTag || !settings.GlobalFreeze || !settings.DefaultRipple {
+		t.Errorf("Expected RequireDestTag, GlobalFreeze and DefaultRipple to be set, got: %#v\n", settings)
+	}
+
+	if settings.RequireAuth || settings.DisallowXRP || settings.NoFreeze || settings.DisableMaster {
+		t.Errorf("Expected no other flags to be set, got: %#v\n", settings)
+	}
+
+	if settings.Domain != "ripple.com" {
+		t.Errorf("Expected: ripple.com, got: %s\n", settings.Domain)
+	}
+
+	if settings.TransferRate != rippleapi.TransferRateNoFee {
+		t.Errorf("Expected a missing transfer rate to be no fee, got: %d\n", settings.TransferRate)
+	}
+}
+
+func TestAccountSetTransactions(t *testing.T) {
+	fields := rippleapi.AccountSet{Domain: "726970706C652E636F6D"}
+
+	var testData = []struct {
+		Sets            []uint32
+		Clears          []uint32
+		HasFields       bool
+		Expected        int
+		CaseDescription string
+	}{
+		{nil, nil, false, 0, "No changes"},
+		{nil, nil, true, 1, "Only fields are changed"},
+		{[]uint32{rippleapi.AsfRequireDest}, []uint32{rippleapi.AsfDisallowXRP}, true, 1, "A set and a clear fit in one transaction with the fields"},
+		{[]uint32{rippleapi.AsfRequireDest, rippleapi.AsfRequireAuth, rippleapi.AsfGlobalFreeze}, []uint32{rippleapi.AsfDisallowXRP}, false, 3, "Each set needs its own transaction"},
+	}
+
+	for _, s := range testData {
+		txs := accountSetTransactions(s.Sets, s.Clears, fields, s.HasFields)
+
+		if len(txs) != s.Expected {
+			t.Errorf("%s. Expected %d transactions, got: %d\n", s.CaseDescription, s.Expected, len(txs))
+			continue
+		}
+
+		for i, tx := range txs {
+			if (i == 0 && s.HasFields) != (tx.Domain != "") {
+				t.Errorf("%s. Expected the fields only on the first transaction, got: %#v\n", s.CaseDescription, txs)
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package ripplehandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/keystore"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/ripplecrypto"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Hands out the next unused account of a Ripple family seed and records the assignment against the label. The wallet is
+// given as the public generator of the seed or a custodial wallet. Accounts are derived as per ripplecrypto.CreateWallet()
+// so account 0 is the master account of the seed, which is already in use by a custodial wallet.
+func WalletNextAddress(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var derivationKey string
+	var firstIndex uint32
+	var walletId string
+	var publicGenerator string
+	var label string
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+
+	if m["walletId"] != nil {
+		walletId = m["walletId"].(string)
+	}
+	if m["publicGenerator"] != nil {
+		publicGenerator = m["publicGenerator"].(string)
+	}
+	if m["label"] != nil {
+		label = m["label"].(string)
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "WalletNextAddress: received request walletId: %s, publicGenerator: %s, label: %s from accessKey: %s\n", walletId, publicGenerator, label, accessKey)
+
+	if publicGenerator != "" {
+		derivationKey = publicGenerator
+	} else {
+		wallet, errorCode, err := keystore.GetWallet(c, accessKey, walletId)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in keystore.GetWallet(): %s", err.Error())
+			handlers.ReturnKeystoreError(c, w, errorCode, err)
+
+			return nil
+		}
+
+		if wallet.BlockchainId != consts.RippleBlockchainId {
+			handlers.ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidWalletId.Code, consts.GenericErrors.InvalidWalletId.Description)
+
+			return nil
+		}
+
+		passphrase, errorCode, err := keystore.GetPassphrase(c, accessKey, walletId, c.Value(consts.RequestTypeKey).(string), "", "")
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in keystore.GetPassphrase(): %s", err.Error())
+			handlers.ReturnKeystoreError(c, w, errorCode, err)
+
+			return nil
+		}
+
+		// Only the public generator is kept so the secret isn't held while the assignment is made
+		publicGenerator, err = ripplecrypto.PublicGeneratorFromSecret(ripplecrypto.PassphraseToSecret(c, passphrase))
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in ripplecrypto.PublicGeneratorFromSecret(): %s", err.Error())
+			handlers.ReturnServerError(c, w)
+
+			return nil
+		}
+
+		derivationKey = walletId
+		firstIndex = uint32(len(wallet.Addresses))
+	}
+
+	if _, err := ripplecrypto.AddressFromPublicGenerator(publicGenerator, 0); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in ripplecrypto.AddressFromPublicGenerator(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidXpub.Code, consts.GenericErrors.InvalidXpub.Description)
+
+		return nil
+	}
+
+	derive := func(index uint32) (string, error) {
+		return ripplecrypto.AddressFromPublicGenerator(publicGenerator, index)
+	}
+
+	assignment, err := database.AssignNextAddress(c, accessKey, consts.RippleBlockchainId, walletId, derivationKey, firstIndex, label, derive)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in AssignNextAddress(): %s", err.Error())
+		handlers.ReturnUnprocessableEntity(c, w, consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description))
+
+		return nil
+	}
+
+	if walletId == "" {
+		assignment.PublicGenerator = publicGenerator
+	}
+	assignment.RequestId = requestId
+
+	log.FluentfContext(consts.LOGINFO, c, "Assigned address: %s at index %d to label: %s for access key: %s", assignment.Address, assignment.Index, label, accessKey)
+
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(assignment); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
@@ -15,7 +15,7 @@ import (
 	"github.com/vennd/enu/internal/github.com/vennd/mneumonic"
 	"github.com/vennd/enu/log"
 	"github.com/vennd/enu/rippleapi"
-	"github.com/vennd/enu/ripplecrypto"
+	"github.com/vennd/enu/signer"
 
 	"github.com/vennd/enu/internal/golang.org/x/net/context"
 )
@@ -32,7 +32,6 @@ func AssetCreate(c context.Context, w http.ResponseWriter, r *http.Request, m ma
 
 	// The issuing address
 	sourceAddress := m["sourceAddress"].(string)
-	passphrase := m["passphrase"].(string)
 
 	// The address which will hold the asset once it is issued
 	if m["distributionAddress"] != nil {
@@ -56,6 +55,11 @@ func AssetCreate(c context.Context, w http.ResponseWriter, r *http.Request, m ma
 		log.FluentfContext(consts.LOGINFO, c, "Error in call to rippleapi.ToCurrency(): %s", err.Error())
 	}
 
+	issuingSigner, ok := signerFromRequest(c, w, m, sourceAddress, assetId)
+	if !ok {
+		return nil
+	}
+
 	//   If a distribution address has been specified, the passphrase must also be specified
 	if distributionAddress != "" && distributionPassphrase == "" {
 		log.FluentfContext(consts.LOGERROR, c, "If a distribution address is specified, the passphrase for the distribution address must be given.")
@@ -103,13 +107,13 @@ func AssetCreate(c context.Context, w http.ResponseWriter, r *http.Request, m ma
 	}
 
 	// Start asset creation in async mode
-	go delegatedAssetCreate(c, sourceAddress, passphrase, distributionAddress, distributionPassphrase, asset, asset, quantity, assetId)
+	go delegatedAssetCreate(c, sourceAddress, issuingSigner, distributionAddress, signer.NewPassphraseSigner(distributionPassphrase), asset, asset, quantity, assetId)
 
 	return nil
 }
 
 // Concurrency safe to create and send transactions from a single address.
-func delegatedAssetCreate(c context.Context, issuingAddress string, issuingPassphrase string, distributionAddress string, distributionPassphrase string, asset string, assetDescription string, quantity uint64, assetId string) (int64, error) {
+func delegatedAssetCreate(c context.Context, issuingAddress string, issuingSigner signer.Signer, distributionAddress string, distributionSigner signer.Signer, asset string, assetDescription string, quantity uint64, assetId string) (int64, error) {
 	//	var complete bool = false
 	//	var numLinesRequired = 0
 	//	var retries int = 0
@@ -149,7 +153,7 @@ func delegatedAssetCreate(c context.Context, issuingAddress string, issuingPassp
 
 	if defaultRipple != rippleapi.LsfDefaultRipple {
 		log.FluentfContext(consts.LOGINFO, c, "defaultRipple is NOT set for account %s. Setting the flag...", issuingAddress)
-		txHash, _, err := rippleapi.AccountSetFlag(c, issuingAddress, 8, ripplecrypto.PassphraseToSecret(c, issuingPassphrase))
+		txHash, _, err := rippleapi.AccountSetFlag(c, issuingAddress, 8, issuingSigner)
 		if err != nil {
 			log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.AccountSetFlag(): %s", err.Error())
 
@@ -166,7 +170,7 @@ func delegatedAssetCreate(c context.Context, issuingAddress string, issuingPassp
 	assets := []rippleapi.Amount{
 		{Currency: asset, Issuer: issuingAddress},
 	}
-	_, err = delegatedActivateAddress(c, distributionAddress, distributionPassphrase, 1, assets, assetId)
+	_, err = delegatedActivateAddress(c, distributionAddress, distributionSigner, 1, assets, assetId)
 	if err != nil {
 		log.FluentfContext(consts.LOGERROR, c, "Error in delegatedActivateAddress(): %s", err.Error())
 
@@ -197,7 +201,7 @@ func delegatedAssetCreate(c context.Context, issuingAddress string, issuingPassp
 	}
 
 	// Pay from the issuer wallet to the distribution wallet the amount of custom currency specified
-	payTxId, _, err := delegatedSend(c, accessKey, issuingPassphrase, issuingAddress, distributionAddress, asset, issuingAddress, quantity, assetId, "Asset creation")
+	payTxId, _, err := delegatedSend(c, accessKey, issuingSigner, issuingAddress, distributionAddress, asset, issuingAddress, quantity, assetId, "Asset creation", paymentOptions{})
 	if err != nil {
 		log.FluentfContext(consts.LOGERROR, c, "Error in delegatedSend: %s", err.Error())
 
@@ -0,0 +1,232 @@
+package ripplehandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/ripplecrypto"
+
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Returns the holders of the currency from the trust lines of the issuer. The issuer sees the amount held on each
+// line as a negative balance. Lines the issuer has frozen, or every line if the issuer has frozen them globally,
+// are flagged as frozen
+func ledgerBalances(lines rippleapi.Lines, currency string, supply uint64, globalFreeze bool) []enulib.AddressAmount {
+	var result []enulib.AddressAmount
+
+	for _, line := range lines {
+		if line.Currency != currency {
+			continue
+		}
+
+		// A positive balance is owed to the issuer by the peer so the peer is not a holder
+		if line.Balance != "0" && !strings.HasPrefix(line.Balance, "-") {
+			continue
+		}
+
+		quantity, err := rippleapi.AmountToUint64(strings.TrimPrefix(line.Balance, "-"))
+		if err != nil {
+			continue
+		}
+
+		balance := enulib.AddressAmount{Address: line.Account, Quantity: quantity, Frozen: line.Freeze || globalFreeze}
+		if supply > 0 {
+			balance.PercentageHolding = float64(quantity) / float64(supply) * 100
+		}
+
+		result = append(result, balance)
+	}
+
+	return result
+}
+
+// The supply of an issuer's currencies can no longer change once the issuer is blackholed, ie the master key is
+// disabled and no regular key is set
+func isIssuerLocked(accountInfo rippleapi.AccountInfo) bool {
+	return accountInfo.Flags&rippleapi.LsfDisableMaster != 0 && accountInfo.RegularKey == ""
+}
+
+// Returns the asset in the path and its issuer in the query string of the request along with the Ripple currency
+// of the asset. Returns false if the error has been returned to the client
+func getIssuedAsset(c context.Context, w http.ResponseWriter, r *http.Request) (string, string, string, bool) {
+	asset := mux.Vars(r)["asset"]
+	issuer := r.URL.Query().Get("issuer")
+
+	if asset == "" || strings.ToUpper(asset) == "XRP" {
+		log.FluentfContext(consts.LOGERROR, c, "Invalid asset")
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAsset.Code, consts.GenericErrors.InvalidAsset.Description)
+
+		return "", "", "", false
+	}
+
+	currency, err := rippleapi.ToCurrency(asset)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in ToCurrency(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidCurrency.Code, consts.RippleErrors.InvalidCurrency.Description)
+
+		return "", "", "", false
+	}
+
+	if issuer == "" {
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.IssuerMustBeGiven.Code, consts.RippleErrors.IssuerMustBeGiven.Description)
+
+		return "", "", "", false
+	}
+
+	if _, err := ripplecrypto.DecodeAccountId(issuer); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in DecodeAccountId(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return "", "", "", false
+	}
+
+	return asset, currency, issuer, true
+}
+
+// Summarises the ledger of an asset issued on Ripple. The supply is the amount the issuer owes across all its trust
+// lines and the obligations of the issuer in every other currency it has issued are also returned
+func AssetLedger(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var assetBalances enulib.AssetBalances
+
+	assetBalances.RequestId = c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	asset, currency, issuer, ok := getIssuedAsset(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "AssetLedger: received request asset: %s, issuer: %s from accessKey: %s\n", asset, issuer, c.Value(consts.AccessKeyKey).(string))
+
+	accountInfo, errorCode, err := rippleapi.GetAccountInfo(c, issuer)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetAccountInfo(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	gatewayBalances, errorCode, err := rippleapi.GetGatewayBalances(c, issuer, nil)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetGatewayBalances(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	lines, errorCode, err := rippleapi.GetAccountLines(c, issuer)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetAccountLines(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	for obligationCurrency, value := range gatewayBalances.Obligations {
+		quantity, err := rippleapi.AmountToUint64(value)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in AmountToUint64(): %s", err.Error())
+			continue
+		}
+
+		if obligationCurrency == currency {
+			assetBalances.Supply = quantity
+		}
+
+		obligationAsset, err := rippleapi.FromCurrency(obligationCurrency)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in FromCurrency(): %s", err.Error())
+			obligationAsset = obligationCurrency
+		}
+
+		assetBalances.Obligations = append(assetBalances.Obligations, enulib.Amount{Asset: obligationAsset, Issuer: issuer, Quantity: quantity})
+	}
+	sort.Slice(assetBalances.Obligations, func(i, j int) bool {
+		return assetBalances.Obligations[i].Asset < assetBalances.Obligations[j].Asset
+	})
+
+	assetBalances.Asset = asset
+	assetBalances.Issuer = issuer
+	assetBalances.Description = asset
+	assetBalances.Divisible = true
+	assetBalances.Divisibility = 100000000 // quantities of Ripple currencies are always given to 8 decimal places
+	assetBalances.Locked = isIssuerLocked(accountInfo)
+	assetBalances.Balances = ledgerBalances(lines, currency, assetBalances.Supply, accountInfo.Flags&rippleapi.LsfGlobalFreeze != 0)
+
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(assetBalances); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Lists the payments by which the issuer issued the asset on Ripple, oldest first
+func AssetIssuances(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var issuanceForAsset enulib.AssetIssuances
+
+	issuanceForAsset.RequestId = c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	asset, currency, issuer, ok := getIssuedAsset(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "AssetIssuances: received request asset: %s, issuer: %s from accessKey: %s\n", asset, issuer, c.Value(consts.AccessKeyKey).(string))
+
+	accountInfo, errorCode, err := rippleapi.GetAccountInfo(c, issuer)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetAccountInfo(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	issuances, errorCode, err := rippleapi.GetIssuances(c, issuer, currency)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetIssuances(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	issuanceForAsset.Asset = asset
+	issuanceForAsset.Description = asset
+	issuanceForAsset.Divisible = true
+	issuanceForAsset.Divisibility = 100000000 // quantities of Ripple currencies are always given to 8 decimal places
+	issuanceForAsset.Locked = isIssuerLocked(accountInfo)
+	issuanceForAsset.Issuances = make([]enulib.Issuance, 0)
+
+	for _, tx := range issuances {
+		quantity, err := rippleapi.AmountToUint64(tx.DeliveredAmount.Value)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in AmountToUint64(): %s", err.Error())
+			continue
+		}
+
+		issuanceForAsset.Issuances = append(issuanceForAsset.Issuances, enulib.Issuance{BlockIndex: tx.LedgerIndex, Quantity: quantity, Issuer: issuer, Destination: tx.Destination, TxId: tx.Hash})
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(issuanceForAsset); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package ripplehandlers
+
+import (
+	"testing"
+
+	"github.com/vennd/enu/rippleapi"
+)
+
+func TestLedgerBalances(t *testing.T) {
+	lines := rippleapi.Lines{
+		{Account: "rHolderOne", Currency: "USD", Balance: "-75"},
+		{Account: "rHolderTwo", Currency: "USD", Balance: "-25", Freeze: true},
+		{Account: "rHolderThree", Currency: "USD", Balance: "0"},
+		{Account: "rIssuerOfPeer", Currency: "USD", Balance: "10"},
+		{Account: "rHolderOne", Currency: "EUR", Balance: "-5"},
+	}
+
+	result := ledgerBalances(lines, "USD", 10000000000, false)
+
+	if len(result) != 3 {
+		t.Fatalf("Expected 3 holders, got: %d\n", len(result))
+	}
+
+	if result[0].Address != "rHolderOne" || result[0].Quantity != 7500000000 || result[0].PercentageHolding != 75 || result[0].Frozen {
+		t.Errorf("Unexpected balance for first holder: %+v\n", result[0])
+	}
+
+	if result[1].Quantity != 2500000000 || result[1].PercentageHolding != 25 || !result[1].Frozen {
+		t.Errorf("Expected the second holder to be frozen with 25 percent, got: %+v\n", result[1])
+	}
+
+	if result[2].Quantity != 0 || result[2].PercentageHolding != 0 {
+		t.Errorf("Expected the third holder to have nothing, got: %+v\n", result[2])
+	}
+
+	for _, balance := range ledgerBalances(lines, "USD", 0, true) {
+		if !balance.Frozen || balance.PercentageHolding != 0 {
+			t.Errorf("Expected every line to be frozen with no percentage when there is no supply, got: %+v\n", balance)
+		}
+	}
+}
+
+func TestIsIssuerLocked(t *testing.T) {
+	if isIssuerLocked(rippleapi.AccountInfo{Flags: rippleapi.LsfDisableMaster, RegularKey: "rRegularKey"}) {
+		t.Errorf("Expected an issuer with a regular key not to be locked\n")
+	}
+
+	if !isIssuerLocked(rippleapi.AccountInfo{Flags: rippleapi.LsfDisableMaster}) {
+		t.Errorf("Expected an issuer with the master key disabled and no regular key to be locked\n")
+	}
+
+	if isIssuerLocked(rippleapi.AccountInfo{}) {
+		t.Errorf("Expected an issuer with the master key enabled not to be locked\n")
+	}
+}
@@ -0,0 +1,641 @@
+package ripplehandlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/ripplecrypto"
+	"github.com/vennd/enu/signer"
+
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Channel ids are the hex of the 256 bit hash of the channel in the ledger
+func isChannelId(channelId string) bool {
+	if _, err := hex.DecodeString(channelId); err != nil || len(channelId) != 64 {
+		return false
+	}
+
+	return true
+}
+
+// Payment channels only hold XRP. Returns the drops of XRP of a quantity in the Enu denomination
+func toDrops(quantity uint64) uint64 {
+	return quantity / 100
+}
+
+// Returns the cumulative quantity the destination may claim once the channel authorises the further quantity
+func authoriseClaim(channel enulib.RippleChannel, quantity uint64) (uint64, int64, error) {
+	if channel.Status != "open" {
+		return 0, consts.RippleErrors.ChannelNotOpen.Code, errors.New(consts.RippleErrors.ChannelNotOpen.Description)
+	}
+
+	if toDrops(quantity) == 0 {
+		return 0, consts.RippleErrors.InvalidAmount.Code, errors.New(consts.RippleErrors.InvalidAmount.Description)
+	}
+
+	// Claims are for whole drops so the authorised quantity is always a whole number of drops
+	authorised := channel.AuthorisedQuantity + toDrops(quantity)*100
+	if authorised > channel.Quantity {
+		return 0, consts.RippleErrors.ChannelInsufficientFunds.Code, errors.New(consts.RippleErrors.ChannelInsufficientFunds.Description)
+	}
+
+	return authorised, 0, nil
+}
+
+// Returns the signer of the request, ie the passphrase, the custodial wallet given by walletId or the remote signer.
+// Otherwise writes the error and returns false
+func signerFromRequest(c context.Context, w http.ResponseWriter, m map[string]interface{}, address string, reference string) (signer.Signer, bool) {
+	txSigner, errorCode, err := signer.FromRequest(c, m, address, reference)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in signer.FromRequest(): %s\n", err.Error())
+		handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+		return nil, false
+	}
+
+	return txSigner, true
+}
+
+// Returns the signer of the request if it can sign claims off ledger. Otherwise writes the error and returns false
+func claimSignerFromRequest(c context.Context, w http.ResponseWriter, m map[string]interface{}, address string, reference string) (signer.Signer, signer.ClaimSigner, bool) {
+	txSigner, ok := signerFromRequest(c, w, m, address, reference)
+	if !ok {
+		return nil, nil, false
+	}
+
+	claimSigner, ok := txSigner.(signer.ClaimSigner)
+	if !ok {
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.SignerCannotSignClaims.Code, consts.RippleErrors.SignerCannotSignClaims.Description)
+
+		return nil, nil, false
+	}
+
+	return txSigner, claimSigner, true
+}
+
+func writeChannel(c context.Context, w http.ResponseWriter, status int, channel enulib.RippleChannel) {
+	channel.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(channel); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+	}
+}
+
+// Opens a payment channel holding XRP from the address for the destination. Claims against the channel are signed off
+// ledger with the key of the address so the signer must be a passphrase or custodial wallet. The channel can be closed
+// by the address after settleDelay seconds and expires at cancelAfter, a unix time
+func ChannelCreate(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var channel enulib.RippleChannel
+	var walletId string
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+
+	channel.BlockchainId = consts.RippleBlockchainId
+	channel.Address = m["address"].(string)
+	channel.Destination = m["destination"].(string)
+	channel.Quantity = toDrops(uint64(m["quantity"].(float64))) * 100
+	channel.SettleDelay = uint32(m["settleDelay"].(float64))
+
+	if m["destinationTag"] != nil {
+		tag := uint32(m["destinationTag"].(float64))
+		channel.DestinationTag = &tag
+	}
+
+	if m["cancelAfter"] != nil {
+		channel.CancelAfter = int64(m["cancelAfter"].(float64))
+	}
+
+	if m["walletId"] != nil {
+		walletId = m["walletId"].(string)
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "ChannelCreate called for '%s' to '%s' by '%s'\n", channel.Address, channel.Destination, accessKey)
+
+	for _, address := range []string{channel.Address, channel.Destination} {
+		if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+			return nil
+		}
+	}
+
+	if channel.Quantity == 0 {
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidAmount.Code, consts.RippleErrors.InvalidAmount.Description)
+
+		return nil
+	}
+
+	if channel.CancelAfter != 0 && channel.CancelAfter <= time.Now().Unix() {
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidChannelTimes.Code, consts.RippleErrors.InvalidChannelTimes.Description)
+
+		return nil
+	}
+
+	// Sign with the passphrase or the custodial wallet given by walletId. The channel is created with the public key
+	// of the address so the same signer can sign its claims
+	txSigner, claimSigner, ok := claimSignerFromRequest(c, w, m, channel.Address, "")
+	if !ok {
+		return nil
+	}
+
+	publicKey, errorCode, err := claimSigner.RipplePublicKey(c, channel.Address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in RipplePublicKey(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+		return nil
+	}
+	channel.PublicKey = publicKey
+
+	txFee, ok := currentFee(c, w)
+	if !ok {
+		return nil
+	}
+
+	unlock := lockAddress(c, channel.Address)
+	txHash, channelId, errorCode, err := rippleapi.CreateChannel(c, channel.Address, channel.Destination, strconv.FormatUint(toDrops(channel.Quantity), 10), channel.DestinationTag, channel.SettleDelay, channel.PublicKey, rippleapi.ToRippleTime(channel.CancelAfter), txSigner)
+	unlock()
+
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.CreateChannel(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	channel.ChannelId = channelId
+	channel.TxFee = int64(txFee)
+	channel.BroadcastTxId = txHash
+	channel.Status = "open"
+	if err := database.InsertChannel(c, accessKey, walletId, channel); err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Payment channel %s created. Complete.", channel.ChannelId)
+
+	writeChannel(c, w, http.StatusCreated, channel)
+
+	return nil
+}
+
+// Returns the payment channel created by the access key with the given channelId
+func GetChannel(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	channelId := strings.ToUpper(mux.Vars(r)["channelId"])
+	if !isChannelId(channelId) {
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidChannelId.Code, consts.GenericErrors.InvalidChannelId.Description)
+
+		return nil
+	}
+
+	channel := database.GetChannelByChannelId(c, c.Value(consts.AccessKeyKey).(string), channelId)
+	if channel.Status == consts.NotFound {
+		handlers.ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidChannelId.Code, consts.GenericErrors.InvalidChannelId.Description)
+
+		return nil
+	}
+
+	writeChannel(c, w, http.StatusOK, channel)
+
+	return nil
+}
+
+// Lists the payment channels created by the access key from the address given by the address query parameter
+func GetChannels(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var result enulib.RippleChannels
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	address := r.URL.Query().Get("address")
+	if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return nil
+	}
+
+	result.Address = address
+	result.Channels = database.GetChannelsByAddress(c, c.Value(consts.AccessKeyKey).(string), address)
+	if result.Channels == nil {
+		result.Channels = make([]enulib.RippleChannel, 0)
+	}
+	result.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Returns the payment channel given by the channelId in the path if it has one of the statuses. Otherwise writes the
+// error and returns false
+func channelWithStatus(c context.Context, w http.ResponseWriter, r *http.Request, statuses ...string) (enulib.RippleChannel, bool) {
+	channelId := strings.ToUpper(mux.Vars(r)["channelId"])
+
+	channel := database.GetChannelByChannelId(c, c.Value(consts.AccessKeyKey).(string), channelId)
+	if channel.Status == consts.NotFound {
+		handlers.ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidChannelId.Code, consts.GenericErrors.InvalidChannelId.Description)
+
+		return channel, false
+	}
+
+	for _, status := range statuses {
+		if channel.Status == status {
+			return channel, true
+		}
+	}
+
+	handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.ChannelNotOpen.Code, errors.New(consts.RippleErrors.ChannelNotOpen.Description))
+
+	return channel, false
+}
+
+// Adds XRP from the address to a payment channel created through ChannelCreate. If expiration, a unix time, is given
+// the channel expires then instead
+func ChannelFund(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var expiration int64
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+
+	log.FluentfContext(consts.LOGINFO, c, "ChannelFund called for '%s' by '%s'\n", mux.Vars(r)["channelId"], accessKey)
+
+	channel, ok := channelWithStatus(c, w, r, "open")
+	if !ok {
+		return nil
+	}
+
+	quantity := toDrops(uint64(m["quantity"].(float64))) * 100
+	if quantity == 0 {
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidAmount.Code, consts.RippleErrors.InvalidAmount.Description)
+
+		return nil
+	}
+
+	if m["expiration"] != nil {
+		expiration = int64(m["expiration"].(float64))
+
+		if expiration <= time.Now().Unix() {
+			handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidChannelTimes.Code, consts.RippleErrors.InvalidChannelTimes.Description)
+
+			return nil
+		}
+	}
+
+	txSigner, ok := signerFromRequest(c, w, m, channel.Address, channel.ChannelId)
+	if !ok {
+		return nil
+	}
+
+	unlock := lockAddress(c, channel.Address)
+	_, errorCode, err := rippleapi.FundChannel(c, channel.Address, channel.ChannelId, strconv.FormatUint(toDrops(quantity), 10), rippleapi.ToRippleTime(expiration), txSigner)
+	unlock()
+
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.FundChannel(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	database.UpdateChannelFundedByChannelId(c, accessKey, channel.ChannelId, quantity, expiration)
+
+	channel.Quantity += quantity
+	if expiration != 0 {
+		channel.Expiration = expiration
+	}
+
+	writeChannel(c, w, http.StatusOK, channel)
+
+	return nil
+}
+
+// Authorises the destination to claim a further quantity of XRP from the payment channel. The claim is signed off
+// ledger for the cumulative quantity authorised so only the latest claim needs to be given to the destination, which
+// can redeem it at any time before the channel closes
+func ChannelAuthorise(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var claim enulib.RippleChannelClaim
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+	channelId := strings.ToUpper(mux.Vars(r)["channelId"])
+
+	log.FluentfContext(consts.LOGINFO, c, "ChannelAuthorise called for '%s' by '%s'\n", channelId, accessKey)
+
+	// Claims against the channel are cumulative so they must be authorised one at a time
+	unlock := lockAddress(c, channelId)
+	defer unlock()
+
+	channel, ok := channelWithStatus(c, w, r, "open")
+	if !ok {
+		return nil
+	}
+
+	authorised, errorCode, err := authoriseClaim(channel, uint64(m["quantity"].(float64)))
+	if err != nil {
+		handlers.ReturnUnprocessableEntity(c, w, errorCode, err)
+
+		return nil
+	}
+
+	_, claimSigner, ok := claimSignerFromRequest(c, w, m, channel.Address, channel.ChannelId)
+	if !ok {
+		return nil
+	}
+
+	signature, publicKey, errorCode, err := claimSigner.SignRippleClaim(c, channel.Address, channel.ChannelId, toDrops(authorised))
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in SignRippleClaim(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	// Claims signed by any other key would be rejected by the ledger
+	if publicKey != channel.PublicKey {
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.SigningError.Code, consts.RippleErrors.SigningError.Description)
+
+		return nil
+	}
+
+	if err := database.UpdateChannelAuthorisedByChannelId(c, accessKey, channel.ChannelId, authorised, signature); err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	claim.ChannelId = channel.ChannelId
+	claim.Quantity = authorised
+	claim.Signature = signature
+	claim.PublicKey = publicKey
+	claim.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(claim); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Verifies a claim received off ledger against the payment channel in the ledger. The claim is valid if it was signed
+// by the key of the channel and the channel holds the quantity claimed. Any channel can be verified, not only those
+// created through ChannelCreate
+func ChannelVerify(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var claim enulib.RippleChannelClaim
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	claim.ChannelId = strings.ToUpper(m["channelId"].(string))
+	claim.Quantity = uint64(m["quantity"].(float64))
+	claim.Signature = strings.ToUpper(m["signature"].(string))
+
+	if !isChannelId(claim.ChannelId) {
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidChannelId.Code, consts.GenericErrors.InvalidChannelId.Description)
+
+		return nil
+	}
+
+	channel, errorCode, err := rippleapi.GetChannel(c, claim.ChannelId)
+	if errorCode == consts.RippleErrors.ChannelNotOpen.Code {
+		handlers.ReturnUnprocessableEntity(c, w, errorCode, err)
+
+		return nil
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.GetChannel(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	amount, err := strconv.ParseUint(channel.Amount, 10, 64)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Unable to parse the channel amount %s: %s", channel.Amount, err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	valid, err := ripplecrypto.VerifyClaim(channel.PublicKey, claim.ChannelId, toDrops(claim.Quantity), claim.Signature)
+	if err != nil {
+		valid = false
+	}
+	valid = valid && toDrops(claim.Quantity) <= amount
+
+	claim.PublicKey = channel.PublicKey
+	claim.Valid = &valid
+	claim.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(claim); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Redeems the latest claim authorised against a payment channel created through ChannelCreate. The claim is submitted
+// by the destination unless the channel's address is given, in which case no signature is needed. A claim of another
+// quantity can be redeemed by giving the quantity and signature. If close is true the channel is also closed; closing
+// from the address waits settleDelay seconds if the channel still holds XRP
+func ChannelClaim(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var balance string
+	var amount string
+	var signature string
+	var publicKey string
+	var flags uint32
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+
+	log.FluentfContext(consts.LOGINFO, c, "ChannelClaim called for '%s' by '%s'\n", mux.Vars(r)["channelId"], accessKey)
+
+	channel, ok := channelWithStatus(c, w, r, "open", "closing")
+	if !ok {
+		return nil
+	}
+
+	address := channel.Destination
+	if m["address"] != nil {
+		address = m["address"].(string)
+	}
+
+	if address != channel.Address && address != channel.Destination {
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return nil
+	}
+
+	quantity := channel.AuthorisedQuantity
+	claimSignature := channel.ClaimSignature
+	if m["quantity"] != nil || m["signature"] != nil {
+		if m["quantity"] == nil || m["signature"] == nil {
+			handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidClaim.Code, consts.RippleErrors.InvalidClaim.Description)
+
+			return nil
+		}
+
+		quantity = toDrops(uint64(m["quantity"].(float64))) * 100
+		claimSignature = strings.ToUpper(m["signature"].(string))
+
+		if valid, err := ripplecrypto.VerifyClaim(channel.PublicKey, channel.ChannelId, toDrops(quantity), claimSignature); err != nil || !valid || quantity > channel.Quantity {
+			handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidClaim.Code, consts.RippleErrors.InvalidClaim.Description)
+
+			return nil
+		}
+	}
+
+	closing := m["close"] != nil && m["close"].(bool)
+	if closing {
+		flags = rippleapi.TfClose
+	}
+
+	// The balance delivered to the destination can only increase
+	if quantity > channel.ClaimedQuantity {
+		balance = strconv.FormatUint(toDrops(quantity), 10)
+
+		// Only the destination needs the signature of the address to claim
+		if address == channel.Destination {
+			amount = balance
+			signature = claimSignature
+			publicKey = channel.PublicKey
+		}
+	} else if !closing {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.InvalidClaim.Code, errors.New(consts.RippleErrors.InvalidClaim.Description))
+
+		return nil
+	}
+
+	txSigner, ok := signerFromRequest(c, w, m, address, channel.ChannelId)
+	if !ok {
+		return nil
+	}
+
+	unlock := lockAddress(c, address)
+	txHash, errorCode, err := rippleapi.ClaimChannel(c, address, channel.ChannelId, balance, amount, signature, publicKey, flags, txSigner)
+	unlock()
+
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.ClaimChannel(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	if balance != "" {
+		database.UpdateChannelClaimedByChannelId(c, accessKey, channel.ChannelId, quantity, txHash)
+
+		channel.ClaimedQuantity = quantity
+		channel.ClaimTxId = txHash
+	}
+
+	if closing {
+		// Closing from the address leaves the channel open for the destination to claim for settleDelay seconds.
+		// The channel is marked closed once it is removed from the ledger
+		if address == channel.Address {
+			channel.Expiration = settleExpiry(channel.Expiration, channel.SettleDelay, time.Now().Unix())
+		}
+
+		database.UpdateChannelClosingByChannelId(c, accessKey, channel.ChannelId, txHash, channel.Expiration)
+
+		channel.Status = "closing"
+		channel.CloseTxId = txHash
+	}
+
+	writeChannel(c, w, http.StatusOK, channel)
+
+	return nil
+}
+
+// Returns the unix time the payment channel in the ledger expires, or zero if it doesn't expire
+func ChannelExpiry(channel rippleapi.Channel) int64 {
+	expiry := rippleapi.FromRippleTime(channel.CancelAfter)
+
+	if channel.Expiration != 0 && (expiry == 0 || rippleapi.FromRippleTime(channel.Expiration) < expiry) {
+		expiry = rippleapi.FromRippleTime(channel.Expiration)
+	}
+
+	return expiry
+}
+
+// Returns when a channel closed by its address at now expires. The destination has settleDelay seconds to redeem its
+// claims unless the channel expires sooner
+func settleExpiry(expiry int64, settleDelay uint32, now int64) int64 {
+	settle := now + int64(settleDelay)
+	if expiry == 0 || settle < expiry {
+		return settle
+	}
+
+	return expiry
+}
+
+// Closes a payment channel before it expires so the destination receives the quantity authorised by the claims
+// signed off ledger. Once a channel expires it closes without paying the destination what it hasn't yet redeemed.
+// The close is submitted from the channel's address with the custodial wallet the channel was created with, or
+// otherwise the remote signer
+func CloseChannel(c context.Context, accessKey string, walletId string, channel enulib.RippleChannel, ledgerChannel rippleapi.Channel) (int64, error) {
+	var balance string
+
+	ledgerBalance, _ := strconv.ParseUint(ledgerChannel.Balance, 10, 64)
+	if toDrops(channel.AuthorisedQuantity) > ledgerBalance {
+		balance = strconv.FormatUint(toDrops(channel.AuthorisedQuantity), 10)
+	}
+
+	txSigner, errorCode, err := signer.FromRequest(c, map[string]interface{}{"walletId": walletId}, channel.Address, channel.ChannelId)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Unable to sign the close of payment channel %s: %s", channel.ChannelId, err.Error())
+		return errorCode, err
+	}
+
+	unlock := lockAddress(c, channel.Address)
+	txHash, errorCode, err := rippleapi.ClaimChannel(c, channel.Address, channel.ChannelId, balance, "", "", "", rippleapi.TfClose, txSigner)
+	unlock()
+
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.ClaimChannel(): %s", err.Error())
+		return errorCode, err
+	}
+
+	if balance != "" {
+		database.UpdateChannelClaimedByChannelId(c, accessKey, channel.ChannelId, channel.AuthorisedQuantity, txHash)
+	}
+
+	database.UpdateChannelClosingByChannelId(c, accessKey, channel.ChannelId, txHash, settleExpiry(ChannelExpiry(ledgerChannel), channel.SettleDelay, time.Now().Unix()))
+
+	log.FluentfContext(consts.LOGINFO, c, "Requested payment channel %s to close. Tx: %s", channel.ChannelId, txHash)
+
+	return 0, nil
+}
@@ -0,0 +1,58 @@
+package ripplehandlers
+
+import (
+	"testing"
+
+	"github.com/vennd/enu/enulib"
+)
+
+func TestAuthoriseClaim(t *testing.T) {
+	var testData = []struct {
+		Status             string
+		Quantity           uint64
+		AuthorisedQuantity uint64
+		Increment          uint64
+		Expected           uint64
+		ExpectedError      bool
+		CaseDescription    string
+	}{
+		{"open", 100000000, 0, 1000, 1000, false, "First claim"},
+		{"open", 100000000, 1000, 2000, 3000, false, "Claims are cumulative"},
+		{"open", 100000000, 1000, 2050, 3000, false, "Claims are whole drops"},
+		{"open", 100000000, 99999000, 1000, 100000000, false, "Claim of the whole channel"},
+		{"open", 100000000, 99999000, 1100, 0, true, "Claim exceeds the channel"},
+		{"open", 100000000, 0, 99, 0, true, "Claim of less than a drop"},
+		{"closing", 100000000, 0, 1000, 0, true, "Channel is closing"},
+	}
+
+	for _, s := range testData {
+		channel := enulib.RippleChannel{Status: s.Status, Quantity: s.Quantity, AuthorisedQuantity: s.AuthorisedQuantity}
+
+		result, _, err := authoriseClaim(channel, s.Increment)
+		if result != s.Expected || (err != nil) != s.ExpectedError {
+			t.Errorf("%s. Expected: %d, %t. Got: %d, %v\n", s.CaseDescription, s.Expected, s.ExpectedError, result, err)
+		}
+	}
+}
+
+func TestSettleExpiry(t *testing.T) {
+	var now int64 = 1500000000
+
+	var testData = []struct {
+		Expiry          int64
+		SettleDelay     uint32
+		Expected        int64
+		CaseDescription string
+	}{
+		{0, 3600, now + 3600, "Channel which never expires"},
+		{now + 7200, 3600, now + 3600, "Channel expiring after the settle delay"},
+		{now + 60, 3600, now + 60, "Channel expiring before the settle delay"},
+	}
+
+	for _, s := range testData {
+		result := settleExpiry(s.Expiry, s.SettleDelay, now)
+		if result != s.Expected {
+			t.Errorf("%s. Expected: %d. Got: %d\n", s.CaseDescription, s.Expected, result)
+		}
+	}
+}
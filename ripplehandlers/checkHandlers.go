@@ -0,0 +1,437 @@
+package ripplehandlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/ripplecrypto"
+	"github.com/vennd/enu/signer"
+
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Returns true if the id is the 256 bit id of a check in the ledger rather than a checkId generated by the API
+func isLedgerCheckId(id string) bool {
+	decoded, err := hex.DecodeString(id)
+
+	return err == nil && len(decoded) == 32
+}
+
+// Returns the upper case hex of an invoice id
+func normaliseInvoiceId(invoiceId string) (string, int64, error) {
+	if !isLedgerCheckId(invoiceId) {
+		return "", consts.RippleErrors.InvalidInvoiceId.Code, errors.New(consts.RippleErrors.InvalidInvoiceId.Description)
+	}
+
+	return strings.ToUpper(invoiceId), 0, nil
+}
+
+func toLedgerCheck(c context.Context, check rippleapi.Check) enulib.RippleLedgerCheck {
+	amount := rippleapi.ToEnuAmount(c, check.SendMax)
+
+	return enulib.RippleLedgerCheck{
+		LedgerCheckId:  check.CheckId,
+		Address:        check.Account,
+		Destination:    check.Destination,
+		DestinationTag: check.DestinationTag,
+		Asset:          amount.Asset,
+		Issuer:         amount.Issuer,
+		Quantity:       amount.Quantity,
+		Expiration:     rippleapi.FromRippleTime(check.Expiration),
+		InvoiceId:      check.InvoiceID,
+		PreviousTxId:   check.PreviousTxnID,
+	}
+}
+
+// Creates a check from the address which the destination can cash for up to the quantity of the asset. The check
+// expires at the expiration, a unix time, if given. The check is tracked by the returned checkId while it is submitted
+// in the background.
+func CheckCreate(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var check enulib.RippleCheck
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	amount := offerAmountFromRequest(m)
+
+	check.CheckId = enulib.GenerateCheckId()
+	check.BlockchainId = consts.RippleBlockchainId
+	// Missing addresses are rejected as invalid below
+	check.Address, _ = m["address"].(string)
+	check.Destination, _ = m["destination"].(string)
+	check.Asset = amount.Asset
+	check.Issuer = amount.Issuer
+	check.Quantity = amount.Quantity
+
+	if m["destinationTag"] != nil {
+		tag := uint32(m["destinationTag"].(float64))
+		check.DestinationTag = &tag
+	}
+
+	if m["expiration"] != nil {
+		check.Expiration = int64(m["expiration"].(float64))
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "CheckCreate called for '%s' to '%s' by '%s'. Generated checkId: %s\n", check.Address, check.Destination, c.Value(consts.AccessKeyKey).(string), check.CheckId)
+
+	for _, address := range []string{check.Address, check.Destination} {
+		if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+			return nil
+		}
+	}
+
+	sendMax, errorCode, err := toAmount(amount)
+	if err != nil {
+		handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	if check.Expiration != 0 && check.Expiration <= time.Now().Unix() {
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidCheckExpiration.Code, consts.RippleErrors.InvalidCheckExpiration.Description)
+
+		return nil
+	}
+
+	if m["invoiceId"] != nil {
+		invoiceId, errorCode, err := normaliseInvoiceId(m["invoiceId"].(string))
+		if err != nil {
+			handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+			return nil
+		}
+		check.InvoiceId = invoiceId
+	}
+
+	txSigner, ok := signerFromRequest(c, w, m, check.Address, check.CheckId)
+	if !ok {
+		return nil
+	}
+
+	txFee, ok := currentFee(c, w)
+	if !ok {
+		return nil
+	}
+
+	check.TxFee = int64(txFee)
+	check.Status = "valid"
+	if err := database.InsertCheck(c, c.Value(consts.AccessKeyKey).(string), check); err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	// Return the checkId and unblock the client
+	check.RequestId = c.Value(consts.RequestIdKey).(string)
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(check); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	go delegatedCheckCreate(c, c.Value(consts.AccessKeyKey).(string), txSigner, check, sendMax)
+
+	return nil
+}
+
+func delegatedCheckCreate(c context.Context, accessKey string, txSigner signer.Signer, check enulib.RippleCheck, sendMax interface{}) (string, int64, error) {
+	unlock := lockAddress(c, check.Address)
+	defer unlock()
+
+	txHash, ledgerCheckId, errCode, err := rippleapi.CreateCheck(c, check.Address, check.Destination, sendMax, check.DestinationTag, rippleapi.ToRippleTime(check.Expiration), check.InvoiceId, txSigner)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.CreateCheck(): %s", err.Error())
+		database.UpdateCheckWithErrorByCheckId(c, accessKey, check.CheckId, txHash, errCode, err.Error())
+
+		return txHash, errCode, err
+	}
+
+	database.UpdateCheckCompleteByCheckId(c, accessKey, check.CheckId, txHash, ledgerCheckId)
+
+	log.FluentfContext(consts.LOGINFO, c, "Check %s created with ledger id %s. Complete.", check.CheckId, ledgerCheckId)
+
+	return txHash, 0, nil
+}
+
+// Returns the check created by the access key with the given checkId
+func GetCheck(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	checkId := mux.Vars(r)["checkId"]
+	if checkId == "" || len(checkId) < 16 {
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidCheckId.Code, consts.GenericErrors.InvalidCheckId.Description)
+
+		return nil
+	}
+
+	check := database.GetCheckByCheckId(c, c.Value(consts.AccessKeyKey).(string), checkId)
+	if check.Status == consts.NotFound {
+		handlers.ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidCheckId.Code, consts.GenericErrors.InvalidCheckId.Description)
+
+		return nil
+	}
+
+	check.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(check); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Returns the check given in the path as it is in the ledger, together with the check created through CheckCreate if
+// the access key created it. The path gives either the checkId returned by CheckCreate or the id of the check in the
+// ledger, so checks received from other senders can be cashed. Otherwise writes the error and returns false
+func openCheck(c context.Context, w http.ResponseWriter, r *http.Request) (enulib.RippleCheck, rippleapi.Check, bool) {
+	var ledgerCheck rippleapi.Check
+
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+	checkId := mux.Vars(r)["checkId"]
+
+	check := database.GetCheckByCheckId(c, accessKey, checkId)
+	if check.Status == consts.NotFound && isLedgerCheckId(checkId) {
+		checkId = strings.ToUpper(checkId)
+		check = database.GetCheckByLedgerCheckId(c, accessKey, checkId)
+		check.LedgerCheckId = checkId
+	} else if check.Status == consts.NotFound {
+		handlers.ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidCheckId.Code, consts.GenericErrors.InvalidCheckId.Description)
+
+		return check, ledgerCheck, false
+	}
+
+	// Only checks which have been created in the ledger have a ledger id to cash or cancel
+	if check.Status != consts.NotFound && check.Status != "complete" {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.CheckNotOpen.Code, errors.New(consts.RippleErrors.CheckNotOpen.Description))
+
+		return check, ledgerCheck, false
+	}
+
+	ledgerCheck, errorCode, err := rippleapi.GetCheck(c, check.LedgerCheckId)
+	if errorCode == consts.RippleErrors.CheckNotOpen.Code {
+		handlers.ReturnUnprocessableEntity(c, w, errorCode, err)
+
+		return check, ledgerCheck, false
+	} else if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.GetCheck(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return check, ledgerCheck, false
+	}
+
+	// Checks received from other senders are returned as they are in the ledger
+	if check.Status == consts.NotFound {
+		l := toLedgerCheck(c, ledgerCheck)
+
+		check = enulib.RippleCheck{LedgerCheckId: l.LedgerCheckId, BlockchainId: consts.RippleBlockchainId, Address: l.Address, Destination: l.Destination, DestinationTag: l.DestinationTag, Asset: l.Asset, Issuer: l.Issuer, Quantity: l.Quantity, Expiration: l.Expiration, InvoiceId: l.InvoiceId, BroadcastTxId: l.PreviousTxId}
+	}
+
+	return check, ledgerCheck, true
+}
+
+// Cashes a check into its destination, or the address if given. Either the exact quantity or, with deliverMin, as
+// much as possible but at least deliverMin is cashed. The asset is the asset of the check
+func CheckCash(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var amount interface{}
+	var deliverMin interface{}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+
+	log.FluentfContext(consts.LOGINFO, c, "CheckCash called for '%s' by '%s'\n", mux.Vars(r)["checkId"], accessKey)
+
+	// Exactly one of quantity and deliverMin
+	if (m["quantity"] != nil) == (m["deliverMin"] != nil) {
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidDocument.Code, consts.GenericErrors.InvalidDocument.Description)
+
+		return nil
+	}
+
+	check, ledgerCheck, ok := openCheck(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	address := ledgerCheck.Destination
+	if m["address"] != nil {
+		address = m["address"].(string)
+
+		if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+			return nil
+		}
+	}
+
+	cash := enulib.RippleOfferAmount{Asset: check.Asset, Issuer: check.Issuer}
+	if m["quantity"] != nil {
+		cash.Quantity = uint64(m["quantity"].(float64))
+	} else if m["deliverMin"] != nil {
+		cash.Quantity = uint64(m["deliverMin"].(float64))
+	}
+
+	cashAmount, errorCode, err := toAmount(cash)
+	if err != nil {
+		handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	if m["quantity"] != nil {
+		amount = cashAmount
+	} else {
+		deliverMin = cashAmount
+	}
+
+	txSigner, ok := signerFromRequest(c, w, m, address, check.LedgerCheckId)
+	if !ok {
+		return nil
+	}
+
+	unlock := lockAddress(c, address)
+	txHash, errorCode, err := rippleapi.CashCheck(c, address, check.LedgerCheckId, amount, deliverMin, txSigner)
+	unlock()
+
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.CashCheck(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	if check.CheckId != "" {
+		database.UpdateCheckCashedByCheckId(c, accessKey, check.CheckId, txHash)
+	}
+
+	check.Status = "cashed"
+	check.CashTxId = txHash
+	check.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(check); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Removes a check from the ledger. The cancel is submitted from the check's address unless another address, eg the
+// destination, is given
+func CheckCancel(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+
+	log.FluentfContext(consts.LOGINFO, c, "CheckCancel called for '%s' by '%s'\n", mux.Vars(r)["checkId"], accessKey)
+
+	check, ledgerCheck, ok := openCheck(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	address := ledgerCheck.Account
+	if m["address"] != nil {
+		address = m["address"].(string)
+
+		if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+			return nil
+		}
+	}
+
+	txSigner, ok := signerFromRequest(c, w, m, address, check.LedgerCheckId)
+	if !ok {
+		return nil
+	}
+
+	unlock := lockAddress(c, address)
+	txHash, errorCode, err := rippleapi.CancelCheck(c, address, check.LedgerCheckId, txSigner)
+	unlock()
+
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.CancelCheck(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	if check.CheckId != "" {
+		database.UpdateCheckCancelledByCheckId(c, accessKey, check.CheckId, txHash)
+	}
+
+	check.Status = "cancelled"
+	check.CancelTxId = txHash
+	check.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(check); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Lists the outstanding checks in the ledger sent by, or to, the address given by the address query parameter
+func GetChecks(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var result enulib.RippleLedgerChecks
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	address := r.URL.Query().Get("address")
+	if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return nil
+	}
+
+	checks, errorCode, err := rippleapi.GetAccountChecks(c, address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetAccountChecks(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	result.Address = address
+	result.Checks = make([]enulib.RippleLedgerCheck, 0)
+	for _, check := range checks {
+		result.Checks = append(result.Checks, toLedgerCheck(c, check))
+	}
+	result.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
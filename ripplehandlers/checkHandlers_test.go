@@ -0,0 +1,109 @@
+package ripplehandlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func checkRequestContext(requestType string) context.Context {
+	// The handlers log through Fluent which otherwise requires enuapi.json
+	log.InitWithoutConfig()
+
+	c := context.WithValue(context.Background(), consts.RequestIdKey, "requestId")
+	c = context.WithValue(c, consts.AccessKeyKey, "accessKey")
+	c = context.WithValue(c, consts.BlockchainIdKey, consts.RippleBlockchainId)
+
+	return context.WithValue(c, consts.RequestTypeKey, requestType)
+}
+
+func TestIsLedgerCheckId(t *testing.T) {
+	var testData = []struct {
+		Id              string
+		Expected        bool
+		CaseDescription string
+	}{
+		{"49647F0D748DC3FE26BDACBC57F251AADEFFF391403EC9BF87C97F67E9977FB0", true, "Ledger check id"},
+		{"49647f0d748dc3fe26bdacbc57f251aadefff391403ec9bf87c97f67e9977fb0", true, "Lower case ledger check id"},
+		{"3f2a6e4b1c9d8e7f0a1b2c3d4e5f6a7b", false, "Check id generated by the API"},
+		{"49647F0D748DC3FE26BDACBC57F251AADEFFF391403EC9BF87C97F67E9977FBZ", false, "Not hex"},
+		{"", false, "Empty"},
+	}
+
+	for _, s := range testData {
+		if result := isLedgerCheckId(s.Id); result != s.Expected {
+			t.Errorf("%s. Expected: %t, got: %t\n", s.CaseDescription, s.Expected, result)
+		}
+	}
+}
+
+func TestNormaliseInvoiceId(t *testing.T) {
+	invoiceId, _, err := normaliseInvoiceId("6f1dfd1d0fe8a32e40e1f2c05cf1c15545bab56b617f9c6c2d63a6b704bef59b")
+	if err != nil || invoiceId != "6F1DFD1D0FE8A32E40E1F2C05CF1C15545BAB56B617F9C6C2D63A6B704BEF59B" {
+		t.Errorf("Expected the invoice id in upper case, got: %s, %v\n", invoiceId, err)
+	}
+
+	for _, invalid := range []string{"", "not hex", "6F1DFD1D"} {
+		if _, _, err := normaliseInvoiceId(invalid); err == nil {
+			t.Errorf("Expected an error for: %s\n", invalid)
+		}
+	}
+}
+
+func TestCheckCreateWithoutDestination(t *testing.T) {
+	c := checkRequestContext("checkCreate")
+	m := map[string]interface{}{"address": "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", "asset": "XRP", "quantity": float64(1000000)}
+
+	if err := handlers.ValidateParameters(c, m); err == nil {
+		t.Errorf("Expected the request without a destination to fail validation\n")
+	}
+
+	// Reaching the handler anyway is a bad request, not a panic
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("POST", "/ripple/checks", nil)
+	CheckCreate(c, w, r, m)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected: %d, got: %d\n", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestCheckCashQuantityOrDeliverMin(t *testing.T) {
+	c := checkRequestContext("checkCash")
+
+	var testData = []struct {
+		Parameters      map[string]interface{}
+		ExpectError     bool
+		CaseDescription string
+	}{
+		{map[string]interface{}{"quantity": float64(100)}, false, "Quantity"},
+		{map[string]interface{}{"deliverMin": float64(100)}, false, "Deliver min"},
+		{map[string]interface{}{"quantity": float64(100), "deliverMin": float64(100)}, true, "Both quantity and deliver min"},
+		{map[string]interface{}{}, true, "Neither quantity nor deliver min"},
+	}
+
+	for _, s := range testData {
+		if err := handlers.ValidateParameters(c, s.Parameters); (err != nil) != s.ExpectError {
+			t.Errorf("%s. Expected error: %t, got: %v\n", s.CaseDescription, s.ExpectError, err)
+		}
+
+		if !s.ExpectError {
+			continue
+		}
+
+		// The handler rejects these before looking up the check
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/ripple/checks/checkId/cash", nil)
+		CheckCash(c, w, r, s.Parameters)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s. Expected: %d, got: %d\n", s.CaseDescription, http.StatusBadRequest, w.Code)
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package ripplehandlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/ripplecrypto"
+	"github.com/vennd/enu/signer"
+
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Checks that the escrow can be finished, either after finishAfter or with the condition, and that finishAfter is
+// before cancelAfter. Times given must be in the future
+func checkEscrowTimes(now int64, finishAfter int64, cancelAfter int64, condition string) (int64, error) {
+	if finishAfter == 0 && condition == "" {
+		return consts.RippleErrors.InvalidEscrowTimes.Code, errors.New(consts.RippleErrors.InvalidEscrowTimes.Description)
+	}
+
+	if (finishAfter != 0 && finishAfter <= now) || (cancelAfter != 0 && cancelAfter <= now) {
+		return consts.RippleErrors.InvalidEscrowTimes.Code, errors.New(consts.RippleErrors.InvalidEscrowTimes.Description)
+	}
+
+	if finishAfter != 0 && cancelAfter != 0 && finishAfter >= cancelAfter {
+		return consts.RippleErrors.InvalidEscrowTimes.Code, errors.New(consts.RippleErrors.InvalidEscrowTimes.Description)
+	}
+
+	return 0, nil
+}
+
+// Returns the upper case hex of a crypto-condition or fulfillment
+func normaliseCondition(condition string) (string, int64, error) {
+	if _, err := hex.DecodeString(condition); err != nil || condition == "" {
+		return "", consts.RippleErrors.InvalidCondition.Code, errors.New(consts.RippleErrors.InvalidCondition.Description)
+	}
+
+	return strings.ToUpper(condition), 0, nil
+}
+
+func toLedgerEscrow(escrow rippleapi.Escrow) enulib.RippleLedgerEscrow {
+	var quantity uint64
+
+	drops, err := strconv.ParseUint(escrow.Amount, 10, 64)
+	if err == nil {
+		quantity = drops * 100
+	}
+
+	return enulib.RippleLedgerEscrow{
+		Address:        escrow.Account,
+		Destination:    escrow.Destination,
+		DestinationTag: escrow.DestinationTag,
+		Quantity:       quantity,
+		FinishAfter:    rippleapi.FromRippleTime(escrow.FinishAfter),
+		CancelAfter:    rippleapi.FromRippleTime(escrow.CancelAfter),
+		Condition:      escrow.Condition,
+		PreviousTxId:   escrow.PreviousTxnID,
+	}
+}
+
+// Holds XRP from the address in escrow for the destination. The escrow can be finished after finishAfter, or when the
+// fulfillment of the condition is given, and cancelled after cancelAfter. Times are unix times. The escrow is tracked
+// by the returned escrowId while it is submitted in the background.
+func EscrowCreate(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var escrow enulib.RippleEscrow
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	escrow.EscrowId = enulib.GenerateEscrowId()
+	escrow.BlockchainId = consts.RippleBlockchainId
+	escrow.Address = m["address"].(string)
+	escrow.Destination = m["destination"].(string)
+	escrow.Quantity = uint64(m["quantity"].(float64))
+
+	if m["destinationTag"] != nil {
+		tag := uint32(m["destinationTag"].(float64))
+		escrow.DestinationTag = &tag
+	}
+
+	if m["finishAfter"] != nil {
+		escrow.FinishAfter = int64(m["finishAfter"].(float64))
+	}
+
+	if m["cancelAfter"] != nil {
+		escrow.CancelAfter = int64(m["cancelAfter"].(float64))
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "EscrowCreate called for '%s' to '%s' by '%s'. Generated escrowId: %s\n", escrow.Address, escrow.Destination, c.Value(consts.AccessKeyKey).(string), escrow.EscrowId)
+
+	if m["asset"] != nil && strings.ToUpper(m["asset"].(string)) != "XRP" {
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.EscrowNotXRP.Code, consts.RippleErrors.EscrowNotXRP.Description)
+
+		return nil
+	}
+
+	for _, address := range []string{escrow.Address, escrow.Destination} {
+		if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+			return nil
+		}
+	}
+
+	amount, err := toRippleAmount("XRP", escrow.Quantity)
+	if err != nil || amount == "0" {
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidAmount.Code, consts.RippleErrors.InvalidAmount.Description)
+
+		return nil
+	}
+
+	if m["condition"] != nil {
+		condition, errorCode, err := normaliseCondition(m["condition"].(string))
+		if err != nil {
+			handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+			return nil
+		}
+		escrow.Condition = condition
+	}
+
+	if errorCode, err := checkEscrowTimes(time.Now().Unix(), escrow.FinishAfter, escrow.CancelAfter, escrow.Condition); err != nil {
+		handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	txSigner, ok := signerFromRequest(c, w, m, escrow.Address, escrow.EscrowId)
+	if !ok {
+		return nil
+	}
+
+	txFee, ok := currentFee(c, w)
+	if !ok {
+		return nil
+	}
+
+	escrow.TxFee = int64(txFee)
+	escrow.Status = "valid"
+	if err := database.InsertEscrow(c, c.Value(consts.AccessKeyKey).(string), escrow); err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	// Return the escrowId and unblock the client
+	escrow.RequestId = c.Value(consts.RequestIdKey).(string)
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(escrow); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	go delegatedEscrowCreate(c, c.Value(consts.AccessKeyKey).(string), txSigner, escrow, amount)
+
+	return nil
+}
+
+func delegatedEscrowCreate(c context.Context, accessKey string, txSigner signer.Signer, escrow enulib.RippleEscrow, amount string) (string, int64, error) {
+	unlock := lockAddress(c, escrow.Address)
+	defer unlock()
+
+	txHash, offerSequence, errCode, err := rippleapi.CreateEscrow(c, escrow.Address, escrow.Destination, amount, escrow.DestinationTag, rippleapi.ToRippleTime(escrow.FinishAfter), rippleapi.ToRippleTime(escrow.CancelAfter), escrow.Condition, txSigner)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.CreateEscrow(): %s", err.Error())
+		database.UpdateEscrowWithErrorByEscrowId(c, accessKey, escrow.EscrowId, txHash, errCode, err.Error())
+
+		return txHash, errCode, err
+	}
+
+	database.UpdateEscrowCompleteByEscrowId(c, accessKey, escrow.EscrowId, txHash, offerSequence)
+
+	log.FluentfContext(consts.LOGINFO, c, "Escrow %s created with sequence %d. Complete.", escrow.EscrowId, offerSequence)
+
+	return txHash, 0, nil
+}
+
+// Returns the escrow created by the access key with the given escrowId
+func GetEscrow(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	escrowId := mux.Vars(r)["escrowId"]
+	if escrowId == "" || len(escrowId) < 16 {
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidEscrowId.Code, consts.GenericErrors.InvalidEscrowId.Description)
+
+		return nil
+	}
+
+	escrow := database.GetEscrowByEscrowId(c, c.Value(consts.AccessKeyKey).(string), escrowId)
+	if escrow.Status == consts.NotFound {
+		handlers.ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidEscrowId.Code, consts.GenericErrors.InvalidEscrowId.Description)
+
+		return nil
+	}
+
+	escrow.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(escrow); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Returns the escrow given by the escrowId in the path if it is held in the ledger. Otherwise writes the error and
+// returns false
+func openEscrow(c context.Context, w http.ResponseWriter, r *http.Request) (enulib.RippleEscrow, bool) {
+	escrowId := mux.Vars(r)["escrowId"]
+
+	escrow := database.GetEscrowByEscrowId(c, c.Value(consts.AccessKeyKey).(string), escrowId)
+	if escrow.Status == consts.NotFound {
+		handlers.ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidEscrowId.Code, consts.GenericErrors.InvalidEscrowId.Description)
+
+		return escrow, false
+	}
+
+	// Only escrows which have been created in the ledger have a sequence to finish or cancel
+	if escrow.Status != "complete" {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.EscrowNotOpen.Code, errors.New(consts.RippleErrors.EscrowNotOpen.Description))
+
+		return escrow, false
+	}
+
+	return escrow, true
+}
+
+// Releases the XRP held in an escrow created through EscrowCreate to its destination. The finish is submitted from the
+// escrow's address unless another address, eg the destination, is given. The fulfillment must be given if the escrow
+// was created with a condition
+func EscrowFinish(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var fulfillment string
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+
+	log.FluentfContext(consts.LOGINFO, c, "EscrowFinish called for '%s' by '%s'\n", mux.Vars(r)["escrowId"], accessKey)
+
+	escrow, ok := openEscrow(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	if escrow.FinishAfter != 0 && escrow.FinishAfter > time.Now().Unix() {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.EscrowNotReady.Code, errors.New(consts.RippleErrors.EscrowNotReady.Description))
+
+		return nil
+	}
+
+	if escrow.Condition != "" {
+		var errorCode int64
+		var err error
+
+		if m["fulfillment"] == nil {
+			handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidCondition.Code, consts.RippleErrors.InvalidCondition.Description)
+
+			return nil
+		}
+
+		fulfillment, errorCode, err = normaliseCondition(m["fulfillment"].(string))
+		if err != nil {
+			handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+			return nil
+		}
+	}
+
+	address := escrow.Address
+	if m["address"] != nil {
+		address = m["address"].(string)
+
+		if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+			return nil
+		}
+	}
+
+	txSigner, ok := signerFromRequest(c, w, m, address, escrow.EscrowId)
+	if !ok {
+		return nil
+	}
+
+	unlock := lockAddress(c, address)
+	txHash, errorCode, err := rippleapi.FinishEscrow(c, address, escrow.Address, escrow.OfferSequence, escrow.Condition, fulfillment, txSigner)
+	unlock()
+
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.FinishEscrow(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	database.UpdateEscrowFinishedByEscrowId(c, accessKey, escrow.EscrowId, txHash)
+
+	escrow.Status = "finished"
+	escrow.FinishTxId = txHash
+	escrow.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(escrow); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Returns the XRP held in an escrow created through EscrowCreate to its address once cancelAfter has passed
+func EscrowCancel(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+
+	log.FluentfContext(consts.LOGINFO, c, "EscrowCancel called for '%s' by '%s'\n", mux.Vars(r)["escrowId"], accessKey)
+
+	escrow, ok := openEscrow(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	// Escrows without a cancelAfter can never be cancelled
+	if escrow.CancelAfter == 0 || escrow.CancelAfter > time.Now().Unix() {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.EscrowNotReady.Code, errors.New(consts.RippleErrors.EscrowNotReady.Description))
+
+		return nil
+	}
+
+	txSigner, ok := signerFromRequest(c, w, m, escrow.Address, escrow.EscrowId)
+	if !ok {
+		return nil
+	}
+
+	unlock := lockAddress(c, escrow.Address)
+	txHash, errorCode, err := rippleapi.CancelEscrow(c, escrow.Address, escrow.Address, escrow.OfferSequence, txSigner)
+	unlock()
+
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.CancelEscrow(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	database.UpdateEscrowCancelledByEscrowId(c, accessKey, escrow.EscrowId, txHash)
+
+	escrow.Status = "cancelled"
+	escrow.CancelTxId = txHash
+	escrow.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(escrow); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Lists the escrows in the ledger held by, or for, the address given by the address query parameter
+func GetEscrows(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var result enulib.RippleLedgerEscrows
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	address := r.URL.Query().Get("address")
+	if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return nil
+	}
+
+	escrows, errorCode, err := rippleapi.GetAccountEscrows(c, address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetAccountEscrows(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	result.Address = address
+	result.Escrows = make([]enulib.RippleLedgerEscrow, 0)
+	for _, escrow := range escrows {
+		result.Escrows = append(result.Escrows, toLedgerEscrow(escrow))
+	}
+	result.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package ripplehandlers
+
+import (
+	"testing"
+)
+
+func TestCheckEscrowTimes(t *testing.T) {
+	var now int64 = 1500000000
+
+	var testData = []struct {
+		FinishAfter     int64
+		CancelAfter     int64
+		Condition       string
+		ExpectedError   bool
+		CaseDescription string
+	}{
+		{now + 60, 0, "", false, "Time locked"},
+		{now + 60, now + 120, "", false, "Time locked with a cancel time"},
+		{0, now + 120, "A0258020", false, "Condition locked with a cancel time"},
+		{0, now + 120, "", true, "Can never be finished"},
+		{now - 60, 0, "", true, "Finish time in the past"},
+		{now + 120, now + 60, "", true, "Cancelled before it can be finished"},
+		{now + 60, now + 60, "", true, "Finish and cancel at the same time"},
+	}
+
+	for _, s := range testData {
+		_, err := checkEscrowTimes(now, s.FinishAfter, s.CancelAfter, s.Condition)
+		if (err != nil) != s.ExpectedError {
+			t.Errorf("%s. Expected error: %t, got: %v\n", s.CaseDescription, s.ExpectedError, err)
+		}
+	}
+}
+
+func TestNormaliseCondition(t *testing.T) {
+	condition, _, err := normaliseCondition("a0258020e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855810100")
+	if err != nil || condition != "A0258020E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855810100" {
+		t.Errorf("Expected the condition in upper case, got: %s, %v\n", condition, err)
+	}
+
+	for _, invalid := range []string{"", "not hex", "A02"} {
+		if _, _, err := normaliseCondition(invalid); err == nil {
+			t.Errorf("Expected an error for: %s\n", invalid)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package ripplehandlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/keystore"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/ripplecrypto"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Signs the message with the key of the account as proof of ownership of the account. The key is derived from the
+// passphrase or the passphrase of the custodial wallet given by walletId. The public key is returned as it is needed
+// to verify a Ripple signature.
+func SignMessage(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var signedMessage enulib.SignedMessage
+	var passphrase string
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	signedMessage.RequestId = requestId
+
+	signedMessage.BlockchainId = consts.RippleBlockchainId
+	signedMessage.Address = m["address"].(string)
+	signedMessage.Message = m["message"].(string)
+
+	log.FluentfContext(consts.LOGINFO, c, "SignMessage: received request address: %s from accessKey: %s\n", signedMessage.Address, c.Value(consts.AccessKeyKey).(string))
+
+	if m["passphrase"] != nil {
+		passphrase = m["passphrase"].(string)
+	} else {
+		p, errorCode, err := keystore.GetPassphrase(c, c.Value(consts.AccessKeyKey).(string), m["walletId"].(string), c.Value(consts.RequestTypeKey).(string), signedMessage.Address, "")
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in keystore.GetPassphrase(): %s", err.Error())
+			handlers.ReturnKeystoreError(c, w, errorCode, err)
+
+			return nil
+		}
+		passphrase = p
+	}
+
+	signature, publicKey, address, err := ripplecrypto.SignMessage(ripplecrypto.PassphraseToSecret(c, passphrase), signedMessage.Message)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in ripplecrypto.SignMessage(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.SigningError.Code, consts.RippleErrors.SigningError.Description)
+
+		return nil
+	}
+
+	// The passphrase must be for the account given
+	if address != signedMessage.Address {
+		log.FluentfContext(consts.LOGERROR, c, "The passphrase is for %s not %s", address, signedMessage.Address)
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.SigningError.Code, consts.RippleErrors.SigningError.Description)
+
+		return nil
+	}
+
+	signedMessage.Signature = signature
+	signedMessage.PublicKey = publicKey
+
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(signedMessage); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Verifies the signature of the message was made by the public key and the public key is the key of the account
+func VerifyMessage(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var verification enulib.MessageVerification
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	verification.RequestId = requestId
+
+	verification.BlockchainId = consts.RippleBlockchainId
+	verification.Address = m["address"].(string)
+	verification.Message = m["message"].(string)
+	verification.Signature = m["signature"].(string)
+	verification.PublicKey = m["publicKey"].(string)
+
+	log.FluentfContext(consts.LOGINFO, c, "VerifyMessage: received request address: %s from accessKey: %s\n", verification.Address, c.Value(consts.AccessKeyKey).(string))
+
+	valid, err := ripplecrypto.VerifyMessage(verification.Address, verification.Message, verification.Signature, verification.PublicKey)
+	if err != nil {
+		// A malformed signature or public key is reported as invalid rather than as an error
+		log.FluentfContext(consts.LOGINFO, c, "Unable to verify signature: %s", err.Error())
+	}
+	verification.Valid = valid
+
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(verification); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,631 @@
+package ripplehandlers
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/ripplecrypto"
+	"github.com/vennd/enu/signer"
+
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+const (
+	MultisigProposalTypeSend          = "send"
+	MultisigProposalTypeDisableMaster = "disableMaster"
+
+	MultisigStatusAwaitingSignatures = "awaiting signatures"
+	MultisigStatusReady              = "ready"
+)
+
+// Checks the signer list can be set on the account. rippled requires between 1 and 8 distinct signers, none of which
+// may be the account itself, with weights which are able to reach the quorum
+func validateSignerList(account string, quorum uint32, entries []rippleapi.SignerEntry) error {
+	var total uint32
+
+	if quorum == 0 || len(entries) == 0 || len(entries) > rippleapi.MaxSigners {
+		return errors.New(consts.RippleErrors.InvalidSignerList.Description)
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Account == account || seen[entry.Account] || entry.SignerWeight == 0 {
+			return errors.New(consts.RippleErrors.InvalidSignerList.Description)
+		}
+
+		if _, err := ripplecrypto.DecodeAccountId(entry.Account); err != nil {
+			return errors.New(consts.RippleErrors.InvalidSignerList.Description)
+		}
+
+		seen[entry.Account] = true
+		total += uint32(entry.SignerWeight)
+	}
+
+	if total < quorum {
+		return errors.New(consts.RippleErrors.InvalidSignerList.Description)
+	}
+
+	return nil
+}
+
+// Returns the sum of the weights of the signers who have signed
+func signedWeight(cosigners []enulib.MultisigCosigner) int64 {
+	var result int64
+
+	for _, cosigner := range cosigners {
+		if cosigner.Signed {
+			result += int64(cosigner.Weight)
+		}
+	}
+
+	return result
+}
+
+// Decodes the unsigned tx of the proposal keeping the numbers as they were composed
+func proposalTx(proposal enulib.MultisigProposal) (map[string]interface{}, error) {
+	var result map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(proposal.UnsignedTx)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Sets the signer list of the address so transactions from it may be multisigned. The signer list is set with the
+// passphrase, the custodial wallet given by walletId or the remote signer of the address. If disableMaster is given the
+// master key of the address is disabled once the signer list is validated, after which only the signers can move its
+// funds.
+func MultisigWalletCreate(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var wallet enulib.MultisigWallet
+	var entries []rippleapi.SignerEntry
+	var disableMaster bool
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	wallet.RequestId = requestId
+
+	address := m["address"].(string)
+	quorum := uint32(m["requiredSignatures"].(float64))
+	if m["disableMaster"] != nil {
+		disableMaster = m["disableMaster"].(bool)
+	}
+
+	for _, cosigner := range m["cosigners"].([]interface{}) {
+		entry := rippleapi.SignerEntry{SignerWeight: 1}
+
+		cosignerMap := cosigner.(map[string]interface{})
+		if cosignerMap["address"] != nil {
+			entry.Account = cosignerMap["address"].(string)
+		}
+		if cosignerMap["weight"] != nil {
+			entry.SignerWeight = uint16(cosignerMap["weight"].(float64))
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return nil
+	}
+
+	if err := validateSignerList(address, quorum, entries); err != nil {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.InvalidSignerList.Code, err)
+
+		return nil
+	}
+
+	txSigner, ok := signerFromRequest(c, w, m, address, "")
+	if !ok {
+		return nil
+	}
+
+	unlock := lockAddress(c, address)
+	defer unlock()
+
+	txHash, errorCode, err := rippleapi.SetSignerList(c, address, quorum, entries, txSigner)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.SetSignerList(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	// The master key can only be disabled once the signer list is in the ledger
+	if disableMaster {
+		if _, errorCode, err := rippleapi.WaitForValidation(c, txHash, address); err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Signer list %s was not validated: %s", txHash, err.Error())
+			handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+			return nil
+		}
+
+		if _, errorCode, err := rippleapi.SubmitAccountSet(c, address, rippleapi.AccountSet{SetFlag: rippleapi.AsfDisableMaster}, txSigner); err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.SubmitAccountSet(): %s", err.Error())
+			handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+			return nil
+		}
+	}
+
+	wallet.WalletId = enulib.GenerateWalletId()
+	wallet.BlockchainId = consts.RippleBlockchainId
+	wallet.Address = address
+	wallet.RequiredSignatures = int64(quorum)
+	wallet.Status = "valid"
+	for _, entry := range entries {
+		wallet.PublicKeys = append(wallet.PublicKeys, entry.Account)
+		wallet.SignerWeights = append(wallet.SignerWeights, entry.SignerWeight)
+	}
+
+	err = database.InsertMultisigWallet(c, accessKey, wallet.WalletId, wallet.BlockchainId, wallet.Address, "", wallet.RequiredSignatures, wallet.PublicKeys, wallet.SignerWeights)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Set signer list of %s with quorum %d and %d signers. Multisig wallet: %s, txid: %s", address, quorum, len(entries), wallet.WalletId, txHash)
+
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(wallet); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Returns the multisig wallet if it was created by the access key for Ripple
+func rippleMultisigWallet(c context.Context, w http.ResponseWriter, walletId string) (enulib.MultisigWallet, bool) {
+	wallet, err := database.GetMultisigWalletByWalletId(c, c.Value(consts.AccessKeyKey).(string), walletId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return wallet, false
+	}
+
+	if wallet.Status == consts.NotFound || wallet.BlockchainId != consts.RippleBlockchainId {
+		handlers.ReturnNotFound(c, w)
+
+		return wallet, false
+	}
+
+	return wallet, true
+}
+
+// Returns the multisig proposal if it was created by the access key for Ripple
+func rippleMultisigProposal(c context.Context, w http.ResponseWriter, proposalId string) (enulib.MultisigProposal, bool) {
+	proposal, err := database.GetMultisigProposalByProposalId(c, c.Value(consts.AccessKeyKey).(string), proposalId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return proposal, false
+	}
+
+	if proposal.Status == consts.NotFound {
+		handlers.ReturnNotFound(c, w)
+
+		return proposal, false
+	}
+
+	_, ok := rippleMultisigWallet(c, w, proposal.WalletId)
+
+	return proposal, ok
+}
+
+func writeMultisigProposal(c context.Context, w http.ResponseWriter, status int, proposal enulib.MultisigProposal) {
+	proposal.RequestId = c.Value(consts.RequestIdKey).(string)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(proposal); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+	}
+}
+
+func GetMultisigWallet(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	vars := mux.Vars(r)
+	wallet, ok := rippleMultisigWallet(c, w, vars["walletId"])
+	if !ok {
+		return nil
+	}
+
+	wallet.RequestId = requestId
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(wallet); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Composes a payment, or the AccountSet which disables the master key, from the address of the multisig wallet. The
+// unsigned tx and the hash each signer signs are returned so signers are able to sign either on the server or offline
+// with sign_for. The tx uses the next sequence of the address so any other tx sent from the address before the
+// proposal is submitted invalidates it.
+func MultisigProposalCreate(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var proposal enulib.MultisigProposal
+	var unsignedTx interface{}
+	var errorCode int64
+	var err error
+
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	walletId := m["walletId"].(string)
+	proposal.ProposalType = m["proposalType"].(string)
+	if m["asset"] != nil {
+		proposal.Asset = m["asset"].(string)
+	}
+	if m["quantity"] != nil {
+		proposal.Quantity = uint64(m["quantity"].(float64))
+	}
+	if m["destinationAddress"] != nil {
+		proposal.DestinationAddress = m["destinationAddress"].(string)
+	}
+
+	wallet, ok := rippleMultisigWallet(c, w, walletId)
+	if !ok {
+		return nil
+	}
+
+	switch proposal.ProposalType {
+	case MultisigProposalTypeSend:
+		var issuer string
+		if m["issuer"] != nil {
+			issuer = m["issuer"].(string)
+		}
+
+		if _, err := ripplecrypto.DecodeAccountId(proposal.DestinationAddress); err != nil {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+			return nil
+		}
+
+		if proposal.Asset == "" || proposal.Quantity == 0 {
+			handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidAmount.Code, consts.RippleErrors.InvalidAmount.Description)
+
+			return nil
+		}
+
+		amount, err := toRippleAmount(proposal.Asset, proposal.Quantity)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in toRippleAmount(): %s", err.Error())
+			handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidAmount.Code, consts.RippleErrors.InvalidAmount.Description)
+
+			return nil
+		}
+
+		currency, err := rippleapi.ToCurrency(proposal.Asset)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.ToCurrency(): %s", err.Error())
+			handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidCurrency.Code, consts.RippleErrors.InvalidCurrency.Description)
+
+			return nil
+		}
+
+		options, errorCode, err := paymentOptionsFromRequest(m).toRipple()
+		if err != nil {
+			handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+			return nil
+		}
+
+		unsignedTx, errorCode, err = rippleapi.ComposePayment(c, wallet.Address, proposal.DestinationAddress, amount, currency, issuer, options)
+	case MultisigProposalTypeDisableMaster:
+		unsignedTx, errorCode, err = rippleapi.ComposeAccountSet(c, wallet.Address, rippleapi.AccountSet{SetFlag: rippleapi.AsfDisableMaster})
+	}
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error composing %s for multisig wallet %s: %s", proposal.ProposalType, walletId, err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	txMap, err := rippleapi.PrepareMultisigned(unsignedTx, len(wallet.PublicKeys))
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.PrepareMultisigned(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	// Each signer signs a different hash
+	for _, signerAccount := range wallet.PublicKeys {
+		hash, err := ripplecrypto.MultiSigningHash(txMap, signerAccount)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in ripplecrypto.MultiSigningHash(): %s", err.Error())
+			handlers.ReturnServerError(c, w)
+
+			return nil
+		}
+
+		proposal.SignatureHashes = append(proposal.SignatureHashes, strings.ToUpper(hex.EncodeToString(hash)))
+	}
+
+	unsignedTxJson, err := json.Marshal(txMap)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Marshal(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	proposal.ProposalId = enulib.GenerateProposalId()
+	proposal.WalletId = walletId
+	proposal.SourceAddress = wallet.Address
+	proposal.UnsignedTx = string(unsignedTxJson)
+	proposal.RequiredSignatures = wallet.RequiredSignatures
+	proposal.Status = MultisigStatusAwaitingSignatures
+
+	err = database.InsertMultisigProposal(c, accessKey, proposal)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	for i, signerAccount := range wallet.PublicKeys {
+		proposal.Cosigners = append(proposal.Cosigners, enulib.MultisigCosigner{PublicKey: signerAccount, Weight: wallet.SignerWeights[i]})
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Created multisig proposal: %s for wallet: %s", proposal.ProposalId, walletId)
+
+	writeMultisigProposal(c, w, http.StatusCreated, proposal)
+
+	return nil
+}
+
+func GetMultisigProposal(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	vars := mux.Vars(r)
+	proposal, ok := rippleMultisigProposal(c, w, vars["proposalId"])
+	if !ok {
+		return nil
+	}
+
+	writeMultisigProposal(c, w, http.StatusOK, proposal)
+
+	return nil
+}
+
+// Adds the signature of one signer account to the proposal. The signer either gives the passphrase or walletId of the
+// signer account so the server signs, or the signature and public key made offline, for instance with sign_for. Once
+// the weights of the signers reach the quorum the multisigned tx is submitted.
+func MultisigProposalSign(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var multiSigner ripplecrypto.MultiSigner
+
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	vars := mux.Vars(r)
+	proposalId := vars["proposalId"]
+	signerAccount := m["address"].(string)
+
+	// Signatures of the same proposal are added one at a time so the quorum is only reached once
+	unlock := lockAddress(c, proposalId)
+	defer unlock()
+
+	proposal, ok := rippleMultisigProposal(c, w, proposalId)
+	if !ok {
+		return nil
+	}
+
+	if proposal.Status != MultisigStatusAwaitingSignatures {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.ProposalNotPending.Code, errors.New(consts.RippleErrors.ProposalNotPending.Description))
+
+		return nil
+	}
+
+	var isCosigner bool
+	for _, cosigner := range proposal.Cosigners {
+		if cosigner.PublicKey == signerAccount {
+			isCosigner = true
+
+			if cosigner.Signed {
+				handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.AlreadySigned.Code, errors.New(consts.RippleErrors.AlreadySigned.Description))
+
+				return nil
+			}
+		}
+	}
+
+	if isCosigner == false {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.UnknownSigner.Code, errors.New(consts.RippleErrors.UnknownSigner.Description))
+
+		return nil
+	}
+
+	txMap, err := proposalTx(proposal)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Unable to decode the unsigned tx of proposal %s: %s", proposalId, err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	if m["signature"] != nil && m["publicKey"] != nil {
+		multiSigner = ripplecrypto.MultiSigner{Account: signerAccount, SigningPubKey: strings.ToUpper(m["publicKey"].(string)), TxnSignature: strings.ToUpper(m["signature"].(string))}
+	} else {
+		txSigner, ok := signerFromRequest(c, w, m, signerAccount, proposalId)
+		if !ok {
+			return nil
+		}
+
+		txMultiSigner, ok := txSigner.(signer.MultiSigner)
+		if !ok {
+			handlers.ReturnBadRequest(c, w, consts.RippleErrors.SignerCannotMultiSign.Code, consts.RippleErrors.SignerCannotMultiSign.Description)
+
+			return nil
+		}
+
+		var errorCode int64
+		var err error
+
+		multiSigner, errorCode, err = txMultiSigner.MultiSignRippleTransaction(c, signerAccount, txMap)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in MultiSignRippleTransaction(): %s", err.Error())
+			handlers.ReturnUnprocessableEntity(c, w, errorCode, err)
+
+			return nil
+		}
+	}
+
+	// The key of the signature can only be checked against the signer account when the tx is applied to the ledger
+	if valid, err := ripplecrypto.VerifyMultiSignature(txMap, multiSigner); err != nil || !valid {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.InvalidMultiSignature.Code, errors.New(consts.RippleErrors.InvalidMultiSignature.Description))
+
+		return nil
+	}
+
+	err = database.InsertMultisigSignatures(c, proposalId, signerAccount, []string{multiSigner.SigningPubKey, multiSigner.TxnSignature})
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Signer %s signed multisig proposal: %s", signerAccount, proposalId)
+
+	proposal, err = database.GetMultisigProposalByProposalId(c, accessKey, proposalId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	if signedWeight(proposal.Cosigners) >= proposal.RequiredSignatures {
+		if err := database.UpdateMultisigProposalStatusByProposalId(c, accessKey, proposalId, MultisigStatusReady); err != nil {
+			handlers.ReturnServerError(c, w)
+
+			return nil
+		}
+		proposal.Status = MultisigStatusReady
+
+		// If the submission fails the proposal stays ready so it can be finalised again
+		if errorCode, err := submitMultisigProposal(c, accessKey, proposal); err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Unable to submit multisig proposal %s once the quorum was reached. Error code: %d, %s", proposalId, errorCode, err.Error())
+		}
+
+		proposal, err = database.GetMultisigProposalByProposalId(c, accessKey, proposalId)
+		if err != nil {
+			handlers.ReturnServerError(c, w)
+
+			return nil
+		}
+	}
+
+	writeMultisigProposal(c, w, http.StatusOK, proposal)
+
+	return nil
+}
+
+// Combines the signatures collected for the proposal into the multisigned tx and submits it
+func submitMultisigProposal(c context.Context, accessKey string, proposal enulib.MultisigProposal) (int64, error) {
+	var signers []ripplecrypto.MultiSigner
+
+	txMap, err := proposalTx(proposal)
+	if err != nil {
+		return consts.RippleErrors.SigningError.Code, err
+	}
+
+	signatures, err := database.GetMultisigSignaturesByProposalId(c, proposal.ProposalId)
+	if err != nil {
+		return consts.GenericErrors.GeneralError.Code, err
+	}
+
+	for signerAccount, signature := range signatures {
+		if len(signature) != 2 {
+			return consts.RippleErrors.SigningError.Code, errors.New(consts.RippleErrors.SigningError.Description)
+		}
+
+		signers = append(signers, ripplecrypto.MultiSigner{Account: signerAccount, SigningPubKey: signature[0], TxnSignature: signature[1]})
+	}
+
+	unlock := lockAddress(c, proposal.SourceAddress)
+	txHash, errorCode, err := rippleapi.SubmitMultisigned(c, txMap, signers)
+	unlock()
+
+	if err != nil {
+		return errorCode, err
+	}
+
+	database.UpdateMultisigProposalCompleteByProposalId(c, accessKey, proposal.ProposalId, proposal.UnsignedTx, txHash)
+	log.FluentfContext(consts.LOGINFO, c, "Submitted multisig proposal: %s, txid: %s", proposal.ProposalId, txHash)
+
+	return 0, nil
+}
+
+// Submits the multisigned tx of a proposal which has reached its quorum but was not submitted when the last signature
+// was added
+func MultisigProposalFinalise(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	vars := mux.Vars(r)
+	proposalId := vars["proposalId"]
+
+	unlock := lockAddress(c, proposalId)
+	defer unlock()
+
+	proposal, ok := rippleMultisigProposal(c, w, proposalId)
+	if !ok {
+		return nil
+	}
+
+	if proposal.Status == MultisigStatusAwaitingSignatures {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.QuorumNotReached.Code, errors.New(consts.RippleErrors.QuorumNotReached.Description))
+
+		return nil
+	}
+
+	if proposal.Status != MultisigStatusReady {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.ProposalNotPending.Code, errors.New(consts.RippleErrors.ProposalNotPending.Description))
+
+		return nil
+	}
+
+	if errorCode, err := submitMultisigProposal(c, accessKey, proposal); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in submitMultisigProposal(): %s", err.Error())
+		database.UpdateMultisigProposalWithErrorByProposalId(c, accessKey, proposalId, errorCode, err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	proposal, err := database.GetMultisigProposalByProposalId(c, accessKey, proposalId)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	writeMultisigProposal(c, w, http.StatusOK, proposal)
+
+	return nil
+}
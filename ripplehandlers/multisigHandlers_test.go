@@ -0,0 +1,50 @@
+package ripplehandlers
+
+import (
+	"testing"
+
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/rippleapi"
+)
+
+func TestValidateSignerList(t *testing.T) {
+	var account = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	var signer1 = "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"
+	var signer2 = "rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn"
+
+	var testData = []struct {
+		Quorum          uint32
+		Entries         []rippleapi.SignerEntry
+		ExpectedError   bool
+		CaseDescription string
+	}{
+		{2, []rippleapi.SignerEntry{{Account: signer1, SignerWeight: 1}, {Account: signer2, SignerWeight: 1}}, false, "2 of 2"},
+		{3, []rippleapi.SignerEntry{{Account: signer1, SignerWeight: 2}, {Account: signer2, SignerWeight: 1}}, false, "Weighted signers"},
+		{3, []rippleapi.SignerEntry{{Account: signer1, SignerWeight: 1}, {Account: signer2, SignerWeight: 1}}, true, "Quorum can't be reached"},
+		{0, []rippleapi.SignerEntry{{Account: signer1, SignerWeight: 1}}, true, "Zero quorum"},
+		{1, nil, true, "No signers"},
+		{1, []rippleapi.SignerEntry{{Account: signer1, SignerWeight: 1}, {Account: signer1, SignerWeight: 1}}, true, "Duplicate signer"},
+		{1, []rippleapi.SignerEntry{{Account: account, SignerWeight: 1}}, true, "Account signs for itself"},
+		{1, []rippleapi.SignerEntry{{Account: signer1, SignerWeight: 0}}, true, "Zero weight"},
+		{1, []rippleapi.SignerEntry{{Account: "rNotAnAddress", SignerWeight: 1}}, true, "Invalid signer address"},
+	}
+
+	for _, s := range testData {
+		err := validateSignerList(account, s.Quorum, s.Entries)
+		if (err != nil) != s.ExpectedError {
+			t.Errorf("%s. Expected error: %t. Got: %v\n", s.CaseDescription, s.ExpectedError, err)
+		}
+	}
+}
+
+func TestSignedWeight(t *testing.T) {
+	cosigners := []enulib.MultisigCosigner{
+		{PublicKey: "a", Weight: 2, Signed: true},
+		{PublicKey: "b", Weight: 1, Signed: false},
+		{PublicKey: "c", Weight: 3, Signed: true},
+	}
+
+	if result := signedWeight(cosigners); result != 5 {
+		t.Errorf("Expected: 5. Got: %d\n", result)
+	}
+}
@@ -0,0 +1,419 @@
+package ripplehandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/ripplecrypto"
+	"github.com/vennd/enu/signer"
+
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Locks the address so only one transaction is created from it at a time. Returns the function which unlocks it
+func lockAddress(c context.Context, address string) func() {
+	ripple_Mutexes.Lock()
+	if ripple_Mutexes.m[address] == nil {
+		log.FluentfContext(consts.LOGINFO, c, "Created new entry in map for %s", address)
+		ripple_Mutexes.m[address] = new(sync.Mutex)
+	}
+	mutex := ripple_Mutexes.m[address]
+	ripple_Mutexes.Unlock()
+
+	mutex.Lock()
+	log.FluentfContext(consts.LOGINFO, c, "Locked: %s\n", address)
+
+	return mutex.Unlock
+}
+
+// Reads the asset, issuer and quantity of one side of an offer from the request
+func offerAmountFromRequest(m interface{}) enulib.RippleOfferAmount {
+	var amount enulib.RippleOfferAmount
+
+	side := m.(map[string]interface{})
+	amount.Asset = side["asset"].(string)
+	amount.Quantity = uint64(side["quantity"].(float64))
+
+	if side["issuer"] != nil {
+		amount.Issuer = side["issuer"].(string)
+	}
+
+	// XRP has no issuer
+	if strings.ToUpper(amount.Asset) == "XRP" {
+		amount.Asset = "XRP"
+		amount.Issuer = ""
+	}
+
+	return amount
+}
+
+// Checks the asset and issuer of an amount and returns the Ripple currency of the asset
+func assetCurrency(amount enulib.RippleOfferAmount) (string, int64, error) {
+	if amount.Asset != "XRP" {
+		if amount.Issuer == "" {
+			return "", consts.RippleErrors.IssuerMustBeGiven.Code, errors.New(consts.RippleErrors.IssuerMustBeGiven.Description)
+		}
+
+		if _, err := ripplecrypto.DecodeAccountId(amount.Issuer); err != nil {
+			return "", consts.GenericErrors.InvalidAddress.Code, errors.New(consts.GenericErrors.InvalidAddress.Description)
+		}
+	}
+
+	currency, err := rippleapi.ToCurrency(amount.Asset)
+	if err != nil {
+		return "", consts.RippleErrors.InvalidCurrency.Code, errors.New(consts.RippleErrors.InvalidCurrency.Description)
+	}
+
+	return currency, 0, nil
+}
+
+// Converts the quantity in the Enu API denomination to the amount given in a transaction, eg the TakerGets of an
+// OfferCreate or the SendMax of a payment
+func toAmount(amount enulib.RippleOfferAmount) (interface{}, int64, error) {
+	currency, errorCode, err := assetCurrency(amount)
+	if err != nil {
+		return nil, errorCode, err
+	}
+
+	value, err := toRippleAmount(amount.Asset, amount.Quantity)
+	if err != nil || value == "0" {
+		return nil, consts.RippleErrors.InvalidAmount.Code, errors.New(consts.RippleErrors.InvalidAmount.Description)
+	}
+
+	return rippleapi.NewAmount(currency, amount.Issuer, value), 0, nil
+}
+
+func setOfferFlags(offer *enulib.RippleOffer) {
+	offer.Passive = offer.Flags&rippleapi.TfPassive != 0
+	offer.Sell = offer.Flags&rippleapi.TfSell != 0
+	offer.ImmediateOrCancel = offer.Flags&rippleapi.TfImmediateOrCancel != 0
+	offer.FillOrKill = offer.Flags&rippleapi.TfFillOrKill != 0
+}
+
+func toLedgerOffer(c context.Context, offer rippleapi.Offer) enulib.RippleLedgerOffer {
+	return enulib.RippleLedgerOffer{
+		Address:       offer.Account,
+		OfferSequence: offer.Sequence,
+		TakerGets:     rippleapi.ToEnuAmount(c, offer.TakerGets),
+		TakerPays:     rippleapi.ToEnuAmount(c, offer.TakerPays),
+		Passive:       offer.Flags&rippleapi.LsfPassive != 0,
+		Sell:          offer.Flags&rippleapi.LsfSell != 0,
+		Quality:       offer.Quality,
+		OwnerFunds:    offer.OwnerFunds,
+	}
+}
+
+// Places an offer in the decentralised exchange from the address. The address gives takerGets in exchange for
+// takerPays. The offer is tracked by the returned offerId while it is submitted in the background.
+func OfferCreate(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var offer enulib.RippleOffer
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	offer.OfferId = enulib.GenerateOfferId()
+	offer.BlockchainId = consts.RippleBlockchainId
+	offer.Address = m["address"].(string)
+	offer.TakerGets = offerAmountFromRequest(m["takerGets"])
+	offer.TakerPays = offerAmountFromRequest(m["takerPays"])
+
+	log.FluentfContext(consts.LOGINFO, c, "OfferCreate called for '%s' by '%s'. Generated offerId: %s\n", offer.Address, c.Value(consts.AccessKeyKey).(string), offer.OfferId)
+
+	for _, flag := range []struct {
+		name string
+		flag uint32
+	}{{"passive", rippleapi.TfPassive}, {"sell", rippleapi.TfSell}, {"immediateOrCancel", rippleapi.TfImmediateOrCancel}, {"fillOrKill", rippleapi.TfFillOrKill}} {
+		if m[flag.name] != nil && m[flag.name].(bool) {
+			offer.Flags |= flag.flag
+		}
+	}
+	setOfferFlags(&offer)
+
+	if offer.ImmediateOrCancel && offer.FillOrKill {
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidOfferFlags.Code, consts.RippleErrors.InvalidOfferFlags.Description)
+
+		return nil
+	}
+
+	if offer.TakerGets.Asset == offer.TakerPays.Asset && offer.TakerGets.Issuer == offer.TakerPays.Issuer {
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.SameAsset.Code, consts.RippleErrors.SameAsset.Description)
+
+		return nil
+	}
+
+	if _, err := ripplecrypto.DecodeAccountId(offer.Address); err != nil {
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return nil
+	}
+
+	takerGets, errorCode, err := toAmount(offer.TakerGets)
+	if err != nil {
+		handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	takerPays, errorCode, err := toAmount(offer.TakerPays)
+	if err != nil {
+		handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	txSigner, ok := signerFromRequest(c, w, m, offer.Address, offer.OfferId)
+	if !ok {
+		return nil
+	}
+
+	txFee, ok := currentFee(c, w)
+	if !ok {
+		return nil
+	}
+
+	offer.TxFee = int64(txFee)
+	offer.Status = "valid"
+	if err := database.InsertOffer(c, c.Value(consts.AccessKeyKey).(string), offer); err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	// Return the offerId and unblock the client
+	offer.RequestId = c.Value(consts.RequestIdKey).(string)
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(offer); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	go delegatedOfferCreate(c, c.Value(consts.AccessKeyKey).(string), txSigner, offer, takerGets, takerPays)
+
+	return nil
+}
+
+func delegatedOfferCreate(c context.Context, accessKey string, txSigner signer.Signer, offer enulib.RippleOffer, takerGets interface{}, takerPays interface{}) (string, int64, error) {
+	unlock := lockAddress(c, offer.Address)
+	defer unlock()
+
+	txHash, offerSequence, errCode, err := rippleapi.CreateOffer(c, offer.Address, takerGets, takerPays, offer.Flags, txSigner)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.CreateOffer(): %s", err.Error())
+		database.UpdateOfferWithErrorByOfferId(c, accessKey, offer.OfferId, txHash, errCode, err.Error())
+
+		return txHash, errCode, err
+	}
+
+	database.UpdateOfferCompleteByOfferId(c, accessKey, offer.OfferId, txHash, offerSequence)
+
+	log.FluentfContext(consts.LOGINFO, c, "Offer %s placed with sequence %d. Complete.", offer.OfferId, offerSequence)
+
+	return txHash, 0, nil
+}
+
+// Returns the offer placed by the access key with the given offerId
+func GetOffer(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	offerId := mux.Vars(r)["offerId"]
+	if offerId == "" || len(offerId) < 16 {
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidOfferId.Code, consts.GenericErrors.InvalidOfferId.Description)
+
+		return nil
+	}
+
+	offer := database.GetOfferByOfferId(c, c.Value(consts.AccessKeyKey).(string), offerId)
+	if offer.Status == consts.NotFound {
+		handlers.ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidOfferId.Code, consts.GenericErrors.InvalidOfferId.Description)
+
+		return nil
+	}
+
+	setOfferFlags(&offer)
+	offer.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(offer); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Removes an offer placed through OfferCreate from the decentralised exchange
+func OfferCancel(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	accessKey := c.Value(consts.AccessKeyKey).(string)
+	offerId := mux.Vars(r)["offerId"]
+
+	log.FluentfContext(consts.LOGINFO, c, "OfferCancel called for '%s' by '%s'\n", offerId, accessKey)
+
+	offer := database.GetOfferByOfferId(c, accessKey, offerId)
+	if offer.Status == consts.NotFound {
+		handlers.ReturnNotFoundWithCustomError(c, w, consts.GenericErrors.InvalidOfferId.Code, consts.GenericErrors.InvalidOfferId.Description)
+
+		return nil
+	}
+
+	// Only offers which have been placed in the ledger have a sequence to cancel
+	if offer.Status != "complete" {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.OfferNotOpen.Code, errors.New(consts.RippleErrors.OfferNotOpen.Description))
+
+		return nil
+	}
+
+	txSigner, ok := signerFromRequest(c, w, m, offer.Address, offer.OfferId)
+	if !ok {
+		return nil
+	}
+
+	unlock := lockAddress(c, offer.Address)
+	txHash, errorCode, err := rippleapi.CancelOffer(c, offer.Address, offer.OfferSequence, txSigner)
+	unlock()
+
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.CancelOffer(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	database.UpdateOfferCancelledByOfferId(c, accessKey, offer.OfferId, txHash)
+
+	offer.Status = "cancelled"
+	offer.CancelTxId = txHash
+	offer.RequestId = c.Value(consts.RequestIdKey).(string)
+	setOfferFlags(&offer)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(offer); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Lists the offers of the address given by the address query parameter which are still in the ledger
+func GetOffers(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var result enulib.RippleLedgerOffers
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	address := r.URL.Query().Get("address")
+	if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return nil
+	}
+
+	offers, errorCode, err := rippleapi.GetAccountOffers(c, address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetAccountOffers(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	result.Address = address
+	result.Offers = make([]enulib.RippleLedgerOffer, 0)
+	for _, offer := range offers {
+		result.Offers = append(result.Offers, toLedgerOffer(c, offer))
+	}
+	result.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Returns the order book for the pair given by the takerGets, takerGetsIssuer, takerPays and takerPaysIssuer query
+// parameters. The optional limit query parameter gives the maximum number of offers to return.
+func GetOrderBook(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var result enulib.RippleLedgerOffers
+	var limit uint64
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	query := r.URL.Query()
+	takerGets := enulib.RippleOfferAmount{Asset: query.Get("takerGets"), Issuer: query.Get("takerGetsIssuer")}
+	takerPays := enulib.RippleOfferAmount{Asset: query.Get("takerPays"), Issuer: query.Get("takerPaysIssuer")}
+
+	if query.Get("limit") != "" {
+		var err error
+
+		limit, err = strconv.ParseUint(query.Get("limit"), 10, 32)
+		if err != nil {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidDocument.Code, consts.GenericErrors.InvalidDocument.Description)
+
+			return nil
+		}
+	}
+
+	var book [2]rippleapi.Amount
+	for i, side := range []*enulib.RippleOfferAmount{&takerGets, &takerPays} {
+		if strings.ToUpper(side.Asset) == "XRP" {
+			side.Asset = "XRP"
+			side.Issuer = ""
+		}
+
+		currency, errorCode, err := assetCurrency(*side)
+		if err != nil {
+			handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+
+			return nil
+		}
+
+		book[i] = rippleapi.Amount{Currency: currency, Issuer: side.Issuer}
+	}
+
+	offers, errorCode, err := rippleapi.GetBookOffers(c, book[0], book[1], uint(limit))
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetBookOffers(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	result.TakerGets = &takerGets
+	result.TakerPays = &takerPays
+	result.Offers = make([]enulib.RippleLedgerOffer, 0)
+	for _, offer := range offers {
+		result.Offers = append(result.Offers, toLedgerOffer(c, offer))
+	}
+	result.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
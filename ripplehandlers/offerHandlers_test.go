@@ -0,0 +1,51 @@
+package ripplehandlers
+
+import (
+	"testing"
+
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/rippleapi"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func TestOfferAmounts(t *testing.T) {
+	var testData = []struct {
+		Amount          enulib.RippleOfferAmount
+		ExpectedError   bool
+		CaseDescription string
+	}{
+		{enulib.RippleOfferAmount{Asset: "XRP", Quantity: 2500000000}, false, "25 XRP"},
+		{enulib.RippleOfferAmount{Asset: "USD", Issuer: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", Quantity: 150000000}, false, "1.5 USD"},
+		{enulib.RippleOfferAmount{Asset: "VENNDTOKEN", Issuer: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", Quantity: 100000000}, false, "Custom currency"},
+		{enulib.RippleOfferAmount{Asset: "USD", Quantity: 100000000}, true, "Missing issuer"},
+		{enulib.RippleOfferAmount{Asset: "USD", Issuer: "notanaddress", Quantity: 100000000}, true, "Invalid issuer"},
+		{enulib.RippleOfferAmount{Asset: "XRP", Quantity: 99}, true, "Less than a drop"},
+	}
+
+	for _, s := range testData {
+		amount, _, err := toAmount(s.Amount)
+		if (err != nil) != s.ExpectedError {
+			t.Errorf("%s. Expected error: %t, got: %v\n", s.CaseDescription, s.ExpectedError, err)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		// Amounts read back from rippled must convert to the same quantity in the Enu denomination
+		var parsed rippleapi.Amount
+		switch a := amount.(type) {
+		case string:
+			parsed = rippleapi.Amount{Currency: "XRP", Value: a}
+		case rippleapi.Amount:
+			parsed = a
+		}
+
+		result := rippleapi.ToEnuAmount(context.TODO(), parsed)
+		if result != s.Amount {
+			t.Errorf("%s. Expected: %#v, got: %#v\n", s.CaseDescription, s.Amount, result)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package ripplehandlers
+
+import (
+	"errors"
+	"math"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/signer"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// A path payment delivers the destination amount while the source pays in the send asset. The source pays at most the
+// quoted amount plus the maximum slippage, in percent. If deliverMin is not zero the payment may deliver less than the
+// destination amount but no less than deliverMin.
+type pathPayment struct {
+	destination enulib.RippleOfferAmount
+	send        enulib.RippleOfferAmount
+	maxSlippage float64
+	deliverMin  uint64
+}
+
+// Returns the most the source may pay when the path was quoted at quoted
+func sendMaxWithSlippage(quoted uint64, maxSlippage float64) uint64 {
+	return uint64(math.Ceil(float64(quoted) * (1 + maxSlippage/100)))
+}
+
+// Returns the alternative which costs the source the least in the send asset
+func cheapestAlternative(c context.Context, alternatives []rippleapi.PathAlternative) (rippleapi.PathAlternative, uint64, bool) {
+	var cheapest rippleapi.PathAlternative
+	var cheapestQuantity uint64
+	var found bool
+
+	for _, alternative := range alternatives {
+		quantity := rippleapi.ToEnuAmount(c, alternative.SourceAmount).Quantity
+
+		if quantity > 0 && (!found || quantity < cheapestQuantity) {
+			cheapest = alternative
+			cheapestQuantity = quantity
+			found = true
+		}
+	}
+
+	return cheapest, cheapestQuantity, found
+}
+
+// Finds a path for the payment, sends it and records the amount delivered once the tx is validated
+func delegatedPathSend(c context.Context, accessKey string, txSigner signer.Signer, sourceAddress string, destinationAddress string, payment pathPayment, paymentId string, paymentTag string, options paymentOptions) (string, int64, error) {
+	database.InsertPayment(c, accessKey, 0, c.Value(consts.BlockchainIdKey).(string), paymentId, sourceAddress, destinationAddress, payment.destination.Asset, payment.destination.Issuer, payment.destination.Quantity, "valid", 0, 0, paymentTag)
+	if !options.isEmpty() {
+		database.UpdatePaymentOptionsByPaymentId(c, accessKey, paymentId, options.destinationTag, options.sourceTag, options.memos)
+	}
+
+	unlock := lockAddress(c, sourceAddress)
+	defer unlock()
+
+	fail := func(txHash string, errCode int64, err error) (string, int64, error) {
+		database.UpdatePaymentWithErrorByPaymentId(c, accessKey, paymentId, txHash, errCode, err.Error())
+
+		return txHash, errCode, err
+	}
+
+	amount, errCode, err := toAmount(payment.destination)
+	if err != nil {
+		return fail("", errCode, err)
+	}
+
+	sendCurrency, errCode, err := assetCurrency(payment.send)
+	if err != nil {
+		return fail("", errCode, err)
+	}
+
+	alternatives, errCode, err := rippleapi.FindPaths(c, sourceAddress, destinationAddress, amount, rippleapi.Amount{Currency: sendCurrency, Issuer: payment.send.Issuer})
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.FindPaths(): %s", err.Error())
+		return fail("", errCode, err)
+	}
+
+	alternative, quoted, found := cheapestAlternative(c, alternatives)
+	if !found {
+		return fail("", consts.RippleErrors.NoPathFound.Code, errors.New(consts.RippleErrors.NoPathFound.Description))
+	}
+
+	send := payment.send
+	send.Quantity = sendMaxWithSlippage(quoted, payment.maxSlippage)
+	log.FluentfContext(consts.LOGINFO, c, "Path found costing %d %s. Sending with a SendMax of %d", quoted, send.Asset, send.Quantity)
+
+	sendMax, errCode, err := toAmount(send)
+	if err != nil {
+		return fail("", errCode, err)
+	}
+	database.UpdatePaymentSendMaxByPaymentId(c, accessKey, paymentId, send.Asset, send.Issuer, send.Quantity)
+
+	var deliverMin interface{}
+	if payment.deliverMin > 0 {
+		deliverMin, errCode, err = toAmount(enulib.RippleOfferAmount{Asset: payment.destination.Asset, Issuer: payment.destination.Issuer, Quantity: payment.deliverMin})
+		if err != nil {
+			return fail("", errCode, err)
+		}
+	}
+
+	rippleOptions, errCode, err := options.toRipple()
+	if err != nil {
+		return fail("", errCode, err)
+	}
+
+	signedTx, txFee, errCode, err := rippleapi.CreatePathPayment(c, sourceAddress, destinationAddress, amount, sendMax, deliverMin, alternative.Paths, rippleOptions, txSigner)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.CreatePathPayment(): %s", err.Error())
+		return fail("", errCode, err)
+	}
+	database.UpdatePaymentTxFeeByPaymentId(c, accessKey, paymentId, txFee)
+
+	txHash, errCode, err := rippleapi.Submit(c, signedTx)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Submit(): %s", err.Error())
+		return fail(txHash, errCode, err)
+	}
+
+	database.UpdatePaymentCompleteByPaymentId(c, accessKey, paymentId, txHash)
+
+	// The amount delivered is only known once the tx is in a validated ledger
+	tx, errCode, err := rippleapi.WaitForValidation(c, txHash, sourceAddress)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in WaitForValidation(): %s", err.Error())
+		return txHash, errCode, err
+	}
+
+	// A validated tx may still have failed, eg with tecPATH_PARTIAL, and only its fee was taken
+	if tx.TransactionResult != "" && tx.TransactionResult != "tesSUCCESS" {
+		log.FluentfContext(consts.LOGERROR, c, "Tx %s was validated with result %s", txHash, tx.TransactionResult)
+
+		errCode, err := rippleapi.TxResultError(tx.TransactionResult)
+		return fail(txHash, errCode, err)
+	}
+
+	if tx.DeliveredAmount.Currency != "" {
+		delivered := rippleapi.ToEnuAmount(c, tx.DeliveredAmount).Quantity
+		database.UpdatePaymentDeliveredAmountByPaymentId(c, accessKey, paymentId, delivered)
+
+		log.FluentfContext(consts.LOGINFO, c, "Delivered %d %s of %d requested.", delivered, payment.destination.Asset, payment.destination.Quantity)
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Complete.")
+
+	return txHash, 0, nil
+}
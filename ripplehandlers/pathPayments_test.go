@@ -0,0 +1,51 @@
+package ripplehandlers
+
+import (
+	"testing"
+
+	"github.com/vennd/enu/rippleapi"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func TestSendMaxWithSlippage(t *testing.T) {
+	var testData = []struct {
+		Quoted          uint64
+		MaxSlippage     float64
+		Expected        uint64
+		CaseDescription string
+	}{
+		{100000000, 0, 100000000, "No slippage"},
+		{100000000, 1, 101000000, "1% slippage"},
+		{100000000, 0.5, 100500000, "Half a percent slippage"},
+		{3, 50, 5, "Rounds up"},
+	}
+
+	for _, s := range testData {
+		result := sendMaxWithSlippage(s.Quoted, s.MaxSlippage)
+		if result != s.Expected {
+			t.Errorf("%s. Expected: %d, got: %d\n", s.CaseDescription, s.Expected, result)
+		}
+	}
+}
+
+func TestCheapestAlternative(t *testing.T) {
+	alternatives := []rippleapi.PathAlternative{
+		{SourceAmount: rippleapi.Amount{Currency: "XRP", Value: "2000000"}},
+		{SourceAmount: rippleapi.Amount{Currency: "XRP", Value: "1500000"}},
+		{SourceAmount: rippleapi.Amount{Currency: "XRP", Value: "1800000"}},
+	}
+
+	alternative, quoted, found := cheapestAlternative(context.TODO(), alternatives)
+	if !found {
+		t.Fatalf("Expected an alternative to be found\n")
+	}
+
+	if quoted != 150000000 || alternative.SourceAmount.Value != "1500000" {
+		t.Errorf("Expected the 1.5 XRP alternative, got: %d, %#v\n", quoted, alternative)
+	}
+
+	if _, _, found := cheapestAlternative(context.TODO(), nil); found {
+		t.Errorf("Expected no alternative to be found when there are none\n")
+	}
+}
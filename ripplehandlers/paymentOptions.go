@@ -0,0 +1,105 @@
+package ripplehandlers
+
+import (
+	"errors"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// The optional tags and memos of a payment. Tags are pointers because 0 is a valid tag
+type paymentOptions struct {
+	destinationTag *uint32
+	sourceTag      *uint32
+	memos          []enulib.RippleMemo
+}
+
+// Reads the destinationTag, sourceTag and memos from the request
+func paymentOptionsFromRequest(m map[string]interface{}) paymentOptions {
+	var options paymentOptions
+
+	if m["destinationTag"] != nil {
+		tag := uint32(m["destinationTag"].(float64))
+		options.destinationTag = &tag
+	}
+
+	if m["sourceTag"] != nil {
+		tag := uint32(m["sourceTag"].(float64))
+		options.sourceTag = &tag
+	}
+
+	if m["memos"] != nil {
+		for _, v := range m["memos"].([]interface{}) {
+			var memo enulib.RippleMemo
+
+			memoMap := v.(map[string]interface{})
+			if memoMap["type"] != nil {
+				memo.Type = memoMap["type"].(string)
+			}
+			if memoMap["format"] != nil {
+				memo.Format = memoMap["format"].(string)
+			}
+			if memoMap["data"] != nil {
+				memo.Data = memoMap["data"].(string)
+			}
+
+			options.memos = append(options.memos, memo)
+		}
+	}
+
+	return options
+}
+
+func (o paymentOptions) isEmpty() bool {
+	return o.destinationTag == nil && o.sourceTag == nil && len(o.memos) == 0
+}
+
+// Checks the memos and converts the options to the fields given in the payment transaction
+func (o paymentOptions) toRipple() (rippleapi.PaymentOptions, int64, error) {
+	var size int
+
+	result := rippleapi.PaymentOptions{DestinationTag: o.destinationTag, SourceTag: o.sourceTag}
+
+	for _, memo := range o.memos {
+		if memo.Type == "" && memo.Format == "" && memo.Data == "" {
+			return result, consts.RippleErrors.InvalidMemo.Code, errors.New(consts.RippleErrors.InvalidMemo.Description)
+		}
+
+		size += len(memo.Type) + len(memo.Format) + len(memo.Data)
+		result.Memos = append(result.Memos, rippleapi.NewMemo(memo.Type, memo.Format, memo.Data))
+	}
+
+	if size > rippleapi.MaxMemoSize {
+		return result, consts.RippleErrors.InvalidMemo.Code, errors.New(consts.RippleErrors.InvalidMemo.Description)
+	}
+
+	return result, 0, nil
+}
+
+// Returns an error if the destination requires a destination tag and none is given. Destinations which don't exist yet
+// can't have set RequireDestTag
+func checkDestinationTag(c context.Context, destination string, destinationTag *uint32) (int64, error) {
+	if destinationTag != nil {
+		return 0, nil
+	}
+
+	accountInfo, errCode, err := rippleapi.GetAccountInfo(c, destination)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.GetAccountInfo(): %s", err.Error())
+		if errCode == 0 {
+			errCode = consts.RippleErrors.MiscError.Code
+		}
+
+		return errCode, err
+	}
+
+	if accountInfo.Flags&rippleapi.LsfRequireDestTag != 0 {
+		return consts.RippleErrors.DestinationTagRequired.Code, errors.New(consts.RippleErrors.DestinationTagRequired.Description)
+	}
+
+	return 0, nil
+}
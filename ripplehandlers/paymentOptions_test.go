@@ -0,0 +1,45 @@
+package ripplehandlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vennd/enu/enulib"
+)
+
+func TestPaymentOptionsToRipple(t *testing.T) {
+	tag := uint32(0)
+
+	var testData = []struct {
+		Options         paymentOptions
+		ExpectedError   bool
+		CaseDescription string
+	}{
+		{paymentOptions{}, false, "No options"},
+		{paymentOptions{destinationTag: &tag}, false, "A destination tag of 0"},
+		{paymentOptions{memos: []enulib.RippleMemo{{Type: "invoice", Format: "text/plain", Data: "INV-1"}}}, false, "A memo"},
+		{paymentOptions{memos: []enulib.RippleMemo{{}}}, true, "An empty memo"},
+		{paymentOptions{memos: []enulib.RippleMemo{{Data: strings.Repeat("a", 1025)}}}, true, "Memos larger than 1KB"},
+	}
+
+	for _, s := range testData {
+		result, _, err := s.Options.toRipple()
+		if (err != nil) != s.ExpectedError {
+			t.Errorf("%s. Expected error: %t, got: %v\n", s.CaseDescription, s.ExpectedError, err)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if result.DestinationTag != s.Options.destinationTag || len(result.Memos) != len(s.Options.memos) {
+			t.Errorf("%s. Expected the tags and memos to be kept, got: %#v\n", s.CaseDescription, result)
+		}
+	}
+
+	result, _, _ := paymentOptions{memos: []enulib.RippleMemo{{Type: "invoice", Data: "INV-1"}}}.toRipple()
+	if result.Memos[0].MemoType != "696E766F696365" || result.Memos[0].MemoData != "494E562D31" || result.Memos[0].MemoFormat != "" {
+		t.Errorf("Expected the memo to be hex encoded, got: %#v\n", result.Memos[0])
+	}
+}
@@ -16,12 +16,27 @@ import (
 func GetRippleLedgerStatus(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 
+	var result struct {
+		rippleapi.LedgerValue
+		Fees rippleapi.FeeLevels `json:"fees"`
+	}
+
 	// Get ripple ledger status
-	result, _, err := rippleapi.GetLatestValidatedLedger(c)
+	ledger, _, err := rippleapi.GetLatestValidatedLedger(c)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+		return nil
+	}
+	result.LedgerValue = ledger
+
+	// The current fee levels show whether fees have escalated
+	fees, _, err := rippleapi.GetFeeLevels(c)
 	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetFeeLevels(): %s", err.Error())
 		handlers.ReturnServerError(c, w)
 		return nil
 	}
+	result.Fees = fees
 
 	w.WriteHeader(http.StatusOK)
 	if err = json.NewEncoder(w).Encode(result); err != nil {
@@ -32,4 +47,22 @@ func GetRippleLedgerStatus(c context.Context, w http.ResponseWriter, r *http.Req
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// Returns the fee to pay for the tx of the request. Called before the request is accepted so a fee above the maxFee of
+// the request is rejected straight away as a bad request. Otherwise the error is returned to the client and ok is false
+func currentFee(c context.Context, w http.ResponseWriter) (uint64, bool) {
+	txFee, errorCode, err := rippleapi.CurrentFee(c)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.CurrentFee(): %s", err.Error())
+		if errorCode == consts.RippleErrors.FeeTooHigh.Code {
+			handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+		} else {
+			handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+		}
+
+		return 0, false
+	}
+
+	return txFee, true
+}
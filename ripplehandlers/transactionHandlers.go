@@ -0,0 +1,274 @@
+package ripplehandlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/ripplecrypto"
+
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// The filters of a transaction history request. Empty filters match every transaction
+type transactionFilter struct {
+	TransactionType string
+	Asset           string
+	Issuer          string
+	Counterparty    string
+}
+
+// Returns true if the transaction of the address matches every filter given. The counterparty is the destination of
+// transactions the address sent or the source of transactions it received
+func (f transactionFilter) matches(address string, tx enulib.RippleAccountTransaction) bool {
+	if f.TransactionType != "" && !strings.EqualFold(f.TransactionType, tx.TransactionType) {
+		return false
+	}
+
+	if f.Asset != "" {
+		if strings.ToUpper(f.Asset) == "XRP" {
+			if tx.Asset != "XRP" {
+				return false
+			}
+		} else if f.Asset != tx.Asset {
+			return false
+		}
+	}
+
+	if f.Issuer != "" && f.Issuer != tx.Issuer {
+		return false
+	}
+
+	if f.Counterparty != "" {
+		counterparty := tx.SourceAddress
+		if tx.SourceAddress == address {
+			counterparty = tx.DestinationAddress
+		}
+
+		if f.Counterparty != counterparty {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Markers returned by rippled are objects so they are given to clients as an opaque string
+func encodeMarker(marker interface{}) (string, error) {
+	if marker == nil {
+		return "", nil
+	}
+
+	markerJson, err := json.Marshal(marker)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(markerJson), nil
+}
+
+func decodeMarker(marker string) (interface{}, error) {
+	var result interface{}
+
+	if marker == "" {
+		return nil, nil
+	}
+
+	markerJson, err := base64.RawURLEncoding.DecodeString(marker)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(markerJson, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Normalises a transaction returned by account_tx into the Enu denomination
+func toAccountTransaction(c context.Context, address string, tx rippleapi.AccountTransaction) enulib.RippleAccountTransaction {
+	result := enulib.RippleAccountTransaction{
+		BlockchainId:       consts.RippleBlockchainId,
+		TransactionType:    tx.TransactionType,
+		Direction:          "received",
+		SourceAddress:      tx.Account,
+		DestinationAddress: tx.Destination,
+		BroadcastTxId:      tx.Hash,
+		BlockchainStatus:   "unconfirmed",
+		LedgerIndex:        tx.LedgerIndex,
+		Date:               rippleapi.FromRippleTime(tx.Date),
+		DestinationTag:     tx.DestinationTag,
+		SourceTag:          tx.SourceTag,
+		TransactionResult:  tx.TransactionResult,
+	}
+
+	if tx.Account == address {
+		result.Direction = "sent"
+	}
+
+	if tx.Validated {
+		result.BlockchainStatus = "confirmed"
+	}
+
+	if tx.Fee != "" {
+		fee, err := strconv.ParseInt(tx.Fee, 10, 64)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in ParseInt(): %s", err.Error())
+		}
+
+		result.TxFee = fee
+	}
+
+	if tx.Amount.Currency != "" {
+		amount := rippleapi.ToEnuAmount(c, tx.Amount)
+
+		result.Asset = amount.Asset
+		result.Issuer = amount.Issuer
+		result.Amount = amount.Quantity
+	}
+
+	if tx.DeliveredAmount.Currency != "" {
+		result.DeliveredAmount = rippleapi.ToEnuAmount(c, tx.DeliveredAmount).Quantity
+	}
+
+	for _, memo := range tx.Memos {
+		memoType, memoFormat, memoData := rippleapi.DecodeMemo(memo)
+
+		result.Memos = append(result.Memos, enulib.RippleMemo{Type: memoType, Format: memoFormat, Data: memoData})
+	}
+
+	return result
+}
+
+// Lists the validated transactions which affected the address, newest first unless forward is true. Filters are
+// applied to each page so a page may hold fewer transactions than the limit. Read the next page by giving the marker
+// returned until no marker is returned
+func GetAccountTransactions(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var result enulib.RippleAccountTransactions
+	var limit uint64
+	var forward bool
+	var ledgerIndexMin int64 = -1
+	var ledgerIndexMax int64 = -1
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	address := mux.Vars(r)["address"]
+	query := r.URL.Query()
+
+	if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in DecodeAccountId(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "GetAccountTransactions called for '%s' by '%s'\n", address, c.Value(consts.AccessKeyKey).(string))
+
+	filter := transactionFilter{
+		TransactionType: query.Get("type"),
+		Asset:           query.Get("asset"),
+		Issuer:          query.Get("issuer"),
+		Counterparty:    query.Get("counterparty"),
+	}
+
+	for _, filterAddress := range []string{filter.Issuer, filter.Counterparty} {
+		if filterAddress == "" {
+			continue
+		}
+
+		if _, err := ripplecrypto.DecodeAccountId(filterAddress); err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in DecodeAccountId(): %s", err.Error())
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+			return nil
+		}
+	}
+
+	var err error
+	if query.Get("limit") != "" {
+		if limit, err = strconv.ParseUint(query.Get("limit"), 10, 32); err != nil {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidDocument.Code, consts.GenericErrors.InvalidDocument.Description)
+
+			return nil
+		}
+	}
+
+	if query.Get("forward") != "" {
+		if forward, err = strconv.ParseBool(query.Get("forward")); err != nil {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidDocument.Code, consts.GenericErrors.InvalidDocument.Description)
+
+			return nil
+		}
+	}
+
+	if query.Get("ledgerIndexMin") != "" {
+		if ledgerIndexMin, err = strconv.ParseInt(query.Get("ledgerIndexMin"), 10, 64); err != nil {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidDocument.Code, consts.GenericErrors.InvalidDocument.Description)
+
+			return nil
+		}
+	}
+
+	if query.Get("ledgerIndexMax") != "" {
+		if ledgerIndexMax, err = strconv.ParseInt(query.Get("ledgerIndexMax"), 10, 64); err != nil {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidDocument.Code, consts.GenericErrors.InvalidDocument.Description)
+
+			return nil
+		}
+	}
+
+	marker, err := decodeMarker(query.Get("marker"))
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in decodeMarker(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidMarker.Code, consts.RippleErrors.InvalidMarker.Description)
+
+		return nil
+	}
+
+	page, errorCode, err := rippleapi.GetAccountTransactions(c, address, ledgerIndexMin, ledgerIndexMax, uint32(limit), forward, marker)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetAccountTransactions(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	result.Address = address
+	result.Transactions = make([]enulib.RippleAccountTransaction, 0)
+	result.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	for _, tx := range page.Transactions {
+		transaction := toAccountTransaction(c, address, tx)
+
+		if filter.matches(address, transaction) {
+			result.Transactions = append(result.Transactions, transaction)
+		}
+	}
+
+	result.Marker, err = encodeMarker(page.Marker)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in encodeMarker(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
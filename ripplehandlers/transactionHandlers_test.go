@@ -0,0 +1,103 @@
+package ripplehandlers
+
+import (
+	"testing"
+
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/rippleapi"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func TestMarker(t *testing.T) {
+	marker, err := encodeMarker(map[string]interface{}{"ledger": 35000000, "seq": 12})
+	if err != nil || marker == "" {
+		t.Fatalf("Expected a marker, got: %s, %v\n", marker, err)
+	}
+
+	decoded, err := decodeMarker(marker)
+	if err != nil {
+		t.Fatalf("Error decoding the marker: %s\n", err.Error())
+	}
+
+	object := decoded.(map[string]interface{})
+	if object["ledger"].(float64) != 35000000 || object["seq"].(float64) != 12 {
+		t.Errorf("Expected the marker to round trip, got: %#v\n", object)
+	}
+
+	if marker, err := encodeMarker(nil); err != nil || marker != "" {
+		t.Errorf("Expected no marker on the last page, got: %s, %v\n", marker, err)
+	}
+
+	if _, err := decodeMarker("not a marker!"); err == nil {
+		t.Errorf("Expected an error for an invalid marker\n")
+	}
+}
+
+func TestTransactionFilter(t *testing.T) {
+	address := "rAddress"
+	sent := enulib.RippleAccountTransaction{TransactionType: "Payment", SourceAddress: address, DestinationAddress: "rOther", Asset: "USD", Issuer: "rIssuer"}
+	received := enulib.RippleAccountTransaction{TransactionType: "Payment", SourceAddress: "rOther", DestinationAddress: address, Asset: "XRP"}
+	offer := enulib.RippleAccountTransaction{TransactionType: "OfferCreate", SourceAddress: address}
+
+	var testData = []struct {
+		Filter          transactionFilter
+		Tx              enulib.RippleAccountTransaction
+		Expected        bool
+		CaseDescription string
+	}{
+		{transactionFilter{}, offer, true, "No filters"},
+		{transactionFilter{TransactionType: "payment"}, sent, true, "Type in lower case"},
+		{transactionFilter{TransactionType: "Payment"}, offer, false, "Different type"},
+		{transactionFilter{Asset: "xrp"}, received, true, "XRP in lower case"},
+		{transactionFilter{Asset: "USD", Issuer: "rIssuer"}, sent, true, "Currency and issuer"},
+		{transactionFilter{Asset: "USD", Issuer: "rOtherIssuer"}, sent, false, "Different issuer"},
+		{transactionFilter{Asset: "USD"}, offer, false, "Transaction without an amount"},
+		{transactionFilter{Counterparty: "rOther"}, sent, true, "Counterparty is the destination"},
+		{transactionFilter{Counterparty: "rOther"}, received, true, "Counterparty is the source"},
+		{transactionFilter{Counterparty: address}, sent, false, "Address is not its own counterparty"},
+	}
+
+	for _, s := range testData {
+		if result := s.Filter.matches(address, s.Tx); result != s.Expected {
+			t.Errorf("%s. Expected: %t, got: %t\n", s.CaseDescription, s.Expected, result)
+		}
+	}
+}
+
+func TestToAccountTransaction(t *testing.T) {
+	tag := uint32(7)
+	tx := rippleapi.AccountTransaction{
+		Hash:              "ABC",
+		LedgerIndex:       100,
+		Date:              1,
+		TransactionType:   "Payment",
+		Account:           "rOther",
+		Destination:       "rAddress",
+		DestinationTag:    &tag,
+		Amount:            rippleapi.Amount{Value: "1000000", Currency: "XRP"},
+		DeliveredAmount:   rippleapi.Amount{Value: "500000", Currency: "XRP"},
+		Fee:               "12",
+		Memos:             []rippleapi.Memo{rippleapi.NewMemo("invoice", "text/plain", "1234")},
+		TransactionResult: "tesSUCCESS",
+		Validated:         true,
+	}
+
+	result := toAccountTransaction(context.Background(), "rAddress", tx)
+
+	if result.Direction != "received" || result.BlockchainStatus != "confirmed" || result.BroadcastTxId != "ABC" {
+		t.Errorf("Unexpected transaction: %+v\n", result)
+	}
+
+	if result.Asset != "XRP" || result.Amount != 100000000 || result.DeliveredAmount != 50000000 || result.TxFee != 12 {
+		t.Errorf("Expected the amounts in the Enu denomination, got: %+v\n", result)
+	}
+
+	if result.Date != rippleapi.RippleEpoch+1 || *result.DestinationTag != 7 {
+		t.Errorf("Expected the date as a unix time and the destination tag, got: %+v\n", result)
+	}
+
+	if len(result.Memos) != 1 || result.Memos[0].Type != "invoice" || result.Memos[0].Format != "text/plain" || result.Memos[0].Data != "1234" {
+		t.Errorf("Expected the memo in plain text, got: %+v\n", result.Memos)
+	}
+}
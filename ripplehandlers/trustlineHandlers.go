@@ -0,0 +1,281 @@
+package ripplehandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/ripplecrypto"
+
+	"github.com/vennd/enu/internal/github.com/gorilla/mux"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Returns the trust line of the account for the currency and issuer
+func findLine(lines rippleapi.Lines, currency string, issuer string) (rippleapi.Line, bool) {
+	for _, line := range lines {
+		if line.Account == issuer && line.Currency == currency {
+			return line, true
+		}
+	}
+
+	return rippleapi.Line{}, false
+}
+
+// Returns the account info and trust lines of the address. Returns false if the error has been returned to the client
+func getTrustLineState(c context.Context, w http.ResponseWriter, address string) (rippleapi.AccountInfo, rippleapi.Lines, bool) {
+	if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in DecodeAccountId(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return rippleapi.AccountInfo{}, nil, false
+	}
+
+	accountInfo, errorCode, err := rippleapi.GetAccountInfo(c, address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetAccountInfo(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return accountInfo, nil, false
+	}
+
+	lines, errorCode, err := rippleapi.GetAccountLines(c, address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetAccountLines(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return accountInfo, nil, false
+	}
+
+	return accountInfo, lines, true
+}
+
+// Returns the currency and issuer of the trust line given in the request. Returns false if they are invalid
+func getTrustLineCurrency(c context.Context, w http.ResponseWriter, m map[string]interface{}) (string, string, bool) {
+	currency, err := rippleapi.ToCurrency(m["asset"].(string))
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in ToCurrency(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidCurrency.Code, consts.RippleErrors.InvalidCurrency.Description)
+
+		return "", "", false
+	}
+
+	issuer := m["issuer"].(string)
+	if _, err := ripplecrypto.DecodeAccountId(issuer); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in DecodeAccountId(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+		return "", "", false
+	}
+
+	return currency, issuer, true
+}
+
+func returnTrustSet(c context.Context, w http.ResponseWriter, trustSet enulib.RippleTrustSet) {
+	trustSet.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(trustSet); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+	}
+}
+
+// Lists the trust lines of the address. The reserve required is in drops and covers every object the account owns
+func GetTrustLines(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var result enulib.RippleTrustLines
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	address := mux.Vars(r)["address"]
+
+	log.FluentfContext(consts.LOGINFO, c, "GetTrustLines called for '%s' by '%s'\n", address, c.Value(consts.AccessKeyKey).(string))
+
+	accountInfo, lines, ok := getTrustLineState(c, w, address)
+	if !ok {
+		return nil
+	}
+
+	result.Address = address
+	result.OwnerCount = accountInfo.OwnerCount
+	result.ReserveRequired = rippleapi.CalculateReserve(c, uint64(accountInfo.OwnerCount))
+	result.Lines = make([]enulib.RippleTrustLine, 0)
+	result.RequestId = c.Value(consts.RequestIdKey).(string)
+
+	for _, line := range lines {
+		asset, err := rippleapi.FromCurrency(line.Currency)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in FromCurrency(): %s", err.Error())
+			asset = line.Currency
+		}
+
+		result.Lines = append(result.Lines, enulib.RippleTrustLine{Asset: asset, Issuer: line.Account, Balance: line.Balance, Limit: line.Limit, LimitPeer: line.LimitPeer, NoRipple: line.NoRipple, NoRipplePeer: line.NoRipplePeer, Freeze: line.Freeze, FreezePeer: line.FreezePeer, QualityIn: line.QualityIn, QualityOut: line.QualityOut})
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Adds a trust line or changes the limit of an existing line. The NoRipple and Freeze flags of the line are set or
+// cleared if noRipple or freeze are given. A new line increases the reserve so the address must hold enough XRP for it.
+func TrustLineSet(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var trustSet enulib.RippleTrustSet
+	var flags uint32
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	address := m["address"].(string)
+	limit := uint64(m["limit"].(float64))
+
+	log.FluentfContext(consts.LOGINFO, c, "TrustLineSet called for '%s' by '%s'\n", address, c.Value(consts.AccessKeyKey).(string))
+
+	currency, issuer, ok := getTrustLineCurrency(c, w, m)
+	if !ok {
+		return nil
+	}
+
+	if m["noRipple"] != nil && m["noRipple"].(bool) {
+		flags |= rippleapi.TfSetNoRipple
+	} else if m["noRipple"] != nil {
+		flags |= rippleapi.TfClearNoRipple
+	}
+
+	if m["freeze"] != nil && m["freeze"].(bool) {
+		flags |= rippleapi.TfSetFreeze
+	} else if m["freeze"] != nil {
+		flags |= rippleapi.TfClearFreeze
+	}
+
+	txSigner, ok := signerFromRequest(c, w, m, address, "")
+	if !ok {
+		return nil
+	}
+
+	accountInfo, lines, ok := getTrustLineState(c, w, address)
+	if !ok {
+		return nil
+	}
+
+	// A new line is another object owned by the account which must be covered by the reserve
+	ownerCount := uint64(accountInfo.OwnerCount)
+	if _, exists := findLine(lines, currency, issuer); !exists {
+		ownerCount++
+
+		var balance uint64
+		if accountInfo.Balance != "" {
+			balance, _ = strconv.ParseUint(accountInfo.Balance, 10, 64)
+		}
+
+		if balance < rippleapi.CalculateReserve(c, ownerCount) {
+			log.FluentfContext(consts.LOGERROR, c, "%s holds %d drops which is below the reserve of %d drops for %d objects", address, balance, rippleapi.CalculateReserve(c, ownerCount), ownerCount)
+			handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.InsufficientReserve.Code, errors.New(consts.RippleErrors.InsufficientReserve.Description))
+
+			return nil
+		}
+	}
+
+	value, err := rippleapi.Uint64ToAmount(limit)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Uint64ToAmount(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidAmount.Code, consts.RippleErrors.InvalidAmount.Description)
+
+		return nil
+	}
+
+	unlock := lockAddress(c, address)
+	txHash, errorCode, err := rippleapi.TrustSet(c, address, currency, value, issuer, flags, txSigner)
+	unlock()
+
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in TrustSet(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	trustSet = enulib.RippleTrustSet{Address: address, Asset: m["asset"].(string), Issuer: issuer, Limit: limit, Flags: flags, ReserveRequired: rippleapi.CalculateReserve(c, ownerCount), TxHash: txHash}
+	returnTrustSet(c, w, trustSet)
+
+	return nil
+}
+
+// Removes a trust line by returning it to its default state. The line must not hold a balance. Once removed the line
+// no longer counts towards the reserve of the address.
+func TrustLineRemove(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var flags uint32 = rippleapi.TfClearFreeze
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	address := m["address"].(string)
+
+	log.FluentfContext(consts.LOGINFO, c, "TrustLineRemove called for '%s' by '%s'\n", address, c.Value(consts.AccessKeyKey).(string))
+
+	currency, issuer, ok := getTrustLineCurrency(c, w, m)
+	if !ok {
+		return nil
+	}
+
+	txSigner, ok := signerFromRequest(c, w, m, address, "")
+	if !ok {
+		return nil
+	}
+
+	accountInfo, lines, ok := getTrustLineState(c, w, address)
+	if !ok {
+		return nil
+	}
+
+	line, exists := findLine(lines, currency, issuer)
+	if !exists {
+		handlers.ReturnNotFoundWithCustomError(c, w, consts.RippleErrors.TrustLineNotFound.Code, consts.RippleErrors.TrustLineNotFound.Description)
+
+		return nil
+	}
+
+	if balance, err := strconv.ParseFloat(line.Balance, 64); err != nil || balance != 0 {
+		handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.TrustLineHasBalance.Code, errors.New(consts.RippleErrors.TrustLineHasBalance.Description))
+
+		return nil
+	}
+
+	// The default state of the NoRipple flag depends on whether the account ripples by default
+	if accountInfo.Flags&rippleapi.LsfDefaultRipple == 0 {
+		flags |= rippleapi.TfSetNoRipple
+	} else {
+		flags |= rippleapi.TfClearNoRipple
+	}
+
+	unlock := lockAddress(c, address)
+	txHash, errorCode, err := rippleapi.TrustSet(c, address, currency, "0", issuer, flags, txSigner)
+	unlock()
+
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in TrustSet(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+
+	ownerCount := uint64(0)
+	if accountInfo.OwnerCount > 0 {
+		ownerCount = uint64(accountInfo.OwnerCount) - 1
+	}
+
+	trustSet := enulib.RippleTrustSet{Address: address, Asset: m["asset"].(string), Issuer: issuer, Limit: 0, Flags: flags, ReserveRequired: rippleapi.CalculateReserve(c, ownerCount), TxHash: txHash}
+	returnTrustSet(c, w, trustSet)
+
+	return nil
+}
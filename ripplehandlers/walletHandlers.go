@@ -15,12 +15,13 @@ import (
 	"github.com/vennd/enu/consts"
 	"github.com/vennd/enu/database"
 	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/fuel"
 	"github.com/vennd/enu/handlers"
 	"github.com/vennd/enu/internal/github.com/vennd/mneumonic"
+	"github.com/vennd/enu/keystore"
 	"github.com/vennd/enu/log"
 	"github.com/vennd/enu/rippleapi"
-	"github.com/vennd/enu/ripplecrypto"
-	"math/rand"
+	"github.com/vennd/enu/signer"
 )
 
 var ripple_BackEndPollRate = 1000
@@ -58,6 +59,32 @@ func WalletCreate(c context.Context, w http.ResponseWriter, r *http.Request, m m
 	mn := mneumonic.FromHexstring(wallet.MasterSeedHex)
 	walletModel.Passphrase = strings.Join(mn.ToWords(), " ") // The hex seed for Ripple wallets can be translated to the same mneumonic that generates counterparty wallets
 
+	// Custodial wallets are held in the keystore and only the walletId and public information is returned
+	if m["custodial"] != nil && m["custodial"].(bool) == true {
+		var unlockPolicy string
+		var unlockSeconds int64
+
+		if m["unlockPolicy"] != nil {
+			unlockPolicy = m["unlockPolicy"].(string)
+		}
+		if m["unlockSeconds"] != nil {
+			unlockSeconds = int64(m["unlockSeconds"].(float64))
+		}
+
+		keystoreWallet, errorCode, err := keystore.StoreWallet(c, c.Value(consts.AccessKeyKey).(string), consts.RippleBlockchainId, walletModel.Passphrase, walletModel.Addresses, m["walletPassword"].(string), unlockPolicy, unlockSeconds)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in keystore.StoreWallet(): %s", err.Error())
+			handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+			return nil
+		}
+
+		walletModel.WalletId = keystoreWallet.WalletId
+		walletModel.Passphrase = ""
+		walletModel.HexSeed = ""
+		walletModel.MasterSeed = ""
+	}
+
 	w.WriteHeader(http.StatusCreated)
 	if err = json.NewEncoder(w).Encode(walletModel); err != nil {
 		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
@@ -82,7 +109,6 @@ func WalletSend(c context.Context, w http.ResponseWriter, r *http.Request, m map
 	// Add to the context the RequestType
 	c = context.WithValue(c, consts.RequestTypeKey, "walletPayment")
 
-	passphrase := m["passphrase"].(string)
 	sourceAddress := m["sourceAddress"].(string)
 	destinationAddress := m["destinationAddress"].(string)
 	asset := m["asset"].(string)
@@ -103,18 +129,94 @@ func WalletSend(c context.Context, w http.ResponseWriter, r *http.Request, m map
 		return nil
 	}
 
+	// If a send asset is given the source pays in the send asset and the destination receives the asset
+	var payment pathPayment
+	isPathPayment := m["sendAsset"] != nil
+	if isPathPayment {
+		payment.destination = enulib.RippleOfferAmount{Asset: asset, Issuer: issuer, Quantity: quantity}
+		payment.send.Asset = m["sendAsset"].(string)
+		if m["sendIssuer"] != nil {
+			payment.send.Issuer = m["sendIssuer"].(string)
+		}
+
+		// XRP has no issuer
+		for _, a := range []*enulib.RippleOfferAmount{&payment.destination, &payment.send} {
+			if strings.ToUpper(a.Asset) == "XRP" {
+				a.Asset = "XRP"
+				a.Issuer = ""
+			}
+		}
+
+		if m["maxSlippage"] != nil {
+			payment.maxSlippage = m["maxSlippage"].(float64)
+		}
+
+		if m["deliverMin"] != nil {
+			payment.deliverMin = uint64(m["deliverMin"].(float64))
+		}
+
+		if _, errorCode, err := assetCurrency(payment.send); err != nil {
+			handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+			return nil
+		}
+
+		if _, errorCode, err := toAmount(payment.destination); err != nil {
+			handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+			return nil
+		}
+
+		if payment.send.Asset == payment.destination.Asset && payment.send.Issuer == payment.destination.Issuer {
+			handlers.ReturnBadRequest(c, w, consts.RippleErrors.SameAsset.Code, consts.RippleErrors.SameAsset.Description)
+			return nil
+		}
+
+		if payment.deliverMin > quantity {
+			handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidAmount.Code, consts.RippleErrors.InvalidAmount.Description)
+			return nil
+		}
+
+		log.FluentfContext(consts.LOGINFO, c, "WalletSend: paying with sendAsset: %s, sendIssuer: %s, maxSlippage: %f, deliverMin: %d", payment.send.Asset, payment.send.Issuer, payment.maxSlippage, payment.deliverMin)
+	}
+
+	// Destination and source tags and memos are optional
+	options := paymentOptionsFromRequest(m)
+	if _, errorCode, err := options.toRipple(); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in toRipple(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+		return nil
+	}
+
+	// Exchanges and other shared accounts require a destination tag to credit the payment
+	if errorCode, err := checkDestinationTag(c, destinationAddress, options.destinationTag); err != nil {
+		if errorCode == consts.RippleErrors.DestinationTagRequired.Code {
+			handlers.ReturnBadRequest(c, w, errorCode, err.Error())
+		} else {
+			handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+		}
+		return nil
+	}
+
 	log.FluentfContext(consts.LOGINFO, c, "WalletSend: received request sourceAddress: %s, destinationAddress: %s, asset: %s, issuer: %s, quantity: %d, paymentTag: %s from accessKey: %s\n", sourceAddress, destinationAddress, asset, issuer, quantity, c.Value(consts.AccessKeyKey).(string), paymentTag)
 	// Generate a paymentId
 	paymentId := enulib.GeneratePaymentId()
 
 	log.FluentfContext(consts.LOGINFO, c, "Generated paymentId: %s", paymentId)
 
+	txSigner, ok := signerFromRequest(c, w, m, sourceAddress, paymentId)
+	if !ok {
+		return nil
+	}
+
 	// Return to the client the walletPayment containing requestId and paymentId and unblock the client
 	walletPayment.PaymentId = paymentId
 	walletPayment.Asset = asset
 	walletPayment.SourceAddress = sourceAddress
 	walletPayment.DestinationAddress = destinationAddress
 	walletPayment.Quantity = quantity
+	walletPayment.PaymentTag = paymentTag
+	walletPayment.DestinationTag = options.destinationTag
+	walletPayment.SourceTag = options.sourceTag
+	walletPayment.Memos = options.memos
 	w.WriteHeader(http.StatusCreated)
 
 	if err := json.NewEncoder(w).Encode(walletPayment); err != nil {
@@ -124,21 +226,43 @@ func WalletSend(c context.Context, w http.ResponseWriter, r *http.Request, m map
 		return nil
 	}
 
+	if isPathPayment {
+		go delegatedPathSend(c, c.Value(consts.AccessKeyKey).(string), txSigner, sourceAddress, destinationAddress, payment, paymentId, paymentTag, options)
+
+		return nil
+	}
+
 	//	txHash, errCode, err := rippleapi.SendPayment(c, sourceAddress, destinationAddress, amount, asset, issuer, secret)
-	go delegatedSend(c, c.Value(consts.AccessKeyKey).(string), passphrase, sourceAddress, destinationAddress, asset, issuer, quantity, paymentId, paymentTag)
+	go delegatedSend(c, c.Value(consts.AccessKeyKey).(string), txSigner, sourceAddress, destinationAddress, asset, issuer, quantity, paymentId, paymentTag, options)
 
 	return nil
 }
 
+// Converts the quantity in the Enu API denomination to the Ripple amount. XRP is returned in drops.
+func toRippleAmount(asset string, quantity uint64) (string, error) {
+	if strings.ToUpper(asset) == "XRP" {
+		// Amounts are specified in satoshis in the Enu API
+		// Convert to a string and truncate the last two characters
+		a := strconv.FormatUint(quantity, 10)
+		if len(a) <= 2 {
+			return "0", nil
+		}
+
+		return a[:len(a)-2], nil
+	}
+
+	return rippleapi.Uint64ToAmount(quantity)
+}
+
 // Concurrency safe to create and send transactions from a single address.
-func delegatedSend(c context.Context, accessKey string, passphrase string, sourceAddress string, destinationAddress string, asset string, issuer string, quantity uint64, paymentId string, paymentTag string) (string, int64, error) {
+func delegatedSend(c context.Context, accessKey string, txSigner signer.Signer, sourceAddress string, destinationAddress string, asset string, issuer string, quantity uint64, paymentId string, paymentTag string, options paymentOptions) (string, int64, error) {
 
-	// Write the payment with the generated payment id to the database
-	defaultFee, err := strconv.ParseUint(rippleapi.DefaultFee, 10, 64)
-	if err != nil {
-		log.FluentfContext(consts.LOGERROR, c, "Error in converting ripple fee: %s", err.Error())
+	// Write the payment with the generated payment id to the database. The payment must exist before the tags, memos
+	// and the fee paid once the tx is created can be recorded against it
+	database.InsertPayment(c, accessKey, 0, c.Value(consts.BlockchainIdKey).(string), paymentId, sourceAddress, destinationAddress, asset, issuer, quantity, "valid", 0, 0, paymentTag)
+	if !options.isEmpty() {
+		database.UpdatePaymentOptionsByPaymentId(c, accessKey, paymentId, options.destinationTag, options.sourceTag, options.memos)
 	}
-	go database.InsertPayment(c, accessKey, 0, c.Value(consts.BlockchainIdKey).(string), paymentId, sourceAddress, destinationAddress, asset, issuer, quantity, "valid", 0, defaultFee, paymentTag)
 
 	// Mutex lock this address
 	ripple_Mutexes.Lock()
@@ -163,21 +287,12 @@ func delegatedSend(c context.Context, accessKey string, passphrase string, sourc
 	log.FluentfContext(consts.LOGINFO, c, "Sleep complete")
 
 	// Convert int to the ripple amount
-	var amount string
-	if strings.ToUpper(asset) == "XRP" {
-		// Amounts are specified in satoshis in the Enu API
-		// Convert to a string and truncate the last two characters
-		a := strconv.FormatUint(quantity, 10)
-		amount = a[:len(a)-2]
-	} else {
-		a, err := rippleapi.Uint64ToAmount(quantity)
-		if err != nil {
-			log.FluentfContext(consts.LOGERROR, c, "Error in Uint64ToAmount(): %s", err.Error())
-			database.UpdatePaymentWithErrorByPaymentId(c, accessKey, paymentId, "", consts.GenericErrors.GeneralError.Code, consts.GenericErrors.GeneralError.Description)
+	amount, err := toRippleAmount(asset, quantity)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in toRippleAmount(): %s", err.Error())
+		database.UpdatePaymentWithErrorByPaymentId(c, accessKey, paymentId, "", consts.GenericErrors.GeneralError.Code, consts.GenericErrors.GeneralError.Description)
 
-			return "", consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
-		}
-		amount = a
+		return "", consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
 	}
 
 	// Convert asset name to ripple currency name
@@ -189,24 +304,23 @@ func delegatedSend(c context.Context, accessKey string, passphrase string, sourc
 		return "", consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
 	}
 
-	// Convert passphrase to ripple secret
-	seed := mneumonic.FromWords(strings.Split(passphrase, " "))
-	secret, err := ripplecrypto.ToSecret(seed.ToHex())
+	rippleOptions, errCode, err := options.toRipple()
 	if err != nil {
-		log.FluentfContext(consts.LOGERROR, c, "Error in ripplecrypto.ToSecret(): %s", err.Error())
-		database.UpdatePaymentWithErrorByPaymentId(c, accessKey, paymentId, "", consts.GenericErrors.InvalidPassphrase.Code, consts.GenericErrors.InvalidPassphrase.Description)
+		log.FluentfContext(consts.LOGERROR, c, "Error in toRipple(): %s", err.Error())
+		database.UpdatePaymentWithErrorByPaymentId(c, accessKey, paymentId, "", errCode, err.Error())
 
-		return "", consts.GenericErrors.InvalidPassphrase.Code, errors.New(consts.GenericErrors.InvalidPassphrase.Description)
+		return "", errCode, err
 	}
 
 	// Create and sign the transaction
-	signedTx, errCode, err := rippleapi.CreatePayment(c, sourceAddress, destinationAddress, amount, currency, issuer, secret)
+	signedTx, txFee, errCode, err := rippleapi.CreatePayment(c, sourceAddress, destinationAddress, amount, currency, issuer, rippleOptions, txSigner)
 	if err != nil {
 		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.CreatePayment(): %s", err.Error())
 		database.UpdatePaymentWithErrorByPaymentId(c, accessKey, paymentId, "", errCode, err.Error())
 
 		return "", errCode, err
 	}
+	database.UpdatePaymentTxFeeByPaymentId(c, accessKey, paymentId, txFee)
 
 	//	 Submit the transaction
 	txHash, errCode, err := rippleapi.Submit(c, signedTx)
@@ -275,17 +389,23 @@ func ActivateAddress(c context.Context, w http.ResponseWriter, r *http.Request,
 		}
 	}
 
-	var passphrase string
-	if m["passphrase"] != nil {
-		passphrase = m["passphrase"].(string)
-	}
-
 	log.FluentfContext(consts.LOGINFO, c, "ActivateAddress: received request address to activate: %s, number of transactions to activate: %d", address, amount)
 	// Generate an activationId
 	activationId := enulib.GenerateActivationId()
 
 	log.FluentfContext(consts.LOGINFO, c, "Generated activationId: %s", activationId)
 
+	// Trust lines are signed by the address being activated so a signer is only needed if the passphrase or walletId is given
+	var txSigner signer.Signer
+	if m["passphrase"] != nil || m["walletId"] != nil {
+		var ok bool
+
+		txSigner, ok = signerFromRequest(c, w, m, address, activationId)
+		if !ok {
+			return nil
+		}
+	}
+
 	// Return to the client the activationId and requestId and unblock the client
 	var result = map[string]interface{}{
 		"address":       address,
@@ -306,14 +426,14 @@ func ActivateAddress(c context.Context, w http.ResponseWriter, r *http.Request,
 		return nil
 	}
 
-	go delegatedActivateAddress(c, address, passphrase, amount, assets, activationId)
+	go delegatedActivateAddress(c, address, txSigner, amount, assets, activationId)
 
 	return nil
 }
 
 // Concurrency safe to activate an an address.
 // Trust lines can be added by specifying a slice of rippleapi.Amounts
-func delegatedActivateAddress(c context.Context, addressToActivate string, passphrase string, amount uint64, assets []rippleapi.Amount, activationId string) (int64, error) {
+func delegatedActivateAddress(c context.Context, addressToActivate string, txSigner signer.Signer, amount uint64, assets []rippleapi.Amount, activationId string) (int64, error) {
 	var complete bool = false
 	var linesRequired []rippleapi.Amount
 	var numLinesRequired = 0
@@ -325,7 +445,7 @@ func delegatedActivateAddress(c context.Context, addressToActivate string, passp
 	//	env := c.Value(consts.EnvKey).(string)
 
 	// Write the activation with the generated activation id to the database
-	go database.InsertActivation(c, accessKey, activationId, blockchainId, addressToActivate, 0)
+	database.InsertActivation(c, accessKey, activationId, blockchainId, addressToActivate, amount)
 
 	log.FluentfContext(consts.LOGINFO, c, "Number of trust lines requested: %d", len(assets))
 
@@ -337,6 +457,7 @@ func delegatedActivateAddress(c context.Context, addressToActivate string, passp
 		accountInfo, _, err := rippleapi.GetAccountInfo(c, addressToActivate)
 		if err != nil {
 			log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.GetAccountInfo(): %s", err.Error())
+			database.UpdateActivationWithError(c, accessKey, activationId, consts.RippleErrors.MiscError.Code, err.Error())
 			return 0, nil
 		}
 
@@ -356,6 +477,7 @@ func delegatedActivateAddress(c context.Context, addressToActivate string, passp
 		lines, _, err := rippleapi.GetAccountLines(c, addressToActivate)
 		if err != nil {
 			log.FluentfContext(consts.LOGERROR, c, "Error in GetAccountLines(): %s", err.Error())
+			database.UpdateActivationWithError(c, accessKey, activationId, consts.RippleErrors.MiscError.Code, err.Error())
 			return 0, nil
 		}
 
@@ -396,6 +518,7 @@ func delegatedActivateAddress(c context.Context, addressToActivate string, passp
 		txXRPAmount, _, err := rippleapi.CalculateFeeAmount(c, amount)
 		if err != nil {
 			log.FluentfContext(consts.LOGERROR, c, "Error in CalculateFeeAmount(): %s", err.Error())
+			database.UpdateActivationWithError(c, accessKey, activationId, consts.RippleErrors.MiscError.Code, consts.RippleErrors.MiscError.Description)
 			return consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
 		}
 		amountXRPToSend += txXRPAmount
@@ -403,21 +526,26 @@ func delegatedActivateAddress(c context.Context, addressToActivate string, passp
 		log.FluentfContext(consts.LOGINFO, c, "XRP for %d transactions txXRPAmount: %d", amount, txXRPAmount)
 		log.FluentfContext(consts.LOGINFO, c, "XRP that we need to send from our master wallet: %d", amountXRPToSend)
 
-		// Pick a random internal address to send from
-		rand.Seed(time.Now().Unix()) // initialize global pseudo random generator
-		rand.Intn(len(rippleapi.RippleWallets))
-		var randomNumber int = rand.Intn(len(rippleapi.RippleWallets))
+		// Pick a fuel wallet to send from
+		fuelWallet, fuelSigner, errorCode, err := fuel.Select(c, blockchainId, amountXRPToSend)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in fuel.Select(): %s", err.Error())
+			database.UpdateActivationWithError(c, accessKey, activationId, errorCode, err.Error())
+			return errorCode, err
+		}
 
 		// todo - Activation should specify a value
 
 		// Send the xrp - note that XRP must be specified in satoshis so we multiply by 100
-		_, errorCode, err := delegatedSend(c, accessKey, rippleapi.RippleWallets[randomNumber].Passphrase, rippleapi.RippleWallets[randomNumber].Address, addressToActivate, "XRP", "", amountXRPToSend*100, activationId, "")
+		_, errorCode, err = delegatedSend(c, accessKey, fuelSigner, fuelWallet.Address, addressToActivate, "XRP", "", amountXRPToSend*100, activationId, "", paymentOptions{})
 		if err != nil {
 			log.FluentfContext(consts.LOGERROR, c, "Error in delegatedSend(): %s", err.Error())
+			fuel.Release(c, blockchainId, fuelWallet.Address, 0)
 			// Don't update the payment because delegatedSend() already does this
 			//database.UpdatePaymentWithErrorByPaymentId(c, accessKey, activationId, consts.RippleErrors.MiscError.Code, consts.RippleErrors.MiscError.Description)
 			return errorCode, err
 		}
+		fuel.Release(c, blockchainId, fuelWallet.Address, amountXRPToSend)
 
 		complete = true
 
@@ -438,6 +566,11 @@ func delegatedActivateAddress(c context.Context, addressToActivate string, passp
 		log.FluentfContext(consts.LOGERROR, c, "Wait complete")
 	}
 
+	if txSigner == nil && len(linesRequired) > 0 {
+		log.FluentfContext(consts.LOGERROR, c, "No passphrase or walletId was given for %s. Skipping creation of %d trust lines", addressToActivate, len(linesRequired))
+		linesRequired = nil
+	}
+
 	// For each trustline which doesn't already exist, create it
 	for _, line := range linesRequired {
 		database.InsertTrustAsset(c, accessKey, activationId, blockchainId, line.Currency, line.Issuer, rippleapi.DefaultAmountToTrust)
@@ -446,6 +579,7 @@ func delegatedActivateAddress(c context.Context, addressToActivate string, passp
 		rippleAmount, err := rippleapi.Uint64ToAmount(rippleapi.DefaultAmountToTrust)
 		if err != nil {
 			log.FluentfContext(consts.LOGERROR, c, "Error in Uint64ToAmount(): %s", err.Error())
+			database.UpdateTrustAsset(c, accessKey, activationId, line.Currency, line.Issuer, "error", "", consts.RippleErrors.MiscError.Code, consts.RippleErrors.MiscError.Description)
 			return consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
 		}
 
@@ -453,20 +587,19 @@ func delegatedActivateAddress(c context.Context, addressToActivate string, passp
 		currency, err := rippleapi.ToCurrency(line.Currency)
 		if err != nil {
 			log.FluentfContext(consts.LOGERROR, c, "Error in Uint64ToAmount(): %s", err.Error())
+			database.UpdateTrustAsset(c, accessKey, activationId, line.Currency, line.Issuer, "error", "", consts.RippleErrors.InvalidCurrency.Code, consts.RippleErrors.InvalidCurrency.Description)
 			return consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
 		}
 
 		log.FluentfContext(consts.LOGINFO, c, "Creating trust line: currency=%s->%s, issuer=%s, amountToTrust=%d", line.Currency, currency, line.Issuer, rippleapi.DefaultAmountToTrust)
 
-		// Convert passphrase to ripple secret
-		seed := mneumonic.FromWords(strings.Split(passphrase, " "))
-		secret, err := ripplecrypto.ToSecret(seed.ToHex())
+		txHash, errorCode, err := rippleapi.TrustSet(c, addressToActivate, currency, rippleAmount, line.Issuer, 0, txSigner)
 		if err != nil {
-			log.FluentfContext(consts.LOGERROR, c, "Error in ripplecrypto.ToSecret(): %s", err.Error())
-			return consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+			log.FluentfContext(consts.LOGERROR, c, "Error in TrustSet(): %s", err.Error())
+			database.UpdateTrustAsset(c, accessKey, activationId, line.Currency, line.Issuer, "error", txHash, errorCode, err.Error())
+		} else {
+			database.UpdateTrustAsset(c, accessKey, activationId, line.Currency, line.Issuer, "complete", txHash, 0, "")
 		}
-
-		_, _, err = rippleapi.TrustSet(c, addressToActivate, currency, rippleAmount, line.Issuer, 0, secret)
 	}
 
 	log.FluentfContext(consts.LOGINFO, c, "delegatedActivateAddress() complete")
@@ -475,6 +608,21 @@ func delegatedActivateAddress(c context.Context, addressToActivate string, passp
 
 }
 
+// Returns the number of transactions the XRP balance of the address above its reserve can pay for
+func NumberOfTransactions(c context.Context, address string) (uint64, error) {
+	balances, _, err := getAddressBalances(c, address)
+	if err != nil {
+		return 0, err
+	}
+
+	return balances.NumberOfTransactions, nil
+}
+
+// Sends the address enough XRP for the amount of transactions using the activation flow. Used by automatic top ups
+func TopUpAddress(c context.Context, address string, amount uint64, activationId string) (int64, error) {
+	return delegatedActivateAddress(c, address, nil, amount, nil, activationId)
+}
+
 func WalletBalance(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
 	var xrpBalance uint64
 	var walletbalance enulib.AddressBalances
@@ -0,0 +1,289 @@
+package ripplehandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/handlers"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/ripplecrypto"
+	"github.com/vennd/enu/ripplewatch"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Registers a list of Ripple accounts as a watch only wallet
+func WatchWalletCreate(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var wallet enulib.WatchWallet
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	wallet.RequestId = requestId
+
+	seen := make(map[string]bool)
+	for _, a := range m["addresses"].([]interface{}) {
+		address := a.(string)
+
+		if _, err := ripplecrypto.DecodeAccountId(address); err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in DecodeAccountId(): %s", err.Error())
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.InvalidAddress.Code, consts.GenericErrors.InvalidAddress.Description)
+
+			return nil
+		}
+
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+
+		wallet.Addresses = append(wallet.Addresses, address)
+	}
+
+	wallet.WalletId = enulib.GenerateWalletId()
+	wallet.BlockchainId = consts.RippleBlockchainId
+	wallet.Status = "valid"
+
+	err := database.InsertWatchWallet(c, c.Value(consts.AccessKeyKey).(string), wallet)
+	if err != nil {
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "Created watch only wallet: %s with %d addresses for access key: %s", wallet.WalletId, len(wallet.Addresses), c.Value(consts.AccessKeyKey).(string))
+
+	// Record the payments the addresses receive from now on
+	ripplewatch.Watch(c.Value(consts.AccessKeyKey).(string), wallet.Addresses)
+
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(wallet); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Returns the balances of each account of the watch only wallet and the total of each currency and issuer across the wallet
+func WatchWalletBalance(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var walletbalance enulib.WatchWalletBalances
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	walletbalance.RequestId = requestId
+
+	wallet, ok := handlers.GetWatchWallet(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "WatchWalletBalance: received request walletId: %s from accessKey: %s\n", wallet.WalletId, c.Value(consts.AccessKeyKey).(string))
+
+	walletbalance.WalletId = wallet.WalletId
+	walletbalance.BlockchainId = consts.RippleBlockchainId
+
+	// Totals are kept per currency and issuer as the same currency from different issuers is a different asset
+	totals := make(map[enulib.Amount]uint64)
+	var keys []enulib.Amount
+	for _, address := range wallet.Addresses {
+		addressbalance, errorCode, err := getAddressBalances(c, address)
+		if err != nil {
+			handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+			return nil
+		}
+
+		walletbalance.NumberOfTransactions += addressbalance.NumberOfTransactions
+
+		for _, balance := range addressbalance.Balances {
+			key := enulib.Amount{Asset: balance.Asset, Issuer: balance.Issuer}
+			if _, ok := totals[key]; !ok {
+				keys = append(keys, key)
+			}
+			totals[key] += balance.Quantity
+		}
+
+		walletbalance.Addresses = append(walletbalance.Addresses, addressbalance)
+	}
+
+	for _, key := range keys {
+		walletbalance.Balances = append(walletbalance.Balances, enulib.Amount{Asset: key.Asset, Issuer: key.Issuer, Quantity: totals[key]})
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(walletbalance); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Returns the balances of the address in the Enu API denomination and the number of transactions the XRP above the
+// reserve will pay for
+func getAddressBalances(c context.Context, address string) (enulib.AddressBalances, int64, error) {
+	var xrpBalance uint64
+	var addressbalance enulib.AddressBalances
+
+	addressbalance.Address = address
+	addressbalance.BlockchainId = consts.RippleBlockchainId
+
+	result, errorCode, err := rippleapi.GetAccountBalances(c, address)
+	if err != nil {
+		return addressbalance, errorCode, err
+	}
+
+	for _, item := range result {
+		asset, err := rippleapi.FromCurrency(item.Currency)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.FromCurrency(): %s", err.Error())
+			return addressbalance, consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
+		}
+
+		quantity, err := rippleapi.AmountToUint64(item.Value)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.AmountToUint64(): %s", err.Error())
+			return addressbalance, consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
+		}
+
+		if strings.ToUpper(asset) == "XRP" {
+			xrpBalance = quantity
+		}
+
+		addressbalance.Balances = append(addressbalance.Balances, enulib.Amount{Asset: asset, Issuer: item.Counterparty, Quantity: quantity})
+	}
+
+	lines, errorCode, err := rippleapi.GetAccountLines(c, address)
+	if err != nil {
+		return addressbalance, errorCode, err
+	}
+
+	reserveRequired := rippleapi.CalculateReserve(c, uint64(len(lines)))
+	if xrpBalance > reserveRequired {
+		numberOfTransactions, err := rippleapi.CalculateNumberOfTransactions(c, xrpBalance-reserveRequired)
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Unable to calculate number of transactions: %s", err.Error())
+		}
+		addressbalance.NumberOfTransactions = numberOfTransactions
+	}
+
+	return addressbalance, 0, nil
+}
+
+// Composes an unsigned payment from the watch only wallet so it can be signed offline by the holder of the keys.
+// The Sequence, Fee and LastLedgerSequence are filled. If no sourceAddress is given the first account of the wallet
+// holding enough of the asset is used.
+func WatchWalletCompose(c context.Context, w http.ResponseWriter, r *http.Request, m map[string]interface{}) *enulib.AppError {
+	var compose enulib.WatchWalletCompose
+
+	requestId := c.Value(consts.RequestIdKey).(string)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	compose.RequestId = requestId
+
+	wallet, ok := handlers.GetWatchWallet(c, w, r)
+	if !ok {
+		return nil
+	}
+
+	compose.WalletId = wallet.WalletId
+	compose.BlockchainId = consts.RippleBlockchainId
+	compose.DestinationAddress = m["destinationAddress"].(string)
+	compose.Asset = m["asset"].(string)
+	compose.Quantity = uint64(m["quantity"].(float64))
+	if m["issuer"] != nil {
+		compose.Issuer = m["issuer"].(string)
+	}
+	if m["sourceAddress"] != nil {
+		compose.SourceAddress = m["sourceAddress"].(string)
+	}
+
+	if strings.ToUpper(compose.Asset) != "XRP" && compose.Issuer == "" {
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.IssuerMustBeGiven.Code, consts.RippleErrors.IssuerMustBeGiven.Description)
+
+		return nil
+	}
+
+	log.FluentfContext(consts.LOGINFO, c, "WatchWalletCompose: received request walletId: %s, sourceAddress: %s, destinationAddress: %s, asset: %s, issuer: %s, quantity: %d from accessKey: %s\n", wallet.WalletId, compose.SourceAddress, compose.DestinationAddress, compose.Asset, compose.Issuer, compose.Quantity, c.Value(consts.AccessKeyKey).(string))
+
+	found := false
+	for _, address := range wallet.Addresses {
+		if compose.SourceAddress != "" {
+			if address == compose.SourceAddress {
+				found = true
+				break
+			}
+			continue
+		}
+
+		addressbalance, errorCode, err := getAddressBalances(c, address)
+		if err != nil {
+			handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+			return nil
+		}
+
+		for _, balance := range addressbalance.Balances {
+			if strings.ToUpper(balance.Asset) == strings.ToUpper(compose.Asset) && (compose.Issuer == "" || balance.Issuer == compose.Issuer) && balance.Quantity >= compose.Quantity {
+				found = true
+			}
+		}
+
+		if found {
+			compose.SourceAddress = address
+			break
+		}
+	}
+
+	if !found {
+		if compose.SourceAddress != "" {
+			handlers.ReturnBadRequest(c, w, consts.GenericErrors.AddressNotInWallet.Code, consts.GenericErrors.AddressNotInWallet.Description)
+		} else {
+			handlers.ReturnUnprocessableEntity(c, w, consts.RippleErrors.InsufficientFunds.Code, errors.New(consts.RippleErrors.InsufficientFunds.Description))
+		}
+
+		return nil
+	}
+
+	amount, err := toRippleAmount(compose.Asset, compose.Quantity)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in toRippleAmount(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidAmount.Code, consts.RippleErrors.InvalidAmount.Description)
+
+		return nil
+	}
+
+	currency, err := rippleapi.ToCurrency(compose.Asset)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.ToCurrency(): %s", err.Error())
+		handlers.ReturnBadRequest(c, w, consts.RippleErrors.InvalidCurrency.Code, consts.RippleErrors.InvalidCurrency.Description)
+
+		return nil
+	}
+
+	unsignedTx, errorCode, err := rippleapi.ComposePayment(c, compose.SourceAddress, compose.DestinationAddress, amount, currency, compose.Issuer, rippleapi.PaymentOptions{})
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in rippleapi.ComposePayment(): %s", err.Error())
+		handlers.ReturnServerErrorWithCustomError(c, w, errorCode, err.Error())
+
+		return nil
+	}
+	compose.UnsignedTx = unsignedTx
+
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(compose); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in Encode(): %s", err.Error())
+		handlers.ReturnServerError(c, w)
+
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+// Package ripplewatch records the payments received by the addresses of Ripple watch only wallets.
+//
+// The addresses are followed on the rippled WebSocket stream, which is configured with rippleWebsocketHost in
+// enuapi.json. Each validated payment to a watched address is recorded as a payment of every access key watching the
+// address, unless the access key already has the payment because it was made through the API. Payments received
+// while the stream was disconnected are recorded once it reconnects.
+package ripplewatch
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// The access keys watching each address
+var watched = struct {
+	sync.Mutex
+	m map[string][]string
+}{m: make(map[string][]string)}
+
+var isInit bool = false
+
+// Follows the addresses of every Ripple watch only wallet and records the payments they receive
+func Init() {
+	if isInit == true {
+		return
+	}
+	isInit = true
+
+	rippleapi.OnValidatedTransaction(paymentReceived)
+
+	addresses, err := database.GetWatchedAddresses(context.TODO(), consts.RippleBlockchainId)
+	if err != nil {
+		log.Printf("Unable to load the addresses of Ripple watch only wallets: %s", err.Error())
+		return
+	}
+
+	for address, accessKeys := range addresses {
+		for _, accessKey := range accessKeys {
+			Watch(accessKey, []string{address})
+		}
+	}
+}
+
+// Starts recording the payments received by the addresses for the access key
+func Watch(accessKey string, addresses []string) {
+	for _, address := range addresses {
+		if addWatcher(accessKey, address) {
+			rippleapi.WatchAccount(address)
+		}
+	}
+}
+
+// Returns true if the address wasn't watched before
+func addWatcher(accessKey string, address string) bool {
+	watched.Lock()
+	defer watched.Unlock()
+
+	for _, a := range watched.m[address] {
+		if a == accessKey {
+			return false
+		}
+	}
+
+	watched.m[address] = append(watched.m[address], accessKey)
+
+	return len(watched.m[address]) == 1
+}
+
+func watchers(address string) []string {
+	watched.Lock()
+	defer watched.Unlock()
+
+	result := make([]string, len(watched.m[address]))
+	copy(result, watched.m[address])
+
+	return result
+}
+
+// Records a successful payment to a watched address for each access key watching it
+func paymentReceived(c context.Context, tx rippleapi.AccountTransaction) {
+	if tx.TransactionType != "Payment" || tx.TransactionResult != "tesSUCCESS" {
+		return
+	}
+
+	accessKeys := watchers(tx.Destination)
+	if len(accessKeys) == 0 {
+		return
+	}
+
+	// The database panics on failures which would otherwise stop the stream
+	defer func() {
+		if r := recover(); r != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Unable to record payment %s received by %s: %v", tx.Hash, tx.Destination, r)
+		}
+	}()
+
+	amount := rippleapi.ToEnuAmount(c, tx.Amount)
+	deliveredAmount := rippleapi.ToEnuAmount(c, tx.DeliveredAmount).Quantity
+	fee, _ := strconv.ParseUint(tx.Fee, 10, 64)
+
+	var memos []enulib.RippleMemo
+	for _, memo := range tx.Memos {
+		memoType, memoFormat, memoData := rippleapi.DecodeMemo(memo)
+
+		memos = append(memos, enulib.RippleMemo{Type: memoType, Format: memoFormat, Data: memoData})
+	}
+
+	for _, accessKey := range accessKeys {
+		exists, err := database.PaymentExistsByBroadcastTxId(c, accessKey, tx.Hash)
+		if err != nil || exists {
+			continue
+		}
+
+		paymentId := enulib.GeneratePaymentId()
+
+		database.InsertPayment(c, accessKey, int64(tx.LedgerIndex), consts.RippleBlockchainId, paymentId, tx.Account, tx.Destination, amount.Asset, amount.Issuer, amount.Quantity, "valid", 0, fee, "")
+		database.UpdatePaymentCompleteByPaymentId(c, accessKey, paymentId, tx.Hash)
+		database.UpdatePaymentDeliveredAmountByPaymentId(c, accessKey, paymentId, deliveredAmount)
+
+		if tx.DestinationTag != nil || tx.SourceTag != nil || len(memos) > 0 {
+			database.UpdatePaymentOptionsByPaymentId(c, accessKey, paymentId, tx.DestinationTag, tx.SourceTag, memos)
+		}
+
+		log.FluentfContext(consts.LOGINFO, c, "Recorded payment %s of %d %s received by %s in tx %s for access key %s", paymentId, deliveredAmount, amount.Asset, tx.Destination, tx.Hash, accessKey)
+	}
+}
@@ -0,0 +1,23 @@
+package ripplewatch
+
+import (
+	"testing"
+)
+
+func TestAddWatcher(t *testing.T) {
+	if !addWatcher("key1", "rWatched") {
+		t.Errorf("Expected the first watcher to follow the address\n")
+	}
+
+	if addWatcher("key1", "rWatched") {
+		t.Errorf("Expected the same access key to be added only once\n")
+	}
+
+	if addWatcher("key2", "rWatched") {
+		t.Errorf("Expected a second watcher not to follow the address again\n")
+	}
+
+	if accessKeys := watchers("rWatched"); len(accessKeys) != 2 {
+		t.Errorf("Expected 2 watchers, got: %v\n", accessKeys)
+	}
+}
@@ -31,6 +31,39 @@ func NewRouter() *mux.Router {
 	router.Handle("/wallet/payment", ctxHandler(WalletSend)).Methods("POST")
 	router.Handle("/wallet/payment/{paymentId}", ctxHandler(GetPayment)).Methods("GET")
 	router.Handle("/wallet/activate/address/{address}", ctxHandler(ActivateAddress)).Methods("POST")
+	router.Handle("/wallet/activate/{activationId}", ctxHandler(GetActivation)).Methods("GET")
+	router.Handle("/wallet/activations", ctxHandler(GetActivationsByAddress)).Methods("GET")
+	router.Handle("/wallet/topup", ctxHandler(TopUpPolicyCreate)).Methods("POST")
+	router.Handle("/wallet/topup/cap", ctxHandler(GetTopUpCap)).Methods("GET")
+	router.Handle("/wallet/topup/cap", ctxHandler(TopUpCapSet)).Methods("POST")
+	router.Handle("/wallet/topup/{address}", ctxHandler(GetTopUpPolicy)).Methods("GET")
+	router.Handle("/wallet/topup/{address}/disable", ctxHandler(TopUpPolicyDisable)).Methods("POST")
+	router.Handle("/wallet/discover", ctxHandler(WalletDiscover)).Methods("POST")
+	router.Handle("/wallet/address/next", ctxHandler(WalletNextAddress)).Methods("POST")
+	router.Handle("/wallet/sign-message", ctxHandler(SignMessage)).Methods("POST")
+	router.Handle("/wallet/verify-message", ctxHandler(VerifyMessage)).Methods("POST")
+	router.Handle("/wallet/keystore/{walletId}", ctxHandler(GetKeystoreWallet)).Methods("GET")
+	router.Handle("/wallet/keystore/{walletId}/unlock", ctxHandler(KeystoreUnlock)).Methods("POST")
+	router.Handle("/wallet/keystore/{walletId}/lock", ctxHandler(KeystoreLock)).Methods("POST")
+	router.Handle("/wallet/keystore/{walletId}/policy", ctxHandler(KeystorePolicy)).Methods("POST")
+	router.Handle("/wallet/keystore/{walletId}/export", ctxHandler(KeystoreExport)).Methods("POST")
+	router.Handle("/wallet/keystore/{walletId}/audit", ctxHandler(GetKeystoreAudit)).Methods("GET")
+	router.Handle("/wallet/watch", ctxHandler(WatchWalletCreate)).Methods("POST")
+	router.Handle("/wallet/watch/{walletId}", ctxHandler(GetWatchWallet)).Methods("GET")
+	router.Handle("/wallet/watch/{walletId}/balances", ctxHandler(WatchWalletBalance)).Methods("GET")
+	router.Handle("/wallet/watch/{walletId}/payments", ctxHandler(WatchWalletPayments)).Methods("GET")
+	router.Handle("/wallet/watch/{walletId}/compose", ctxHandler(WatchWalletCompose)).Methods("POST")
+
+	router.Handle("/fuel/status", ctxHandler(GetFuelStatus)).Methods("GET")
+	router.Handle("/fuel/wallet/{address}/lock", ctxHandler(FuelWalletLock)).Methods("POST")
+	router.Handle("/fuel/wallet/{address}/unlock", ctxHandler(FuelWalletUnlock)).Methods("POST")
+
+	router.Handle("/multisig/wallet", ctxHandler(MultisigWalletCreate)).Methods("POST")
+	router.Handle("/multisig/wallet/{walletId}", ctxHandler(GetMultisigWallet)).Methods("GET")
+	router.Handle("/multisig/proposal", ctxHandler(MultisigProposalCreate)).Methods("POST")
+	router.Handle("/multisig/proposal/{proposalId}", ctxHandler(GetMultisigProposal)).Methods("GET")
+	router.Handle("/multisig/proposal/{proposalId}/signature", ctxHandler(MultisigProposalSign)).Methods("POST")
+	router.Handle("/multisig/proposal/{proposalId}/finalise", ctxHandler(MultisigProposalFinalise)).Methods("POST")
 
 	// Direct access to Counterparty resources
 	router.Handle("/counterparty/asset", ctxHandler(AssetCreate)).Methods("POST")
@@ -44,10 +77,74 @@ func NewRouter() *mux.Router {
 	router.Handle("/counterparty/wallet/payment", ctxHandler(WalletSend)).Methods("POST")
 	router.Handle("/counterparty/wallet/payment/{paymentId}", ctxHandler(GetPayment)).Methods("GET")
 	router.Handle("/counterparty/wallet/activate/address/{address}", ctxHandler(ActivateAddress)).Methods("POST")
+	router.Handle("/counterparty/wallet/activate/{activationId}", ctxHandler(GetActivation)).Methods("GET")
+	router.Handle("/counterparty/wallet/activations", ctxHandler(GetActivationsByAddress)).Methods("GET")
+	router.Handle("/counterparty/wallet/topup", ctxHandler(TopUpPolicyCreate)).Methods("POST")
+	router.Handle("/counterparty/wallet/topup/cap", ctxHandler(GetTopUpCap)).Methods("GET")
+	router.Handle("/counterparty/wallet/topup/cap", ctxHandler(TopUpCapSet)).Methods("POST")
+	router.Handle("/counterparty/wallet/topup/{address}", ctxHandler(GetTopUpPolicy)).Methods("GET")
+	router.Handle("/counterparty/wallet/topup/{address}/disable", ctxHandler(TopUpPolicyDisable)).Methods("POST")
+	router.Handle("/counterparty/wallet/discover", ctxHandler(WalletDiscover)).Methods("POST")
+	router.Handle("/counterparty/wallet/address/next", ctxHandler(WalletNextAddress)).Methods("POST")
+	router.Handle("/counterparty/wallet/sign-message", ctxHandler(SignMessage)).Methods("POST")
+	router.Handle("/counterparty/wallet/verify-message", ctxHandler(VerifyMessage)).Methods("POST")
+	router.Handle("/counterparty/wallet/watch", ctxHandler(WatchWalletCreate)).Methods("POST")
+	router.Handle("/counterparty/wallet/watch/{walletId}", ctxHandler(GetWatchWallet)).Methods("GET")
+	router.Handle("/counterparty/wallet/watch/{walletId}/balances", ctxHandler(WatchWalletBalance)).Methods("GET")
+	router.Handle("/counterparty/wallet/watch/{walletId}/payments", ctxHandler(WatchWalletPayments)).Methods("GET")
+	router.Handle("/counterparty/wallet/watch/{walletId}/compose", ctxHandler(WatchWalletCompose)).Methods("POST")
 	router.Handle("/counterparty/payment/address/{address}", ctxHandler(GetPaymentsByAddress)).Methods("GET")
+	router.Handle("/counterparty/fuel/status", ctxHandler(GetFuelStatus)).Methods("GET")
+	router.Handle("/counterparty/fuel/wallet/{address}/lock", ctxHandler(FuelWalletLock)).Methods("POST")
+	router.Handle("/counterparty/fuel/wallet/{address}/unlock", ctxHandler(FuelWalletUnlock)).Methods("POST")
+	router.Handle("/counterparty/multisig/wallet", ctxHandler(MultisigWalletCreate)).Methods("POST")
+	router.Handle("/counterparty/multisig/wallet/{walletId}", ctxHandler(GetMultisigWallet)).Methods("GET")
+	router.Handle("/counterparty/multisig/proposal", ctxHandler(MultisigProposalCreate)).Methods("POST")
+	router.Handle("/counterparty/multisig/proposal/{proposalId}", ctxHandler(GetMultisigProposal)).Methods("GET")
+	router.Handle("/counterparty/multisig/proposal/{proposalId}/signature", ctxHandler(MultisigProposalSign)).Methods("POST")
+	router.Handle("/counterparty/multisig/proposal/{proposalId}/finalise", ctxHandler(MultisigProposalFinalise)).Methods("POST")
 
 	// Direct access to Ripple resources
 	router.Handle("/ripple/ledger/status", ctxHandler(GetRippleLedgerStatus)).Methods("GET")
+	router.Handle("/ripple/asset/issuances/{asset}", ctxHandler(AssetIssuances)).Methods("GET")
+	router.Handle("/ripple/asset/ledger/{asset}", ctxHandler(AssetLedger)).Methods("GET")
+	router.Handle("/ripple/fuel/status", ctxHandler(GetFuelStatus)).Methods("GET")
+	router.Handle("/ripple/fuel/wallet/{address}/lock", ctxHandler(FuelWalletLock)).Methods("POST")
+	router.Handle("/ripple/fuel/wallet/{address}/unlock", ctxHandler(FuelWalletUnlock)).Methods("POST")
+	router.Handle("/ripple/trustline", ctxHandler(TrustLineSet)).Methods("POST")
+	router.Handle("/ripple/trustline/remove", ctxHandler(TrustLineRemove)).Methods("POST")
+	router.Handle("/ripple/trustline/{address}", ctxHandler(GetTrustLines)).Methods("GET")
+	router.Handle("/ripple/account/settings", ctxHandler(GetAccountSettings)).Methods("GET")
+	router.Handle("/ripple/account/settings", ctxHandler(AccountSettingsSet)).Methods("POST")
+	router.Handle("/ripple/offer", ctxHandler(OfferCreate)).Methods("POST")
+	router.Handle("/ripple/offer", ctxHandler(GetOffers)).Methods("GET")
+	router.Handle("/ripple/offer/{offerId}", ctxHandler(GetOffer)).Methods("GET")
+	router.Handle("/ripple/offer/{offerId}/cancel", ctxHandler(OfferCancel)).Methods("POST")
+	router.Handle("/ripple/orderbook", ctxHandler(GetOrderBook)).Methods("GET")
+	router.Handle("/ripple/escrow", ctxHandler(EscrowCreate)).Methods("POST")
+	router.Handle("/ripple/escrow", ctxHandler(GetEscrows)).Methods("GET")
+	router.Handle("/ripple/escrow/{escrowId}", ctxHandler(GetEscrow)).Methods("GET")
+	router.Handle("/ripple/escrow/{escrowId}/finish", ctxHandler(EscrowFinish)).Methods("POST")
+	router.Handle("/ripple/escrow/{escrowId}/cancel", ctxHandler(EscrowCancel)).Methods("POST")
+	router.Handle("/ripple/channel", ctxHandler(ChannelCreate)).Methods("POST")
+	router.Handle("/ripple/channel", ctxHandler(GetChannels)).Methods("GET")
+	router.Handle("/ripple/channel/verify", ctxHandler(ChannelVerify)).Methods("POST")
+	router.Handle("/ripple/channel/{channelId}", ctxHandler(GetChannel)).Methods("GET")
+	router.Handle("/ripple/channel/{channelId}/fund", ctxHandler(ChannelFund)).Methods("POST")
+	router.Handle("/ripple/channel/{channelId}/authorise", ctxHandler(ChannelAuthorise)).Methods("POST")
+	router.Handle("/ripple/channel/{channelId}/claim", ctxHandler(ChannelClaim)).Methods("POST")
+	router.Handle("/ripple/multisig/wallet", ctxHandler(MultisigWalletCreate)).Methods("POST")
+	router.Handle("/ripple/multisig/wallet/{walletId}", ctxHandler(GetMultisigWallet)).Methods("GET")
+	router.Handle("/ripple/multisig/proposal", ctxHandler(MultisigProposalCreate)).Methods("POST")
+	router.Handle("/ripple/multisig/proposal/{proposalId}", ctxHandler(GetMultisigProposal)).Methods("GET")
+	router.Handle("/ripple/multisig/proposal/{proposalId}/signature", ctxHandler(MultisigProposalSign)).Methods("POST")
+	router.Handle("/ripple/multisig/proposal/{proposalId}/finalise", ctxHandler(MultisigProposalFinalise)).Methods("POST")
+	router.Handle("/ripple/check", ctxHandler(CheckCreate)).Methods("POST")
+	router.Handle("/ripple/check", ctxHandler(GetChecks)).Methods("GET")
+	router.Handle("/ripple/check/{checkId}", ctxHandler(GetCheck)).Methods("GET")
+	router.Handle("/ripple/check/{checkId}/cash", ctxHandler(CheckCash)).Methods("POST")
+	router.Handle("/ripple/check/{checkId}/cancel", ctxHandler(CheckCancel)).Methods("POST")
+	router.Handle("/ripple/account/{address}/transactions", ctxHandler(GetAccountTransactions)).Methods("GET")
 
 	router.Handle("/blocks", ctxHandler(GetBlocks)).Methods("GET")
 	return router
@@ -0,0 +1,91 @@
+package signer
+
+import (
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/keystore"
+	"github.com/vennd/enu/ripplecrypto"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Operation recorded in the keystore audit trail when a public key is retrieved to compose a transaction
+const AuditOperationPublicKey = "publicKey"
+
+// Signs with a custodial wallet. The passphrase is retrieved from the keystore for each operation so every signing is
+// checked against the wallet's unlock policy and recorded in the audit trail against the request type and reference.
+type KeystoreSigner struct {
+	accessKey string
+	walletId  string
+	reference string
+}
+
+func NewKeystoreSigner(accessKey string, walletId string, reference string) *KeystoreSigner {
+	return &KeystoreSigner{accessKey: accessKey, walletId: walletId, reference: reference}
+}
+
+func (s *KeystoreSigner) passphraseSigner(c context.Context, operation string, address string) (*PassphraseSigner, int64, error) {
+	if operation == "" && c.Value(consts.RequestTypeKey) != nil {
+		operation = c.Value(consts.RequestTypeKey).(string)
+	}
+
+	passphrase, errorCode, err := keystore.GetPassphrase(c, s.accessKey, s.walletId, operation, address, s.reference)
+	if err != nil {
+		return nil, errorCode, err
+	}
+
+	return NewPassphraseSigner(passphrase), 0, nil
+}
+
+func (s *KeystoreSigner) PublicKey(c context.Context, address string) (string, error) {
+	p, _, err := s.passphraseSigner(c, AuditOperationPublicKey, address)
+	if err != nil {
+		return "", err
+	}
+
+	return p.PublicKey(c, address)
+}
+
+func (s *KeystoreSigner) SignRawTransaction(c context.Context, address string, rawTxHexString string) (string, error) {
+	p, _, err := s.passphraseSigner(c, "", address)
+	if err != nil {
+		return "", err
+	}
+
+	return p.SignRawTransaction(c, address, rawTxHexString)
+}
+
+func (s *KeystoreSigner) SignRippleTransaction(c context.Context, account string, tx interface{}) (string, int64, error) {
+	p, errorCode, err := s.passphraseSigner(c, "", account)
+	if err != nil {
+		return "", errorCode, err
+	}
+
+	return p.SignRippleTransaction(c, account, tx)
+}
+
+func (s *KeystoreSigner) RipplePublicKey(c context.Context, account string) (string, int64, error) {
+	p, errorCode, err := s.passphraseSigner(c, AuditOperationPublicKey, account)
+	if err != nil {
+		return "", errorCode, err
+	}
+
+	return p.RipplePublicKey(c, account)
+}
+
+func (s *KeystoreSigner) SignRippleClaim(c context.Context, account string, channelId string, drops uint64) (string, string, int64, error) {
+	p, errorCode, err := s.passphraseSigner(c, "", account)
+	if err != nil {
+		return "", "", errorCode, err
+	}
+
+	return p.SignRippleClaim(c, account, channelId, drops)
+}
+
+func (s *KeystoreSigner) MultiSignRippleTransaction(c context.Context, signerAccount string, tx interface{}) (ripplecrypto.MultiSigner, int64, error) {
+	p, errorCode, err := s.passphraseSigner(c, "", signerAccount)
+	if err != nil {
+		return ripplecrypto.MultiSigner{}, errorCode, err
+	}
+
+	return p.MultiSignRippleTransaction(c, signerAccount, tx)
+}
@@ -0,0 +1,99 @@
+package signer
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/counterpartyapi"
+	"github.com/vennd/enu/counterpartycrypto"
+	"github.com/vennd/enu/internal/github.com/vennd/mneumonic"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/rippleapi"
+	"github.com/vennd/enu/ripplecrypto"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Signs in process with the keys derived from the wallet passphrase
+type PassphraseSigner struct {
+	passphrase string
+}
+
+func NewPassphraseSigner(passphrase string) *PassphraseSigner {
+	return &PassphraseSigner{passphrase: passphrase}
+}
+
+func (s *PassphraseSigner) PublicKey(c context.Context, address string) (string, error) {
+	return counterpartycrypto.GetPublicKey(s.passphrase, address)
+}
+
+func (s *PassphraseSigner) SignRawTransaction(c context.Context, address string, rawTxHexString string) (string, error) {
+	return counterpartyapi.SignRawTransaction(c, s.passphrase, rawTxHexString)
+}
+
+// The hex seed of a Ripple wallet is the same as the seed of the passphrase mneumonic
+func (s *PassphraseSigner) rippleSecret(c context.Context) (string, int64, error) {
+	seed := mneumonic.FromWords(strings.Split(s.passphrase, " "))
+	secret, err := ripplecrypto.ToSecret(seed.ToHex())
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in ripplecrypto.ToSecret(): %s", err.Error())
+		return "", consts.GenericErrors.InvalidPassphrase.Code, errors.New(consts.GenericErrors.InvalidPassphrase.Description)
+	}
+
+	return secret, 0, nil
+}
+
+func (s *PassphraseSigner) SignRippleTransaction(c context.Context, account string, tx interface{}) (string, int64, error) {
+	secret, errorCode, err := s.rippleSecret(c)
+	if err != nil {
+		return "", errorCode, err
+	}
+
+	return rippleapi.Sign(c, tx, secret)
+}
+
+func (s *PassphraseSigner) RipplePublicKey(c context.Context, account string) (string, int64, error) {
+	secret, errorCode, err := s.rippleSecret(c)
+	if err != nil {
+		return "", errorCode, err
+	}
+
+	publicKey, err := ripplecrypto.PublicKeyFromSecret(secret)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in ripplecrypto.PublicKeyFromSecret(): %s", err.Error())
+		return "", consts.RippleErrors.SigningError.Code, errors.New(consts.RippleErrors.SigningError.Description)
+	}
+
+	return publicKey, 0, nil
+}
+
+func (s *PassphraseSigner) SignRippleClaim(c context.Context, account string, channelId string, drops uint64) (string, string, int64, error) {
+	secret, errorCode, err := s.rippleSecret(c)
+	if err != nil {
+		return "", "", errorCode, err
+	}
+
+	signature, publicKey, err := ripplecrypto.SignClaim(secret, channelId, drops)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in ripplecrypto.SignClaim(): %s", err.Error())
+		return "", "", consts.RippleErrors.SigningError.Code, errors.New(consts.RippleErrors.SigningError.Description)
+	}
+
+	return signature, publicKey, 0, nil
+}
+
+func (s *PassphraseSigner) MultiSignRippleTransaction(c context.Context, signerAccount string, tx interface{}) (ripplecrypto.MultiSigner, int64, error) {
+	secret, errorCode, err := s.rippleSecret(c)
+	if err != nil {
+		return ripplecrypto.MultiSigner{}, errorCode, err
+	}
+
+	signer, err := ripplecrypto.MultiSign(tx, secret, signerAccount)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in ripplecrypto.MultiSign(): %s", err.Error())
+		return signer, consts.RippleErrors.SigningError.Code, errors.New(consts.RippleErrors.SigningError.Description)
+	}
+
+	return signer, 0, nil
+}
@@ -0,0 +1,243 @@
+package signer
+
+// The remote signer protocol allows the keys to be held on an isolated signing server.
+//
+// Requests are HTTP POSTs of a JSON RemoteSignRequest. The body is authenticated with the "Signature" header which is
+// the hex encoded HMAC-SHA512 of the body using the secret shared by Enu and the signing server.
+//
+//   POST /publickey  {"blockchainId":"counterparty","address":"1..."}                    -> {"publicKey":"02..."}
+//   POST /sign       {"blockchainId":"counterparty","address":"1...","unsignedTx":"0100..."} -> {"signedTx":"0100..."}
+//   POST /sign       {"blockchainId":"ripple","address":"r...","unsignedTx":{tx_json}}       -> {"signedTx":"1200..."}
+//
+// Errors are returned with a non 200 status and a RemoteSignResponse containing the code and description.
+// NewRemoteSignerHandler() serves the protocol with any Signer so a signing server can be stood up locally for testing.
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+type RemoteSignRequest struct {
+	BlockchainId string          `json:"blockchainId"`
+	Address      string          `json:"address"`
+	UnsignedTx   json.RawMessage `json:"unsignedTx,omitempty"`
+	Operation    string          `json:"operation,omitempty"`
+	RequestId    string          `json:"requestId,omitempty"`
+}
+
+type RemoteSignResponse struct {
+	PublicKey   string `json:"publicKey,omitempty"`
+	SignedTx    string `json:"signedTx,omitempty"`
+	Code        int64  `json:"code,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Timeout for requests to the signing server
+var RemoteSignerTimeout = 30 * time.Second
+
+// Sends transactions to the signing server to be signed
+type RemoteSigner struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewRemoteSigner(url string, secret string) *RemoteSigner {
+	return &RemoteSigner{url: strings.TrimRight(url, "/"), secret: secret, client: &http.Client{Timeout: RemoteSignerTimeout}}
+}
+
+func (s *RemoteSigner) post(c context.Context, path string, request RemoteSignRequest) (RemoteSignResponse, error) {
+	var response RemoteSignResponse
+
+	if c.Value(consts.RequestIdKey) != nil {
+		request.RequestId = c.Value(consts.RequestIdKey).(string)
+	}
+	if c.Value(consts.RequestTypeKey) != nil {
+		request.Operation = c.Value(consts.RequestTypeKey).(string)
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return response, err
+	}
+
+	req, err := http.NewRequest("POST", s.url+path, bytes.NewBuffer(body))
+	if err != nil {
+		return response, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Signature", enulib.ComputeHmac512(body, s.secret))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error contacting remote signer %s: %s", s.url+path, err.Error())
+		return response, err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(io.LimitReader(resp.Body, 512000))
+	if err != nil {
+		return response, err
+	}
+
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Malformed response from remote signer. Status: %d, body: %s", resp.StatusCode, string(responseBody))
+		return response, errors.New("Malformed response from remote signer")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.FluentfContext(consts.LOGERROR, c, "Remote signer returned status: %d, code: %d, description: %s", resp.StatusCode, response.Code, response.Description)
+		return response, errors.New(response.Description)
+	}
+
+	return response, nil
+}
+
+func (s *RemoteSigner) PublicKey(c context.Context, address string) (string, error) {
+	response, err := s.post(c, "/publickey", RemoteSignRequest{BlockchainId: consts.CounterpartyBlockchainId, Address: address})
+	if err != nil {
+		return "", err
+	}
+
+	return response.PublicKey, nil
+}
+
+func (s *RemoteSigner) SignRawTransaction(c context.Context, address string, rawTxHexString string) (string, error) {
+	unsignedTx, err := json.Marshal(rawTxHexString)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := s.post(c, "/sign", RemoteSignRequest{BlockchainId: consts.CounterpartyBlockchainId, Address: address, UnsignedTx: unsignedTx})
+	if err != nil {
+		return "", err
+	}
+
+	return response.SignedTx, nil
+}
+
+func (s *RemoteSigner) SignRippleTransaction(c context.Context, account string, tx interface{}) (string, int64, error) {
+	unsignedTx, err := json.Marshal(tx)
+	if err != nil {
+		return "", consts.RippleErrors.MiscError.Code, errors.New(consts.RippleErrors.MiscError.Description)
+	}
+
+	response, err := s.post(c, "/sign", RemoteSignRequest{BlockchainId: consts.RippleBlockchainId, Address: account, UnsignedTx: unsignedTx})
+	if err != nil {
+		if response.Code != 0 {
+			return "", response.Code, err
+		}
+
+		return "", consts.RippleErrors.SigningError.Code, err
+	}
+
+	return response.SignedTx, 0, nil
+}
+
+// Serves the remote signer protocol using the given signer to sign. Requests not signed with the secret are rejected.
+func NewRemoteSignerHandler(s Signer, secret string) http.Handler {
+	mux := http.NewServeMux()
+
+	handle := func(w http.ResponseWriter, r *http.Request, fn func(context.Context, RemoteSignRequest) (RemoteSignResponse, int64, error)) {
+		var request RemoteSignRequest
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+		if r.Method != "POST" {
+			writeRemoteSignResponse(w, http.StatusMethodNotAllowed, RemoteSignResponse{Code: consts.GenericErrors.FunctionNotAvailable.Code, Description: consts.GenericErrors.FunctionNotAvailable.Description})
+			return
+		}
+
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, 512000))
+		if err != nil {
+			writeRemoteSignResponse(w, http.StatusBadRequest, RemoteSignResponse{Code: consts.GenericErrors.InvalidDocument.Code, Description: consts.GenericErrors.InvalidDocument.Description})
+			return
+		}
+
+		if !hmac.Equal([]byte(enulib.ComputeHmac512(body, secret)), []byte(r.Header.Get("Signature"))) {
+			writeRemoteSignResponse(w, http.StatusForbidden, RemoteSignResponse{Code: consts.GenericErrors.InvalidSignature.Code, Description: consts.GenericErrors.InvalidSignature.Description})
+			return
+		}
+
+		if err := json.Unmarshal(body, &request); err != nil {
+			writeRemoteSignResponse(w, http.StatusBadRequest, RemoteSignResponse{Code: consts.GenericErrors.InvalidDocument.Code, Description: consts.GenericErrors.InvalidDocument.Description})
+			return
+		}
+
+		c := context.WithValue(context.TODO(), consts.RequestIdKey, request.RequestId)
+		c = context.WithValue(c, consts.BlockchainIdKey, request.BlockchainId)
+		c = context.WithValue(c, consts.RequestTypeKey, request.Operation)
+
+		response, errorCode, err := fn(c, request)
+		if err != nil {
+			writeRemoteSignResponse(w, http.StatusBadRequest, RemoteSignResponse{Code: errorCode, Description: err.Error()})
+			return
+		}
+
+		writeRemoteSignResponse(w, http.StatusOK, response)
+	}
+
+	mux.HandleFunc("/publickey", func(w http.ResponseWriter, r *http.Request) {
+		handle(w, r, func(c context.Context, request RemoteSignRequest) (RemoteSignResponse, int64, error) {
+			publicKey, err := s.PublicKey(c, request.Address)
+			if err != nil {
+				return RemoteSignResponse{}, consts.GenericErrors.InvalidAddress.Code, err
+			}
+
+			return RemoteSignResponse{PublicKey: publicKey}, 0, nil
+		})
+	})
+
+	mux.HandleFunc("/sign", func(w http.ResponseWriter, r *http.Request) {
+		handle(w, r, func(c context.Context, request RemoteSignRequest) (RemoteSignResponse, int64, error) {
+			switch request.BlockchainId {
+			case consts.CounterpartyBlockchainId:
+				var rawTxHexString string
+				if err := json.Unmarshal(request.UnsignedTx, &rawTxHexString); err != nil {
+					return RemoteSignResponse{}, consts.GenericErrors.InvalidDocument.Code, err
+				}
+
+				signedTx, err := s.SignRawTransaction(c, request.Address, rawTxHexString)
+				if err != nil {
+					return RemoteSignResponse{}, consts.CounterpartyErrors.SigningError.Code, err
+				}
+
+				return RemoteSignResponse{SignedTx: signedTx}, 0, nil
+			case consts.RippleBlockchainId:
+				var tx interface{}
+				if err := json.Unmarshal(request.UnsignedTx, &tx); err != nil {
+					return RemoteSignResponse{}, consts.GenericErrors.InvalidDocument.Code, err
+				}
+
+				signedTx, errorCode, err := s.SignRippleTransaction(c, request.Address, tx)
+				if err != nil {
+					return RemoteSignResponse{}, errorCode, err
+				}
+
+				return RemoteSignResponse{SignedTx: signedTx}, 0, nil
+			}
+
+			return RemoteSignResponse{}, consts.GenericErrors.UnsupportedBlockchain.Code, errors.New(consts.GenericErrors.UnsupportedBlockchain.Description)
+		})
+	})
+
+	return mux
+}
+
+func writeRemoteSignResponse(w http.ResponseWriter, status int, response RemoteSignResponse) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
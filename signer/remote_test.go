@@ -0,0 +1,82 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vennd/enu/counterpartycrypto"
+	"github.com/vennd/enu/log"
+
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/chaincfg"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/txscript"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcd/wire"
+	"github.com/vennd/enu/internal/github.com/btcsuite/btcutil"
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func init() {
+	// The signers log through Fluent which otherwise requires enuapi.json
+	log.InitWithoutConfig()
+	isInit = true
+}
+
+func TestRemoteSigner(t *testing.T) {
+	c := context.TODO()
+
+	wallet, err := counterpartycrypto.CreateWallet(1)
+	if err != nil {
+		t.Fatalf("Unable to create wallet: %s\n", err.Error())
+	}
+	address := wallet.Addresses[0]
+
+	local := NewPassphraseSigner(wallet.Passphrase)
+	server := httptest.NewServer(NewRemoteSignerHandler(local, "secret"))
+	defer server.Close()
+
+	remote := NewRemoteSigner(server.URL, "secret")
+
+	localPublicKey, err := local.PublicKey(c, address)
+	if err != nil {
+		t.Fatalf("Unable to get public key: %s\n", err.Error())
+	}
+	remotePublicKey, err := remote.PublicKey(c, address)
+	if err != nil {
+		t.Fatalf("Unable to get public key from remote signer: %s\n", err.Error())
+	}
+	if remotePublicKey != localPublicKey {
+		t.Errorf("Expected public key %s, got %s\n", localPublicKey, remotePublicKey)
+	}
+
+	// Build an unsigned transaction the way Counterparty returns it, with the output script being spent in the sigscript
+	decoded, err := btcutil.DecodeAddress(address, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("Unable to decode address: %s\n", err.Error())
+	}
+	pkScript, _ := txscript.PayToAddrScript(decoded)
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&wire.ShaHash{1}, 0), pkScript))
+	tx.AddTxOut(wire.NewTxOut(5430, pkScript))
+	var buf bytes.Buffer
+	tx.BtcEncode(&buf, wire.ProtocolVersion)
+	unsignedTx := hex.EncodeToString(buf.Bytes())
+
+	// Signatures are deterministic (RFC6979) so the remote signer must return exactly the same transaction
+	localSigned, err := local.SignRawTransaction(c, address, unsignedTx)
+	if err != nil {
+		t.Fatalf("Unable to sign: %s\n", err.Error())
+	}
+	remoteSigned, err := remote.SignRawTransaction(c, address, unsignedTx)
+	if err != nil {
+		t.Fatalf("Unable to sign with remote signer: %s\n", err.Error())
+	}
+	if remoteSigned != localSigned {
+		t.Errorf("Expected signed tx %s, got %s\n", localSigned, remoteSigned)
+	}
+
+	// Requests which aren't signed with the shared secret must be rejected
+	if _, err := NewRemoteSigner(server.URL, "wrong secret").PublicKey(c, address); err == nil {
+		t.Errorf("Expected request with an invalid signature to be rejected\n")
+	}
+}
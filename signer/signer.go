@@ -0,0 +1,183 @@
+// Package signer provides the signers used to sign Counterparty and Ripple transactions.
+//
+// Three signers are provided:
+// 1) PassphraseSigner derives the keys in process from the wallet passphrase
+// 2) KeystoreSigner retrieves the passphrase of a custodial wallet from the keystore for each signing operation
+// 3) RemoteSigner sends the unsigned transaction to a signing server which holds the keys, see remote.go
+package signer
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/keystore"
+	"github.com/vennd/enu/ripplecrypto"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+type Signer interface {
+	// Returns the hex encoded compressed public key of the Counterparty address. Required to compose Counterparty transactions
+	PublicKey(c context.Context, address string) (string, error)
+
+	// Signs every input of the Counterparty raw transaction which spends from the address. Returns the signed transaction hex
+	SignRawTransaction(c context.Context, address string, rawTxHexString string) (string, error)
+
+	// Signs the Ripple transaction for the account. Returns the signed tx blob
+	SignRippleTransaction(c context.Context, account string, tx interface{}) (string, int64, error)
+}
+
+// Implemented by the signers which hold the keys in process so can sign Ripple payment channel claims off ledger. The
+// remote signer only signs transactions so doesn't implement it
+type ClaimSigner interface {
+	// Returns the hex encoded public key of the Ripple account. Payment channels are created with the key which signs their claims
+	RipplePublicKey(c context.Context, account string) (string, int64, error)
+
+	// Signs a claim of drops of XRP from the payment channel. Returns the signature and the public key which verifies it
+	SignRippleClaim(c context.Context, account string, channelId string, drops uint64) (string, string, int64, error)
+}
+
+// Implemented by the signers which hold the keys in process so can add the signature of a signer account to a Ripple
+// multisigned transaction
+type MultiSigner interface {
+	// Signs the Ripple transaction as one of the signers of its account. Returns the signer entry of the Signers array
+	MultiSignRippleTransaction(c context.Context, signerAccount string, tx interface{}) (ripplecrypto.MultiSigner, int64, error)
+}
+
+// Signing server used when a request gives neither a passphrase nor a walletId
+var defaultRemoteSigner *RemoteSigner
+
+// Returns true if the access key may sign for the address with the remote signer. Replaced in tests
+var isRemoteSignerAddress = database.IsRemoteSignerAddress
+var isInit bool = false
+
+// Initialises the remote signer from enuapi.json if "remotesignerurl" is configured
+func Init() {
+	var configFilePath string
+
+	if isInit == true {
+		return
+	}
+
+	if _, err := os.Stat("./enuapi.json"); err == nil {
+		log.Println("Found and using configuration file ./enuapi.json")
+		configFilePath = "./enuapi.json"
+	} else {
+		if _, err := os.Stat(os.Getenv("GOPATH") + "/bin/enuapi.json"); err == nil {
+			configFilePath = os.Getenv("GOPATH") + "/bin/enuapi.json"
+			log.Printf("Found and using configuration file from GOPATH: %s\n", configFilePath)
+
+		} else {
+			if _, err := os.Stat(os.Getenv("GOPATH") + "/src/github.com/vennd/enu/enuapi.json"); err == nil {
+				configFilePath = os.Getenv("GOPATH") + "/src/github.com/vennd/enu/enuapi.json"
+				log.Printf("Found and using configuration file from GOPATH: %s\n", configFilePath)
+
+			} else {
+				log.Println("Cannot find enuapi.json")
+				os.Exit(-100)
+			}
+		}
+	}
+
+	InitWithConfigPath(configFilePath)
+}
+
+func InitWithConfigPath(configFilePath string) {
+	var configuration interface{}
+
+	if isInit == true {
+		return
+	}
+
+	// Read configuration from file
+	log.Printf("Reading %s\n", configFilePath)
+	file, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		log.Println("Unable to read configuration file enuapi.json")
+		log.Println(err.Error())
+		os.Exit(-100)
+	}
+
+	err = json.Unmarshal(file, &configuration)
+
+	m := configuration.(map[string]interface{})
+
+	// The remote signer is optional
+	if m["remotesignerurl"] != nil && m["remotesignerurl"].(string) != "" {
+		var secret string
+		if m["remotesignersecret"] != nil {
+			secret = m["remotesignersecret"].(string)
+		}
+
+		defaultRemoteSigner = NewRemoteSigner(m["remotesignerurl"].(string), secret)
+		log.Printf("Using remote signer: %s\n", m["remotesignerurl"].(string))
+	}
+
+	isInit = true
+}
+
+// Returns the signer for the request:
+// 1) If a walletId is given, the custodial wallet is used. The wallet must currently be permitted to sign
+// 2) If a passphrase is given, the keys are derived from the passphrase
+// 3) Otherwise the remote signer is used if one is configured and the access key has been granted the address in the
+// remotesigneraddresses table. The signing server holds keys for many addresses, so without the grant any access key
+// could sign for any of them
+func FromRequest(c context.Context, m map[string]interface{}, address string, reference string) (Signer, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	if m["walletId"] != nil && m["walletId"].(string) != "" {
+		var operation string
+		if c.Value(consts.RequestTypeKey) != nil {
+			operation = c.Value(consts.RequestTypeKey).(string)
+		}
+
+		accessKey := c.Value(consts.AccessKeyKey).(string)
+		walletId := m["walletId"].(string)
+
+		if errorCode, err := keystore.CanSign(c, accessKey, walletId, operation, address, reference); err != nil {
+			return nil, errorCode, err
+		}
+
+		return NewKeystoreSigner(accessKey, walletId, reference), 0, nil
+	}
+
+	if m["passphrase"] != nil && m["passphrase"].(string) != "" {
+		return NewPassphraseSigner(m["passphrase"].(string)), 0, nil
+	}
+
+	if defaultRemoteSigner != nil {
+		accessKey, _ := c.Value(consts.AccessKeyKey).(string)
+
+		allowed, err := isRemoteSignerAddress(c, accessKey, address)
+		if err != nil {
+			return nil, consts.GenericErrors.GeneralError.Code, errors.New(consts.GenericErrors.GeneralError.Description)
+		}
+
+		if allowed {
+			return defaultRemoteSigner, 0, nil
+		}
+	}
+
+	return nil, consts.GenericErrors.InvalidPassphrase.Code, errors.New(consts.GenericErrors.InvalidPassphrase.Description)
+}
+
+// Returns the remote signer for Enu's own wallets, eg the fuel wallets, which aren't signed for on behalf of a request
+// so aren't granted to any access key. Never use it for an address given in a request, use FromRequest()
+func InternalRemoteSigner() (Signer, int64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	if defaultRemoteSigner == nil {
+		return nil, consts.GenericErrors.InvalidPassphrase.Code, errors.New(consts.GenericErrors.InvalidPassphrase.Description)
+	}
+
+	return defaultRemoteSigner, 0, nil
+}
@@ -0,0 +1,56 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/vennd/enu/consts"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func TestFromRequestRemoteSigner(t *testing.T) {
+	c := context.WithValue(context.TODO(), consts.AccessKeyKey, "key1")
+
+	defaultRemoteSigner = NewRemoteSigner("http://localhost", "secret")
+	defer func() { defaultRemoteSigner = nil }()
+
+	isRemoteSignerAddress = func(c context.Context, accessKey string, address string) (bool, error) {
+		return accessKey == "key1" && address == "rGranted", nil
+	}
+
+	if s, _, err := FromRequest(c, map[string]interface{}{}, "rGranted", ""); err != nil || s != defaultRemoteSigner {
+		t.Errorf("Expected the remote signer for an address granted to the access key, got: %v, %v\n", s, err)
+	}
+
+	s, errorCode, err := FromRequest(c, map[string]interface{}{}, "rOther", "")
+	if err == nil || s != nil || errorCode != consts.GenericErrors.InvalidPassphrase.Code {
+		t.Errorf("Expected an address not granted to the access key to require a passphrase, got: %v, %d, %v\n", s, errorCode, err)
+	}
+
+	c2 := context.WithValue(context.TODO(), consts.AccessKeyKey, "key2")
+	if _, _, err := FromRequest(c2, map[string]interface{}{}, "rGranted", ""); err == nil {
+		t.Errorf("Expected another access key not to use the remote signer for the address\n")
+	}
+
+	if s, _, err := FromRequest(c2, map[string]interface{}{"passphrase": "some passphrase"}, "rGranted", ""); err != nil || s == defaultRemoteSigner {
+		t.Errorf("Expected the passphrase to be used when given, got: %v, %v\n", s, err)
+	}
+}
+
+func TestInternalRemoteSigner(t *testing.T) {
+	if _, _, err := InternalRemoteSigner(); err == nil {
+		t.Errorf("Expected an error without a remote signer\n")
+	}
+
+	defaultRemoteSigner = NewRemoteSigner("http://localhost", "secret")
+	defer func() { defaultRemoteSigner = nil }()
+
+	// Internal wallets aren't granted to any access key
+	isRemoteSignerAddress = func(c context.Context, accessKey string, address string) (bool, error) {
+		return false, nil
+	}
+
+	if s, _, err := InternalRemoteSigner(); err != nil || s != defaultRemoteSigner {
+		t.Errorf("Expected the remote signer, got: %v, %v\n", s, err)
+	}
+}
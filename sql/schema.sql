@@ -31,10 +31,36 @@ CREATE TABLE `activations` (
   `accessKey` varchar(64) NOT NULL,
   `addressToActivate` varchar(200) NOT NULL,
   `amount` bigint(10) NOT NULL,
-  PRIMARY KEY (`rowid`)
+  `errorCode` int(11) DEFAULT NULL,
+  `errorDescription` varchar(200) DEFAULT NULL,
+  PRIMARY KEY (`rowid`),
+  KEY `activations1` (`accessKey`,`activationId`),
+  KEY `activations2` (`accessKey`,`addressToActivate`)
 ) ENGINE=InnoDB AUTO_INCREMENT=1529 DEFAULT CHARSET=latin1;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `addressassignments`
+--
+
+DROP TABLE IF EXISTS `addressassignments`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `addressassignments` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `accessKey` varchar(64) DEFAULT NULL,
+  `blockchainId` varchar(50) DEFAULT NULL,
+  `walletId` varchar(64) DEFAULT NULL,
+  `derivationKey` varchar(200) DEFAULT NULL,
+  `derivationIndex` int(11) DEFAULT NULL,
+  `address` varchar(200) DEFAULT NULL,
+  `label` varchar(200) DEFAULT NULL,
+  `created` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`rowid`),
+  UNIQUE KEY `addressassignments1` (`accessKey`,`blockchainId`,`derivationKey`,`derivationIndex`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `addresses`
 --
@@ -50,6 +76,25 @@ CREATE TABLE `addresses` (
 ) ENGINE=InnoDB AUTO_INCREMENT=357 DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `addressindexes`
+--
+
+DROP TABLE IF EXISTS `addressindexes`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `addressindexes` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `seedHash` varchar(64) DEFAULT NULL,
+  `addressType` varchar(20) DEFAULT NULL,
+  `addressIndex` int(10) unsigned DEFAULT NULL,
+  `address` varchar(64) DEFAULT NULL,
+  PRIMARY KEY (`rowid`),
+  UNIQUE KEY `addressindexes1` (`seedHash`,`addressType`,`addressIndex`),
+  KEY `addressindexes2` (`address`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `addressmaps`
 --
@@ -156,6 +201,41 @@ CREATE TABLE `blocks` (
 ) ENGINE=InnoDB AUTO_INCREMENT=331 DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `checks`
+--
+
+DROP TABLE IF EXISTS `checks`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `checks` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `accessKey` varchar(64) NOT NULL,
+  `blockchainId` varchar(50) NOT NULL,
+  `checkId` varchar(45) NOT NULL,
+  `address` varchar(200) NOT NULL,
+  `destination` varchar(200) NOT NULL,
+  `destinationTag` bigint(20) DEFAULT NULL,
+  `asset` varchar(200) NOT NULL,
+  `issuer` varchar(200) DEFAULT NULL,
+  `amount` bigint(20) NOT NULL,
+  `expiration` bigint(20) DEFAULT NULL,
+  `invoiceId` varchar(64) DEFAULT NULL,
+  `ledgerCheckId` varchar(64) DEFAULT NULL,
+  `txFee` bigint(20) DEFAULT NULL,
+  `broadcastTxId` varchar(200) DEFAULT NULL,
+  `cashTxId` varchar(200) DEFAULT NULL,
+  `cancelTxId` varchar(200) DEFAULT NULL,
+  `status` varchar(45) DEFAULT NULL,
+  `errorCode` bigint(20) DEFAULT NULL,
+  `errorDescription` varchar(512) DEFAULT NULL,
+  `created` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`rowid`),
+  UNIQUE KEY `checks1` (`accessKey`,`checkId`),
+  KEY `checks2` (`accessKey`,`ledgerCheckId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `credits`
 --
@@ -230,6 +310,40 @@ CREATE TABLE `dividends` (
 ) ENGINE=InnoDB AUTO_INCREMENT=145 DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `escrows`
+--
+
+DROP TABLE IF EXISTS `escrows`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `escrows` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `accessKey` varchar(64) NOT NULL,
+  `blockchainId` varchar(50) NOT NULL,
+  `escrowId` varchar(45) NOT NULL,
+  `address` varchar(200) NOT NULL,
+  `destination` varchar(200) NOT NULL,
+  `destinationTag` bigint(20) DEFAULT NULL,
+  `amount` bigint(20) NOT NULL,
+  `finishAfter` bigint(20) DEFAULT NULL,
+  `cancelAfter` bigint(20) DEFAULT NULL,
+  `escrowCondition` varchar(512) DEFAULT NULL,
+  `offerSequence` int(10) unsigned DEFAULT NULL,
+  `txFee` bigint(20) DEFAULT NULL,
+  `broadcastTxId` varchar(200) DEFAULT NULL,
+  `finishTxId` varchar(200) DEFAULT NULL,
+  `cancelTxId` varchar(200) DEFAULT NULL,
+  `status` varchar(45) DEFAULT NULL,
+  `errorCode` bigint(20) DEFAULT NULL,
+  `errorDescription` varchar(512) DEFAULT NULL,
+  `created` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`rowid`),
+  UNIQUE KEY `escrows1` (`accessKey`,`escrowId`),
+  KEY `escrows2` (`accessKey`,`address`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `fees`
 --
@@ -265,6 +379,160 @@ CREATE TABLE `inputaddresses` (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `keystoreaudit`
+--
+
+DROP TABLE IF EXISTS `keystoreaudit`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `keystoreaudit` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `walletId` varchar(64) DEFAULT NULL,
+  `accessKey` varchar(64) DEFAULT NULL,
+  `operation` varchar(45) DEFAULT NULL,
+  `address` varchar(200) DEFAULT NULL,
+  `reference` varchar(64) DEFAULT NULL,
+  `result` varchar(45) DEFAULT NULL,
+  `description` varchar(200) DEFAULT NULL,
+  `requestId` varchar(64) DEFAULT NULL,
+  `created` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`rowid`),
+  KEY `keystoreaudit1` (`walletId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `keystorewallets`
+--
+
+DROP TABLE IF EXISTS `keystorewallets`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `keystorewallets` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `walletId` varchar(64) DEFAULT NULL,
+  `accessKey` varchar(64) DEFAULT NULL,
+  `blockchainId` varchar(50) DEFAULT NULL,
+  `addresses` text,
+  `encryptedPassphrase` text,
+  `encryptedKey` varchar(200) DEFAULT NULL,
+  `passwordHash` varchar(200) DEFAULT NULL,
+  `unlockPolicy` varchar(45) DEFAULT NULL,
+  `unlockSeconds` bigint(20) DEFAULT NULL,
+  `unlockedUntil` bigint(20) DEFAULT '0',
+  `status` varchar(45) DEFAULT NULL,
+  PRIMARY KEY (`rowid`),
+  UNIQUE KEY `keystorewallets1` (`walletId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `multisigproposals`
+--
+
+DROP TABLE IF EXISTS `multisigproposals`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `multisigproposals` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `proposalId` varchar(64) DEFAULT NULL,
+  `walletId` varchar(64) DEFAULT NULL,
+  `accessKey` varchar(64) DEFAULT NULL,
+  `proposalType` varchar(45) DEFAULT NULL,
+  `sourceAddress` varchar(200) DEFAULT NULL,
+  `destinationAddress` varchar(200) DEFAULT NULL,
+  `asset` varchar(200) DEFAULT NULL,
+  `description` varchar(200) DEFAULT NULL,
+  `quantity` bigint(20) DEFAULT NULL,
+  `divisible` tinyint(1) DEFAULT NULL,
+  `unsignedTx` text,
+  `signatureHashes` text,
+  `requiredSignatures` int(11) DEFAULT NULL,
+  `status` varchar(45) DEFAULT NULL,
+  `signedRawTx` text,
+  `broadcastTxId` varchar(200) DEFAULT NULL,
+  `errorCode` bigint(20) DEFAULT NULL,
+  `errorDescription` varchar(512) DEFAULT NULL,
+  PRIMARY KEY (`rowid`),
+  KEY `multisigproposals1` (`proposalId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `multisigsignatures`
+--
+
+DROP TABLE IF EXISTS `multisigsignatures`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `multisigsignatures` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `proposalId` varchar(64) DEFAULT NULL,
+  `publicKey` varchar(66) DEFAULT NULL,
+  `signatures` text,
+  PRIMARY KEY (`rowid`),
+  UNIQUE KEY `multisigsignatures1` (`proposalId`,`publicKey`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `multisigwallets`
+--
+
+DROP TABLE IF EXISTS `multisigwallets`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `multisigwallets` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `walletId` varchar(64) DEFAULT NULL,
+  `accessKey` varchar(64) DEFAULT NULL,
+  `blockchainId` varchar(50) DEFAULT NULL,
+  `address` varchar(200) DEFAULT NULL,
+  `redeemScript` text,
+  `requiredSignatures` int(11) DEFAULT NULL,
+  `publicKeys` text,
+  `signerWeights` text,
+  `status` varchar(45) DEFAULT NULL,
+  PRIMARY KEY (`rowid`),
+  KEY `multisigwallets1` (`walletId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `offers`
+--
+
+DROP TABLE IF EXISTS `offers`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `offers` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `accessKey` varchar(64) NOT NULL,
+  `blockchainId` varchar(50) NOT NULL,
+  `offerId` varchar(45) NOT NULL,
+  `address` varchar(200) NOT NULL,
+  `takerGetsAsset` varchar(200) NOT NULL,
+  `takerGetsIssuer` varchar(200) DEFAULT NULL,
+  `takerGetsAmount` bigint(20) NOT NULL,
+  `takerPaysAsset` varchar(200) NOT NULL,
+  `takerPaysIssuer` varchar(200) DEFAULT NULL,
+  `takerPaysAmount` bigint(20) NOT NULL,
+  `flags` int(10) unsigned NOT NULL DEFAULT '0',
+  `offerSequence` int(10) unsigned DEFAULT NULL,
+  `txFee` bigint(20) DEFAULT NULL,
+  `broadcastTxId` varchar(200) DEFAULT NULL,
+  `cancelTxId` varchar(200) DEFAULT NULL,
+  `status` varchar(45) DEFAULT NULL,
+  `errorCode` bigint(20) DEFAULT NULL,
+  `errorDescription` varchar(512) DEFAULT NULL,
+  `created` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`rowid`),
+  UNIQUE KEY `offers1` (`accessKey`,`offerId`),
+  KEY `offers2` (`accessKey`,`address`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `outputaddresses`
 --
@@ -282,6 +550,45 @@ CREATE TABLE `outputaddresses` (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `paymentChannels`
+--
+
+DROP TABLE IF EXISTS `paymentChannels`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `paymentChannels` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `accessKey` varchar(64) NOT NULL,
+  `blockchainId` varchar(50) NOT NULL,
+  `channelId` varchar(64) NOT NULL,
+  `walletId` varchar(45) DEFAULT NULL,
+  `address` varchar(200) NOT NULL,
+  `destination` varchar(200) NOT NULL,
+  `destinationTag` bigint(20) DEFAULT NULL,
+  `publicKey` varchar(80) NOT NULL,
+  `amount` bigint(20) NOT NULL,
+  `authorisedAmount` bigint(20) NOT NULL DEFAULT '0',
+  `claimSignature` varchar(200) DEFAULT NULL,
+  `claimedAmount` bigint(20) NOT NULL DEFAULT '0',
+  `settleDelay` int(10) unsigned NOT NULL,
+  `cancelAfter` bigint(20) DEFAULT NULL,
+  `expiration` bigint(20) DEFAULT NULL,
+  `txFee` bigint(20) DEFAULT NULL,
+  `broadcastTxId` varchar(200) DEFAULT NULL,
+  `claimTxId` varchar(200) DEFAULT NULL,
+  `closeTxId` varchar(200) DEFAULT NULL,
+  `status` varchar(45) DEFAULT NULL,
+  `errorCode` bigint(20) DEFAULT NULL,
+  `errorDescription` varchar(512) DEFAULT NULL,
+  `created` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`rowid`),
+  UNIQUE KEY `paymentChannels1` (`accessKey`,`channelId`),
+  KEY `paymentChannels2` (`accessKey`,`address`),
+  KEY `paymentChannels3` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `payments`
 --
@@ -310,6 +617,13 @@ CREATE TABLE `payments` (
   `paymentTag` varchar(512) DEFAULT NULL,
   `retryCount` tinyint(4) DEFAULT NULL,
   `signedRawTx` text,
+  `sendAsset` varchar(200) DEFAULT NULL,
+  `sendIssuer` varchar(200) DEFAULT NULL,
+  `sendMax` bigint(20) DEFAULT NULL,
+  `deliveredAmount` bigint(20) DEFAULT NULL,
+  `destinationTag` bigint(20) DEFAULT NULL,
+  `sourceTag` bigint(20) DEFAULT NULL,
+  `memos` text,
   PRIMARY KEY (`rowid`),
   KEY `payments1` (`blockId`)
 ) ENGINE=InnoDB AUTO_INCREMENT=1742 DEFAULT CHARSET=utf8;
@@ -333,6 +647,82 @@ CREATE TABLE `registry` (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `remotesigneraddresses`
+--
+
+DROP TABLE IF EXISTS `remotesigneraddresses`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `remotesigneraddresses` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `accessKey` varchar(64) NOT NULL,
+  `address` varchar(100) NOT NULL,
+  `status` varchar(45) DEFAULT NULL,
+  PRIMARY KEY (`rowid`),
+  UNIQUE KEY `remotesigneraddresses1` (`accessKey`,`address`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `topupcaps`
+--
+
+DROP TABLE IF EXISTS `topupcaps`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `topupcaps` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `accessKey` varchar(64) NOT NULL,
+  `blockchainId` varchar(50) NOT NULL,
+  `dailyCap` bigint(20) NOT NULL,
+  PRIMARY KEY (`rowid`),
+  UNIQUE KEY `topupcaps1` (`accessKey`,`blockchainId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `topuppolicies`
+--
+
+DROP TABLE IF EXISTS `topuppolicies`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `topuppolicies` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `accessKey` varchar(64) NOT NULL,
+  `blockchainId` varchar(50) NOT NULL,
+  `address` varchar(200) NOT NULL,
+  `minimumTransactions` bigint(20) NOT NULL,
+  `topUpAmount` bigint(20) NOT NULL,
+  `status` varchar(20) DEFAULT NULL,
+  `created` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`rowid`),
+  UNIQUE KEY `topuppolicies1` (`accessKey`,`blockchainId`,`address`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `topups`
+--
+
+DROP TABLE IF EXISTS `topups`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `topups` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `accessKey` varchar(64) NOT NULL,
+  `blockchainId` varchar(50) NOT NULL,
+  `address` varchar(200) NOT NULL,
+  `activationId` varchar(45) NOT NULL,
+  `amount` bigint(20) NOT NULL,
+  `created` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`rowid`),
+  KEY `topups1` (`accessKey`,`blockchainId`,`created`),
+  KEY `topups2` (`accessKey`,`blockchainId`,`address`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `transactions`
 --
@@ -365,7 +755,12 @@ CREATE TABLE `trustassets` (
   `asset` varchar(200) DEFAULT NULL,
   `issuer` varchar(200) DEFAULT NULL,
   `trustAmount` bigint(20) DEFAULT NULL,
-  PRIMARY KEY (`rowid`)
+  `status` varchar(20) DEFAULT NULL,
+  `broadcastTxId` varchar(200) DEFAULT NULL,
+  `errorCode` int(11) DEFAULT NULL,
+  `errorDescription` varchar(200) DEFAULT NULL,
+  PRIMARY KEY (`rowid`),
+  KEY `trustassets1` (`accessKey`,`activationId`)
 ) ENGINE=InnoDB AUTO_INCREMENT=74 DEFAULT CHARSET=latin1;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
@@ -389,6 +784,25 @@ CREATE TABLE `userkeys` (
   PRIMARY KEY (`rowId`)
 ) ENGINE=InnoDB AUTO_INCREMENT=337 DEFAULT CHARSET=utf8;
 /*!40101 SET character_set_client = @saved_cs_client */;
+--
+-- Table structure for table `watchwallets`
+--
+
+DROP TABLE IF EXISTS `watchwallets`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `watchwallets` (
+  `rowid` bigint(20) NOT NULL AUTO_INCREMENT,
+  `walletId` varchar(64) DEFAULT NULL,
+  `accessKey` varchar(64) DEFAULT NULL,
+  `blockchainId` varchar(50) DEFAULT NULL,
+  `xpub` varchar(200) DEFAULT NULL,
+  `addresses` text,
+  `status` varchar(45) DEFAULT NULL,
+  PRIMARY KEY (`rowid`),
+  KEY `watchwallets1` (`walletId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
 /*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
 
 /*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
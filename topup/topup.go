@@ -0,0 +1,239 @@
+// Package topup automatically tops up the fuel of addresses which have a top up policy.
+//
+// An access key registers a policy for an address giving the minimum number of transactions the address must be able
+// to pay for and the number of transactions to top up with. The job periodically checks the number of transactions
+// each address can pay for and sends a top up through the activation flow when it falls below the minimum.
+//
+// Top ups are limited by the daily cap of the access key, ie the number of transactions the access key may be topped
+// up with in any 24 hours. Access keys which haven't set a cap use "topupdefaultdailycap" from enuapi.json, which is
+// zero unless configured so no top ups are sent until a cap is set. Other optional settings are:
+//
+//	"topupintervalseconds": 600,  // How often the addresses are checked
+//	"topupcooldownseconds": 3600  // How long to wait after a top up before topping up the same address again
+//
+// The cool down stops an address being topped up repeatedly while the previous top up is still unconfirmed.
+package topup
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/counterpartyhandlers"
+	"github.com/vennd/enu/database"
+	"github.com/vennd/enu/enulib"
+	"github.com/vennd/enu/log"
+	"github.com/vennd/enu/ripplehandlers"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+// Number of recent top ups returned with a policy
+const RecentTopUps = 20
+
+type chainFunctions struct {
+	numberOfTransactions func(c context.Context, address string) (uint64, error)
+	topUpAddress         func(c context.Context, address string, amount uint64, activationId string) (int64, error)
+}
+
+var chains = map[string]chainFunctions{
+	consts.CounterpartyBlockchainId: {counterpartyhandlers.NumberOfTransactions, counterpartyhandlers.TopUpAddress},
+	consts.RippleBlockchainId:       {ripplehandlers.NumberOfTransactions, ripplehandlers.TopUpAddress},
+}
+
+var intervalSeconds = 600
+var cooldownSeconds int64 = 3600
+var defaultDailyCap uint64
+var isInit bool = false
+
+// Reads the top up settings from enuapi.json and starts the top up job
+func Init() {
+	var configFilePath string
+
+	if isInit == true {
+		return
+	}
+
+	if _, err := os.Stat("./enuapi.json"); err == nil {
+		configFilePath = "./enuapi.json"
+	} else {
+		if _, err := os.Stat(os.Getenv("GOPATH") + "/bin/enuapi.json"); err == nil {
+			configFilePath = os.Getenv("GOPATH") + "/bin/enuapi.json"
+		} else {
+			if _, err := os.Stat(os.Getenv("GOPATH") + "/src/github.com/vennd/enu/enuapi.json"); err == nil {
+				configFilePath = os.Getenv("GOPATH") + "/src/github.com/vennd/enu/enuapi.json"
+			} else {
+				log.Println("Cannot find enuapi.json")
+				os.Exit(-100)
+			}
+		}
+	}
+
+	InitWithConfigPath(configFilePath)
+}
+
+func InitWithConfigPath(configFilePath string) {
+	var configuration interface{}
+
+	if isInit == true {
+		return
+	}
+
+	// Read configuration from file
+	file, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		log.Println("Unable to read configuration file enuapi.json")
+		log.Println(err.Error())
+		os.Exit(-101)
+	}
+
+	err = json.Unmarshal(file, &configuration)
+	if err != nil {
+		log.Println("Unable to parse enuapi.json")
+		log.Println(err.Error())
+		os.Exit(-101)
+	}
+
+	m := configuration.(map[string]interface{})
+
+	if m["topupintervalseconds"] != nil && m["topupintervalseconds"].(float64) > 0 {
+		intervalSeconds = int(m["topupintervalseconds"].(float64))
+	}
+
+	if m["topupcooldownseconds"] != nil {
+		cooldownSeconds = int64(m["topupcooldownseconds"].(float64))
+	}
+
+	if m["topupdefaultdailycap"] != nil {
+		defaultDailyCap = uint64(m["topupdefaultdailycap"].(float64))
+	}
+
+	isInit = true
+
+	go run()
+}
+
+func run() {
+	for {
+		CheckPolicies(context.TODO())
+
+		time.Sleep(time.Duration(intervalSeconds) * time.Second)
+	}
+}
+
+// Returns the daily cap of the access key, or the default if the access key hasn't set one
+func DailyCap(c context.Context, accessKey string, blockchainId string) (uint64, error) {
+	if isInit == false {
+		Init()
+	}
+
+	dailyCap, found, err := database.GetTopUpCap(c, accessKey, blockchainId)
+	if err != nil {
+		return 0, err
+	}
+
+	if !found {
+		return defaultDailyCap, nil
+	}
+
+	return dailyCap, nil
+}
+
+// Decides whether the address should be topped up. Returns the reason if it shouldn't
+func shouldTopUp(policy enulib.TopUpPolicy, numberOfTransactions uint64, spent uint64, dailyCap uint64, secondsSinceLastTopUp int64, toppedUp bool) (bool, error) {
+	if numberOfTransactions >= policy.MinimumTransactions {
+		return false, nil
+	}
+
+	if toppedUp && secondsSinceLastTopUp < cooldownSeconds {
+		return false, errors.New("The address was topped up recently. Waiting for the top up to confirm")
+	}
+
+	if spent+policy.TopUpAmount > dailyCap {
+		return false, errors.New("The top up would exceed the daily cap of the access key")
+	}
+
+	return true, nil
+}
+
+// Checks the addresses of every enabled policy and tops up those below their minimum number of transactions
+func CheckPolicies(c context.Context) {
+	policies, err := database.GetEnabledTopUpPolicies(c)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Error in GetEnabledTopUpPolicies(): %s", err.Error())
+		return
+	}
+
+	env := os.Getenv("ENV")
+	if env == "" {
+		env = "dev"
+	}
+
+	for _, record := range policies {
+		chain, ok := chains[record.Policy.BlockchainId]
+		if !ok {
+			continue
+		}
+
+		// Build the context the activation flow expects as if the access key had requested the activation
+		policyContext := context.WithValue(c, consts.RequestIdKey, enulib.GenerateRequestId())
+		policyContext = context.WithValue(policyContext, consts.EnvKey, env)
+		policyContext = context.WithValue(policyContext, consts.AccessKeyKey, record.AccessKey)
+		policyContext = context.WithValue(policyContext, consts.BlockchainIdKey, record.Policy.BlockchainId)
+		policyContext = context.WithValue(policyContext, consts.RequestTypeKey, "topup")
+
+		checkPolicy(policyContext, chain, record.AccessKey, record.Policy)
+	}
+}
+
+func checkPolicy(c context.Context, chain chainFunctions, accessKey string, policy enulib.TopUpPolicy) {
+	numberOfTransactions, err := chain.numberOfTransactions(c, policy.Address)
+	if err != nil {
+		log.FluentfContext(consts.LOGERROR, c, "Unable to get the number of transactions of %s: %s", policy.Address, err.Error())
+		return
+	}
+
+	// Avoid querying the spend and cap for addresses which have enough fuel
+	if numberOfTransactions >= policy.MinimumTransactions {
+		return
+	}
+
+	spent, err := database.GetTopUpSpend(c, accessKey, policy.BlockchainId)
+	if err != nil {
+		return
+	}
+
+	dailyCap, err := DailyCap(c, accessKey, policy.BlockchainId)
+	if err != nil {
+		return
+	}
+
+	secondsSinceLastTopUp, toppedUp, err := database.GetSecondsSinceLastTopUp(c, accessKey, policy.BlockchainId, policy.Address)
+	if err != nil {
+		return
+	}
+
+	if ok, err := shouldTopUp(policy, numberOfTransactions, spent, dailyCap, secondsSinceLastTopUp, toppedUp); !ok {
+		if err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Not topping up %s which can pay for %d transactions: %s", policy.Address, numberOfTransactions, err.Error())
+		}
+		return
+	}
+
+	activationId := enulib.GenerateActivationId()
+	log.FluentfContext(consts.LOGINFO, c, "Topping up %s which can pay for %d transactions with %d transactions. ActivationId: %s", policy.Address, numberOfTransactions, policy.TopUpAmount, activationId)
+
+	// The top up is recorded before it is sent so it counts towards the cap and cool down even if the send is slow
+	if err := database.InsertTopUp(c, accessKey, policy.BlockchainId, policy.Address, activationId, policy.TopUpAmount); err != nil {
+		return
+	}
+
+	go func() {
+		if _, err := chain.topUpAddress(c, policy.Address, policy.TopUpAmount, activationId); err != nil {
+			log.FluentfContext(consts.LOGERROR, c, "Top up %s of %s failed: %s", activationId, policy.Address, err.Error())
+		}
+	}()
+}
@@ -0,0 +1,38 @@
+package topup
+
+import (
+	"testing"
+
+	"github.com/vennd/enu/enulib"
+)
+
+func TestShouldTopUp(t *testing.T) {
+	cooldownSeconds = 3600
+	policy := enulib.TopUpPolicy{Address: "1E5YgFkC4HNHwWTF5iUdDbKpzry1SRLv8e", MinimumTransactions: 10, TopUpAmount: 50}
+
+	var testData = []struct {
+		NumberOfTransactions  uint64
+		Spent                 uint64
+		DailyCap              uint64
+		SecondsSinceLastTopUp int64
+		ToppedUp              bool
+		Expected              bool
+		ExpectedReason        bool
+		CaseDescription       string
+	}{
+		{10, 0, 100, 0, false, false, false, "Address has the minimum number of transactions"},
+		{9, 0, 100, 0, false, true, false, "Address below the minimum which has never been topped up"},
+		{9, 0, 100, 7200, true, true, false, "Address below the minimum topped up before the cool down"},
+		{9, 0, 100, 60, true, false, true, "Address topped up during the cool down"},
+		{9, 50, 100, 0, false, true, false, "Top up reaches the daily cap"},
+		{9, 51, 100, 0, false, false, true, "Top up exceeds the daily cap"},
+		{0, 0, 0, 0, false, false, true, "Access key without a daily cap"},
+	}
+
+	for _, s := range testData {
+		result, err := shouldTopUp(policy, s.NumberOfTransactions, s.Spent, s.DailyCap, s.SecondsSinceLastTopUp, s.ToppedUp)
+		if result != s.Expected || (err != nil) != s.ExpectedReason {
+			t.Errorf("%s. Expected: %t, %t. Got: %t, %v\n", s.CaseDescription, s.Expected, s.ExpectedReason, result, err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func TopUpPolicyCreate(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "topUpPolicyCreate")
+
+	return handle(c, w, r)
+}
+
+func GetTopUpPolicy(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getTopUpPolicy")
+
+	return handle(c, w, r)
+}
+
+func TopUpPolicyDisable(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "topUpPolicyDisable")
+
+	return handle(c, w, r)
+}
+
+func TopUpCapSet(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "topUpCapSet")
+
+	return handle(c, w, r)
+}
+
+func GetTopUpCap(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getTopUpCap")
+
+	return handle(c, w, r)
+}
@@ -36,3 +36,45 @@ func ActivateAddress(c context.Context, w http.ResponseWriter, r *http.Request)
 
 	return handle(c, w, r)
 }
+
+func GetActivation(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getactivation")
+
+	return handle(c, w, r)
+}
+
+func GetActivationsByAddress(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "activationsbyaddress")
+
+	return handle(c, w, r)
+}
+
+func WalletDiscover(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "walletDiscover")
+
+	return handle(c, w, r)
+}
+
+func WalletNextAddress(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "walletNextAddress")
+
+	return handle(c, w, r)
+}
+
+func SignMessage(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "signMessage")
+
+	return handle(c, w, r)
+}
+
+func VerifyMessage(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "verifyMessage")
+
+	return handle(c, w, r)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/vennd/enu/consts"
+	"github.com/vennd/enu/enulib"
+
+	"github.com/vennd/enu/internal/golang.org/x/net/context"
+)
+
+func WatchWalletCreate(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "watchWalletCreate")
+
+	return handle(c, w, r)
+}
+
+func GetWatchWallet(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "getWatchWallet")
+
+	return handle(c, w, r)
+}
+
+func WatchWalletBalance(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "watchWalletBalance")
+
+	return handle(c, w, r)
+}
+
+func WatchWalletPayments(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "watchWalletPayments")
+
+	return handle(c, w, r)
+}
+
+func WatchWalletCompose(c context.Context, w http.ResponseWriter, r *http.Request) *enulib.AppError {
+	// Add to the context the RequestType
+	c = context.WithValue(c, consts.RequestTypeKey, "watchWalletCompose")
+
+	return handle(c, w, r)
+}